@@ -1,16 +1,20 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"log"
 	"net/http"
 	"os"
 
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/certifications"
 	"github.com/BiryaniJedi/LandscapeForm-backend/internal/chemicals"
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/customers"
 	"github.com/BiryaniJedi/LandscapeForm-backend/internal/db"
 	"github.com/BiryaniJedi/LandscapeForm-backend/internal/forms"
 	"github.com/BiryaniJedi/LandscapeForm-backend/internal/handlers"
 	"github.com/BiryaniJedi/LandscapeForm-backend/internal/middleware"
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/reports"
 	"github.com/BiryaniJedi/LandscapeForm-backend/internal/users"
 	"github.com/go-chi/chi/v5"
 	chimiddleware "github.com/go-chi/chi/v5/middleware"
@@ -35,7 +39,7 @@ func healthHandler(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
-func setupRouter(formsHandler *handlers.FormsHandler, usersHandler *handlers.UsersHandler, authHandler *handlers.AuthHandler, chemicalsHandler *handlers.ChemicalsHandler, usersRepo *users.UsersRepository) *chi.Mux {
+func setupRouter(formsHandler *handlers.FormsHandler, usersHandler *handlers.UsersHandler, authHandler *handlers.AuthHandler, chemicalsHandler *handlers.ChemicalsHandler, customersHandler *handlers.CustomersHandler, reportsHandler *handlers.ReportsHandler, certificationsHandler *handlers.CertificationsHandler, usersRepo *users.UsersRepository) *chi.Mux {
 	r := chi.NewRouter()
 
 	// Global middleware
@@ -57,10 +61,14 @@ func setupRouter(formsHandler *handlers.FormsHandler, usersHandler *handlers.Use
 	r.Route("/api", func(r chi.Router) {
 		r.Use(middleware.AuthMiddleware(usersRepo))
 		r.Get("/auth/me", authHandler.Me)
+		r.Get("/site-codes", handlers.ListSiteCodes)
+		r.Get("/holidays", handlers.ListHolidays)
 
 		r.Route("/forms", func(r chi.Router) {
 			r.Use(middleware.RequireApproved)
 			r.Get("/", formsHandler.ListForms)
+			r.Get("/export.csv", formsHandler.ExportForms)
+			r.Get("/trash", formsHandler.ListTrash)
 			r.Route("/shrub", func(r chi.Router) {
 				r.Post("/", formsHandler.CreateShrubForm)
 				r.Put("/{id}", formsHandler.UpdateShrubForm)
@@ -75,6 +83,49 @@ func setupRouter(formsHandler *handlers.FormsHandler, usersHandler *handlers.Use
 			r.Route("/{id}", func(r chi.Router) {
 				r.Get("/", formsHandler.GetFormView)
 				r.Delete("/", formsHandler.DeleteForm)
+				r.Post("/restore", formsHandler.RestoreForm)
+				r.Post("/clone", formsHandler.CloneForm)
+				r.Get("/pdf", formsHandler.GetFormPDF)
+
+				r.Post("/applications", formsHandler.CreatePestApp)
+				r.Put("/applications/{appId}", formsHandler.UpdatePestApp)
+				r.Delete("/applications/{appId}", formsHandler.DeletePestApp)
+
+				r.Get("/notes", formsHandler.ListNotes)
+				r.Post("/notes", formsHandler.CreateNote)
+				r.Put("/notes/{noteId}", formsHandler.UpdateNote)
+				r.Delete("/notes/{noteId}", formsHandler.DeleteNote)
+
+				r.Get("/history", formsHandler.GetFormHistory)
+			})
+		})
+
+		// Customers are shared by every approved user, only admins may edit or delete them
+		// since edits are copied onto every user's forms
+		r.Route("/customers", func(r chi.Router) {
+			r.Use(middleware.RequireApproved)
+			r.Get("/", customersHandler.ListCustomers)
+			r.Post("/", customersHandler.CreateCustomer)
+
+			// The timeline shows every user's notes, applications and edits at an address
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.AdminOnly)
+
+				r.Get("/timeline", customersHandler.GetTimeline)
+			})
+
+			r.Route("/{id}", func(r chi.Router) {
+				r.Get("/", customersHandler.GetCustomer)
+				r.Post("/properties", customersHandler.CreateProperty)
+
+				r.Group(func(r chi.Router) {
+					r.Use(middleware.AdminOnly)
+
+					r.Put("/", customersHandler.UpdateCustomer)
+					r.Put("/properties/{propertyId}", customersHandler.UpdateProperty)
+					r.Delete("/", customersHandler.DeleteCustomer)
+					r.Delete("/properties/{propertyId}", customersHandler.DeleteProperty)
+				})
 			})
 		})
 
@@ -89,12 +140,36 @@ func setupRouter(formsHandler *handlers.FormsHandler, usersHandler *handlers.Use
 				r.Get("/", usersHandler.ListUsers)
 				r.Delete("/{id}", usersHandler.DeleteUser)
 				r.Post("/{id}/approve", usersHandler.ApproveUser)
+				r.Put("/{id}/license", usersHandler.SetUserLicense)
+				r.Delete("/{id}/license", usersHandler.DeleteUserLicense)
 			})
 		})
 
 		r.Route("/admin/forms", func(r chi.Router) {
 			r.Use(middleware.AdminOnly)
 			r.Get("/", formsHandler.ListAllForms)
+			r.Get("/export.csv", formsHandler.ExportAllForms)
+			r.Get("/trash", formsHandler.ListAllTrash)
+			r.Get("/duplicates", formsHandler.ListDuplicateClusters)
+			r.Post("/merge", formsHandler.MergeForms)
+			r.Post("/clone-season", formsHandler.CloneSeason)
+			r.Post("/import", formsHandler.ImportForms)
+			r.Post("/pdf-packet", formsHandler.CreatePdfPacket)
+			r.Get("/pdf-packet/{packetId}", formsHandler.GetPdfPacket)
+			r.Get("/pdf-packet/{packetId}/pdf", formsHandler.DownloadPdfPacket)
+			r.Post("/trash/purge", formsHandler.PurgeTrash)
+			r.Get("/over-label", formsHandler.ListOverLabelApps)
+			r.Get("/{id}/history", formsHandler.GetAnyFormHistory)
+			r.Post("/{id}/rollback", formsHandler.RollbackForm)
+			r.Get("/{id}/calls", formsHandler.ListCalls)
+			r.Post("/{id}/calls", formsHandler.LogCall)
+			r.Post("/{id}/applications/{appId}/acknowledge", formsHandler.AcknowledgeOverLabelApp)
+		})
+
+		r.Route("/admin/call-queue", func(r chi.Router) {
+			r.Use(middleware.AdminOnly)
+
+			r.Get("/", formsHandler.GetCallQueue)
 		})
 
 		// Chemicals routes (public for listing by category, admin for management)
@@ -110,6 +185,21 @@ func setupRouter(formsHandler *handlers.FormsHandler, usersHandler *handlers.Use
 			r.Put("/{id}", chemicalsHandler.UpdateChemical)
 			r.Delete("/{id}", chemicalsHandler.DeleteChemical)
 		})
+
+		r.Route("/admin/reports", func(r chi.Router) {
+			r.Use(middleware.AdminOnly)
+
+			r.Get("/pesticide-usage", reportsHandler.GetPesticideUsage)
+		})
+
+		r.Route("/admin/certifications", func(r chi.Router) {
+			r.Use(middleware.AdminOnly)
+
+			r.Get("/expiring", certificationsHandler.ListExpiringLicenses)
+			r.Get("/users/{id}", certificationsHandler.GetStanding)
+			r.Post("/users/{id}/credits", certificationsHandler.AddCredit)
+			r.Delete("/users/{id}/credits/{creditId}", certificationsHandler.DeleteCredit)
+		})
 	})
 
 	return r
@@ -134,6 +224,13 @@ func main() {
 	formsRepo := forms.NewFormsRepository(database)
 	formsHandler := handlers.NewFormsHandler(formsRepo)
 
+	// No packet can be rendering yet, so pending ones were cut off by the last shutdown
+	if count, err := formsRepo.FailInterruptedPdfPackets(context.Background()); err != nil {
+		log.Printf("Failed to clean up interrupted PDF packets: %v", err)
+	} else if count > 0 {
+		log.Printf("Marked %d interrupted PDF packets as failed", count)
+	}
+
 	usersRepo := users.NewUsersRepository(database)
 	usersHandler := handlers.NewUsersHandler(usersRepo)
 	authHandler := handlers.NewAuthHandler(usersRepo)
@@ -141,7 +238,19 @@ func main() {
 	chemicalsRepo := chemicals.NewChemicalsRepository(database)
 	chemicalsHandler := handlers.NewChemicalsHandler(chemicalsRepo)
 
-	router := setupRouter(formsHandler, usersHandler, authHandler, chemicalsHandler, usersRepo)
+	customersRepo := customers.NewCustomersRepository(database)
+	customersHandler := handlers.NewCustomersHandler(customersRepo)
+
+	reportsRepo := reports.NewReportsRepository(database)
+	reportsHandler := handlers.NewReportsHandler(reportsRepo)
+
+	certificationsRepo := certifications.NewCertificationsRepository(database)
+	certificationsHandler := handlers.NewCertificationsHandler(certificationsRepo)
+
+	// Warn about applicator licenses about to lapse, once a day
+	go certificationsRepo.RunDailyCheck(context.Background())
+
+	router := setupRouter(formsHandler, usersHandler, authHandler, chemicalsHandler, customersHandler, reportsHandler, certificationsHandler, usersRepo)
 
 	log.Printf("Server starting on localhost:%s", port)
 	log.Printf("Database connected successfully")
@@ -159,6 +268,8 @@ func main() {
 		log.Printf("  GET    /api/users                    (admin only)")
 		log.Printf("  DELETE /api/users/{id}               (admin only)")
 		log.Printf("  POST   /api/users/{id}/approve       (admin only)")
+		log.Printf("  PUT    /api/users/{id}/license       (admin only)")
+		log.Printf("  DELETE /api/users/{id}/license       (admin only)")
 		log.Printf("")
 		log.Printf("  Form endpoints:")
 		log.Printf("  GET    /api/forms                    (auth required - supports pagination & filtering)")
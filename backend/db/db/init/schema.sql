@@ -1,4 +1,5 @@
 CREATE EXTENSION IF NOT EXISTS "pgcrypto";
+CREATE EXTENSION IF NOT EXISTS "pg_trgm";
 --
 -- Users table
 CREATE TABLE users (
@@ -11,7 +12,34 @@ CREATE TABLE users (
     last_name TEXT NOT NULL,
     date_of_birth DATE NOT NULL DEFAULT '2000-01-01',
     username TEXT UNIQUE NOT NULL,
-    password_hash TEXT NOT NULL
+    password_hash TEXT NOT NULL,
+    -- Certified pesticide applicator license; NULL for users without one on file
+    license_number TEXT,
+    license_category TEXT,
+    license_state CHAR(2),
+    license_expires_on DATE
+);
+
+-- Customers and the properties they own
+CREATE TABLE customers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    first_name TEXT NOT NULL,
+    last_name TEXT NOT NULL,
+    home_phone TEXT NOT NULL,
+    other_phone TEXT NOT NULL
+);
+
+CREATE TABLE properties (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    customer_id UUID NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    street_number TEXT NOT NULL,
+    street_name TEXT NOT NULL,
+    town TEXT NOT NULL,
+    zip_code TEXT NOT NULL CHECK (zip_code ~ '^\d{5}(-\d{4})?$')
 );
 
 -- Forms table
@@ -20,6 +48,10 @@ CREATE TABLE forms (
     created_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
     created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
     form_type TEXT NOT NULL CHECK (form_type IN ('shrub', 'lawn')),
+    -- Client info below is copied from the property and kept in sync with it
+    property_id UUID REFERENCES properties(id) ON DELETE SET NULL,
+    -- Form this one was cloned from in an earlier season
+    cloned_from UUID REFERENCES forms(id) ON DELETE SET NULL,
     updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
 
     -- Client info
@@ -34,7 +66,18 @@ CREATE TABLE forms (
 
     -- General form info
     call_before BOOLEAN NOT NULL DEFAULT FALSE,
-    is_holiday BOOLEAN NOT NULL DEFAULT FALSE
+    is_holiday BOOLEAN NOT NULL DEFAULT FALSE,
+
+    -- Trash (soft delete)
+    deleted_at TIMESTAMPTZ,
+    deleted_by UUID REFERENCES users(id) ON DELETE SET NULL,
+
+    -- Full-text search over client name, address and phones (note text is indexed on notes)
+    search_vector TSVECTOR GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', first_name || ' ' || last_name), 'A') ||
+        setweight(to_tsvector('simple', street_number || ' ' || street_name || ' ' || town || ' ' || zip_code), 'B') ||
+        setweight(to_tsvector('simple', home_phone || ' ' || other_phone), 'C')
+    ) STORED
 );
 
 -- chemical list for forms
@@ -45,7 +88,16 @@ CREATE TABLE chemicals (
     chemical_name TEXT NOT NULL,
     epa_reg_no TEXT NOT NULL,
     recipe TEXT NOT NULL,
-    unit TEXT NOT NULL
+    unit TEXT NOT NULL,
+    -- Label rate range, e.g. 1.5 to 3 oz per 1,000 sq ft; NULL when the label rate is not on file
+    label_rate_min NUMERIC(10, 4),
+    label_rate_max NUMERIC(10, 4),
+    label_rate_unit TEXT,
+    label_rate_basis TEXT CHECK (label_rate_basis IN ('per_1000_sq_ft', 'per_gallon')),
+    -- Set when the chemical's details change, so earlier applications can be flagged in reports
+    edited_at TIMESTAMPTZ,
+    -- Chemicals still used by applications are retired instead of deleted
+    deleted_at TIMESTAMPTZ
 );
 
 CREATE TABLE pesticide_applications (
@@ -55,7 +107,30 @@ CREATE TABLE pesticide_applications (
     app_timestamp TIMESTAMPTZ NOT NULL,
     rate TEXT NOT NULL,
     amount_applied NUMERIC(10, 2) NOT NULL,
-    location_code VARCHAR(2) NOT NULL
+    location_code VARCHAR(2) NOT NULL,
+    -- Structured rate, e.g. 2 oz per 1,000 sq ft; NULL for applications with only the free-text rate
+    rate_amount NUMERIC(10, 4),
+    rate_unit TEXT,
+    rate_basis TEXT CHECK (rate_basis IN ('per_1000_sq_ft', 'per_gallon')),
+    -- Amount the structured rate calls for on a lawn form's area
+    expected_amount NUMERIC(10, 2),
+    -- Set when the rate or amount exceeds the chemical's label maximum, until a supervisor acknowledges it
+    over_label_max BOOLEAN NOT NULL DEFAULT FALSE,
+    over_label_acknowledged_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    over_label_acknowledged_at TIMESTAMPTZ,
+    -- How the chemical was applied and the weather at the time; all optional
+    application_method TEXT CHECK (application_method IN ('spray', 'granular', 'injection', 'bait')),
+    wind_speed_mph NUMERIC(4, 1) CHECK (wind_speed_mph BETWEEN 0 AND 100),
+    wind_direction TEXT,
+    temperature_f NUMERIC(4, 1) CHECK (temperature_f BETWEEN -40 AND 130),
+    sky_conditions TEXT,
+    -- Applicator license in effect when the application was recorded
+    applicator_license_number TEXT,
+    applicator_license_category TEXT,
+    applicator_license_state CHAR(2),
+    applicator_license_expires_on DATE,
+    -- Who made the application; defaults to whoever recorded it
+    applied_by UUID REFERENCES users(id) ON DELETE SET NULL
 );
 
 -- Shrub forms table
@@ -79,15 +154,112 @@ CREATE TABLE lawn_forms (
 
 -- Notes for each form (optional)
 CREATE TABLE notes (
-    id SMALLSERIAL,
+    id SERIAL,
     form_id UUID NOT NULL REFERENCES forms(id) ON DELETE CASCADE,
+    created_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
     note TEXT NOT NULL,
+    search_vector TSVECTOR GENERATED ALWAYS AS (to_tsvector('simple', note)) STORED,
     PRIMARY KEY (id, form_id)
 );
 
 
+-- Change history for each form
+-- Not tied to forms by foreign key so the history outlives the form
+CREATE TABLE form_revisions (
+    id BIGSERIAL PRIMARY KEY,
+    form_id UUID NOT NULL,
+    changed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    action TEXT NOT NULL CHECK (action IN ('create', 'update', 'delete', 'rollback', 'restore', 'purge', 'merge')),
+    diff JSONB NOT NULL,
+    snapshot JSONB NOT NULL
+);
+
+-- Audit log of duplicate forms merged into a surviving form
+-- Like revisions, not tied to forms by foreign key since the merged forms are deleted
+CREATE TABLE form_merges (
+    id BIGSERIAL PRIMARY KEY,
+    survivor_id UUID NOT NULL,
+    merged_ids UUID[] NOT NULL,
+    merged_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    merged_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    -- Field name -> ID of the form whose value the survivor kept
+    field_sources JSONB NOT NULL,
+    application_ids INT[] NOT NULL,
+    note_ids INT[] NOT NULL
+);
+
+-- PDF packets too large to render during the request, built in the background
+CREATE TABLE pdf_packets (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    requested_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMPTZ,
+    form_ids UUID[] NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'ready', 'failed')),
+    error TEXT NOT NULL DEFAULT '',
+    document BYTEA
+);
+
+-- Continuing-education credits applicators earn toward renewing their licenses
+CREATE TABLE certification_credits (
+    id SERIAL PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    course TEXT NOT NULL,
+    completed_on DATE NOT NULL,
+    credits NUMERIC(5, 2) NOT NULL CHECK (credits > 0),
+    category TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Calls made to customers who asked to be called before applications
+CREATE TABLE form_calls (
+    id BIGSERIAL PRIMARY KEY,
+    form_id UUID NOT NULL REFERENCES forms(id) ON DELETE CASCADE,
+    called_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    called_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    outcome TEXT NOT NULL CHECK (outcome IN ('reached', 'voicemail', 'declined')),
+    notes TEXT NOT NULL DEFAULT ''
+);
+
+
+-- Duplicate detection
+-- Folds case, punctuation and common street suffixes so "Main Street." and "main st" compare equal
+CREATE OR REPLACE FUNCTION normalize_street(name TEXT)
+RETURNS TEXT AS $$
+DECLARE
+  abbreviations TEXT[][] := ARRAY[
+    ['street', 'st'], ['avenue', 'ave'], ['road', 'rd'], ['drive', 'dr'], ['lane', 'ln'],
+    ['court', 'ct'], ['place', 'pl'], ['boulevard', 'blvd'], ['terrace', 'ter'],
+    ['circle', 'cir'], ['parkway', 'pkwy'], ['highway', 'hwy'],
+    ['north', 'n'], ['south', 's'], ['east', 'e'], ['west', 'w']
+  ];
+  normalized TEXT := regexp_replace(LOWER(name), '[^a-z0-9]+', ' ', 'g');
+BEGIN
+  FOR i IN 1 .. array_length(abbreviations, 1) LOOP
+    normalized := regexp_replace(normalized, '\m' || abbreviations[i][1] || '\M', abbreviations[i][2], 'g');
+  END LOOP;
+  RETURN TRIM(normalized);
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+-- Last ten digits of a phone number, NULL when there are too few digits to identify a line
+CREATE OR REPLACE FUNCTION phone_digits(phone TEXT)
+RETURNS TEXT AS $$
+  SELECT CASE WHEN length(d) >= 7 THEN right(d, 10) END
+  FROM (SELECT regexp_replace(phone, '\D', '', 'g') AS d) digits
+$$ LANGUAGE sql IMMUTABLE;
+
 -- Indices
+-- Customers and properties
+CREATE INDEX idx_customers_name_lower ON customers (LOWER(last_name), LOWER(first_name));
+CREATE INDEX idx_properties_customer_id ON properties(customer_id);
+CREATE UNIQUE INDEX idx_properties_address ON properties (LOWER(street_number), LOWER(street_name), zip_code);
 -- Forms
+CREATE INDEX idx_forms_property_id ON forms(property_id);
+CREATE INDEX idx_forms_cloned_from ON forms(cloned_from);
 CREATE INDEX idx_forms_user_created_at ON forms(created_by, created_at DESC);
 CREATE INDEX idx_forms_name_lower ON forms (LOWER(first_name), LOWER(last_name));
 CREATE INDEX idx_forms_name ON forms(first_name, last_name);
@@ -98,10 +270,30 @@ CREATE INDEX idx_forms_town_lower ON forms(LOWER(town));
 CREATE INDEX idx_forms_street_number ON forms(street_number);
 CREATE INDEX idx_forms_zip_code ON forms(zip_code);
 CREATE INDEX idx_forms_home_phone ON forms(home_phone);
+CREATE INDEX idx_forms_deleted_at ON forms(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_forms_search_vector ON forms USING GIN (search_vector);
+CREATE INDEX idx_forms_street_number_trgm ON forms USING GIN (street_number gin_trgm_ops);
+CREATE INDEX idx_forms_home_phone_digits_trgm ON forms USING GIN (regexp_replace(home_phone, '\D', '', 'g') gin_trgm_ops);
+CREATE INDEX idx_forms_other_phone_digits_trgm ON forms USING GIN (regexp_replace(other_phone, '\D', '', 'g') gin_trgm_ops);
+CREATE INDEX idx_forms_duplicate_address ON forms (zip_code, LOWER(TRIM(street_number))) WHERE deleted_at IS NULL;
 -- Chemicals
 CREATE INDEX idx_chemicals_id ON chemicals(id);
 -- Pesticide pesticide_applications
 CREATE INDEX idx_pesticide_applications_app_timestamp ON pesticide_applications(app_timestamp);
+CREATE INDEX idx_pesticide_applications_form_id ON pesticide_applications(form_id);
+CREATE INDEX idx_pesticide_applications_chem_used ON pesticide_applications(chem_used);
+CREATE INDEX idx_pesticide_applications_applied_by ON pesticide_applications(applied_by);
+-- Notes
+CREATE INDEX idx_notes_form_created_at ON notes(form_id, created_at);
+CREATE INDEX idx_notes_search_vector ON notes USING GIN (search_vector);
+-- Form revisions
+CREATE INDEX idx_form_revisions_form_changed_at ON form_revisions(form_id, changed_at);
+-- Form merges
+CREATE INDEX idx_form_merges_survivor_id ON form_merges(survivor_id);
+-- Certification credits
+CREATE INDEX idx_certification_credits_user_completed_on ON certification_credits(user_id, completed_on);
+-- Form calls
+CREATE INDEX idx_form_calls_form_called_at ON form_calls(form_id, called_at);
 
 -- Triggers
 CREATE OR REPLACE FUNCTION set_updated_at()
@@ -117,11 +309,26 @@ BEFORE UPDATE ON forms
 FOR EACH ROW
 EXECUTE FUNCTION set_updated_at();
 
+CREATE TRIGGER trg_customers_updated
+BEFORE UPDATE ON customers
+FOR EACH ROW
+EXECUTE FUNCTION set_updated_at();
+
+CREATE TRIGGER trg_properties_updated
+BEFORE UPDATE ON properties
+FOR EACH ROW
+EXECUTE FUNCTION set_updated_at();
+
 CREATE TRIGGER trg_users_updated
 BEFORE UPDATE ON users 
 FOR EACH ROW
 EXECUTE FUNCTION set_updated_at();
 
+CREATE TRIGGER trg_notes_updated
+BEFORE UPDATE ON notes
+FOR EACH ROW
+EXECUTE FUNCTION set_updated_at();
+
 CREATE OR REPLACE FUNCTION prevent_form_type_change()
 RETURNS TRIGGER AS $$
 BEGIN
@@ -0,0 +1,34 @@
+-- Adds the author and timestamps to form notes.
+-- Existing notes are attributed to their form's creator and dated when the form was created.
+--
+-- psql "$DATABASE_URL" -f db/migrations/001_note_authors.sql
+
+BEGIN;
+
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS created_by UUID REFERENCES users(id) ON DELETE CASCADE;
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ;
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ;
+
+UPDATE notes n
+SET created_by = COALESCE(n.created_by, f.created_by),
+    created_at = COALESCE(n.created_at, f.created_at),
+    updated_at = COALESCE(n.updated_at, n.created_at, f.created_at)
+FROM forms f
+WHERE f.id = n.form_id
+  AND (n.created_by IS NULL OR n.created_at IS NULL OR n.updated_at IS NULL);
+
+ALTER TABLE notes ALTER COLUMN created_by SET NOT NULL;
+ALTER TABLE notes ALTER COLUMN created_at SET DEFAULT NOW();
+ALTER TABLE notes ALTER COLUMN created_at SET NOT NULL;
+ALTER TABLE notes ALTER COLUMN updated_at SET DEFAULT NOW();
+ALTER TABLE notes ALTER COLUMN updated_at SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_notes_form_created_at ON notes(form_id, created_at);
+
+DROP TRIGGER IF EXISTS trg_notes_updated ON notes;
+CREATE TRIGGER trg_notes_updated
+BEFORE UPDATE ON notes
+FOR EACH ROW
+EXECUTE FUNCTION set_updated_at();
+
+COMMIT;
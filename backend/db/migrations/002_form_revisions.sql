@@ -0,0 +1,21 @@
+-- Adds the change history recorded for every form.
+-- Forms edited before this migration start their history at their next change.
+--
+-- psql "$DATABASE_URL" -f db/migrations/002_form_revisions.sql
+
+BEGIN;
+
+-- Not tied to forms by foreign key so the history outlives the form
+CREATE TABLE IF NOT EXISTS form_revisions (
+    id BIGSERIAL PRIMARY KEY,
+    form_id UUID NOT NULL,
+    changed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    action TEXT NOT NULL CHECK (action IN ('create', 'update', 'delete', 'rollback')),
+    diff JSONB NOT NULL,
+    snapshot JSONB NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_form_revisions_form_changed_at ON form_revisions(form_id, changed_at);
+
+COMMIT;
@@ -0,0 +1,17 @@
+-- Adds the trash: forms are soft deleted and can be restored until they are purged.
+--
+-- psql "$DATABASE_URL" -f db/migrations/003_form_trash.sql
+
+BEGIN;
+
+ALTER TABLE forms ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE forms ADD COLUMN IF NOT EXISTS deleted_by UUID REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_forms_deleted_at ON forms(deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Allow restore and purge revisions
+ALTER TABLE form_revisions DROP CONSTRAINT IF EXISTS form_revisions_action_check;
+ALTER TABLE form_revisions ADD CONSTRAINT form_revisions_action_check
+    CHECK (action IN ('create', 'update', 'delete', 'rollback', 'restore', 'purge'));
+
+COMMIT;
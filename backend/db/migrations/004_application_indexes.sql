@@ -0,0 +1,11 @@
+-- Adds the indexes used to load the applications of a page of forms in one query
+-- and to filter forms by chemical.
+--
+-- psql "$DATABASE_URL" -f db/migrations/004_application_indexes.sql
+
+BEGIN;
+
+CREATE INDEX IF NOT EXISTS idx_pesticide_applications_form_id ON pesticide_applications(form_id);
+CREATE INDEX IF NOT EXISTS idx_pesticide_applications_chem_used ON pesticide_applications(chem_used);
+
+COMMIT;
@@ -0,0 +1,26 @@
+-- Adds full-text search over forms and notes, and trigram indexes for partial house numbers
+-- and phone digits. Existing rows are indexed as the generated columns are added.
+--
+-- psql "$DATABASE_URL" -f db/migrations/005_form_search.sql
+
+BEGIN;
+
+CREATE EXTENSION IF NOT EXISTS "pg_trgm";
+
+-- Client name, address and phones (note text is indexed on notes)
+ALTER TABLE forms ADD COLUMN IF NOT EXISTS search_vector TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', first_name || ' ' || last_name), 'A') ||
+    setweight(to_tsvector('simple', street_number || ' ' || street_name || ' ' || town || ' ' || zip_code), 'B') ||
+    setweight(to_tsvector('simple', home_phone || ' ' || other_phone), 'C')
+) STORED;
+
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS search_vector TSVECTOR
+    GENERATED ALWAYS AS (to_tsvector('simple', note)) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_forms_search_vector ON forms USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_forms_street_number_trgm ON forms USING GIN (street_number gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_forms_home_phone_digits_trgm ON forms USING GIN (regexp_replace(home_phone, '\D', '', 'g') gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_forms_other_phone_digits_trgm ON forms USING GIN (regexp_replace(other_phone, '\D', '', 'g') gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_notes_search_vector ON notes USING GIN (search_vector);
+
+COMMIT;
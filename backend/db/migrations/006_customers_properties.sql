@@ -0,0 +1,97 @@
+-- Adds customers and properties to an existing database and links every form to one.
+-- Forms are grouped by address (street number, street name and zip, ignoring case and
+-- surrounding spaces); each address becomes a property owned by a customer whose name
+-- and phones come from the most recent form written there.
+-- Safe to re-run: forms that already reference a property are left alone.
+--
+-- psql "$DATABASE_URL" -f db/migrations/006_customers_properties.sql
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS customers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    first_name TEXT NOT NULL,
+    last_name TEXT NOT NULL,
+    home_phone TEXT NOT NULL,
+    other_phone TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS properties (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    customer_id UUID NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    street_number TEXT NOT NULL,
+    street_name TEXT NOT NULL,
+    town TEXT NOT NULL,
+    zip_code TEXT NOT NULL CHECK (zip_code ~ '^\d{5}(-\d{4})?$')
+);
+
+ALTER TABLE forms ADD COLUMN IF NOT EXISTS property_id UUID REFERENCES properties(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_customers_name_lower ON customers (LOWER(last_name), LOWER(first_name));
+CREATE INDEX IF NOT EXISTS idx_properties_customer_id ON properties(customer_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_properties_address ON properties (LOWER(street_number), LOWER(street_name), zip_code);
+CREATE INDEX IF NOT EXISTS idx_forms_property_id ON forms(property_id);
+
+DROP TRIGGER IF EXISTS trg_customers_updated ON customers;
+CREATE TRIGGER trg_customers_updated
+BEFORE UPDATE ON customers
+FOR EACH ROW
+EXECUTE FUNCTION set_updated_at();
+
+DROP TRIGGER IF EXISTS trg_properties_updated ON properties;
+CREATE TRIGGER trg_properties_updated
+BEFORE UPDATE ON properties
+FOR EACH ROW
+EXECUTE FUNCTION set_updated_at();
+
+-- One row per address without a property yet, taken from its most recent form
+CREATE TEMP TABLE address_groups ON COMMIT DROP AS
+SELECT DISTINCT ON (LOWER(TRIM(f.street_number)), LOWER(TRIM(f.street_name)), f.zip_code)
+    gen_random_uuid() AS customer_id,
+    gen_random_uuid() AS property_id,
+    f.created_at,
+    f.first_name,
+    f.last_name,
+    f.home_phone,
+    f.other_phone,
+    TRIM(f.street_number) AS street_number,
+    TRIM(f.street_name) AS street_name,
+    f.town,
+    f.zip_code
+FROM forms f
+WHERE f.property_id IS NULL
+  AND NOT EXISTS (
+      SELECT 1
+      FROM properties p
+      WHERE LOWER(p.street_number) = LOWER(TRIM(f.street_number))
+        AND LOWER(p.street_name) = LOWER(TRIM(f.street_name))
+        AND p.zip_code = f.zip_code
+  )
+ORDER BY LOWER(TRIM(f.street_number)), LOWER(TRIM(f.street_name)), f.zip_code, f.created_at DESC;
+
+INSERT INTO customers (id, created_at, first_name, last_name, home_phone, other_phone)
+SELECT customer_id, created_at, first_name, last_name, home_phone, other_phone
+FROM address_groups;
+
+INSERT INTO properties (id, customer_id, created_at, street_number, street_name, town, zip_code)
+SELECT property_id, customer_id, created_at, street_number, street_name, town, zip_code
+FROM address_groups;
+
+-- Linking forms is not an edit, so leave updated_at alone
+ALTER TABLE forms DISABLE TRIGGER trg_forms_updated;
+
+UPDATE forms f
+SET property_id = p.id
+FROM properties p
+WHERE f.property_id IS NULL
+  AND LOWER(p.street_number) = LOWER(TRIM(f.street_number))
+  AND LOWER(p.street_name) = LOWER(TRIM(f.street_name))
+  AND p.zip_code = f.zip_code;
+
+ALTER TABLE forms ENABLE TRIGGER trg_forms_updated;
+
+COMMIT;
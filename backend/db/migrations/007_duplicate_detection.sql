@@ -0,0 +1,36 @@
+-- Adds the helpers used to spot likely duplicate forms when a form is created
+-- and in the admin duplicate report.
+--
+-- psql "$DATABASE_URL" -f db/migrations/007_duplicate_detection.sql
+
+BEGIN;
+
+-- Folds case, punctuation and common street suffixes so "Main Street." and "main st" compare equal
+CREATE OR REPLACE FUNCTION normalize_street(name TEXT)
+RETURNS TEXT AS $$
+DECLARE
+  abbreviations TEXT[][] := ARRAY[
+    ['street', 'st'], ['avenue', 'ave'], ['road', 'rd'], ['drive', 'dr'], ['lane', 'ln'],
+    ['court', 'ct'], ['place', 'pl'], ['boulevard', 'blvd'], ['terrace', 'ter'],
+    ['circle', 'cir'], ['parkway', 'pkwy'], ['highway', 'hwy'],
+    ['north', 'n'], ['south', 's'], ['east', 'e'], ['west', 'w']
+  ];
+  normalized TEXT := regexp_replace(LOWER(name), '[^a-z0-9]+', ' ', 'g');
+BEGIN
+  FOR i IN 1 .. array_length(abbreviations, 1) LOOP
+    normalized := regexp_replace(normalized, '\m' || abbreviations[i][1] || '\M', abbreviations[i][2], 'g');
+  END LOOP;
+  RETURN TRIM(normalized);
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+-- Last ten digits of a phone number, NULL when there are too few digits to identify a line
+CREATE OR REPLACE FUNCTION phone_digits(phone TEXT)
+RETURNS TEXT AS $$
+  SELECT CASE WHEN length(d) >= 7 THEN right(d, 10) END
+  FROM (SELECT regexp_replace(phone, '\D', '', 'g') AS d) digits
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE INDEX IF NOT EXISTS idx_forms_duplicate_address ON forms (zip_code, LOWER(TRIM(street_number))) WHERE deleted_at IS NULL;
+
+COMMIT;
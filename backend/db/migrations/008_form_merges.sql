@@ -0,0 +1,24 @@
+-- Adds the audit log for merging duplicate forms and allows merge revisions.
+--
+-- psql "$DATABASE_URL" -f db/migrations/008_form_merges.sql
+
+BEGIN;
+
+ALTER TABLE form_revisions DROP CONSTRAINT IF EXISTS form_revisions_action_check;
+ALTER TABLE form_revisions ADD CONSTRAINT form_revisions_action_check
+    CHECK (action IN ('create', 'update', 'delete', 'rollback', 'restore', 'purge', 'merge'));
+
+CREATE TABLE IF NOT EXISTS form_merges (
+    id BIGSERIAL PRIMARY KEY,
+    survivor_id UUID NOT NULL,
+    merged_ids UUID[] NOT NULL,
+    merged_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    merged_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    field_sources JSONB NOT NULL,
+    application_ids INT[] NOT NULL,
+    note_ids INT[] NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_form_merges_survivor_id ON form_merges(survivor_id);
+
+COMMIT;
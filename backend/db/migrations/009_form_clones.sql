@@ -0,0 +1,11 @@
+-- Links forms cloned into a new season back to the form they were cloned from.
+--
+-- psql "$DATABASE_URL" -f db/migrations/009_form_clones.sql
+
+BEGIN;
+
+ALTER TABLE forms ADD COLUMN IF NOT EXISTS cloned_from UUID REFERENCES forms(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_forms_cloned_from ON forms(cloned_from);
+
+COMMIT;
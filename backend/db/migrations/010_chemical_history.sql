@@ -0,0 +1,12 @@
+-- Tracks when chemicals are edited and lets chemicals used by applications be retired
+-- instead of deleted, so the pesticide usage report can flag affected applications.
+-- Existing chemicals start out unedited; edits made before this migration are not known.
+--
+-- psql "$DATABASE_URL" -f db/migrations/010_chemical_history.sql
+
+BEGIN;
+
+ALTER TABLE chemicals ADD COLUMN IF NOT EXISTS edited_at TIMESTAMPTZ;
+ALTER TABLE chemicals ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+COMMIT;
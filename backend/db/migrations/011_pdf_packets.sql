@@ -0,0 +1,18 @@
+-- Stores the PDF packets rendered in the background until they are downloaded.
+--
+-- psql "$DATABASE_URL" -f db/migrations/011_pdf_packets.sql
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS pdf_packets (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    requested_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMPTZ,
+    form_ids UUID[] NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'ready', 'failed')),
+    error TEXT NOT NULL DEFAULT '',
+    document BYTEA
+);
+
+COMMIT;
@@ -0,0 +1,25 @@
+-- Adds structured label rates to chemicals and structured rates to pesticide applications,
+-- so applications over a chemical's label maximum can be flagged for supervisor acknowledgement.
+-- Existing chemicals and applications keep only their free-text recipe and rate.
+--
+-- psql "$DATABASE_URL" -f db/migrations/012_label_rates.sql
+
+BEGIN;
+
+ALTER TABLE chemicals ADD COLUMN IF NOT EXISTS label_rate_min NUMERIC(10, 4);
+ALTER TABLE chemicals ADD COLUMN IF NOT EXISTS label_rate_max NUMERIC(10, 4);
+ALTER TABLE chemicals ADD COLUMN IF NOT EXISTS label_rate_unit TEXT;
+ALTER TABLE chemicals ADD COLUMN IF NOT EXISTS label_rate_basis TEXT
+    CHECK (label_rate_basis IN ('per_1000_sq_ft', 'per_gallon'));
+
+ALTER TABLE pesticide_applications ADD COLUMN IF NOT EXISTS rate_amount NUMERIC(10, 4);
+ALTER TABLE pesticide_applications ADD COLUMN IF NOT EXISTS rate_unit TEXT;
+ALTER TABLE pesticide_applications ADD COLUMN IF NOT EXISTS rate_basis TEXT
+    CHECK (rate_basis IN ('per_1000_sq_ft', 'per_gallon'));
+ALTER TABLE pesticide_applications ADD COLUMN IF NOT EXISTS expected_amount NUMERIC(10, 2);
+ALTER TABLE pesticide_applications ADD COLUMN IF NOT EXISTS over_label_max BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE pesticide_applications ADD COLUMN IF NOT EXISTS over_label_acknowledged_by UUID
+    REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE pesticide_applications ADD COLUMN IF NOT EXISTS over_label_acknowledged_at TIMESTAMPTZ;
+
+COMMIT;
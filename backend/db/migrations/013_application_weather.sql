@@ -0,0 +1,17 @@
+-- Adds the application method and the weather at the time of application to pesticide applications,
+-- so windy spray applications can be flagged. Existing applications are left without them.
+--
+-- psql "$DATABASE_URL" -f db/migrations/013_application_weather.sql
+
+BEGIN;
+
+ALTER TABLE pesticide_applications ADD COLUMN IF NOT EXISTS application_method TEXT
+    CHECK (application_method IN ('spray', 'granular', 'injection', 'bait'));
+ALTER TABLE pesticide_applications ADD COLUMN IF NOT EXISTS wind_speed_mph NUMERIC(4, 1)
+    CHECK (wind_speed_mph BETWEEN 0 AND 100);
+ALTER TABLE pesticide_applications ADD COLUMN IF NOT EXISTS wind_direction TEXT;
+ALTER TABLE pesticide_applications ADD COLUMN IF NOT EXISTS temperature_f NUMERIC(4, 1)
+    CHECK (temperature_f BETWEEN -40 AND 130);
+ALTER TABLE pesticide_applications ADD COLUMN IF NOT EXISTS sky_conditions TEXT;
+
+COMMIT;
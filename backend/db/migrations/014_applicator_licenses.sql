@@ -0,0 +1,18 @@
+-- Adds applicator licenses to users and stamps pesticide applications with the license
+-- in effect when they were recorded. Existing users and applications are left without one.
+--
+-- psql "$DATABASE_URL" -f db/migrations/014_applicator_licenses.sql
+
+BEGIN;
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS license_number TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS license_category TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS license_state CHAR(2);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS license_expires_on DATE;
+
+ALTER TABLE pesticide_applications ADD COLUMN IF NOT EXISTS applicator_license_number TEXT;
+ALTER TABLE pesticide_applications ADD COLUMN IF NOT EXISTS applicator_license_category TEXT;
+ALTER TABLE pesticide_applications ADD COLUMN IF NOT EXISTS applicator_license_state CHAR(2);
+ALTER TABLE pesticide_applications ADD COLUMN IF NOT EXISTS applicator_license_expires_on DATE;
+
+COMMIT;
@@ -0,0 +1,20 @@
+-- Adds the ledger of continuing-education credits applicators earn toward renewing their licenses.
+--
+-- psql "$DATABASE_URL" -f db/migrations/015_certification_credits.sql
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS certification_credits (
+    id SERIAL PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    course TEXT NOT NULL,
+    completed_on DATE NOT NULL,
+    credits NUMERIC(5, 2) NOT NULL CHECK (credits > 0),
+    category TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_certification_credits_user_completed_on
+    ON certification_credits(user_id, completed_on);
+
+COMMIT;
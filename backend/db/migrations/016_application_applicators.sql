@@ -0,0 +1,19 @@
+-- Records who made each pesticide application. Existing applications are attributed to the
+-- creator of their form, and their license stamp is left as it was.
+--
+-- psql "$DATABASE_URL" -f db/migrations/016_application_applicators.sql
+
+BEGIN;
+
+ALTER TABLE pesticide_applications
+    ADD COLUMN IF NOT EXISTS applied_by UUID REFERENCES users(id) ON DELETE SET NULL;
+
+UPDATE pesticide_applications pa
+SET applied_by = f.created_by
+FROM forms f
+WHERE f.id = pa.form_id
+  AND pa.applied_by IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_pesticide_applications_applied_by ON pesticide_applications(applied_by);
+
+COMMIT;
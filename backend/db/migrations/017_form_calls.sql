@@ -0,0 +1,18 @@
+-- Adds the log of calls made to customers who asked to be called before applications.
+--
+-- psql "$DATABASE_URL" -f db/migrations/017_form_calls.sql
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS form_calls (
+    id BIGSERIAL PRIMARY KEY,
+    form_id UUID NOT NULL REFERENCES forms(id) ON DELETE CASCADE,
+    called_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    called_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    outcome TEXT NOT NULL CHECK (outcome IN ('reached', 'voicemail', 'declined')),
+    notes TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS idx_form_calls_form_called_at ON form_calls(form_id, called_at);
+
+COMMIT;
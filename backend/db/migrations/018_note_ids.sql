@@ -0,0 +1,10 @@
+-- Widens note IDs past the 32767 a SMALLSERIAL allows, since season rollovers copy every note.
+--
+-- psql "$DATABASE_URL" -f db/migrations/018_note_ids.sql
+
+BEGIN;
+
+ALTER TABLE notes ALTER COLUMN id TYPE INTEGER;
+ALTER SEQUENCE notes_id_seq AS INTEGER;
+
+COMMIT;
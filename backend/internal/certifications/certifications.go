@@ -0,0 +1,240 @@
+// Package certifications tracks the continuing-education credits applicators earn toward
+// renewing their licenses, and which licenses are about to lapse.
+package certifications
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/users"
+	"github.com/lib/pq"
+	"github.com/shopspring/decimal"
+)
+
+// CertificationsRepository provides database access for credit entries and license standings.
+type CertificationsRepository struct {
+	db *sql.DB
+}
+
+// NewCertificationsRepository returns a repository backed by the given database connection.
+func NewCertificationsRepository(database *sql.DB) *CertificationsRepository {
+	return &CertificationsRepository{db: database}
+}
+
+// RequirementsFromEnv returns the renewal requirements.
+// CERTIFICATION_CYCLE_YEARS is the length of a renewal cycle, and CERTIFICATION_CREDITS lists
+// the credits required per category as category=credits pairs, e.g. "core=8,3B=16".
+func RequirementsFromEnv() Requirements {
+	years, err := strconv.Atoi(os.Getenv("CERTIFICATION_CYCLE_YEARS"))
+	if err != nil || years <= 0 {
+		years = 5 // Fallback when unset or invalid
+	}
+	credits, err := ParseCredits(os.Getenv("CERTIFICATION_CREDITS"))
+	if err != nil || len(credits) == 0 {
+		credits = map[string]decimal.Decimal{"core": decimal.NewFromInt(8)} // Fallback when unset or invalid
+	}
+	return Requirements{CycleYears: years, Credits: credits}
+}
+
+// AddCredit records a credit entry for a user.
+// Returns the created entry upon success.
+// It returns sql.ErrNoRows if the user does not exist,
+// and an error wrapping ErrInvalidCredit if the entry is invalid.
+func (r *CertificationsRepository) AddCredit(
+	ctx context.Context,
+	userID string,
+	input CreditInput,
+) (Credit, error) {
+	if err := input.Validate(time.Now()); err != nil {
+		return Credit{}, err
+	}
+
+	credit := Credit{
+		UserID:   userID,
+		Course:   strings.TrimSpace(input.Course),
+		Credits:  input.Credits,
+		Category: strings.TrimSpace(input.Category),
+	}
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO certification_credits (user_id, course, completed_on, credits, category)
+		SELECT u.id, $2::text, $3::date, $4::numeric, $5::text
+		FROM users u
+		WHERE u.id = $1
+		RETURNING id, completed_on, created_at
+	`,
+		userID,
+		credit.Course,
+		dateOf(input.CompletedOn),
+		credit.Credits,
+		credit.Category,
+	).Scan(&credit.ID, &credit.CompletedOn, &credit.CreatedAt)
+	if err != nil {
+		// sql.ErrNoRows → user not found
+		return Credit{}, err
+	}
+
+	return credit, nil
+}
+
+// ListCredits returns a user's credit entries, most recently completed first
+func (r *CertificationsRepository) ListCredits(ctx context.Context, userID string) ([]Credit, error) {
+	credits, err := r.listCreditsByUserIds(ctx, []string{userID})
+	if err != nil {
+		return nil, err
+	}
+	if credits[userID] == nil {
+		return []Credit{}, nil
+	}
+	return credits[userID], nil
+}
+
+// DeleteCredit deletes one of a user's credit entries.
+// It returns sql.ErrNoRows if the entry does not exist or belongs to another user.
+func (r *CertificationsRepository) DeleteCredit(ctx context.Context, userID string, creditID int) error {
+	return r.db.QueryRowContext(ctx, `
+		DELETE FROM certification_credits
+		WHERE id = $1 AND user_id = $2
+		RETURNING id
+	`, creditID, userID).Scan(&creditID)
+}
+
+// listCreditsByUserIds returns the credit entries of the given users keyed by user ID,
+// most recently completed first
+func (r *CertificationsRepository) listCreditsByUserIds(ctx context.Context, userIDs []string) (map[string][]Credit, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, user_id, course, completed_on, credits, category, created_at
+		FROM certification_credits
+		WHERE user_id = ANY($1::uuid[])
+		ORDER BY completed_on DESC, id DESC
+	`, pq.Array(userIDs))
+	if err != nil {
+		return nil, fmt.Errorf("error fetching credit entries: %w", err)
+	}
+	defer rows.Close()
+
+	credits := map[string][]Credit{}
+	for rows.Next() {
+		var credit Credit
+		err := rows.Scan(
+			&credit.ID,
+			&credit.UserID,
+			&credit.Course,
+			&credit.CompletedOn,
+			&credit.Credits,
+			&credit.Category,
+			&credit.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning credit entry: %w", err)
+		}
+		credits[credit.UserID] = append(credits[credit.UserID], credit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after credit entries query: %w", err)
+	}
+
+	return credits, nil
+}
+
+// listStandings computes the standing of the users matched by condition, a WHERE clause over
+// users u taking args, ordered by license expiry
+func (r *CertificationsRepository) listStandings(
+	ctx context.Context,
+	reqs Requirements,
+	today time.Time,
+	condition string,
+	args ...any,
+) ([]Standing, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT
+			u.id,
+			u.first_name,
+			u.last_name,
+			u.license_number,
+			u.license_category,
+			u.license_state,
+			u.license_expires_on
+		FROM users u
+		WHERE `+condition+`
+		ORDER BY u.license_expires_on, u.last_name, u.first_name
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching applicators: %w", err)
+	}
+	defer rows.Close()
+
+	standings := []Standing{}
+	userIDs := []string{}
+	for rows.Next() {
+		var (
+			standing  Standing
+			number    sql.NullString
+			category  sql.NullString
+			state     sql.NullString
+			expiresOn sql.NullTime
+		)
+		err := rows.Scan(
+			&standing.UserID,
+			&standing.FirstName,
+			&standing.LastName,
+			&number,
+			&category,
+			&state,
+			&expiresOn,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning applicator: %w", err)
+		}
+		if number.Valid && expiresOn.Valid {
+			standing.License = &users.License{
+				Number:    number.String,
+				Category:  category.String,
+				State:     state.String,
+				ExpiresOn: expiresOn.Time,
+			}
+		}
+		standings = append(standings, standing)
+		userIDs = append(userIDs, standing.UserID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after applicators query: %w", err)
+	}
+
+	credits, err := r.listCreditsByUserIds(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i, standing := range standings {
+		standings[i] = computeStanding(standing, credits[standing.UserID], reqs, today)
+	}
+
+	return standings, nil
+}
+
+// GetStanding returns a user's progress toward renewing their license against reqs.
+// It returns sql.ErrNoRows if the user does not exist.
+func (r *CertificationsRepository) GetStanding(ctx context.Context, userID string, reqs Requirements) (Standing, error) {
+	standings, err := r.listStandings(ctx, reqs, time.Now(), "u.id = $1", userID)
+	if err != nil {
+		return Standing{}, err
+	}
+	if len(standings) == 0 {
+		return Standing{}, sql.ErrNoRows
+	}
+	return standings[0], nil
+}
+
+// ListExpiring returns the standing of every applicator whose license expires within the next
+// days days, today included, soonest first. Licenses that have already expired are left out.
+func (r *CertificationsRepository) ListExpiring(ctx context.Context, days int, reqs Requirements) ([]Standing, error) {
+	today := dateOf(time.Now())
+	return r.listStandings(ctx, reqs, today, `
+			u.license_number IS NOT NULL
+			AND u.license_expires_on BETWEEN $1 AND $2
+	`, today, today.AddDate(0, 0, days))
+}
@@ -0,0 +1,200 @@
+package certifications
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/db"
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/users"
+	"github.com/joho/godotenv"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	// Load test-specific environment variables
+	_ = godotenv.Load("../../.env.testing")
+
+	os.Exit(m.Run())
+}
+
+// createTestApplicator creates a user whose license expires on expiresOn, or without a license if it is zero
+func createTestApplicator(t testing.TB, db *sql.DB, lastName string, expiresOn time.Time) string {
+	t.Helper()
+
+	var number, expires any
+	if !expiresOn.IsZero() {
+		number, expires = "57"+lastName, expiresOn
+	}
+	var id string
+	err := db.QueryRow(`
+		INSERT INTO users (first_name, last_name, username, password_hash,
+			license_number, license_category, license_state, license_expires_on)
+		VALUES ('Test', $1, 'TestUser_' || gen_random_uuid()::text, 'TestPass', $2, '3B', 'NJ', $3)
+		RETURNING id
+	`, lastName, number, expires).Scan(&id)
+
+	require.NoError(t, err)
+	return id
+}
+
+func TestCreditLedger(t *testing.T) {
+	ctx := context.Background()
+	database := db.TestDB(t)
+	repo := NewCertificationsRepository(database)
+
+	today := dateOf(time.Now())
+	userID := createTestApplicator(t, database, "Ledger", today.AddDate(1, 0, 0))
+
+	first, err := repo.AddCredit(ctx, userID, CreditInput{
+		Course:      "Turf Pests Update",
+		CompletedOn: today.AddDate(-1, 0, 0),
+		Credits:     decimal.NewFromInt(4),
+		Category:    "core",
+	})
+	require.NoError(t, err)
+	require.NotZero(t, first.ID)
+	require.True(t, today.AddDate(-1, 0, 0).Equal(first.CompletedOn))
+
+	second, err := repo.AddCredit(ctx, userID, CreditInput{
+		Course:      "Ornamental Spraying",
+		CompletedOn: today,
+		Credits:     decimal.RequireFromString("2.5"),
+		Category:    "3B",
+	})
+	require.NoError(t, err)
+
+	credits, err := repo.ListCredits(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, credits, 2)
+	require.Equal(t, second.ID, credits[0].ID, "Most recently completed first")
+
+	_, err = repo.AddCredit(ctx, userID, CreditInput{
+		Course:      "Next Year's Course",
+		CompletedOn: today.AddDate(0, 0, 7),
+		Credits:     decimal.NewFromInt(1),
+		Category:    "core",
+	})
+	require.ErrorIs(t, err, ErrInvalidCredit)
+
+	_, err = repo.AddCredit(ctx, "00000000-0000-0000-0000-000000000000", CreditInput{
+		Course:      "Nobody's Course",
+		CompletedOn: today,
+		Credits:     decimal.NewFromInt(1),
+		Category:    "core",
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	require.NoError(t, repo.DeleteCredit(ctx, userID, first.ID))
+	require.ErrorIs(t, repo.DeleteCredit(ctx, userID, first.ID), sql.ErrNoRows)
+
+	credits, err = repo.ListCredits(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, credits, 1)
+}
+
+func TestListExpiring(t *testing.T) {
+	ctx := context.Background()
+	database := db.TestDB(t)
+	repo := NewCertificationsRepository(database)
+
+	today := dateOf(time.Now())
+	soonID := createTestApplicator(t, database, "Soon", today.AddDate(0, 0, 10))
+	laterID := createTestApplicator(t, database, "Later", today.AddDate(0, 0, 45))
+	createTestApplicator(t, database, "Distant", today.AddDate(0, 0, 200))
+	createTestApplicator(t, database, "Lapsed", today.AddDate(0, 0, -1))
+	createTestApplicator(t, database, "Unlicensed", time.Time{})
+
+	reqs := Requirements{CycleYears: 5, Credits: map[string]decimal.Decimal{
+		"core": decimal.NewFromInt(8),
+		"3b":   decimal.NewFromInt(4),
+	}}
+	for _, credit := range []CreditInput{
+		{Course: "Core Review", CompletedOn: today.AddDate(-2, 0, 0), Credits: decimal.NewFromInt(8), Category: "Core"},
+		{Course: "Ornamentals", CompletedOn: today.AddDate(-1, 0, 0), Credits: decimal.NewFromInt(4), Category: "3B"},
+	} {
+		_, err := repo.AddCredit(ctx, soonID, credit)
+		require.NoError(t, err)
+	}
+	// Credits from before the renewal cycle do not count
+	_, err := repo.AddCredit(ctx, laterID, CreditInput{
+		Course:      "Old Core Review",
+		CompletedOn: today.AddDate(-6, 0, 0),
+		Credits:     decimal.NewFromInt(8),
+		Category:    "core",
+	})
+	require.NoError(t, err)
+
+	standings, err := repo.ListExpiring(ctx, 60, reqs)
+	require.NoError(t, err)
+	require.Len(t, standings, 2)
+
+	soon := standings[0]
+	require.Equal(t, soonID, soon.UserID)
+	require.Equal(t, 10, soon.DaysUntilExpiry)
+	require.True(t, soon.RequirementsMet)
+	require.Len(t, soon.Categories, 2)
+	require.Equal(t, "3b", soon.Categories[0].Category)
+
+	later := standings[1]
+	require.Equal(t, laterID, later.UserID)
+	require.False(t, later.RequirementsMet)
+	require.Equal(t, "core", later.Categories[1].Category)
+	require.True(t, later.Categories[1].Earned.IsZero())
+	require.Equal(t, "8", later.Categories[1].Shortfall().String())
+
+	standings, err = repo.ListExpiring(ctx, 30, reqs)
+	require.NoError(t, err)
+	require.Len(t, standings, 1)
+
+	logged, err := repo.CheckExpiring(ctx, 60, reqs)
+	require.NoError(t, err)
+	require.Len(t, logged, 2)
+
+	standing, err := repo.GetStanding(ctx, laterID, reqs)
+	require.NoError(t, err)
+	require.Equal(t, "57Later", standing.License.Number)
+
+	_, err = repo.GetStanding(ctx, "00000000-0000-0000-0000-000000000000", reqs)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestComputeStanding(t *testing.T) {
+	today := time.Date(2026, 6, 1, 15, 0, 0, 0, time.UTC)
+	reqs := Requirements{CycleYears: 3, Credits: map[string]decimal.Decimal{"core": decimal.NewFromInt(6)}}
+	license := &users.License{Number: "57001", ExpiresOn: time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)}
+
+	standing := computeStanding(Standing{License: license}, []Credit{
+		{CompletedOn: time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC), Credits: decimal.NewFromInt(3), Category: "core"},
+		{CompletedOn: time.Date(2023, 7, 2, 0, 0, 0, 0, time.UTC), Credits: decimal.NewFromInt(2), Category: "CORE"},
+		{CompletedOn: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), Credits: decimal.NewFromInt(5), Category: "other"},
+	}, reqs, today)
+
+	require.Equal(t, 30, standing.DaysUntilExpiry)
+	require.Equal(t, time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC), standing.CycleStart)
+	require.False(t, standing.RequirementsMet)
+	require.Len(t, standing.Categories, 1)
+	require.Equal(t, "2", standing.Categories[0].Earned.String(), "Credits on the cycle's first day belong to the last cycle")
+	require.Equal(t, "4", standing.Categories[0].Shortfall().String())
+
+	// Without a license, the cycle ends today
+	standing = computeStanding(Standing{}, nil, reqs, today)
+	require.Zero(t, standing.DaysUntilExpiry)
+	require.Equal(t, time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC), standing.CycleEnd)
+}
+
+func TestParseCredits(t *testing.T) {
+	credits, err := ParseCredits(" Core=8, 3B=4.5 ")
+	require.NoError(t, err)
+	require.Len(t, credits, 2)
+	require.Equal(t, "8", credits["core"].String())
+	require.Equal(t, "4.5", credits["3b"].String())
+
+	for _, bad := range []string{"core", "=8", "core=many", "core=-1"} {
+		_, err := ParseCredits(bad)
+		require.Error(t, err, bad)
+	}
+}
@@ -0,0 +1,68 @@
+package certifications
+
+import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// AlertDaysFromEnv returns how many days ahead the daily check warns about lapsing licenses
+func AlertDaysFromEnv() int {
+	days, err := strconv.Atoi(os.Getenv("CERTIFICATION_ALERT_DAYS"))
+	if err != nil || days < 0 {
+		days = 60 // Fallback when unset or invalid
+	}
+	return days
+}
+
+// CheckExpiring logs every license expiring within days days, with the credits its holder
+// still needs to renew. Returns the standings it logged.
+func (r *CertificationsRepository) CheckExpiring(ctx context.Context, days int, reqs Requirements) ([]Standing, error) {
+	standings, err := r.ListExpiring(ctx, days, reqs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, standing := range standings {
+		status := "credits complete"
+		if !standing.RequirementsMet {
+			status = "short"
+			for _, category := range standing.Categories {
+				if shortfall := category.Shortfall(); shortfall.IsPositive() {
+					status += " " + shortfall.String() + " " + category.Category
+				}
+			}
+			status += " credits"
+		}
+		log.Printf("Applicator license %s of %s %s expires %s (in %d days), %s",
+			standing.License.Number,
+			standing.FirstName,
+			standing.LastName,
+			standing.License.ExpiresOn.Format("2006-01-02"),
+			standing.DaysUntilExpiry,
+			status,
+		)
+	}
+	return standings, nil
+}
+
+// RunDailyCheck runs CheckExpiring now and then every 24 hours until ctx is done,
+// with the alert window and requirements read from the environment on every run
+func (r *CertificationsRepository) RunDailyCheck(ctx context.Context) {
+	ticker := time.NewTicker(24 * time.Hour)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.CheckExpiring(ctx, AlertDaysFromEnv(), RequirementsFromEnv()); err != nil {
+			log.Printf("Failed to check expiring applicator licenses: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package certifications
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/users"
+	"github.com/shopspring/decimal"
+)
+
+// ErrInvalidCredit is wrapped by the errors CreditInput.Validate returns
+var ErrInvalidCredit = errors.New("invalid credit entry")
+
+// Credit is a continuing-education course an applicator completed
+type Credit struct {
+	ID          int
+	UserID      string
+	Course      string
+	CompletedOn time.Time
+	Credits     decimal.Decimal
+	Category    string
+	CreatedAt   time.Time
+}
+
+// CreditInput contains the fields of a new credit entry
+type CreditInput struct {
+	Course      string
+	CompletedOn time.Time
+	Credits     decimal.Decimal
+	Category    string
+}
+
+// Validate returns an error wrapping ErrInvalidCredit if a field is missing or out of range.
+// Courses cannot be completed after today.
+func (c CreditInput) Validate(today time.Time) error {
+	switch {
+	case strings.TrimSpace(c.Course) == "":
+		return fmt.Errorf("%w: course is required", ErrInvalidCredit)
+	case strings.TrimSpace(c.Category) == "":
+		return fmt.Errorf("%w: category is required", ErrInvalidCredit)
+	case !c.Credits.IsPositive():
+		return fmt.Errorf("%w: credits must be positive", ErrInvalidCredit)
+	case c.CompletedOn.IsZero():
+		return fmt.Errorf("%w: completed_on is required", ErrInvalidCredit)
+	case c.CompletedOn.After(today):
+		return fmt.Errorf("%w: completed_on cannot be in the future", ErrInvalidCredit)
+	}
+	return nil
+}
+
+// Requirements are the credits an applicator must earn to renew a license: for each credit
+// category, the credits completed in the CycleYears before the license expires
+type Requirements struct {
+	CycleYears int
+	Credits    map[string]decimal.Decimal
+}
+
+// ParseCredits parses required credits written as comma-separated category=credits pairs,
+// e.g. "core=8,3B=16". Categories are matched case-insensitively.
+func ParseCredits(s string) (map[string]decimal.Decimal, error) {
+	credits := map[string]decimal.Decimal{}
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		category, amount, ok := strings.Cut(pair, "=")
+		category = strings.ToLower(strings.TrimSpace(category))
+		if !ok || category == "" {
+			return nil, fmt.Errorf("invalid credit requirement %q", pair)
+		}
+		required, err := decimal.NewFromString(strings.TrimSpace(amount))
+		if err != nil || required.IsNegative() {
+			return nil, fmt.Errorf("invalid credits in requirement %q", pair)
+		}
+		credits[category] = required
+	}
+	return credits, nil
+}
+
+// CategoryStanding compares the credits earned in one category with the credits required
+type CategoryStanding struct {
+	Category string
+	Required decimal.Decimal
+	Earned   decimal.Decimal
+}
+
+// Shortfall returns the credits still needed, or zero once the requirement is met
+func (c CategoryStanding) Shortfall() decimal.Decimal {
+	if c.Earned.GreaterThanOrEqual(c.Required) {
+		return decimal.Zero
+	}
+	return c.Required.Sub(c.Earned)
+}
+
+// Standing is an applicator's progress toward renewing their license
+type Standing struct {
+	UserID    string
+	FirstName string
+	LastName  string
+	// License is nil for users without a license on file
+	License *users.License
+	// DaysUntilExpiry is negative once the license has expired, and zero without a license
+	DaysUntilExpiry int
+	// CycleStart and CycleEnd bound the completion dates of the credits that count:
+	// the renewal cycle ending on the license's expiry date, or today without a license
+	CycleStart time.Time
+	CycleEnd   time.Time
+	// Categories lists every required category, sorted by name
+	Categories []CategoryStanding
+	// RequirementsMet is set when no category falls short
+	RequirementsMet bool
+}
+
+// dateOf returns the calendar day of t as midnight UTC, the form DATE columns are scanned in
+func dateOf(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// computeStanding totals an applicator's credits against reqs as of today.
+// Credits completed outside the renewal cycle are ignored.
+func computeStanding(standing Standing, credits []Credit, reqs Requirements, today time.Time) Standing {
+	today = dateOf(today)
+	standing.CycleEnd = today
+	if standing.License != nil {
+		standing.CycleEnd = dateOf(standing.License.ExpiresOn)
+		standing.DaysUntilExpiry = int(standing.CycleEnd.Sub(today).Hours() / 24)
+	}
+	standing.CycleStart = standing.CycleEnd.AddDate(-reqs.CycleYears, 0, 0)
+
+	earned := map[string]decimal.Decimal{}
+	for _, credit := range credits {
+		completedOn := dateOf(credit.CompletedOn)
+		if !completedOn.After(standing.CycleStart) || completedOn.After(standing.CycleEnd) {
+			continue
+		}
+		category := strings.ToLower(credit.Category)
+		earned[category] = earned[category].Add(credit.Credits)
+	}
+
+	standing.Categories = make([]CategoryStanding, 0, len(reqs.Credits))
+	standing.RequirementsMet = true
+	for category, required := range reqs.Credits {
+		categoryStanding := CategoryStanding{Category: category, Required: required, Earned: earned[strings.ToLower(category)]}
+		if categoryStanding.Shortfall().IsPositive() {
+			standing.RequirementsMet = false
+		}
+		standing.Categories = append(standing.Categories, categoryStanding)
+	}
+	sort.Slice(standing.Categories, func(i, j int) bool {
+		return standing.Categories[i].Category < standing.Categories[j].Category
+	})
+	return standing
+}
@@ -4,6 +4,7 @@ package chemicals
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 )
 
@@ -25,6 +26,8 @@ type Chemical struct {
 	EpaRegNo     string
 	Recipe       string
 	Unit         string
+	// LabelRate is nil for chemicals without structured label rates, whose applications are not checked
+	LabelRate *LabelRate
 }
 
 type ChemicalInput struct {
@@ -34,6 +37,7 @@ type ChemicalInput struct {
 	EpaRegNo     string
 	Recipe       string
 	Unit         string
+	LabelRate    *LabelRate
 }
 
 // CreateChemical creates a new chemical record.
@@ -49,6 +53,8 @@ func (r *ChemicalsRepository) CreateChemical(
 	}
 	defer tx.Rollback()
 
+	labelMin, labelMax, labelUnit, labelBasis := labelRateArgs(chemicalInput.LabelRate)
+
 	var formID string
 	err = tx.QueryRowContext(ctx, `
 		INSERT INTO chemicals (
@@ -57,9 +63,13 @@ func (r *ChemicalsRepository) CreateChemical(
 			chemical_name,
 			epa_reg_no,
 			recipe,
-			unit
+			unit,
+			label_rate_min,
+			label_rate_max,
+			label_rate_unit,
+			label_rate_basis
 		)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 		RETURNING id
 	`,
 		chemicalInput.Category,
@@ -68,6 +78,10 @@ func (r *ChemicalsRepository) CreateChemical(
 		chemicalInput.EpaRegNo,
 		chemicalInput.Recipe,
 		chemicalInput.Unit,
+		labelMin,
+		labelMax,
+		labelUnit,
+		labelBasis,
 	).Scan(
 		&formID,
 	)
@@ -95,9 +109,13 @@ func (r *ChemicalsRepository) ListChemicalsByCategory(
 			c.chemical_name,
 			c.epa_reg_no,
 			c.recipe,
-			c.unit
+			c.unit,
+			c.label_rate_min,
+			c.label_rate_max,
+			c.label_rate_unit,
+			c.label_rate_basis
 		FROM chemicals c
-		WHERE c.category = $1
+		WHERE c.category = $1 AND c.deleted_at IS NULL
 	`
 
 	rows, err := r.db.QueryContext(ctx, query, category)
@@ -108,7 +126,10 @@ func (r *ChemicalsRepository) ListChemicalsByCategory(
 
 	var chemicals []Chemical
 	for rows.Next() {
-		var chemical Chemical
+		var (
+			chemical  Chemical
+			labelRate labelRateRow
+		)
 		err := rows.Scan(
 			&chemical.ID,
 			&chemical.Category,
@@ -117,10 +138,15 @@ func (r *ChemicalsRepository) ListChemicalsByCategory(
 			&chemical.EpaRegNo,
 			&chemical.Recipe,
 			&chemical.Unit,
+			&labelRate.Min,
+			&labelRate.Max,
+			&labelRate.Unit,
+			&labelRate.Basis,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("error scanning rows: %w", err)
 		}
+		chemical.LabelRate = labelRate.toDomain()
 
 		chemicals = append(chemicals, chemical)
 	}
@@ -133,8 +159,9 @@ func (r *ChemicalsRepository) ListChemicalsByCategory(
 }
 
 // UpdateChemicalById updates a chemical by ID.
+// Changing any field records when the chemical was edited.
 // Returns the updated chemical upon success.
-// Returns sql.ErrNoRows if the chemical does not exist.
+// Returns sql.ErrNoRows if the chemical does not exist or was deleted.
 func (r *ChemicalsRepository) UpdateChemicalById(
 	ctx context.Context,
 	ID int,
@@ -146,7 +173,11 @@ func (r *ChemicalsRepository) UpdateChemicalById(
 	}
 	defer tx.Rollback()
 
-	var chemical Chemical
+	var (
+		chemical  Chemical
+		labelRate labelRateRow
+	)
+	labelMin, labelMax, labelUnit, labelBasis := labelRateArgs(chemicalInput.LabelRate)
 
 	err = tx.QueryRowContext(ctx, `
 		UPDATE chemicals
@@ -155,8 +186,19 @@ func (r *ChemicalsRepository) UpdateChemicalById(
 			chemical_name = $3,
 			epa_reg_no = $4,
 			recipe = $5,
-			unit = $6
-		WHERE id = $7
+			unit = $6,
+			label_rate_min = $7,
+			label_rate_max = $8,
+			label_rate_unit = $9,
+			label_rate_basis = $10,
+			edited_at = CASE
+				WHEN (category, brand_name, chemical_name, epa_reg_no, recipe, unit,
+					label_rate_min, label_rate_max, label_rate_unit, label_rate_basis)
+					IS DISTINCT FROM ($1, $2, $3, $4, $5, $6, $7::numeric, $8::numeric, $9::text, $10::text)
+				THEN NOW()
+				ELSE edited_at
+			END
+		WHERE id = $11 AND deleted_at IS NULL
 		RETURNING
 			id,
 			category,
@@ -164,7 +206,11 @@ func (r *ChemicalsRepository) UpdateChemicalById(
 			chemical_name,
 			epa_reg_no,
 			recipe,
-			unit
+			unit,
+			label_rate_min,
+			label_rate_max,
+			label_rate_unit,
+			label_rate_basis
 	`,
 		chemicalInput.Category,
 		chemicalInput.BrandName,
@@ -172,6 +218,10 @@ func (r *ChemicalsRepository) UpdateChemicalById(
 		chemicalInput.EpaRegNo,
 		chemicalInput.Recipe,
 		chemicalInput.Unit,
+		labelMin,
+		labelMax,
+		labelUnit,
+		labelBasis,
 		ID,
 	).Scan(
 		&chemical.ID,
@@ -181,10 +231,15 @@ func (r *ChemicalsRepository) UpdateChemicalById(
 		&chemical.EpaRegNo,
 		&chemical.Recipe,
 		&chemical.Unit,
+		&labelRate.Min,
+		&labelRate.Max,
+		&labelRate.Unit,
+		&labelRate.Basis,
 	)
 	if err != nil {
 		return chemical, err
 	}
+	chemical.LabelRate = labelRate.toDomain()
 
 	if err := tx.Commit(); err != nil {
 		return Chemical{}, fmt.Errorf("error committing transaction: %w", err)
@@ -194,21 +249,41 @@ func (r *ChemicalsRepository) UpdateChemicalById(
 }
 
 // DeleteChemicalById deletes a chemical by ID.
-// Returns sql.ErrNoRows if the chemical does not exist.
+// A chemical still used by pesticide applications is retired instead, so the applications
+// and their history survive; it no longer appears in listings and cannot be updated.
+// Returns sql.ErrNoRows if the chemical does not exist or was already deleted.
 func (r *ChemicalsRepository) DeleteChemicalById(
 	ctx context.Context,
 	ID int,
 ) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
 
-	err := r.db.QueryRowContext(ctx, `
-		DELETE FROM chemicals
-		WHERE id = $1
-		RETURNING id
+	err = tx.QueryRowContext(ctx, `
+		UPDATE chemicals c
+		SET deleted_at = NOW()
+		WHERE c.id = $1
+		  AND c.deleted_at IS NULL
+		  AND EXISTS (SELECT 1 FROM pesticide_applications pa WHERE pa.chem_used = c.id)
+		RETURNING c.id
 	`, ID).Scan(&ID)
-
+	if errors.Is(err, sql.ErrNoRows) {
+		err = tx.QueryRowContext(ctx, `
+			DELETE FROM chemicals
+			WHERE id = $1 AND deleted_at IS NULL
+			RETURNING id
+		`, ID).Scan(&ID)
+	}
 	if err != nil {
 		return err
 	}
 
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
 	return nil
 }
@@ -8,6 +8,7 @@ import (
 
 	"github.com/BiryaniJedi/LandscapeForm-backend/internal/db"
 	"github.com/joho/godotenv"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/require"
 )
 
@@ -395,3 +396,145 @@ func TestCreateChemical_TransactionRollback(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, 1, count)
 }
+
+func TestDeleteChemicalById_InUseIsRetired(t *testing.T) {
+	ctx := context.Background()
+	database := db.TestDB(t)
+	repo := NewChemicalsRepository(database)
+
+	input := ChemicalInput{
+		Category:     "lawn",
+		BrandName:    "In Use",
+		ChemicalName: "Test Chemical",
+		EpaRegNo:     "999-999",
+		Recipe:       "Test recipe",
+		Unit:         "oz",
+	}
+	chemicalID, err := repo.CreateChemical(ctx, input)
+	require.NoError(t, err)
+
+	var id int
+	err = database.QueryRow(`SELECT id FROM chemicals WHERE id = $1`, chemicalID).Scan(&id)
+	require.NoError(t, err)
+
+	// Record an application of the chemical
+	_, err = database.Exec(`
+		WITH u AS (
+			INSERT INTO users (first_name, last_name, username, password_hash)
+			VALUES ('Test', 'User', 'TestUser_' || gen_random_uuid()::text, 'TestPass')
+			RETURNING id
+		), f AS (
+			INSERT INTO forms (created_by, form_type, first_name, last_name, street_number, street_name, town, zip_code, home_phone, other_phone)
+			SELECT id, 'lawn', 'A', 'B', '1', 'Main St', 'Town', '10001', '', '' FROM u
+			RETURNING id
+		)
+		INSERT INTO pesticide_applications (form_id, chem_used, app_timestamp, rate, amount_applied, location_code)
+		SELECT id, $1, NOW(), '1 oz', 1, '1A' FROM f
+	`, id)
+	require.NoError(t, err)
+
+	// Editing records when the chemical changed, saving the same values does not
+	_, err = repo.UpdateChemicalById(ctx, id, input)
+	require.NoError(t, err)
+	var edited bool
+	err = database.QueryRow(`SELECT edited_at IS NOT NULL FROM chemicals WHERE id = $1`, id).Scan(&edited)
+	require.NoError(t, err)
+	require.False(t, edited)
+
+	input.EpaRegNo = "999-998"
+	_, err = repo.UpdateChemicalById(ctx, id, input)
+	require.NoError(t, err)
+	err = database.QueryRow(`SELECT edited_at IS NOT NULL FROM chemicals WHERE id = $1`, id).Scan(&edited)
+	require.NoError(t, err)
+	require.True(t, edited)
+
+	err = repo.DeleteChemicalById(ctx, id)
+	require.NoError(t, err)
+
+	// The chemical and its application are kept, but it is gone from listings
+	var applications int
+	err = database.QueryRow(`SELECT COUNT(*) FROM pesticide_applications WHERE chem_used = $1`, id).Scan(&applications)
+	require.NoError(t, err)
+	require.Equal(t, 1, applications)
+
+	chemicals, err := repo.ListChemicalsByCategory(ctx, "lawn")
+	require.NoError(t, err)
+	require.Empty(t, chemicals)
+
+	_, err = repo.UpdateChemicalById(ctx, id, input)
+	require.Equal(t, sql.ErrNoRows, err)
+
+	err = repo.DeleteChemicalById(ctx, id)
+	require.Equal(t, sql.ErrNoRows, err)
+}
+
+func TestChemicalLabelRate(t *testing.T) {
+	ctx := context.Background()
+	database := db.TestDB(t)
+	repo := NewChemicalsRepository(database)
+
+	labelRate := &LabelRate{
+		Min:   decimal.RequireFromString("1.5"),
+		Max:   decimal.RequireFromString("3"),
+		Unit:  "oz",
+		Basis: RateBasisPer1000SqFt,
+	}
+	input := ChemicalInput{
+		Category:     "lawn",
+		BrandName:    "Label Brand",
+		ChemicalName: "Label Chemical",
+		EpaRegNo:     "333-333",
+		Recipe:       "1.5-3 oz per 1,000 sq ft",
+		Unit:         "oz",
+		LabelRate:    labelRate,
+	}
+	chemicalID, err := repo.CreateChemical(ctx, input)
+	require.NoError(t, err)
+
+	list, err := repo.ListChemicalsByCategory(ctx, "lawn")
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.NotNil(t, list[0].LabelRate)
+	require.True(t, labelRate.Min.Equal(list[0].LabelRate.Min))
+	require.True(t, labelRate.Max.Equal(list[0].LabelRate.Max))
+	require.Equal(t, "oz", list[0].LabelRate.Unit)
+	require.Equal(t, RateBasisPer1000SqFt, list[0].LabelRate.Basis)
+
+	// Removing the label rate counts as an edit
+	input.LabelRate = nil
+	updated, err := repo.UpdateChemicalById(ctx, list[0].ID, input)
+	require.NoError(t, err)
+	require.Nil(t, updated.LabelRate)
+
+	var edited bool
+	err = database.QueryRow(`SELECT edited_at IS NOT NULL FROM chemicals WHERE id = $1`, chemicalID).Scan(&edited)
+	require.NoError(t, err)
+	require.True(t, edited)
+}
+
+func TestLabelRateValidate(t *testing.T) {
+	valid := LabelRate{
+		Min:   decimal.RequireFromString("1"),
+		Max:   decimal.RequireFromString("2"),
+		Unit:  "oz",
+		Basis: RateBasisPerGallon,
+	}
+	require.NoError(t, valid.Validate())
+
+	missingUnit := valid
+	missingUnit.Unit = ""
+	require.Error(t, missingUnit.Validate())
+
+	badBasis := valid
+	badBasis.Basis = "per_acre"
+	require.Error(t, badBasis.Validate())
+
+	inverted := valid
+	inverted.Min = decimal.RequireFromString("3")
+	require.Error(t, inverted.Validate())
+
+	zeroMax := valid
+	zeroMax.Min = decimal.Zero
+	zeroMax.Max = decimal.Zero
+	require.Error(t, zeroMax.Validate())
+}
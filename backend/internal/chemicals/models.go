@@ -1 +1,74 @@
 package chemicals
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// Rate bases: what the amount of a label or application rate is measured against
+const (
+	RateBasisPer1000SqFt = "per_1000_sq_ft"
+	RateBasisPerGallon   = "per_gallon"
+)
+
+// ValidRateBasis reports whether basis is one of the rate bases
+func ValidRateBasis(basis string) bool {
+	return basis == RateBasisPer1000SqFt || basis == RateBasisPerGallon
+}
+
+// LabelRate is the range of application rates allowed by a chemical's label,
+// e.g. 1.5 to 3 oz per 1,000 sq ft
+type LabelRate struct {
+	Min   decimal.Decimal
+	Max   decimal.Decimal
+	Unit  string
+	Basis string
+}
+
+// Validate returns an error describing the first problem with the label rate
+func (l LabelRate) Validate() error {
+	switch {
+	case l.Unit == "":
+		return errors.New("label_rate.unit is required")
+	case !ValidRateBasis(l.Basis):
+		return fmt.Errorf("label_rate.basis must be '%s' or '%s'", RateBasisPer1000SqFt, RateBasisPerGallon)
+	case l.Min.IsNegative():
+		return errors.New("label_rate.min cannot be negative")
+	case !l.Max.IsPositive():
+		return errors.New("label_rate.max must be positive")
+	case l.Min.GreaterThan(l.Max):
+		return errors.New("label_rate.min cannot exceed label_rate.max")
+	}
+	return nil
+}
+
+// labelRateRow holds the nullable label rate columns of a chemical
+type labelRateRow struct {
+	Min   decimal.NullDecimal
+	Max   decimal.NullDecimal
+	Unit  sql.NullString
+	Basis sql.NullString
+}
+
+func (r labelRateRow) toDomain() *LabelRate {
+	if !r.Max.Valid {
+		return nil
+	}
+	return &LabelRate{
+		Min:   r.Min.Decimal,
+		Max:   r.Max.Decimal,
+		Unit:  r.Unit.String,
+		Basis: r.Basis.String,
+	}
+}
+
+// labelRateArgs returns the label rate column values, all NULL for a chemical without one
+func labelRateArgs(l *LabelRate) (min, max, unit, basis any) {
+	if l == nil {
+		return nil, nil, nil, nil
+	}
+	return l.Min, l.Max, l.Unit, l.Basis
+}
@@ -0,0 +1,338 @@
+// Package customers provides data access and domain models for customers and their properties.
+// Forms keep their own copy of the client fields; the repository keeps the copies on
+// linked forms in sync whenever a customer or property changes.
+package customers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/forms"
+	"github.com/lib/pq"
+)
+
+// ErrAddressExists is returned when a property's address is already on file
+var ErrAddressExists = errors.New("a property with this address already exists")
+
+// CustomersRepository provides database access for customer and property records.
+// Methods return sql.ErrNoRows when a customer or property does not exist.
+type CustomersRepository struct {
+	db *sql.DB
+}
+
+// NewCustomersRepository returns a repository backed by the given database connection.
+func NewCustomersRepository(database *sql.DB) *CustomersRepository {
+	return &CustomersRepository{db: database}
+}
+
+// isUniqueViolation reports whether err is a postgres unique constraint violation
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+// CreateCustomer creates a new customer along with the given properties.
+// Returns the created customer upon success.
+// It returns ErrAddressExists if one of the addresses is already on file.
+// The operation is atomic.
+func (r *CustomersRepository) CreateCustomer(
+	ctx context.Context,
+	customerInput CustomerInput,
+	propertyInputs []PropertyInput,
+) (Customer, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return Customer{}, err
+	}
+	defer tx.Rollback()
+
+	var customer Customer
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO customers (
+			first_name,
+			last_name,
+			home_phone,
+			other_phone
+		)
+		VALUES ($1, $2, $3, $4)
+		RETURNING
+			id,
+			created_at,
+			updated_at,
+			first_name,
+			last_name,
+			home_phone,
+			other_phone
+	`,
+		customerInput.FirstName,
+		customerInput.LastName,
+		customerInput.HomePhone,
+		customerInput.OtherPhone,
+	).Scan(
+		&customer.ID,
+		&customer.CreatedAt,
+		&customer.UpdatedAt,
+		&customer.FirstName,
+		&customer.LastName,
+		&customer.HomePhone,
+		&customer.OtherPhone,
+	)
+	if err != nil {
+		return Customer{}, fmt.Errorf("failed to insert customer %s %s: %w", customerInput.FirstName, customerInput.LastName, err)
+	}
+
+	for _, propertyInput := range propertyInputs {
+		property, err := insertProperty(ctx, tx, customer.ID, propertyInput)
+		if err != nil {
+			return Customer{}, err
+		}
+		customer.Properties = append(customer.Properties, property)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Customer{}, fmt.Errorf("failed to commit transaction for inserting customer %s %s: %w", customerInput.FirstName, customerInput.LastName, err)
+	}
+
+	return customer, nil
+}
+
+// GetCustomerById returns a customer and their properties.
+// It returns sql.ErrNoRows if the customer does not exist.
+func (r *CustomersRepository) GetCustomerById(
+	ctx context.Context,
+	customerID string,
+) (Customer, error) {
+	var customer Customer
+	err := r.db.QueryRowContext(ctx, `
+		SELECT
+			id,
+			created_at,
+			updated_at,
+			first_name,
+			last_name,
+			home_phone,
+			other_phone
+		FROM customers
+		WHERE id = $1
+	`, customerID).Scan(
+		&customer.ID,
+		&customer.CreatedAt,
+		&customer.UpdatedAt,
+		&customer.FirstName,
+		&customer.LastName,
+		&customer.HomePhone,
+		&customer.OtherPhone,
+	)
+	if err != nil {
+		//sql.ErrNoRows
+		return Customer{}, err
+	}
+
+	properties, err := r.listPropertiesByCustomerIds(ctx, []string{customer.ID})
+	if err != nil {
+		return Customer{}, err
+	}
+	customer.Properties = properties[customer.ID]
+
+	return customer, nil
+}
+
+// ListCustomers returns customers sorted by name with pagination and an optional search.
+// Each returned customer includes their properties.
+func (r *CustomersRepository) ListCustomers(
+	ctx context.Context,
+	opts ListCustomersOptions,
+) ([]Customer, error) {
+	whereClause := ""
+	args := []any{}
+	argIndex := 1
+
+	if search := strings.TrimSpace(opts.Search); search != "" {
+		whereClause = fmt.Sprintf(`
+			WHERE c.first_name ILIKE $%[1]d
+			   OR c.last_name ILIKE $%[1]d
+			   OR c.home_phone ILIKE $%[1]d
+			   OR c.other_phone ILIKE $%[1]d
+			   OR EXISTS (
+			       SELECT 1
+			       FROM properties p
+			       WHERE p.customer_id = c.id
+			         AND (p.street_number || ' ' || p.street_name || ' ' || p.town || ' ' || p.zip_code) ILIKE $%[1]d
+			   )
+		`, argIndex)
+		args = append(args, "%"+search+"%")
+		argIndex++
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			c.id,
+			c.created_at,
+			c.updated_at,
+			c.first_name,
+			c.last_name,
+			c.home_phone,
+			c.other_phone
+		FROM customers c
+		%s
+		ORDER BY LOWER(c.last_name), LOWER(c.first_name), c.id
+	`, whereClause)
+
+	if opts.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT $%d", argIndex)
+		args = append(args, opts.Limit)
+		argIndex++
+	}
+	if opts.Offset > 0 {
+		query += fmt.Sprintf(" OFFSET $%d", argIndex)
+		args = append(args, opts.Offset)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying rows for customers list: %w", err)
+	}
+	defer rows.Close()
+
+	var customers []Customer
+	for rows.Next() {
+		var customer Customer
+		err := rows.Scan(
+			&customer.ID,
+			&customer.CreatedAt,
+			&customer.UpdatedAt,
+			&customer.FirstName,
+			&customer.LastName,
+			&customer.HomePhone,
+			&customer.OtherPhone,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning rows: %w", err)
+		}
+		customers = append(customers, customer)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after queries for customers list: %w", err)
+	}
+
+	if len(customers) == 0 {
+		return customers, nil
+	}
+
+	customerIDs := make([]string, 0, len(customers))
+	for _, customer := range customers {
+		customerIDs = append(customerIDs, customer.ID)
+	}
+	properties, err := r.listPropertiesByCustomerIds(ctx, customerIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range customers {
+		customers[i].Properties = properties[customers[i].ID]
+	}
+
+	return customers, nil
+}
+
+// UpdateCustomerById updates a customer's name and phones, and copies them onto
+// every live form at the customer's properties, recording a revision by the given user on each
+// form it changes. Forms in the trash keep the values they had when deleted.
+// Returns the updated customer upon success.
+// It returns sql.ErrNoRows if the customer does not exist.
+func (r *CustomersRepository) UpdateCustomerById(
+	ctx context.Context,
+	customerID string,
+	userID string,
+	customerInput CustomerInput,
+) (Customer, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return Customer{}, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var customer Customer
+	err = tx.QueryRowContext(ctx, `
+		UPDATE customers
+		SET first_name = $1,
+			last_name = $2,
+			home_phone = $3,
+			other_phone = $4
+		WHERE id = $5
+		RETURNING
+			id,
+			created_at,
+			updated_at,
+			first_name,
+			last_name,
+			home_phone,
+			other_phone
+	`,
+		customerInput.FirstName,
+		customerInput.LastName,
+		customerInput.HomePhone,
+		customerInput.OtherPhone,
+		customerID,
+	).Scan(
+		&customer.ID,
+		&customer.CreatedAt,
+		&customer.UpdatedAt,
+		&customer.FirstName,
+		&customer.LastName,
+		&customer.HomePhone,
+		&customer.OtherPhone,
+	)
+	if err != nil {
+		//sql.ErrNoRows
+		return Customer{}, err
+	}
+
+	var propertyIDs []string
+	err = tx.QueryRowContext(ctx, `
+		SELECT COALESCE(array_agg(id::text), '{}')
+		FROM properties
+		WHERE customer_id = $1
+	`, customer.ID).Scan(pq.Array(&propertyIDs))
+	if err != nil {
+		return Customer{}, fmt.Errorf("error fetching properties for customer %s: %w", customer.ID, err)
+	}
+	if _, err := forms.SyncPropertyForms(ctx, tx, userID, propertyIDs); err != nil {
+		return Customer{}, fmt.Errorf("error updating forms for customer %s: %w", customer.ID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Customer{}, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	properties, err := r.listPropertiesByCustomerIds(ctx, []string{customer.ID})
+	if err != nil {
+		return Customer{}, err
+	}
+	customer.Properties = properties[customer.ID]
+
+	return customer, nil
+}
+
+// DeleteCustomerById deletes a customer and their properties.
+// Forms at those properties are kept and unlinked.
+// It returns sql.ErrNoRows if the customer does not exist.
+func (r *CustomersRepository) DeleteCustomerById(
+	ctx context.Context,
+	customerID string,
+) error {
+	err := r.db.QueryRowContext(ctx, `
+		DELETE FROM customers
+		WHERE id = $1
+		RETURNING id
+	`, customerID).Scan(&customerID)
+	if err != nil {
+		// sql.ErrNoRows → not found
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,208 @@
+package customers
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/db"
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/forms"
+	"github.com/joho/godotenv"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	// Load test-specific environment variables
+	_ = godotenv.Load("../../.env.testing")
+
+	os.Exit(m.Run())
+}
+
+func createTestUser(t testing.TB, db *sql.DB) string {
+	t.Helper()
+
+	var id string
+	err := db.QueryRow(`
+		INSERT INTO users (first_name, last_name, username, password_hash)
+		VALUES ('Test', 'User', 'TestUser_' || gen_random_uuid()::text, 'TestPass')
+		RETURNING id
+	`).Scan(&id)
+
+	require.NoError(t, err)
+	return id
+}
+
+func TestCreateAndGetCustomer(t *testing.T) {
+	ctx := context.Background()
+	database := db.TestDB(t)
+	repo := NewCustomersRepository(database)
+
+	created, err := repo.CreateCustomer(ctx, CustomerInput{
+		FirstName:  "Alice",
+		LastName:   "Gardener",
+		HomePhone:  "555-1111",
+		OtherPhone: "555-2222",
+	}, []PropertyInput{
+		{StreetNumber: "123", StreetName: "Main St", Town: "Springfield", ZipCode: "12345"},
+		{StreetNumber: "9", StreetName: "Lake Rd", Town: "Shelbyville", ZipCode: "12346"},
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, created.ID)
+	require.Len(t, created.Properties, 2)
+
+	customer, err := repo.GetCustomerById(ctx, created.ID)
+	require.NoError(t, err)
+	require.Equal(t, "Alice", customer.FirstName)
+	require.Len(t, customer.Properties, 2)
+	require.Equal(t, "Main St", customer.Properties[0].StreetName)
+
+	// The same address cannot be added twice, whatever the case
+	_, err = repo.CreateProperty(ctx, created.ID, PropertyInput{
+		StreetNumber: "123", StreetName: "MAIN ST", Town: "Springfield", ZipCode: "12345",
+	})
+	require.ErrorIs(t, err, ErrAddressExists)
+	// Or with stray whitespace
+	_, err = repo.CreateProperty(ctx, created.ID, PropertyInput{
+		StreetNumber: " 123", StreetName: "Main St ", Town: "Springfield", ZipCode: "12345 ",
+	})
+	require.ErrorIs(t, err, ErrAddressExists)
+
+	customers, err := repo.ListCustomers(ctx, ListCustomersOptions{Search: "lake rd"})
+	require.NoError(t, err)
+	require.Len(t, customers, 1)
+	require.Equal(t, created.ID, customers[0].ID)
+	require.Len(t, customers[0].Properties, 2)
+}
+
+func TestUpdateCustomerAndProperty_SyncsForms(t *testing.T) {
+	ctx := context.Background()
+	database := db.TestDB(t)
+	repo := NewCustomersRepository(database)
+	formsRepo := forms.NewFormsRepository(database)
+
+	userID := createTestUser(t, database)
+
+	customer, err := repo.CreateCustomer(ctx, CustomerInput{
+		FirstName:  "Bob",
+		LastName:   "Builder",
+		HomePhone:  "555-3333",
+		OtherPhone: "555-4444",
+	}, []PropertyInput{
+		{StreetNumber: "7", StreetName: "Oak Ave", Town: "Springfield", ZipCode: "12345"},
+	})
+	require.NoError(t, err)
+	propertyID := customer.Properties[0].ID
+
+	// Client fields on the form come from the property, not the input
+	formID, err := formsRepo.CreateShrubForm(ctx, forms.CreateShrubFormInput{
+		CreatedBy:  userID,
+		PropertyID: propertyID,
+		FirstName:  "Ignored",
+	})
+	require.NoError(t, err)
+
+	form, err := formsRepo.GetShrubFormById(ctx, formID, userID)
+	require.NoError(t, err)
+	require.Equal(t, propertyID, form.PropertyID)
+	require.Equal(t, "Bob", form.FirstName)
+	require.Equal(t, "Oak Ave", form.StreetName)
+
+	trashedID, err := formsRepo.CreateShrubForm(ctx, forms.CreateShrubFormInput{
+		CreatedBy:  userID,
+		PropertyID: propertyID,
+		Force:      true,
+	})
+	require.NoError(t, err)
+	require.NoError(t, formsRepo.DeleteFormById(ctx, trashedID, userID))
+
+	_, err = repo.UpdateCustomerById(ctx, customer.ID, userID, CustomerInput{
+		FirstName:  "Robert",
+		LastName:   "Builder",
+		HomePhone:  "555-9999",
+		OtherPhone: "555-4444",
+	})
+	require.NoError(t, err)
+
+	_, err = repo.UpdatePropertyById(ctx, customer.ID, propertyID, userID, PropertyInput{
+		StreetNumber: "7A", StreetName: "Oak Ave", Town: "Springfield", ZipCode: "12345",
+	})
+	require.NoError(t, err)
+
+	form, err = formsRepo.GetShrubFormById(ctx, formID, userID)
+	require.NoError(t, err)
+	require.Equal(t, "Robert", form.FirstName)
+	require.Equal(t, "555-9999", form.HomePhone)
+	require.Equal(t, "7A", form.StreetNumber)
+
+	// Each sync is recorded in the form's history
+	revisions, err := formsRepo.ListAllFormRevisions(ctx, formID)
+	require.NoError(t, err)
+	require.Len(t, revisions, 3)
+	require.Equal(t, forms.RevisionActionUpdate, revisions[1].Action)
+	require.Equal(t, userID, revisions[1].ChangedBy)
+	require.Contains(t, revisions[1].Diff.Fields, "first_name")
+	require.Contains(t, revisions[1].Diff.Fields, "home_phone")
+	require.Contains(t, revisions[2].Diff.Fields, "street_number")
+
+	// Forms in the trash keep the values they were deleted with
+	var trashedName string
+	err = database.QueryRow(`SELECT first_name FROM forms WHERE id = $1`, trashedID).Scan(&trashedName)
+	require.NoError(t, err)
+	require.Equal(t, "Bob", trashedName)
+
+	// Properties belong to a single customer
+	other, err := repo.CreateCustomer(ctx, CustomerInput{FirstName: "Other", LastName: "Person"}, nil)
+	require.NoError(t, err)
+	_, err = repo.UpdatePropertyById(ctx, other.ID, propertyID, userID, PropertyInput{
+		StreetNumber: "1", StreetName: "Elsewhere", Town: "Springfield", ZipCode: "12345",
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	_, err = formsRepo.CreateShrubForm(ctx, forms.CreateShrubFormInput{
+		CreatedBy:  userID,
+		PropertyID: "00000000-0000-0000-0000-000000000000",
+	})
+	require.ErrorIs(t, err, forms.ErrPropertyNotFound)
+}
+
+func TestDeleteCustomer_UnlinksForms(t *testing.T) {
+	ctx := context.Background()
+	database := db.TestDB(t)
+	repo := NewCustomersRepository(database)
+	formsRepo := forms.NewFormsRepository(database)
+
+	userID := createTestUser(t, database)
+
+	customer, err := repo.CreateCustomer(ctx, CustomerInput{
+		FirstName:  "Carol",
+		LastName:   "Gone",
+		HomePhone:  "555-5555",
+		OtherPhone: "555-6666",
+	}, []PropertyInput{
+		{StreetNumber: "1", StreetName: "Elm St", Town: "Springfield", ZipCode: "12345"},
+	})
+	require.NoError(t, err)
+
+	formID, err := formsRepo.CreateLawnForm(ctx, forms.CreateLawnFormInput{
+		CreatedBy:    userID,
+		PropertyID:   customer.Properties[0].ID,
+		LawnAreaSqFt: 1000,
+	})
+	require.NoError(t, err)
+
+	err = repo.DeleteCustomerById(ctx, customer.ID)
+	require.NoError(t, err)
+
+	_, err = repo.GetCustomerById(ctx, customer.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	err = repo.DeleteCustomerById(ctx, customer.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	// The form and its copy of the client fields survive
+	form, err := formsRepo.GetLawnFormById(ctx, formID, userID)
+	require.NoError(t, err)
+	require.Empty(t, form.PropertyID)
+	require.Equal(t, "Carol", form.FirstName)
+}
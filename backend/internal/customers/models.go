@@ -0,0 +1,127 @@
+package customers
+
+import (
+	"time"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/forms"
+	"github.com/shopspring/decimal"
+)
+
+type Customer struct {
+	ID         string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	FirstName  string
+	LastName   string
+	HomePhone  string
+	OtherPhone string
+
+	Properties []Property
+}
+
+type Property struct {
+	ID           string
+	CustomerID   string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	StreetNumber string
+	StreetName   string
+	Town         string
+	ZipCode      string
+}
+
+// CustomerInput contains the fields that may be set on a customer.
+type CustomerInput struct {
+	FirstName  string
+	LastName   string
+	HomePhone  string
+	OtherPhone string
+}
+
+// PropertyInput contains the fields that may be set on a property.
+type PropertyInput struct {
+	StreetNumber string
+	StreetName   string
+	Town         string
+	ZipCode      string
+}
+
+// ListCustomersOptions contains optional filtering and pagination parameters
+type ListCustomersOptions struct {
+	Limit  int
+	Offset int
+	// Search matches customer names, phones and property addresses
+	Search string
+}
+
+// Kinds of timeline entries
+const (
+	TimelineKindFormCreated = "form_created"
+	TimelineKindFormEdited  = "form_edited"
+	TimelineKindApplication = "application"
+	TimelineKindNote        = "note"
+)
+
+// TimelineEntry is a single event at an address: a form being created or edited,
+// a pesticide application or a note. Exactly one of Application, Edit and Note is
+// set for the matching kinds; form creations carry no details.
+type TimelineEntry struct {
+	Kind       string
+	OccurredAt time.Time
+	FormID     string
+	FormType   string
+	// UserID is the user behind the event; applications are credited to their applicator
+	UserID   string
+	UserName string
+
+	Application *TimelineApplication
+	Edit        *TimelineEdit
+	Note        *TimelineNote
+}
+
+// TimelineApplication is a pesticide application with its chemical resolved
+type TimelineApplication struct {
+	ID            int
+	ChemicalID    int
+	BrandName     string
+	ChemicalName  string
+	EpaRegNo      string
+	Unit          string
+	Rate          string
+	AmountApplied decimal.Decimal
+	LocationCode  string
+}
+
+// TimelineEdit is a recorded change to a form
+type TimelineEdit struct {
+	RevisionID int64
+	Action     string
+	Diff       forms.RevisionDiff
+}
+
+// TimelineNote is a note left on a form
+type TimelineNote struct {
+	ID      int
+	Message string
+}
+
+// TimelineOptions selects the address of a timeline and the page to return.
+// Either PropertyID or all of StreetNumber, StreetName and ZipCode must be set.
+type TimelineOptions struct {
+	PropertyID   string
+	StreetNumber string
+	StreetName   string
+	ZipCode      string
+
+	Limit  int
+	Offset int
+	// Order is "DESC" (newest first, the default) or "ASC"
+	Order string
+}
+
+// TimelinePage is one page of an address timeline
+type TimelinePage struct {
+	Entries []TimelineEntry
+	// Total is the number of entries at the address across all pages
+	Total int
+}
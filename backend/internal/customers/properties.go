@@ -0,0 +1,238 @@
+package customers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/forms"
+	"github.com/lib/pq"
+)
+
+// insertProperty adds a property to a customer within the given transaction.
+// It returns ErrAddressExists if the address is already on file
+// and sql.ErrNoRows if the customer does not exist.
+func insertProperty(
+	ctx context.Context,
+	tx *sql.Tx,
+	customerID string,
+	input PropertyInput,
+) (Property, error) {
+	var property Property
+	err := tx.QueryRowContext(ctx, `
+		INSERT INTO properties (
+			customer_id,
+			street_number,
+			street_name,
+			town,
+			zip_code
+		)
+		SELECT c.id, TRIM($2), TRIM($3), TRIM($4), TRIM($5)
+		FROM customers c
+		WHERE c.id = $1
+		RETURNING
+			id,
+			customer_id,
+			created_at,
+			updated_at,
+			street_number,
+			street_name,
+			town,
+			zip_code
+	`,
+		customerID,
+		input.StreetNumber,
+		input.StreetName,
+		input.Town,
+		input.ZipCode,
+	).Scan(
+		&property.ID,
+		&property.CustomerID,
+		&property.CreatedAt,
+		&property.UpdatedAt,
+		&property.StreetNumber,
+		&property.StreetName,
+		&property.Town,
+		&property.ZipCode,
+	)
+	if isUniqueViolation(err) {
+		return Property{}, ErrAddressExists
+	}
+	if err != nil {
+		//sql.ErrNoRows
+		return Property{}, err
+	}
+
+	return property, nil
+}
+
+// listPropertiesByCustomerIds returns the properties of every given customer in one query,
+// keyed by customer ID, oldest first.
+func (r *CustomersRepository) listPropertiesByCustomerIds(
+	ctx context.Context,
+	customerIDs []string,
+) (map[string][]Property, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT
+			id,
+			customer_id,
+			created_at,
+			updated_at,
+			street_number,
+			street_name,
+			town,
+			zip_code
+		FROM properties
+		WHERE customer_id = ANY($1::uuid[])
+		ORDER BY created_at, id
+	`, pq.Array(customerIDs))
+	if err != nil {
+		return nil, fmt.Errorf("error fetching properties for customers: %w", err)
+	}
+	defer rows.Close()
+
+	properties := make(map[string][]Property, len(customerIDs))
+	for rows.Next() {
+		var property Property
+		err := rows.Scan(
+			&property.ID,
+			&property.CustomerID,
+			&property.CreatedAt,
+			&property.UpdatedAt,
+			&property.StreetNumber,
+			&property.StreetName,
+			&property.Town,
+			&property.ZipCode,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning property: %w", err)
+		}
+		properties[property.CustomerID] = append(properties[property.CustomerID], property)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after properties query: %w", err)
+	}
+
+	return properties, nil
+}
+
+// CreateProperty adds a property to a customer.
+// Returns the created property upon success.
+// It returns ErrAddressExists if the address is already on file
+// and sql.ErrNoRows if the customer does not exist.
+func (r *CustomersRepository) CreateProperty(
+	ctx context.Context,
+	customerID string,
+	input PropertyInput,
+) (Property, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return Property{}, err
+	}
+	defer tx.Rollback()
+
+	property, err := insertProperty(ctx, tx, customerID, input)
+	if err != nil {
+		return Property{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Property{}, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return property, nil
+}
+
+// UpdatePropertyById updates a customer's property and copies the new address onto
+// every live form written at that property, recording a revision by the given user on each
+// form it changes. Forms in the trash keep the values they had when deleted.
+// Returns the updated property upon success.
+// It returns ErrAddressExists if the new address belongs to another property
+// and sql.ErrNoRows if the property does not exist or belongs to another customer.
+func (r *CustomersRepository) UpdatePropertyById(
+	ctx context.Context,
+	customerID string,
+	propertyID string,
+	userID string,
+	input PropertyInput,
+) (Property, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return Property{}, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var property Property
+	err = tx.QueryRowContext(ctx, `
+		UPDATE properties
+		SET street_number = TRIM($1),
+			street_name = TRIM($2),
+			town = TRIM($3),
+			zip_code = TRIM($4)
+		WHERE id = $5 AND customer_id = $6
+		RETURNING
+			id,
+			customer_id,
+			created_at,
+			updated_at,
+			street_number,
+			street_name,
+			town,
+			zip_code
+	`,
+		input.StreetNumber,
+		input.StreetName,
+		input.Town,
+		input.ZipCode,
+		propertyID,
+		customerID,
+	).Scan(
+		&property.ID,
+		&property.CustomerID,
+		&property.CreatedAt,
+		&property.UpdatedAt,
+		&property.StreetNumber,
+		&property.StreetName,
+		&property.Town,
+		&property.ZipCode,
+	)
+	if isUniqueViolation(err) {
+		return Property{}, ErrAddressExists
+	}
+	if err != nil {
+		//sql.ErrNoRows
+		return Property{}, err
+	}
+
+	if _, err := forms.SyncPropertyForms(ctx, tx, userID, []string{property.ID}); err != nil {
+		return Property{}, fmt.Errorf("error updating forms for property %s: %w", property.ID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Property{}, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return property, nil
+}
+
+// DeletePropertyById deletes a customer's property.
+// Forms written at the property are kept and unlinked.
+// It returns sql.ErrNoRows if the property does not exist or belongs to another customer.
+func (r *CustomersRepository) DeletePropertyById(
+	ctx context.Context,
+	customerID string,
+	propertyID string,
+) error {
+	err := r.db.QueryRowContext(ctx, `
+		DELETE FROM properties
+		WHERE id = $1 AND customer_id = $2
+		RETURNING id
+	`, propertyID, customerID).Scan(&propertyID)
+	if err != nil {
+		// sql.ErrNoRows → not found
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,288 @@
+package customers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrTimelineAddressRequired is returned when a timeline is requested without a property or full address
+var ErrTimelineAddressRequired = errors.New("property_id or street_number, street_name and zip_code are required")
+
+// timelineEntriesCTE gathers every event on the live forms matched by the address condition
+// into one stream. Kind-specific columns are NULL on the other kinds of entries.
+const timelineEntriesCTE = `
+	WITH matched_forms AS (
+		SELECT f.id, f.form_type, f.created_by, f.created_at
+		FROM forms f
+		WHERE f.deleted_at IS NULL AND (%s)
+	),
+	entries AS (
+		SELECT
+			'form_created' AS kind,
+			m.created_at AS occurred_at,
+			m.id AS form_id,
+			m.form_type,
+			m.created_by AS user_id,
+			0::bigint AS entry_id,
+			NULL::int AS chemical_id,
+			NULL::text AS brand_name,
+			NULL::text AS chemical_name,
+			NULL::text AS epa_reg_no,
+			NULL::text AS unit,
+			NULL::text AS rate,
+			NULL::numeric AS amount_applied,
+			NULL::text AS location_code,
+			NULL::text AS action,
+			NULL::jsonb AS diff,
+			NULL::text AS note
+		FROM matched_forms m
+
+		UNION ALL
+
+		SELECT
+			'form_edited',
+			fr.changed_at,
+			m.id,
+			m.form_type,
+			fr.changed_by,
+			fr.id,
+			NULL, NULL, NULL, NULL, NULL, NULL, NULL, NULL,
+			fr.action,
+			fr.diff,
+			NULL
+		FROM form_revisions fr
+		JOIN matched_forms m ON m.id = fr.form_id
+		WHERE fr.action IN ('update', 'rollback', 'merge')
+
+		UNION ALL
+
+		SELECT
+			'application',
+			pa.app_timestamp,
+			m.id,
+			m.form_type,
+			COALESCE(pa.applied_by, m.created_by),
+			pa.id,
+			c.id,
+			c.brand_name,
+			c.chemical_name,
+			c.epa_reg_no,
+			c.unit,
+			pa.rate,
+			pa.amount_applied,
+			pa.location_code,
+			NULL, NULL, NULL
+		FROM pesticide_applications pa
+		JOIN matched_forms m ON m.id = pa.form_id
+		JOIN chemicals c ON c.id = pa.chem_used
+
+		UNION ALL
+
+		SELECT
+			'note',
+			n.created_at,
+			m.id,
+			m.form_type,
+			n.created_by,
+			n.id,
+			NULL, NULL, NULL, NULL, NULL, NULL, NULL, NULL, NULL, NULL,
+			n.note
+		FROM notes n
+		JOIN matched_forms m ON m.id = n.form_id
+	)
+`
+
+// timelineAddressCondition returns the condition matching forms at the requested address.
+// Addresses are compared ignoring case and surrounding spaces. A property also matches
+// forms at its address that were never linked to it.
+func (r *CustomersRepository) timelineAddressCondition(
+	ctx context.Context,
+	opts TimelineOptions,
+) (string, []any, error) {
+	const addressCondition = `
+		LOWER(TRIM(f.street_number)) = LOWER(TRIM($1))
+		AND LOWER(TRIM(f.street_name)) = LOWER(TRIM($2))
+		AND f.zip_code = TRIM($3)
+	`
+
+	if opts.PropertyID != "" {
+		var streetNumber, streetName, zipCode string
+		err := r.db.QueryRowContext(ctx, `
+			SELECT street_number, street_name, zip_code
+			FROM properties
+			WHERE id = $1
+		`, opts.PropertyID).Scan(&streetNumber, &streetName, &zipCode)
+		if err != nil {
+			//sql.ErrNoRows
+			return "", nil, err
+		}
+		condition := fmt.Sprintf("f.property_id = $4 OR (%s)", addressCondition)
+		return condition, []any{streetNumber, streetName, zipCode, opts.PropertyID}, nil
+	}
+
+	if strings.TrimSpace(opts.StreetNumber) == "" ||
+		strings.TrimSpace(opts.StreetName) == "" ||
+		strings.TrimSpace(opts.ZipCode) == "" {
+		return "", nil, ErrTimelineAddressRequired
+	}
+
+	return addressCondition, []any{opts.StreetNumber, opts.StreetName, opts.ZipCode}, nil
+}
+
+// GetTimeline returns the pesticide applications, form creations and edits and notes
+// on every live shrub and lawn form at an address, whoever made them (admin only),
+// newest first unless opts.Order is "ASC".
+// It returns sql.ErrNoRows if opts.PropertyID does not exist and
+// ErrTimelineAddressRequired if neither a property nor a full address was given.
+func (r *CustomersRepository) GetTimeline(
+	ctx context.Context,
+	opts TimelineOptions,
+) (TimelinePage, error) {
+	condition, args, err := r.timelineAddressCondition(ctx, opts)
+	if err != nil {
+		return TimelinePage{}, err
+	}
+	entriesCTE := fmt.Sprintf(timelineEntriesCTE, condition)
+
+	var page TimelinePage
+	err = r.db.QueryRowContext(ctx, entriesCTE+`
+		SELECT COUNT(*) FROM entries
+	`, args...).Scan(&page.Total)
+	if err != nil {
+		return TimelinePage{}, fmt.Errorf("error counting timeline entries: %w", err)
+	}
+
+	order := strings.ToUpper(opts.Order)
+	if order != "ASC" {
+		order = "DESC"
+	}
+
+	// Entries at the same instant keep a stable order: creation, edits, applications, notes
+	query := entriesCTE + fmt.Sprintf(`
+		SELECT
+			e.kind,
+			e.occurred_at,
+			e.form_id,
+			e.form_type,
+			COALESCE(e.user_id::text, ''),
+			COALESCE(u.first_name || ' ' || u.last_name, ''),
+			e.entry_id,
+			e.chemical_id,
+			e.brand_name,
+			e.chemical_name,
+			e.epa_reg_no,
+			e.unit,
+			e.rate,
+			e.amount_applied,
+			e.location_code,
+			e.action,
+			e.diff,
+			e.note
+		FROM entries e
+		LEFT JOIN users u ON u.id = e.user_id
+		ORDER BY
+			e.occurred_at %[1]s,
+			array_position(ARRAY['form_created', 'form_edited', 'application', 'note'], e.kind) %[1]s,
+			e.entry_id %[1]s
+	`, order)
+
+	argIndex := len(args) + 1
+	if opts.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT $%d", argIndex)
+		args = append(args, opts.Limit)
+		argIndex++
+	}
+	if opts.Offset > 0 {
+		query += fmt.Sprintf(" OFFSET $%d", argIndex)
+		args = append(args, opts.Offset)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return TimelinePage{}, fmt.Errorf("error querying timeline entries: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			entry         TimelineEntry
+			entryID       int64
+			chemicalID    sql.NullInt32
+			brandName     sql.NullString
+			chemicalName  sql.NullString
+			epaRegNo      sql.NullString
+			unit          sql.NullString
+			rate          sql.NullString
+			amountApplied decimal.NullDecimal
+			locationCode  sql.NullString
+			action        sql.NullString
+			diffJSON      []byte
+			note          sql.NullString
+		)
+		err := rows.Scan(
+			&entry.Kind,
+			&entry.OccurredAt,
+			&entry.FormID,
+			&entry.FormType,
+			&entry.UserID,
+			&entry.UserName,
+			&entryID,
+			&chemicalID,
+			&brandName,
+			&chemicalName,
+			&epaRegNo,
+			&unit,
+			&rate,
+			&amountApplied,
+			&locationCode,
+			&action,
+			&diffJSON,
+			&note,
+		)
+		if err != nil {
+			return TimelinePage{}, fmt.Errorf("error scanning timeline entry: %w", err)
+		}
+
+		switch entry.Kind {
+		case TimelineKindApplication:
+			entry.Application = &TimelineApplication{
+				ID:            int(entryID),
+				ChemicalID:    int(chemicalID.Int32),
+				BrandName:     brandName.String,
+				ChemicalName:  chemicalName.String,
+				EpaRegNo:      epaRegNo.String,
+				Unit:          unit.String,
+				Rate:          rate.String,
+				AmountApplied: amountApplied.Decimal,
+				LocationCode:  locationCode.String,
+			}
+		case TimelineKindFormEdited:
+			entry.Edit = &TimelineEdit{
+				RevisionID: entryID,
+				Action:     action.String,
+			}
+			if err := json.Unmarshal(diffJSON, &entry.Edit.Diff); err != nil {
+				return TimelinePage{}, fmt.Errorf("error decoding revision %d diff: %w", entryID, err)
+			}
+		case TimelineKindNote:
+			entry.Note = &TimelineNote{
+				ID:      int(entryID),
+				Message: note.String,
+			}
+		}
+
+		page.Entries = append(page.Entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return TimelinePage{}, fmt.Errorf("error after timeline query: %w", err)
+	}
+
+	return page, nil
+}
@@ -0,0 +1,254 @@
+package customers
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/db"
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/forms"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestChemical(t testing.TB, db *sql.DB, category string) int {
+	t.Helper()
+
+	var id int
+	err := db.QueryRow(`
+		INSERT INTO chemicals (category, brand_name, chemical_name, epa_reg_no, recipe, unit)
+		VALUES ($1, 'TestBrand', 'TestChem', '123-456', 'Mix well', 'oz')
+		RETURNING id
+	`, category).Scan(&id)
+
+	require.NoError(t, err)
+	return id
+}
+
+func TestGetTimeline_MergesFormsAtAddress(t *testing.T) {
+	ctx := context.Background()
+	database := db.TestDB(t)
+	repo := NewCustomersRepository(database)
+	formsRepo := forms.NewFormsRepository(database)
+
+	userID := createTestUser(t, database)
+	shrubChem := createTestChemical(t, database, "shrub")
+	lawnChem := createTestChemical(t, database, "lawn")
+
+	base := time.Date(2025, 5, 1, 9, 0, 0, 0, time.UTC)
+
+	shrubID, err := formsRepo.CreateShrubForm(ctx, forms.CreateShrubFormInput{
+		CreatedBy:    userID,
+		FirstName:    "Tim",
+		LastName:     "Line",
+		StreetNumber: "12",
+		StreetName:   "Main St",
+		Town:         "Springfield",
+		ZipCode:      "12345",
+		HomePhone:    "555-0101",
+		OtherPhone:   "555-0102",
+		Applications: []forms.PestApp{
+			{
+				ChemUsed:      shrubChem,
+				AppTimestamp:  base,
+				Rate:          "1 oz/gal",
+				AmountApplied: decimal.NewFromFloat(2.5),
+				LocationCode:  "1A",
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	// Same address typed differently on the lawn form
+	lawnID, err := formsRepo.CreateLawnForm(ctx, forms.CreateLawnFormInput{
+		CreatedBy:    userID,
+		FirstName:    "Tim",
+		LastName:     "Line",
+		StreetNumber: "12 ",
+		StreetName:   "main st",
+		Town:         "Springfield",
+		ZipCode:      "12345",
+		HomePhone:    "555-0101",
+		OtherPhone:   "555-0102",
+		LawnAreaSqFt: 2000,
+		Applications: []forms.PestApp{
+			{
+				ChemUsed:      lawnChem,
+				AppTimestamp:  base.Add(24 * time.Hour),
+				Rate:          "2 oz/1000 sq ft",
+				AmountApplied: decimal.NewFromFloat(4),
+				LocationCode:  "1A",
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	_, err = formsRepo.CreateNote(ctx, lawnID, userID, "Gate code 1234")
+	require.NoError(t, err)
+
+	// A form next door is left out
+	_, err = formsRepo.CreateShrubForm(ctx, forms.CreateShrubFormInput{
+		CreatedBy:    userID,
+		FirstName:    "Nate",
+		LastName:     "Bor",
+		StreetNumber: "14",
+		StreetName:   "Main St",
+		Town:         "Springfield",
+		ZipCode:      "12345",
+	})
+	require.NoError(t, err)
+
+	page, err := repo.GetTimeline(ctx, TimelineOptions{
+		StreetNumber: "12",
+		StreetName:   "Main St",
+		ZipCode:      "12345",
+		Order:        "ASC",
+	})
+	require.NoError(t, err)
+	require.Equal(t, 5, page.Total)
+	require.Len(t, page.Entries, 5)
+
+	kinds := map[string]int{}
+	for _, entry := range page.Entries {
+		kinds[entry.Kind]++
+		require.Contains(t, []string{shrubID, lawnID}, entry.FormID)
+		require.Equal(t, userID, entry.UserID)
+		require.Equal(t, "Test User", entry.UserName)
+	}
+	require.Equal(t, map[string]int{
+		TimelineKindFormCreated: 2,
+		TimelineKindApplication: 2,
+		TimelineKindNote:        1,
+	}, kinds)
+
+	// Applications were backdated, so they come first in ascending order
+	first := page.Entries[0]
+	require.Equal(t, TimelineKindApplication, first.Kind)
+	require.Equal(t, shrubID, first.FormID)
+	require.Equal(t, "TestChem", first.Application.ChemicalName)
+	require.Equal(t, "TestBrand", first.Application.BrandName)
+	require.True(t, decimal.NewFromFloat(2.5).Equal(first.Application.AmountApplied))
+
+	last := page.Entries[len(page.Entries)-1]
+	require.Equal(t, TimelineKindNote, last.Kind)
+	require.Equal(t, "Gate code 1234", last.Note.Message)
+
+	// Paging keeps the total
+	page, err = repo.GetTimeline(ctx, TimelineOptions{
+		StreetNumber: "12",
+		StreetName:   "Main St",
+		ZipCode:      "12345",
+		Limit:        2,
+		Offset:       4,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 5, page.Total)
+	require.Len(t, page.Entries, 1)
+
+	_, err = repo.GetTimeline(ctx, TimelineOptions{StreetNumber: "12"})
+	require.ErrorIs(t, err, ErrTimelineAddressRequired)
+}
+
+func TestGetTimeline_ByProperty(t *testing.T) {
+	ctx := context.Background()
+	database := db.TestDB(t)
+	repo := NewCustomersRepository(database)
+	formsRepo := forms.NewFormsRepository(database)
+
+	userID := createTestUser(t, database)
+
+	customer, err := repo.CreateCustomer(ctx, CustomerInput{
+		FirstName:  "Pat",
+		LastName:   "Owner",
+		HomePhone:  "555-0201",
+		OtherPhone: "555-0202",
+	}, []PropertyInput{
+		{StreetNumber: "5", StreetName: "Elm St", Town: "Springfield", ZipCode: "12345"},
+	})
+	require.NoError(t, err)
+	propertyID := customer.Properties[0].ID
+
+	formID, err := formsRepo.CreateShrubForm(ctx, forms.CreateShrubFormInput{
+		CreatedBy:  userID,
+		PropertyID: propertyID,
+	})
+	require.NoError(t, err)
+
+	form, err := formsRepo.GetShrubFormById(ctx, formID, userID)
+	require.NoError(t, err)
+
+	_, err = formsRepo.UpdateShrubFormById(ctx, formID, userID, forms.UpdateShrubFormInput{
+		FirstName:    form.FirstName,
+		LastName:     form.LastName,
+		StreetNumber: form.StreetNumber,
+		StreetName:   form.StreetName,
+		Town:         form.Town,
+		ZipCode:      form.ZipCode,
+		HomePhone:    form.HomePhone,
+		OtherPhone:   form.OtherPhone,
+		CallBefore:   true,
+	})
+	require.NoError(t, err)
+
+	page, err := repo.GetTimeline(ctx, TimelineOptions{PropertyID: propertyID})
+	require.NoError(t, err)
+	require.Equal(t, 2, page.Total)
+
+	// Newest first
+	require.Equal(t, TimelineKindFormEdited, page.Entries[0].Kind)
+	require.Equal(t, forms.RevisionActionUpdate, page.Entries[0].Edit.Action)
+	require.Equal(t, true, page.Entries[0].Edit.Diff.Fields["call_before"].New)
+	require.Equal(t, TimelineKindFormCreated, page.Entries[1].Kind)
+
+	_, err = repo.GetTimeline(ctx, TimelineOptions{PropertyID: "00000000-0000-0000-0000-000000000000"})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestGetTimeline_CreditsApplicator(t *testing.T) {
+	ctx := context.Background()
+	database := db.TestDB(t)
+	repo := NewCustomersRepository(database)
+	formsRepo := forms.NewFormsRepository(database)
+
+	creatorID := createTestUser(t, database)
+	techID := createTestUser(t, database)
+	chemID := createTestChemical(t, database, "lawn")
+
+	formID, err := formsRepo.CreateLawnForm(ctx, forms.CreateLawnFormInput{
+		CreatedBy:    creatorID,
+		FirstName:    "App",
+		LastName:     "Lied",
+		StreetNumber: "7",
+		StreetName:   "Spray Rd",
+		Town:         "Springfield",
+		ZipCode:      "12345",
+		LawnAreaSqFt: 2000,
+	})
+	require.NoError(t, err)
+
+	// The creator records a round serviced by the tech
+	_, err = formsRepo.CreatePestApp(ctx, formID, creatorID, forms.PestApp{
+		ChemUsed:      chemID,
+		AppTimestamp:  time.Now(),
+		Rate:          "2 oz/1000 sq ft",
+		AmountApplied: decimal.NewFromFloat(4),
+		LocationCode:  "1A",
+		AppliedBy:     techID,
+	})
+	require.NoError(t, err)
+
+	page, err := repo.GetTimeline(ctx, TimelineOptions{
+		StreetNumber: "7",
+		StreetName:   "Spray Rd",
+		ZipCode:      "12345",
+	})
+	require.NoError(t, err)
+
+	users := map[string]string{}
+	for _, entry := range page.Entries {
+		users[entry.Kind] = entry.UserID
+	}
+	require.Equal(t, creatorID, users[TimelineKindFormCreated])
+	require.Equal(t, techID, users[TimelineKindApplication])
+}
@@ -9,7 +9,7 @@ import (
 	_ "github.com/lib/pq"
 )
 
-func TestDB(t *testing.T) *sql.DB {
+func TestDB(t testing.TB) *sql.DB {
 	t.Helper()
 
 	dsn := os.Getenv("DATABASE_URL")
@@ -0,0 +1,195 @@
+package forms
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/db"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppliedBy(t *testing.T) {
+	ctx := context.Background()
+	testDB := db.TestDB(t)
+	repo := NewFormsRepository(testDB)
+
+	creatorID := createTestUser(t, testDB)
+	techID := createTestUser(t, testDB)
+	chemID := createTestChemical(t, testDB, "lawn")
+	nextYear := time.Now().AddDate(1, 0, 0).UTC().Truncate(24 * time.Hour)
+	setTestLicense(t, testDB, techID, "57TECH", nextYear)
+
+	app := PestApp{
+		ChemUsed:      chemID,
+		AppTimestamp:  time.Now(),
+		Rate:          "2 oz/1000 sq ft",
+		AmountApplied: decimal.NewFromFloat(4.0),
+		LocationCode:  "1A",
+	}
+	formID, err := repo.CreateLawnForm(ctx, CreateLawnFormInput{
+		CreatedBy:    creatorID,
+		FirstName:    "Round",
+		LastName:     "Two",
+		StreetNumber: "12",
+		StreetName:   "Sprayer Ln",
+		Town:         "Town",
+		ZipCode:      "10012",
+		HomePhone:    "555-0021",
+		OtherPhone:   "555-0022",
+		LawnAreaSqFt: 2000,
+		Applications: []PestApp{app},
+	})
+	require.NoError(t, err)
+
+	// Applications default to the user recording them
+	lawn, err := repo.GetLawnFormById(ctx, formID, creatorID)
+	require.NoError(t, err)
+	require.Len(t, lawn.AppTimes, 1)
+	require.Equal(t, creatorID, lawn.AppTimes[0].AppliedBy)
+	require.Equal(t, "Test User", lawn.AppTimes[0].AppliedByName)
+
+	// Until the tech makes an application the form is hidden from them
+	_, err = repo.GetFormViewById(ctx, formID, techID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	// The next round is serviced by another tech and stamped with their license
+	app.AppliedBy = techID
+	added, err := repo.CreatePestApp(ctx, formID, creatorID, app)
+	require.NoError(t, err)
+	require.Equal(t, techID, added.AppliedBy)
+	require.NotNil(t, added.ApplicatorLicense)
+	require.Equal(t, "57TECH", added.ApplicatorLicense.Number)
+
+	view, err := repo.GetFormViewById(ctx, formID, techID)
+	require.NoError(t, err)
+	require.Equal(t, formID, view.form().ID)
+
+	revisions, err := repo.ListFormRevisions(ctx, formID, techID)
+	require.NoError(t, err)
+	require.NotEmpty(t, revisions)
+
+	page, err := repo.ListFormsByUserId(ctx, techID, ListFormsOptions{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, page.Forms, 1)
+
+	page, err = repo.ListAllForms(ctx, ListFormsOptions{Limit: 10, AppliedBy: techID})
+	require.NoError(t, err)
+	require.Len(t, page.Forms, 1)
+	page, err = repo.ListAllForms(ctx, ListFormsOptions{Limit: 10, AppliedBy: createTestUser(t, testDB)})
+	require.NoError(t, err)
+	require.Empty(t, page.Forms)
+
+	// Editing an application without naming an applicator keeps the stored one
+	added.AppliedBy = ""
+	added.Rate = "3 oz/1000 sq ft"
+	updated, err := repo.UpdatePestAppById(ctx, formID, creatorID, added)
+	require.NoError(t, err)
+	require.Equal(t, techID, updated.AppliedBy)
+
+	// Reassigning an application stamps the new applicator's license
+	added.AppliedBy = creatorID
+	updated, err = repo.UpdatePestAppById(ctx, formID, creatorID, added)
+	require.NoError(t, err)
+	require.Equal(t, creatorID, updated.AppliedBy)
+	require.Nil(t, updated.ApplicatorLicense)
+
+	// Applicators must be users
+	app.AppliedBy = "00000000-0000-0000-0000-000000000000"
+	_, err = repo.CreatePestApp(ctx, formID, creatorID, app)
+	var valErr *ValidationError
+	require.ErrorAs(t, err, &valErr)
+	require.Equal(t, "applied_by", valErr.Fields[0].Field)
+}
+
+func TestApplicatorEditsOwnApplications(t *testing.T) {
+	ctx := context.Background()
+	testDB := db.TestDB(t)
+	repo := NewFormsRepository(testDB)
+
+	creatorID := createTestUser(t, testDB)
+	techID := createTestUser(t, testDB)
+	chemID := createTestChemical(t, testDB, "lawn")
+
+	app := PestApp{
+		ChemUsed:      chemID,
+		AppTimestamp:  time.Now(),
+		Rate:          "2 oz/1000 sq ft",
+		AmountApplied: decimal.NewFromFloat(4.0),
+		LocationCode:  "1A",
+	}
+	formID := createTestLawnForm(t, repo, CreateLawnFormInput{
+		CreatedBy:    creatorID,
+		Applications: []PestApp{app},
+	})
+	lawn, err := repo.GetLawnFormById(ctx, formID, creatorID)
+	require.NoError(t, err)
+	creatorApp := lawn.AppTimes[0]
+
+	// Before their first application the tech cannot add to the form
+	_, err = repo.CreatePestApp(ctx, formID, techID, app)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	_, err = repo.CreateNote(ctx, formID, techID, "Gate code 1234")
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	app.AppliedBy = techID
+	_, err = repo.CreatePestApp(ctx, formID, creatorID, app)
+	require.NoError(t, err)
+
+	// Once they have one they record their next rounds themselves
+	app.AppliedBy = ""
+	techApp, err := repo.CreatePestApp(ctx, formID, techID, app)
+	require.NoError(t, err)
+	require.Equal(t, techID, techApp.AppliedBy)
+
+	techApp.Rate = "3 oz/1000 sq ft"
+	techApp, err = repo.UpdatePestAppById(ctx, formID, techID, techApp)
+	require.NoError(t, err)
+	require.Equal(t, "3 oz/1000 sq ft", techApp.Rate)
+
+	// But cannot change the creator's applications or replace the whole form
+	creatorApp.Rate = "3 oz/1000 sq ft"
+	_, err = repo.UpdatePestAppById(ctx, formID, techID, creatorApp)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	err = repo.DeletePestAppById(ctx, formID, techID, creatorApp.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	_, err = repo.UpdateLawnFormById(ctx, formID, techID, UpdateLawnFormInput{
+		FirstName:    "Test",
+		LastName:     "Customer",
+		StreetNumber: "1",
+		StreetName:   "Test St",
+		Town:         "Town",
+		ZipCode:      "10001",
+		HomePhone:    "555-0001",
+		OtherPhone:   "555-0000",
+		LawnAreaSqFt: 1000,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	err = repo.DeletePestAppById(ctx, formID, techID, techApp.ID)
+	require.NoError(t, err)
+
+	// Applicators share the form's notes and edit the ones they wrote
+	techNote, err := repo.CreateNote(ctx, formID, techID, "Gate code 1234")
+	require.NoError(t, err)
+	creatorNote, err := repo.CreateNote(ctx, formID, creatorID, "Dog in yard")
+	require.NoError(t, err)
+
+	notes, err := repo.ListNotesByFormId(ctx, formID, techID)
+	require.NoError(t, err)
+	require.Len(t, notes, 2)
+
+	_, err = repo.UpdateNoteById(ctx, formID, techID, techNote.ID, "Gate code 4321")
+	require.NoError(t, err)
+	_, err = repo.UpdateNoteById(ctx, formID, techID, creatorNote.ID, "No dog")
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	err = repo.DeleteNoteById(ctx, formID, techID, creatorNote.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	// The creator still manages every note on the form
+	err = repo.DeleteNoteById(ctx, formID, creatorID, techNote.ID)
+	require.NoError(t, err)
+}
@@ -0,0 +1,49 @@
+package forms
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/holidays"
+)
+
+// ErrHolidayBlackout is returned when an application for a customer who observes Jewish holidays
+// falls on a holiday or Shabbat
+var ErrHolidayBlackout = errors.New("customer is not serviced on Jewish holidays or Shabbat")
+
+// blackoutTimeLayout formats the bounds of a blackout in error messages
+const blackoutTimeLayout = "Mon Jan 2 3:04 PM MST"
+
+// checkHolidayBlackout returns ErrHolidayBlackout when a form with isHoliday set has a new
+// application, or one moved to a new time, inside a holiday or Shabbat at the company's location.
+// stored are the form's applications before the change, nil for a form being created; applications
+// left at their stored time are not checked, so marking a form is_holiday keeps its history.
+func checkHolidayBlackout(isHoliday bool, apps []PestApp, stored []appSnapshot) error {
+	if !isHoliday {
+		return nil
+	}
+
+	storedTimes := make(map[int]appSnapshot, len(stored))
+	for _, app := range stored {
+		storedTimes[app.ID] = app
+	}
+
+	loc := holidays.LocationFromEnv()
+	for _, app := range apps {
+		if previous, ok := storedTimes[app.ID]; ok && app.ID != 0 && previous.AppTimestamp.Equal(app.AppTimestamp) {
+			continue
+		}
+		window, ok := holidays.Observed(app.AppTimestamp, loc)
+		if !ok {
+			continue
+		}
+		return fmt.Errorf("%w: %s falls during %s (%s to %s)",
+			ErrHolidayBlackout,
+			app.AppTimestamp.In(loc.TimeZone).Format(blackoutTimeLayout),
+			window.Name,
+			window.Start.Format(blackoutTimeLayout),
+			window.End.Format(blackoutTimeLayout),
+		)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package forms
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/db"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHolidayBlackout(t *testing.T) {
+	ctx := context.Background()
+	testDB := db.TestDB(t)
+	repo := NewFormsRepository(testDB)
+
+	userID := createTestUser(t, testDB)
+	chemID := createTestChemical(t, testDB, "lawn")
+
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	app := PestApp{
+		ChemUsed: chemID,
+		// Saturday morning
+		AppTimestamp:  time.Date(2025, time.June, 21, 10, 0, 0, 0, newYork),
+		Rate:          "2 oz/1000 sq ft",
+		AmountApplied: decimal.NewFromFloat(4.0),
+		LocationCode:  "1A",
+	}
+	input := CreateLawnFormInput{
+		CreatedBy:    userID,
+		FirstName:    "Shomer",
+		LastName:     "Shabbos",
+		StreetNumber: "18",
+		StreetName:   "Candle Ct",
+		Town:         "Town",
+		ZipCode:      "10025",
+		HomePhone:    "555-0251",
+		OtherPhone:   "555-0252",
+		IsHoliday:    true,
+		LawnAreaSqFt: 2000,
+		Applications: []PestApp{app},
+	}
+
+	_, err = repo.CreateLawnForm(ctx, input)
+	require.ErrorIs(t, err, ErrHolidayBlackout)
+	require.Contains(t, err.Error(), "Shabbat")
+
+	// Customers who do not observe holidays are serviced on Shabbat
+	input.IsHoliday = false
+	formID, err := repo.CreateLawnForm(ctx, input)
+	require.NoError(t, err)
+	lawn, err := repo.GetLawnFormById(ctx, formID, userID)
+	require.NoError(t, err)
+
+	// Marking the customer afterwards keeps the applications already made
+	update := UpdateLawnFormInput{
+		FirstName:    input.FirstName,
+		LastName:     input.LastName,
+		StreetNumber: input.StreetNumber,
+		StreetName:   input.StreetName,
+		Town:         input.Town,
+		ZipCode:      input.ZipCode,
+		HomePhone:    input.HomePhone,
+		OtherPhone:   input.OtherPhone,
+		IsHoliday:    true,
+		LawnAreaSqFt: input.LawnAreaSqFt,
+		Applications: lawn.AppTimes,
+	}
+	_, err = repo.UpdateLawnFormById(ctx, formID, userID, update)
+	require.NoError(t, err)
+
+	// but new applications must avoid holidays, here Yom Kippur
+	app.AppTimestamp = time.Date(2025, time.October, 2, 10, 0, 0, 0, newYork)
+	_, err = repo.CreatePestApp(ctx, formID, userID, app)
+	require.ErrorIs(t, err, ErrHolidayBlackout)
+	require.Contains(t, err.Error(), "Yom Kippur")
+
+	// Friday morning is fine, moving it to Friday night is not
+	app.AppTimestamp = time.Date(2025, time.June, 27, 10, 0, 0, 0, newYork)
+	added, err := repo.CreatePestApp(ctx, formID, userID, app)
+	require.NoError(t, err)
+
+	added.AppTimestamp = time.Date(2025, time.June, 27, 21, 0, 0, 0, newYork)
+	_, err = repo.UpdatePestAppById(ctx, formID, userID, added)
+	require.ErrorIs(t, err, ErrHolidayBlackout)
+}
@@ -0,0 +1,450 @@
+package forms
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Outcomes of a call to a customer who asked to be called before applications
+const (
+	CallOutcomeReached   = "reached"
+	CallOutcomeVoicemail = "voicemail"
+	CallOutcomeDeclined  = "declined"
+)
+
+// Reasons a form is in the call queue
+const (
+	// CallReasonScheduled means the form has an application dated on the queue's day
+	CallReasonScheduled = "scheduled"
+	// CallReasonDue means the form's next round is due: its last application this season was
+	// at least a round interval before the queue's day
+	CallReasonDue = "due"
+)
+
+// ErrInvalidCallOutcome is returned when a call is logged with an unknown outcome
+var ErrInvalidCallOutcome = errors.New("outcome must be 'reached', 'voicemail' or 'declined'")
+
+// ErrCallRequired is returned when an application is recorded for a customer who asked to be called
+// first, without a call that reached them logged within the call window before the application
+var ErrCallRequired = errors.New("customer must be reached by phone before applications")
+
+// CallWindowFromEnv returns how long before an application the customer must have been reached.
+// CALL_BEFORE_WINDOW_HOURS defaults to 48.
+func CallWindowFromEnv() time.Duration {
+	hours, err := strconv.Atoi(os.Getenv("CALL_BEFORE_WINDOW_HOURS"))
+	if err != nil || hours <= 0 {
+		hours = 48 // Fallback when unset or invalid
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// RoundDaysFromEnv returns how many days after an application the next round is due.
+// APPLICATION_ROUND_DAYS defaults to 42.
+func RoundDaysFromEnv() int {
+	days, err := strconv.Atoi(os.Getenv("APPLICATION_ROUND_DAYS"))
+	if err != nil || days <= 0 {
+		days = 42 // Fallback when unset or invalid
+	}
+	return days
+}
+
+// FormCall is a call made to the customer of a form
+type FormCall struct {
+	ID           int64
+	FormID       string
+	CalledBy     string
+	CalledByName string
+	CalledAt     time.Time
+	Outcome      string
+	Notes        string
+}
+
+// CallQueueEntry is a customer to call before applications on the queue's day
+type CallQueueEntry struct {
+	FormID       string
+	FormType     string
+	FirstName    string
+	LastName     string
+	StreetNumber string
+	StreetName   string
+	Town         string
+	ZipCode      string
+	HomePhone    string
+	OtherPhone   string
+	// Reason is CallReasonScheduled or CallReasonDue
+	Reason string
+	// LastApplication is the form's most recent application before the queue's day, nil if none.
+	// Only its ID, chemical, timestamp and applicator are set.
+	LastApplication *PestApp
+	// LastApplicationBrandName is the brand name of the last application's chemical
+	LastApplicationBrandName string
+	// LastCall is the most recent call logged for the form, nil if none
+	LastCall *FormCall
+	// Cleared is set when a call that reached the customer was logged within the call window
+	// before the end of the queue's day
+	Cleared bool
+}
+
+// CallLog is a call made before a form was created, logged along with the form so that its first
+// applications can be recorded
+type CallLog struct {
+	// CalledAt is when the call was made, now if zero
+	CalledAt time.Time
+	Outcome  string
+	Notes    string
+}
+
+// normalizeCallOutcome lowercases and trims an outcome, reporting whether it is known
+func normalizeCallOutcome(outcome string) (string, bool) {
+	outcome = strings.ToLower(strings.TrimSpace(outcome))
+	return outcome, outcome == CallOutcomeReached || outcome == CallOutcomeVoicemail || outcome == CallOutcomeDeclined
+}
+
+// normalizeCallLog returns a copy of a call logged with a new form with its outcome normalized and
+// its time defaulted, or the problems with it. A nil call is left nil.
+func normalizeCallLog(call *CallLog) (*CallLog, []FieldError) {
+	if call == nil {
+		return nil, nil
+	}
+	normalized := *call
+	now := time.Now()
+	if normalized.CalledAt.IsZero() {
+		normalized.CalledAt = now
+	}
+	normalized.Notes = strings.TrimSpace(normalized.Notes)
+
+	var fields []FieldError
+	var ok bool
+	if normalized.Outcome, ok = normalizeCallOutcome(normalized.Outcome); !ok {
+		fields = append(fields, FieldError{Field: "call.outcome", Message: ErrInvalidCallOutcome.Error()})
+	}
+	if normalized.CalledAt.After(now) {
+		fields = append(fields, FieldError{Field: "call.called_at", Message: "cannot be in the future"})
+	}
+	return &normalized, fields
+}
+
+// insertCall logs a call made by the given user for a form inside tx
+func insertCall(ctx context.Context, tx *sql.Tx, formID string, userID string, call CallLog) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO form_calls (form_id, called_by, called_at, outcome, notes)
+		VALUES ($1, $2, $3, $4, $5)
+	`, formID, userID, call.CalledAt, call.Outcome, call.Notes)
+	if err != nil {
+		return fmt.Errorf("error logging call for form %s: %w", formID, err)
+	}
+	return nil
+}
+
+// checkCallBefore returns ErrCallRequired unless the customer of a form with callBefore set was
+// reached within the call window before each new application in apps, or one moved to a new time.
+// stored are the form's applications before the change, as for checkHolidayBlackout.
+// formID is empty for a form being created, which has no calls logged yet; its applications are
+// checked against call, the call being logged along with the form, if any.
+func checkCallBefore(
+	ctx context.Context,
+	tx *sql.Tx,
+	formID string,
+	callBefore bool,
+	apps []PestApp,
+	stored []appSnapshot,
+	call *CallLog,
+) error {
+	if !callBefore {
+		return nil
+	}
+
+	storedTimes := make(map[int]appSnapshot, len(stored))
+	for _, app := range stored {
+		storedTimes[app.ID] = app
+	}
+
+	window := CallWindowFromEnv()
+	for _, app := range apps {
+		if previous, ok := storedTimes[app.ID]; ok && app.ID != 0 && previous.AppTimestamp.Equal(app.AppTimestamp) {
+			continue
+		}
+		if formID == "" {
+			if call == nil ||
+				call.Outcome != CallOutcomeReached ||
+				call.CalledAt.Before(app.AppTimestamp.Add(-window)) ||
+				call.CalledAt.After(app.AppTimestamp) {
+				return ErrCallRequired
+			}
+			continue
+		}
+		var reached bool
+		err := tx.QueryRowContext(ctx, `
+			SELECT EXISTS (
+				SELECT 1
+				FROM form_calls
+				WHERE form_id = $1
+				  AND outcome = 'reached'
+				  AND called_at BETWEEN $2 AND $3
+			)
+		`, formID, app.AppTimestamp.Add(-window), app.AppTimestamp).Scan(&reached)
+		if err != nil {
+			return fmt.Errorf("error checking calls for form %s: %w", formID, err)
+		}
+		if !reached {
+			return ErrCallRequired
+		}
+	}
+	return nil
+}
+
+// LogCall records a call to the customer of a live form, made by the given user.
+// Returns the logged call upon success.
+// It returns sql.ErrNoRows if the form does not exist or is in the trash,
+// and ErrInvalidCallOutcome for an unknown outcome.
+func (r *FormsRepository) LogCall(
+	ctx context.Context,
+	formID string,
+	userID string,
+	outcome string,
+	notes string,
+) (FormCall, error) {
+	outcome, ok := normalizeCallOutcome(outcome)
+	if !ok {
+		return FormCall{}, ErrInvalidCallOutcome
+	}
+
+	call := FormCall{
+		FormID:   formID,
+		CalledBy: userID,
+		Outcome:  outcome,
+		Notes:    strings.TrimSpace(notes),
+	}
+	err := r.db.QueryRowContext(ctx, `
+		WITH inserted AS (
+			INSERT INTO form_calls (form_id, called_by, outcome, notes)
+			SELECT f.id, $2::uuid, $3::text, $4::text
+			FROM forms f
+			WHERE f.id = $1 AND f.deleted_at IS NULL
+			RETURNING id, called_at
+		)
+		SELECT i.id, i.called_at, COALESCE(u.first_name || ' ' || u.last_name, '')
+		FROM inserted i
+		LEFT JOIN users u ON u.id = $2::uuid
+	`, formID, userID, call.Outcome, call.Notes).Scan(&call.ID, &call.CalledAt, &call.CalledByName)
+	if err != nil {
+		// sql.ErrNoRows → form not found
+		return FormCall{}, err
+	}
+
+	return call, nil
+}
+
+// ListCalls returns the calls logged for a form, most recent first.
+// It returns sql.ErrNoRows if the form does not exist.
+func (r *FormsRepository) ListCalls(ctx context.Context, formID string) ([]FormCall, error) {
+	if err := r.db.QueryRowContext(ctx, `SELECT id FROM forms WHERE id = $1`, formID).Scan(&formID); err != nil {
+		//sql.ErrNoRows
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT
+			fc.id,
+			fc.form_id,
+			COALESCE(fc.called_by::text, ''),
+			COALESCE(u.first_name || ' ' || u.last_name, ''),
+			fc.called_at,
+			fc.outcome,
+			fc.notes
+		FROM form_calls fc
+		LEFT JOIN users u ON u.id = fc.called_by
+		WHERE fc.form_id = $1
+		ORDER BY fc.called_at DESC, fc.id DESC
+	`, formID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching calls for form: %s. %w", formID, err)
+	}
+	defer rows.Close()
+
+	calls := []FormCall{}
+	for rows.Next() {
+		var call FormCall
+		err := rows.Scan(
+			&call.ID,
+			&call.FormID,
+			&call.CalledBy,
+			&call.CalledByName,
+			&call.CalledAt,
+			&call.Outcome,
+			&call.Notes,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning call for form: %s. %w", formID, err)
+		}
+		calls = append(calls, call)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after calls query for form: %s. %w", formID, err)
+	}
+
+	return calls, nil
+}
+
+// GetCallQueue lists the live forms with call_before set whose customer needs a call for the given
+// day: forms with an application dated that day, and forms whose last application this season was
+// at least roundDays days earlier. Entries are in route order by zip and street.
+func (r *FormsRepository) GetCallQueue(ctx context.Context, day time.Time, roundDays int) ([]CallQueueEntry, error) {
+	window := CallWindowFromEnv()
+
+	rows, err := r.db.QueryContext(ctx, `
+		WITH form_days AS (
+			SELECT
+				pa.form_id,
+				BOOL_OR(pa.app_timestamp::date = $1::date) AS scheduled,
+				MAX(pa.app_timestamp) FILTER (WHERE pa.app_timestamp::date < $1::date) AS last_app
+			FROM pesticide_applications pa
+			GROUP BY pa.form_id
+		)
+		SELECT
+			f.id,
+			f.form_type,
+			f.first_name,
+			f.last_name,
+			f.street_number,
+			f.street_name,
+			f.town,
+			f.zip_code,
+			f.home_phone,
+			f.other_phone,
+			CASE WHEN fd.scheduled THEN 'scheduled' ELSE 'due' END,
+			last_app.id,
+			last_app.chem_used,
+			last_app.app_timestamp,
+			COALESCE(last_app.applied_by::text, ''),
+			COALESCE(last_app.applied_by_name, ''),
+			COALESCE(last_app.brand_name, ''),
+			last_call.id,
+			COALESCE(last_call.called_by::text, ''),
+			COALESCE(last_call.called_by_name, ''),
+			last_call.called_at,
+			COALESCE(last_call.outcome, ''),
+			COALESCE(last_call.notes, ''),
+			EXISTS (
+				SELECT 1
+				FROM form_calls fc
+				WHERE fc.form_id = f.id
+				  AND fc.outcome = 'reached'
+				  AND fc.called_at >= $1::date + 1 - make_interval(hours => $3::int)
+				  AND fc.called_at < $1::date + 1
+			)
+		FROM forms f
+		JOIN form_days fd ON fd.form_id = f.id
+		LEFT JOIN LATERAL (
+			SELECT
+				pa.id,
+				pa.chem_used,
+				pa.app_timestamp,
+				pa.applied_by,
+				u.first_name || ' ' || u.last_name AS applied_by_name,
+				c.brand_name
+			FROM pesticide_applications pa
+			JOIN chemicals c ON c.id = pa.chem_used
+			LEFT JOIN users u ON u.id = pa.applied_by
+			WHERE pa.form_id = f.id
+			  AND pa.app_timestamp::date < $1::date
+			ORDER BY pa.app_timestamp DESC, pa.id DESC
+			LIMIT 1
+		) last_app ON TRUE
+		LEFT JOIN LATERAL (
+			SELECT
+				fc.id,
+				fc.called_by,
+				u.first_name || ' ' || u.last_name AS called_by_name,
+				fc.called_at,
+				fc.outcome,
+				fc.notes
+			FROM form_calls fc
+			LEFT JOIN users u ON u.id = fc.called_by
+			WHERE fc.form_id = f.id
+			ORDER BY fc.called_at DESC, fc.id DESC
+			LIMIT 1
+		) last_call ON TRUE
+		WHERE f.call_before
+		  AND f.deleted_at IS NULL
+		  AND (
+			fd.scheduled
+			OR (
+				EXTRACT(YEAR FROM fd.last_app) = EXTRACT(YEAR FROM $1::date)
+				AND fd.last_app::date <= $1::date - $2::int
+			)
+		  )
+		ORDER BY f.zip_code, normalize_street(f.street_name), f.street_number, f.last_name, f.id
+	`, day.Format("2006-01-02"), roundDays, int(window.Hours()))
+	if err != nil {
+		return nil, fmt.Errorf("error fetching call queue: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []CallQueueEntry{}
+	for rows.Next() {
+		var (
+			entry    CallQueueEntry
+			app      PestApp
+			appID    sql.NullInt64
+			chemUsed sql.NullInt64
+			appTime  sql.NullTime
+			call     FormCall
+			callID   sql.NullInt64
+			calledAt sql.NullTime
+		)
+		err := rows.Scan(
+			&entry.FormID,
+			&entry.FormType,
+			&entry.FirstName,
+			&entry.LastName,
+			&entry.StreetNumber,
+			&entry.StreetName,
+			&entry.Town,
+			&entry.ZipCode,
+			&entry.HomePhone,
+			&entry.OtherPhone,
+			&entry.Reason,
+			&appID,
+			&chemUsed,
+			&appTime,
+			&app.AppliedBy,
+			&app.AppliedByName,
+			&entry.LastApplicationBrandName,
+			&callID,
+			&call.CalledBy,
+			&call.CalledByName,
+			&calledAt,
+			&call.Outcome,
+			&call.Notes,
+			&entry.Cleared,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning call queue entry: %w", err)
+		}
+		if appID.Valid {
+			app.ID = int(appID.Int64)
+			app.ChemUsed = int(chemUsed.Int64)
+			app.AppTimestamp = appTime.Time
+			entry.LastApplication = &app
+		}
+		if callID.Valid {
+			call.ID = callID.Int64
+			call.FormID = entry.FormID
+			call.CalledAt = calledAt.Time
+			entry.LastCall = &call
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after call queue query: %w", err)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,241 @@
+package forms
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/db"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallBefore(t *testing.T) {
+	ctx := context.Background()
+	testDB := db.TestDB(t)
+	repo := NewFormsRepository(testDB)
+
+	userID := createTestUser(t, testDB)
+	chemID := createTestChemical(t, testDB, "lawn")
+
+	app := PestApp{
+		ChemUsed:      chemID,
+		AppTimestamp:  time.Now(),
+		Rate:          "2 oz/1000 sq ft",
+		AmountApplied: decimal.NewFromFloat(4.0),
+		LocationCode:  "1A",
+	}
+	input := CreateLawnFormInput{
+		CreatedBy:    userID,
+		FirstName:    "Call",
+		LastName:     "First",
+		StreetNumber: "7",
+		StreetName:   "Ringing Rd",
+		Town:         "Town",
+		ZipCode:      "10024",
+		HomePhone:    "555-0241",
+		OtherPhone:   "555-0242",
+		CallBefore:   true,
+		LawnAreaSqFt: 2000,
+		Applications: []PestApp{app},
+	}
+
+	// A new form cannot carry applications before anyone has called
+	_, err := repo.CreateLawnForm(ctx, input)
+	require.ErrorIs(t, err, ErrCallRequired)
+
+	input.Applications = nil
+	formID, err := repo.CreateLawnForm(ctx, input)
+	require.NoError(t, err)
+
+	_, err = repo.CreatePestApp(ctx, formID, userID, app)
+	require.ErrorIs(t, err, ErrCallRequired)
+
+	_, err = repo.LogCall(ctx, formID, userID, "texted", "")
+	require.ErrorIs(t, err, ErrInvalidCallOutcome)
+	_, err = repo.LogCall(ctx, "00000000-0000-0000-0000-000000000000", userID, CallOutcomeReached, "")
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	// Only a call that reached the customer clears applications
+	voicemail, err := repo.LogCall(ctx, formID, userID, "Voicemail", " left a message ")
+	require.NoError(t, err)
+	require.Equal(t, CallOutcomeVoicemail, voicemail.Outcome)
+	require.Equal(t, "left a message", voicemail.Notes)
+	require.Equal(t, "Test User", voicemail.CalledByName)
+
+	_, err = repo.CreatePestApp(ctx, formID, userID, app)
+	require.ErrorIs(t, err, ErrCallRequired)
+
+	reached, err := repo.LogCall(ctx, formID, userID, CallOutcomeReached, "ok for tomorrow")
+	require.NoError(t, err)
+
+	// An application before the call was made is not covered by it
+	early := app
+	early.AppTimestamp = reached.CalledAt.Add(-time.Hour)
+	_, err = repo.CreatePestApp(ctx, formID, userID, early)
+	require.ErrorIs(t, err, ErrCallRequired)
+
+	app.AppTimestamp = reached.CalledAt.Add(time.Hour)
+	_, err = repo.CreatePestApp(ctx, formID, userID, app)
+	require.NoError(t, err)
+
+	calls, err := repo.ListCalls(ctx, formID)
+	require.NoError(t, err)
+	require.Len(t, calls, 2)
+	require.Equal(t, reached.ID, calls[0].ID)
+	require.Equal(t, voicemail.ID, calls[1].ID)
+
+	// The application puts the form in the queue for its day, cleared by the call
+	queue, err := repo.GetCallQueue(ctx, app.AppTimestamp, 42)
+	require.NoError(t, err)
+	require.Len(t, queue, 1)
+	require.Equal(t, formID, queue[0].FormID)
+	require.Equal(t, CallReasonScheduled, queue[0].Reason)
+	require.Equal(t, "555-0241", queue[0].HomePhone)
+	require.Nil(t, queue[0].LastApplication)
+	require.NotNil(t, queue[0].LastCall)
+	require.Equal(t, reached.ID, queue[0].LastCall.ID)
+	require.True(t, queue[0].Cleared)
+
+	// Its next round is due six weeks later and needs a new call
+	dueDay := app.AppTimestamp.AddDate(0, 0, 42)
+	if dueDay.Year() == app.AppTimestamp.Year() {
+		queue, err = repo.GetCallQueue(ctx, dueDay, 42)
+		require.NoError(t, err)
+		require.Len(t, queue, 1)
+		require.Equal(t, CallReasonDue, queue[0].Reason)
+		require.NotNil(t, queue[0].LastApplication)
+		require.Equal(t, chemID, queue[0].LastApplication.ChemUsed)
+		require.False(t, queue[0].Cleared)
+	}
+
+	queue, err = repo.GetCallQueue(ctx, app.AppTimestamp.AddDate(0, 0, 7), 42)
+	require.NoError(t, err)
+	require.Empty(t, queue)
+}
+
+func TestCallBefore_MovedApplication(t *testing.T) {
+	ctx := context.Background()
+	testDB := db.TestDB(t)
+	repo := NewFormsRepository(testDB)
+
+	userID := createTestUser(t, testDB)
+	chemID := createTestChemical(t, testDB, "lawn")
+
+	formID := createTestLawnForm(t, repo, CreateLawnFormInput{
+		CreatedBy:  userID,
+		FirstName:  "Moved",
+		CallBefore: true,
+	})
+
+	reached, err := repo.LogCall(ctx, formID, userID, CallOutcomeReached, "")
+	require.NoError(t, err)
+	app, err := repo.CreatePestApp(ctx, formID, userID, PestApp{
+		ChemUsed:      chemID,
+		AppTimestamp:  reached.CalledAt.Add(time.Hour),
+		Rate:          "2 oz/1000 sq ft",
+		AmountApplied: decimal.NewFromFloat(2.0),
+		LocationCode:  "1A",
+	})
+	require.NoError(t, err)
+
+	// The call does not cover the application once it is moved a week on
+	moved := app
+	moved.AppTimestamp = app.AppTimestamp.AddDate(0, 0, 7)
+	_, err = repo.UpdatePestAppById(ctx, formID, userID, moved)
+	require.ErrorIs(t, err, ErrCallRequired)
+
+	update := UpdateLawnFormInput{
+		FirstName:    "Moved",
+		LastName:     "Customer",
+		StreetNumber: "1",
+		StreetName:   "Test St",
+		Town:         "Town",
+		ZipCode:      "10001",
+		HomePhone:    "555-0001",
+		OtherPhone:   "555-0000",
+		CallBefore:   true,
+		LawnAreaSqFt: 1000,
+		Applications: []PestApp{moved},
+	}
+	_, err = repo.UpdateLawnFormById(ctx, formID, userID, update)
+	require.ErrorIs(t, err, ErrCallRequired)
+
+	// Left at its time it is still covered
+	update.Applications = []PestApp{app}
+	_, err = repo.UpdateLawnFormById(ctx, formID, userID, update)
+	require.NoError(t, err)
+}
+
+func TestCreateFormWithCall(t *testing.T) {
+	ctx := context.Background()
+	testDB := db.TestDB(t)
+	repo := NewFormsRepository(testDB)
+
+	userID := createTestUser(t, testDB)
+	chemID := createTestChemical(t, testDB, "lawn")
+
+	calledAt := time.Now().Add(-2 * time.Hour).Truncate(time.Second)
+	input := CreateLawnFormInput{
+		CreatedBy:    userID,
+		FirstName:    "Called",
+		LastName:     "Ahead",
+		StreetNumber: "9",
+		StreetName:   "Ringing Rd",
+		Town:         "Town",
+		ZipCode:      "10024",
+		HomePhone:    "555-0243",
+		OtherPhone:   "555-0244",
+		CallBefore:   true,
+		LawnAreaSqFt: 2000,
+		Applications: []PestApp{{
+			ChemUsed:      chemID,
+			AppTimestamp:  calledAt.Add(time.Hour),
+			Rate:          "2 oz/1000 sq ft",
+			AmountApplied: decimal.NewFromFloat(4.0),
+			LocationCode:  "1A",
+		}},
+		Force: true,
+	}
+
+	// Calls that did not reach the customer, or came after the application, do not clear it
+	input.Call = &CallLog{CalledAt: calledAt, Outcome: CallOutcomeVoicemail}
+	_, err := repo.CreateLawnForm(ctx, input)
+	require.ErrorIs(t, err, ErrCallRequired)
+
+	input.Call = &CallLog{CalledAt: calledAt.Add(90 * time.Minute), Outcome: CallOutcomeReached}
+	_, err = repo.CreateLawnForm(ctx, input)
+	require.ErrorIs(t, err, ErrCallRequired)
+
+	input.Call = &CallLog{CalledAt: time.Now().Add(time.Hour), Outcome: "texted"}
+	_, err = repo.CreateLawnForm(ctx, input)
+	var valErr *ValidationError
+	require.ErrorAs(t, err, &valErr)
+	require.Len(t, valErr.Fields, 2)
+	require.Equal(t, "call.outcome", valErr.Fields[0].Field)
+	require.Equal(t, "call.called_at", valErr.Fields[1].Field)
+
+	// A call that reached them before the application is logged with the form
+	input.Call = &CallLog{CalledAt: calledAt, Outcome: " Reached ", Notes: "ok for today"}
+	formID, err := repo.CreateLawnForm(ctx, input)
+	require.NoError(t, err)
+
+	calls, err := repo.ListCalls(ctx, formID)
+	require.NoError(t, err)
+	require.Len(t, calls, 1)
+	require.Equal(t, CallOutcomeReached, calls[0].Outcome)
+	require.Equal(t, "ok for today", calls[0].Notes)
+	require.Equal(t, userID, calls[0].CalledBy)
+	require.True(t, calledAt.Equal(calls[0].CalledAt))
+
+	got, err := repo.GetLawnFormById(ctx, formID, userID)
+	require.NoError(t, err)
+	require.Len(t, got.AppTimes, 1)
+
+	// The logged call also clears later applications within the window
+	app := got.AppTimes[0]
+	app.AppTimestamp = calledAt.Add(3 * time.Hour)
+	_, err = repo.CreatePestApp(ctx, formID, userID, app)
+	require.NoError(t, err)
+}
@@ -0,0 +1,318 @@
+package forms
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidCloneYear is returned when a season is cloned into a year that has already passed
+var ErrInvalidCloneYear = errors.New("forms cannot be cloned into a past year")
+
+// ClonedForm pairs a source form with the clone made from it
+type ClonedForm struct {
+	SourceID string
+	CloneID  string
+}
+
+// SkippedClone is a source form left alone because its client already has a form in the new season
+type SkippedClone struct {
+	SourceID   string
+	Candidates []DuplicateCandidate
+}
+
+// SeasonClone is the result of cloning one season's forms into the next
+type SeasonClone struct {
+	FromYear int
+	ToYear   int
+	Cloned   []ClonedForm
+	Skipped  []SkippedClone
+}
+
+// cloneSource is a form about to be cloned
+type cloneSource struct {
+	ID        string
+	FormType  string
+	CreatedBy string
+	Client    propertyClient
+}
+
+// cloneSourceColumns are the columns of forms f scanned by scanCloneSource
+const cloneSourceColumns = `
+	f.id,
+	f.form_type,
+	f.created_by,
+	f.first_name,
+	f.last_name,
+	f.street_number,
+	f.street_name,
+	f.town,
+	f.zip_code,
+	f.home_phone,
+	f.other_phone
+`
+
+func scanCloneSource(scanner interface{ Scan(...any) error }) (cloneSource, error) {
+	var source cloneSource
+	err := scanner.Scan(
+		&source.ID,
+		&source.FormType,
+		&source.CreatedBy,
+		&source.Client.FirstName,
+		&source.Client.LastName,
+		&source.Client.StreetNumber,
+		&source.Client.StreetName,
+		&source.Client.Town,
+		&source.Client.ZipCode,
+		&source.Client.HomePhone,
+		&source.Client.OtherPhone,
+	)
+	return source, err
+}
+
+// cloneForm copies a form's client fields, property link, subtype details and notes into a
+// new form owned by createdBy that links back to the source. Applications are not copied.
+// The clone is dated now, or at the start of season if that is later, so that it counts
+// towards that season. It is recorded as a create revision made by changedBy.
+// Returns the clone's ID.
+func cloneForm(
+	ctx context.Context,
+	tx *sql.Tx,
+	sourceID string,
+	createdBy string,
+	changedBy string,
+	season int,
+) (string, error) {
+	var cloneID string
+	err := tx.QueryRowContext(ctx, `
+		INSERT INTO forms (
+			created_by,
+			form_type,
+			first_name,
+			last_name,
+			street_number,
+			street_name,
+			town,
+			zip_code,
+			home_phone,
+			other_phone,
+			call_before,
+			is_holiday,
+			property_id,
+			cloned_from,
+			created_at,
+			updated_at
+		)
+		SELECT
+			$2,
+			form_type,
+			first_name,
+			last_name,
+			street_number,
+			street_name,
+			town,
+			zip_code,
+			home_phone,
+			other_phone,
+			call_before,
+			is_holiday,
+			property_id,
+			id,
+			GREATEST(NOW(), make_timestamptz($3, 1, 1, 0, 0, 0)),
+			GREATEST(NOW(), make_timestamptz($3, 1, 1, 0, 0, 0))
+		FROM forms
+		WHERE id = $1
+		RETURNING id
+	`, sourceID, createdBy, season).Scan(&cloneID)
+	if err != nil {
+		return "", fmt.Errorf("error cloning form %s: %w", sourceID, err)
+	}
+
+	// Only the statement matching the source's form type inserts a row
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO shrub_forms (form_id, flea_only)
+		SELECT $2, flea_only
+		FROM shrub_forms
+		WHERE form_id = $1
+	`, sourceID, cloneID)
+	if err != nil {
+		return "", fmt.Errorf("error cloning shrub details of form %s: %w", sourceID, err)
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO lawn_forms (form_id, lawn_area_sq_ft, fert_only)
+		SELECT $2, lawn_area_sq_ft, fert_only
+		FROM lawn_forms
+		WHERE form_id = $1
+	`, sourceID, cloneID)
+	if err != nil {
+		return "", fmt.Errorf("error cloning lawn details of form %s: %w", sourceID, err)
+	}
+
+	// Notes keep their author and dates so the history reads the same on the clone
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO notes (form_id, created_by, created_at, updated_at, note)
+		SELECT $2, created_by, created_at, updated_at, note
+		FROM notes
+		WHERE form_id = $1
+		ORDER BY created_at, id
+	`, sourceID, cloneID)
+	if err != nil {
+		return "", fmt.Errorf("error cloning notes of form %s: %w", sourceID, err)
+	}
+
+	if _, err := recordRevision(ctx, tx, cloneID, changedBy, RevisionActionCreate, nil); err != nil {
+		return "", err
+	}
+
+	return cloneID, nil
+}
+
+// CloneFormById creates a new form owned by the given user with the client fields, subtype
+// details and notes of one of their forms, but no applications. The clone links back to the
+// source through ClonedFrom.
+// Unless force is set, it returns a DuplicateFormsError if the client already has a form of
+// this type this season, as CreateShrubForm and CreateLawnForm do.
+// Returns the clone's ID upon success.
+// It returns sql.ErrNoRows if the form does not exist, is in the trash or is not owned by the user.
+func (r *FormsRepository) CloneFormById(
+	ctx context.Context,
+	formID string,
+	userID string,
+	force bool,
+) (string, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	source, err := scanCloneSource(tx.QueryRowContext(ctx, `
+		SELECT `+cloneSourceColumns+`
+		FROM forms f
+		WHERE f.id = $1 AND f.created_by = $2 AND f.deleted_at IS NULL
+	`, formID, userID))
+	if err != nil {
+		// sql.ErrNoRows → not found or not owned
+		return "", err
+	}
+
+	if !force {
+		if err := checkDuplicates(ctx, tx, source.FormType, source.Client); err != nil {
+			return "", err
+		}
+	}
+
+	cloneID, err := cloneForm(ctx, tx, source.ID, userID, userID, time.Now().Year())
+	if err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return cloneID, nil
+}
+
+// CloneFormsIntoNextYear clones every live form with an application in the given year into
+// the following year. Each clone keeps the owner of its source and is recorded as created by
+// clonedBy. The next season can be prepared before it starts, its clones being dated January 1.
+// Forms that already have a live clone are left out, so the operation can be re-run safely.
+// Forms whose client already has a form of the same type in the new season, including one cloned
+// earlier in the same run, are skipped and reported with the matching forms.
+// Sources are cloned oldest first. The operation is atomic.
+// It returns ErrInvalidCloneYear if year + 1 is already over.
+func (r *FormsRepository) CloneFormsIntoNextYear(
+	ctx context.Context,
+	year int,
+	clonedBy string,
+) (SeasonClone, error) {
+	result := SeasonClone{
+		FromYear: year,
+		ToYear:   year + 1,
+		Cloned:   []ClonedForm{},
+		Skipped:  []SkippedClone{},
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return SeasonClone{}, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var currentYear int
+	err = tx.QueryRowContext(ctx, `SELECT EXTRACT(YEAR FROM NOW())::int`).Scan(&currentYear)
+	if err != nil {
+		return SeasonClone{}, fmt.Errorf("error reading the current year: %w", err)
+	}
+	if result.ToYear < currentYear {
+		return SeasonClone{}, fmt.Errorf("%w: cannot clone %d into %d during %d", ErrInvalidCloneYear, year, result.ToYear, currentYear)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT `+cloneSourceColumns+`
+		FROM forms f
+		WHERE f.deleted_at IS NULL
+		  AND EXISTS (
+			  SELECT 1
+			  FROM pesticide_applications pa
+			  WHERE pa.form_id = f.id
+			    AND pa.app_timestamp >= make_timestamptz($1, 1, 1, 0, 0, 0)
+			    AND pa.app_timestamp < make_timestamptz($1 + 1, 1, 1, 0, 0, 0)
+		  )
+		  AND NOT EXISTS (
+			  SELECT 1
+			  FROM forms c
+			  WHERE c.cloned_from = f.id AND c.deleted_at IS NULL
+		  )
+		ORDER BY f.created_at, f.id
+		FOR UPDATE OF f
+	`, year)
+	if err != nil {
+		return SeasonClone{}, fmt.Errorf("error listing forms to clone: %w", err)
+	}
+	var sources []cloneSource
+	for rows.Next() {
+		source, err := scanCloneSource(rows)
+		if err != nil {
+			rows.Close()
+			return SeasonClone{}, fmt.Errorf("error scanning form to clone: %w", err)
+		}
+		sources = append(sources, source)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return SeasonClone{}, fmt.Errorf("error after listing forms to clone: %w", err)
+	}
+
+	for _, source := range sources {
+		candidates, err := findDuplicateCandidates(ctx, tx, source.FormType, source.Client, result.ToYear)
+		if err != nil {
+			return SeasonClone{}, err
+		}
+		if len(candidates) > 0 {
+			result.Skipped = append(result.Skipped, SkippedClone{
+				SourceID:   source.ID,
+				Candidates: candidates,
+			})
+			continue
+		}
+
+		cloneID, err := cloneForm(ctx, tx, source.ID, source.CreatedBy, clonedBy, result.ToYear)
+		if err != nil {
+			return SeasonClone{}, err
+		}
+		result.Cloned = append(result.Cloned, ClonedForm{
+			SourceID: source.ID,
+			CloneID:  cloneID,
+		})
+	}
+
+	if err := tx.Commit(); err != nil {
+		return SeasonClone{}, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,183 @@
+package forms
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/db"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloneFormById(t *testing.T) {
+	ctx := context.Background()
+	testDB := db.TestDB(t)
+	repo := NewFormsRepository(testDB)
+
+	userID := createTestUser(t, testDB)
+	otherUserID := createTestUser(t, testDB)
+	chemID := createTestChemical(t, testDB, "lawn")
+
+	sourceID, err := repo.CreateLawnForm(ctx, CreateLawnFormInput{
+		CreatedBy:    userID,
+		FirstName:    "Casey",
+		LastName:     "Clone",
+		StreetNumber: "14",
+		StreetName:   "Spring Rd",
+		Town:         "Town",
+		ZipCode:      "10001",
+		HomePhone:    "555-0014",
+		OtherPhone:   "",
+		CallBefore:   true,
+		LawnAreaSqFt: 3200,
+		FertOnly:     true,
+		Applications: []PestApp{
+			{
+				ChemUsed:      chemID,
+				AppTimestamp:  time.Now(),
+				Rate:          "2 oz/1000 sq ft",
+				AmountApplied: decimal.NewFromFloat(6.4),
+				LocationCode:  "1A",
+			},
+		},
+	})
+	require.NoError(t, err)
+	_, err = repo.CreateNote(ctx, sourceID, userID, "Gate code 1234")
+	require.NoError(t, err)
+
+	// The source is from this season, so the clone looks like a duplicate
+	_, err = repo.CloneFormById(ctx, sourceID, userID, false)
+	var dupErr *DuplicateFormsError
+	require.ErrorAs(t, err, &dupErr)
+	require.Equal(t, sourceID, dupErr.Candidates[0].ID)
+
+	cloneID, err := repo.CloneFormById(ctx, sourceID, userID, true)
+	require.NoError(t, err)
+	require.NotEqual(t, sourceID, cloneID)
+
+	clone, err := repo.GetLawnFormById(ctx, cloneID, userID)
+	require.NoError(t, err)
+	require.Equal(t, sourceID, clone.ClonedFrom)
+	require.Equal(t, "Casey", clone.FirstName)
+	require.Equal(t, "Spring Rd", clone.StreetName)
+	require.True(t, clone.CallBefore)
+	require.Equal(t, 3200, clone.LawnAreaSqFt)
+	require.True(t, clone.FertOnly)
+	require.Empty(t, clone.AppTimes)
+	require.Len(t, clone.Notes, 1)
+	require.Equal(t, "Gate code 1234", clone.Notes[0].Message)
+
+	revisions, err := repo.ListAllFormRevisions(ctx, cloneID)
+	require.NoError(t, err)
+	require.Len(t, revisions, 1)
+	require.Equal(t, RevisionActionCreate, revisions[0].Action)
+
+	// Only the owner may clone a form
+	_, err = repo.CloneFormById(ctx, sourceID, otherUserID, true)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestCloneFormsIntoNextYear(t *testing.T) {
+	ctx := context.Background()
+	testDB := db.TestDB(t)
+	repo := NewFormsRepository(testDB)
+
+	userID := createTestUser(t, testDB)
+	adminID := createTestUser(t, testDB)
+	chemID := createTestChemical(t, testDB, "shrub")
+
+	lastYear := time.Now().Year() - 1
+	lastSeason := time.Date(lastYear, time.June, 1, 9, 0, 0, 0, time.UTC)
+
+	newShrubForm := func(firstName, streetNumber, homePhone string, apps []PestApp) string {
+		formID, err := repo.CreateShrubForm(ctx, CreateShrubFormInput{
+			CreatedBy:    userID,
+			FirstName:    firstName,
+			LastName:     "Season",
+			StreetNumber: streetNumber,
+			StreetName:   "Harvest Ln",
+			Town:         "Town",
+			ZipCode:      "10001",
+			HomePhone:    homePhone,
+			OtherPhone:   "",
+			FleaOnly:     true,
+			Applications: apps,
+		})
+		require.NoError(t, err)
+		return formID
+	}
+	lastSeasonApp := []PestApp{
+		{
+			ChemUsed:      chemID,
+			AppTimestamp:  lastSeason,
+			Rate:          "1 oz/gal",
+			AmountApplied: decimal.NewFromFloat(2.0),
+			LocationCode:  "1A",
+		},
+	}
+
+	treatedID := newShrubForm("Avery", "1", "555-1001", lastSeasonApp)
+	returningID := newShrubForm("Blake", "2", "555-1002", lastSeasonApp)
+	untreatedID := newShrubForm("Cameron", "3", "555-1003", nil)
+
+	// Move the forms into last season
+	_, err := testDB.Exec(`UPDATE forms SET created_at = $1`, lastSeason)
+	require.NoError(t, err)
+
+	// Blake already has a form this season
+	currentID := newShrubForm("Blake", "2", "555-1002", nil)
+
+	_, err = repo.CloneFormsIntoNextYear(ctx, lastYear-1, adminID)
+	require.ErrorIs(t, err, ErrInvalidCloneYear)
+
+	result, err := repo.CloneFormsIntoNextYear(ctx, lastYear, adminID)
+	require.NoError(t, err)
+	require.Equal(t, lastYear+1, result.ToYear)
+	require.Len(t, result.Cloned, 1)
+	require.Equal(t, treatedID, result.Cloned[0].SourceID)
+	require.Len(t, result.Skipped, 1)
+	require.Equal(t, returningID, result.Skipped[0].SourceID)
+	require.Equal(t, currentID, result.Skipped[0].Candidates[0].ID)
+
+	clone, err := repo.GetShrubFormById(ctx, result.Cloned[0].CloneID, userID)
+	require.NoError(t, err)
+	require.Equal(t, treatedID, clone.ClonedFrom)
+	require.Equal(t, "Avery", clone.FirstName)
+	require.True(t, clone.FleaOnly)
+	require.Empty(t, clone.AppTimes)
+
+	revisions, err := repo.ListAllFormRevisions(ctx, clone.ID)
+	require.NoError(t, err)
+	require.Equal(t, adminID, revisions[0].ChangedBy)
+
+	// Re-running does not clone the same form twice
+	result, err = repo.CloneFormsIntoNextYear(ctx, lastYear, adminID)
+	require.NoError(t, err)
+	require.Empty(t, result.Cloned)
+	for _, skipped := range result.Skipped {
+		require.NotEqual(t, untreatedID, skipped.SourceID)
+	}
+
+	// The next season can be prepared before it starts
+	upcomingID := newShrubForm("Dana", "4", "555-1004", []PestApp{
+		{
+			ChemUsed:      chemID,
+			AppTimestamp:  time.Now(),
+			Rate:          "1 oz/gal",
+			AmountApplied: decimal.NewFromFloat(2.0),
+			LocationCode:  "1A",
+		},
+	})
+	result, err = repo.CloneFormsIntoNextYear(ctx, lastYear+1, adminID)
+	require.NoError(t, err)
+	require.Len(t, result.Cloned, 1)
+	require.Equal(t, upcomingID, result.Cloned[0].SourceID)
+	require.Empty(t, result.Skipped)
+
+	var cloneYear int
+	err = testDB.QueryRow(`SELECT EXTRACT(YEAR FROM created_at)::int FROM forms WHERE id = $1`, result.Cloned[0].CloneID).Scan(&cloneYear)
+	require.NoError(t, err)
+	require.Equal(t, lastYear+2, cloneYear)
+}
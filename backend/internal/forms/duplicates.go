@@ -0,0 +1,361 @@
+package forms
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// ErrLikelyDuplicate is wrapped by DuplicateFormsError
+var ErrLikelyDuplicate = errors.New("form looks like a duplicate of an existing form")
+
+// Reasons two forms are considered likely duplicates
+const (
+	// DuplicateReasonAddress means the house number and zip code match and the street names are similar
+	DuplicateReasonAddress = "address"
+	// DuplicateReasonPhoneAndName means a phone number matches and the client names are similar
+	DuplicateReasonPhoneAndName = "phone_and_name"
+)
+
+// pg_trgm similarity thresholds. Street names are short, so a single typo already
+// costs a lot of trigrams; 0.3 is pg_trgm's own default and still needs the
+// house number and zip code to match exactly.
+const (
+	duplicateStreetSimilarity = 0.3
+	duplicateNameSimilarity   = 0.5
+)
+
+// maxDuplicateCandidates caps the candidates returned when a new form is rejected
+const maxDuplicateCandidates = 10
+
+// DuplicateCandidate is an existing form that looks like the same client and address
+type DuplicateCandidate struct {
+	ID           string
+	FormType     string
+	CreatedBy    string
+	CreatedAt    time.Time
+	FirstName    string
+	LastName     string
+	StreetNumber string
+	StreetName   string
+	Town         string
+	ZipCode      string
+	HomePhone    string
+	OtherPhone   string
+	// Reasons lists why the form matched, see DuplicateReasonAddress and DuplicateReasonPhoneAndName
+	Reasons []string
+}
+
+// DuplicateCluster is a group of live forms of one type and season that look like the same client
+type DuplicateCluster struct {
+	FormType string
+	Forms    []DuplicateCandidate
+}
+
+// DuplicateFormsError is returned when a new form looks like a form already created this season.
+// It unwraps to ErrLikelyDuplicate.
+type DuplicateFormsError struct {
+	Candidates []DuplicateCandidate
+}
+
+func (e *DuplicateFormsError) Error() string {
+	return fmt.Sprintf("%s (%d candidate(s))", ErrLikelyDuplicate, len(e.Candidates))
+}
+
+func (e *DuplicateFormsError) Unwrap() error {
+	return ErrLikelyDuplicate
+}
+
+// duplicateKeys holds the SQL expressions of a form's values compared for duplicate detection
+type duplicateKeys struct {
+	streetNumber string
+	street       string
+	zipCode      string
+	name         string
+	phones       string
+}
+
+// formDuplicateKeys returns the duplicate detection keys of the row aliased as alias.
+// The row needs the client columns of forms.
+func formDuplicateKeys(alias string) duplicateKeys {
+	return duplicateKeys{
+		streetNumber: fmt.Sprintf("LOWER(TRIM(%s.street_number))", alias),
+		street:       fmt.Sprintf("normalize_street(%s.street_name)", alias),
+		zipCode:      fmt.Sprintf("TRIM(%s.zip_code)", alias),
+		name:         fmt.Sprintf("LOWER(%[1]s.first_name || ' ' || %[1]s.last_name)", alias),
+		phones:       fmt.Sprintf("ARRAY[phone_digits(%[1]s.home_phone), phone_digits(%[1]s.other_phone)]", alias),
+	}
+}
+
+// addressMatch returns the condition matching a house number and zip code with a similar street name
+func (k duplicateKeys) addressMatch(other duplicateKeys) string {
+	return fmt.Sprintf(
+		"(%s = %s AND %s = %s AND similarity(%s, %s) >= %v)",
+		k.zipCode, other.zipCode,
+		k.streetNumber, other.streetNumber,
+		k.street, other.street, duplicateStreetSimilarity,
+	)
+}
+
+// phoneAndNameMatch returns the condition matching a shared phone number with a similar client name
+func (k duplicateKeys) phoneAndNameMatch(other duplicateKeys) string {
+	return fmt.Sprintf(
+		"(%s && %s AND similarity(%s, %s) >= %v)",
+		k.phones, other.phones,
+		k.name, other.name, duplicateNameSimilarity,
+	)
+}
+
+// findDuplicateCandidates returns live forms of the same type created in the season's calendar
+// year that look like the given client, most recent first.
+func findDuplicateCandidates(
+	ctx context.Context,
+	tx *sql.Tx,
+	formType string,
+	client propertyClient,
+	season int,
+) ([]DuplicateCandidate, error) {
+	existing := formDuplicateKeys("f")
+	incoming := formDuplicateKeys("i")
+	addressMatch := existing.addressMatch(incoming)
+	phoneAndNameMatch := existing.phoneAndNameMatch(incoming)
+
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf(`
+		WITH i AS (
+			SELECT
+				$1::text AS first_name,
+				$2::text AS last_name,
+				$3::text AS street_number,
+				$4::text AS street_name,
+				$5::text AS zip_code,
+				$6::text AS home_phone,
+				$7::text AS other_phone
+		)
+		SELECT
+			f.id,
+			f.form_type,
+			f.created_by,
+			f.created_at,
+			f.first_name,
+			f.last_name,
+			f.street_number,
+			f.street_name,
+			f.town,
+			f.zip_code,
+			f.home_phone,
+			f.other_phone,
+			%[1]s,
+			%[2]s
+		FROM forms f, i
+		WHERE f.deleted_at IS NULL
+		  AND f.form_type = $8
+		  AND f.created_at >= make_timestamptz($9, 1, 1, 0, 0, 0)
+		  AND f.created_at < make_timestamptz($9 + 1, 1, 1, 0, 0, 0)
+		  AND (%[1]s OR %[2]s)
+		ORDER BY f.created_at DESC, f.id
+		LIMIT %[3]d
+	`, addressMatch, phoneAndNameMatch, maxDuplicateCandidates),
+		client.FirstName,
+		client.LastName,
+		client.StreetNumber,
+		client.StreetName,
+		client.ZipCode,
+		client.HomePhone,
+		client.OtherPhone,
+		formType,
+		season,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error searching for duplicate forms: %w", err)
+	}
+	defer rows.Close()
+
+	var candidates []DuplicateCandidate
+	for rows.Next() {
+		var (
+			candidate           DuplicateCandidate
+			addressMatched      bool
+			phoneAndNameMatched bool
+		)
+		err := rows.Scan(
+			&candidate.ID,
+			&candidate.FormType,
+			&candidate.CreatedBy,
+			&candidate.CreatedAt,
+			&candidate.FirstName,
+			&candidate.LastName,
+			&candidate.StreetNumber,
+			&candidate.StreetName,
+			&candidate.Town,
+			&candidate.ZipCode,
+			&candidate.HomePhone,
+			&candidate.OtherPhone,
+			&addressMatched,
+			&phoneAndNameMatched,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning duplicate form: %w", err)
+		}
+		if addressMatched {
+			candidate.Reasons = append(candidate.Reasons, DuplicateReasonAddress)
+		}
+		if phoneAndNameMatched {
+			candidate.Reasons = append(candidate.Reasons, DuplicateReasonPhoneAndName)
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after duplicate forms query: %w", err)
+	}
+
+	return candidates, nil
+}
+
+// checkDuplicates returns a DuplicateFormsError if the client already has a form of this type this season
+func checkDuplicates(ctx context.Context, tx *sql.Tx, formType string, client propertyClient) error {
+	candidates, err := findDuplicateCandidates(ctx, tx, formType, client, time.Now().Year())
+	if err != nil {
+		return err
+	}
+	if len(candidates) > 0 {
+		return &DuplicateFormsError{Candidates: candidates}
+	}
+	return nil
+}
+
+// ListDuplicateClusters groups the live forms created in the given year into clusters of
+// likely duplicates. Forms are only grouped with forms of the same type, and a form joins a
+// cluster when it matches any member. formType may be empty to report both types.
+// Clusters are ordered by their oldest form, and forms within a cluster oldest first.
+func (r *FormsRepository) ListDuplicateClusters(
+	ctx context.Context,
+	year int,
+	formType string,
+) ([]DuplicateCluster, error) {
+	a := formDuplicateKeys("a")
+	b := formDuplicateKeys("b")
+
+	// Pairs are found by address and by phone separately so each half can use an equi-join
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(`
+		WITH season AS (
+			SELECT f.*
+			FROM forms f
+			WHERE f.deleted_at IS NULL
+			  AND f.created_at >= make_timestamptz($1, 1, 1, 0, 0, 0)
+			  AND f.created_at < make_timestamptz($1 + 1, 1, 1, 0, 0, 0)
+			  AND ($2 = '' OR f.form_type = $2)
+		)
+		SELECT a.id, b.id
+		FROM season a
+		JOIN season b ON b.form_type = a.form_type AND b.id > a.id
+		WHERE %s
+		UNION
+		SELECT a.id, b.id
+		FROM season a
+		JOIN season b ON b.form_type = a.form_type AND b.id > a.id
+		WHERE %s
+	`, a.addressMatch(b), a.phoneAndNameMatch(b)), year, formType)
+	if err != nil {
+		return nil, fmt.Errorf("error finding duplicate form pairs: %w", err)
+	}
+	defer rows.Close()
+
+	// Union-find over form IDs
+	parent := map[string]string{}
+	var find func(id string) string
+	find = func(id string) string {
+		if parent[id] == "" || parent[id] == id {
+			parent[id] = id
+			return id
+		}
+		root := find(parent[id])
+		parent[id] = root
+		return root
+	}
+
+	for rows.Next() {
+		var aID, bID string
+		if err := rows.Scan(&aID, &bID); err != nil {
+			return nil, fmt.Errorf("error scanning duplicate form pair: %w", err)
+		}
+		parent[find(aID)] = find(bID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after duplicate form pairs query: %w", err)
+	}
+
+	if len(parent) == 0 {
+		return []DuplicateCluster{}, nil
+	}
+
+	formIDs := make([]string, 0, len(parent))
+	for id := range parent {
+		formIDs = append(formIDs, id)
+	}
+
+	formRows, err := r.db.QueryContext(ctx, `
+		SELECT
+			f.id,
+			f.form_type,
+			f.created_by,
+			f.created_at,
+			f.first_name,
+			f.last_name,
+			f.street_number,
+			f.street_name,
+			f.town,
+			f.zip_code,
+			f.home_phone,
+			f.other_phone
+		FROM forms f
+		WHERE f.id = ANY($1::uuid[])
+		ORDER BY f.created_at, f.id
+	`, pq.Array(formIDs))
+	if err != nil {
+		return nil, fmt.Errorf("error fetching duplicate forms: %w", err)
+	}
+	defer formRows.Close()
+
+	clusterIndex := map[string]int{}
+	var clusters []DuplicateCluster
+	for formRows.Next() {
+		var form DuplicateCandidate
+		err := formRows.Scan(
+			&form.ID,
+			&form.FormType,
+			&form.CreatedBy,
+			&form.CreatedAt,
+			&form.FirstName,
+			&form.LastName,
+			&form.StreetNumber,
+			&form.StreetName,
+			&form.Town,
+			&form.ZipCode,
+			&form.HomePhone,
+			&form.OtherPhone,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning duplicate form: %w", err)
+		}
+
+		root := find(form.ID)
+		i, ok := clusterIndex[root]
+		if !ok {
+			i = len(clusters)
+			clusterIndex[root] = i
+			clusters = append(clusters, DuplicateCluster{FormType: form.FormType})
+		}
+		// Forms arrive oldest first, which also orders clusters by their oldest form
+		clusters[i].Forms = append(clusters[i].Forms, form)
+	}
+	if err := formRows.Err(); err != nil {
+		return nil, fmt.Errorf("error after duplicate forms query: %w", err)
+	}
+
+	return clusters, nil
+}
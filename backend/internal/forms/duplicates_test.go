@@ -0,0 +1,170 @@
+package forms
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/db"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateForm_RejectsLikelyDuplicate(t *testing.T) {
+	ctx := context.Background()
+	testDB := db.TestDB(t)
+	repo := NewFormsRepository(testDB)
+
+	userID := createTestUser(t, testDB)
+
+	existingID, err := repo.CreateShrubForm(ctx, CreateShrubFormInput{
+		CreatedBy:    userID,
+		FirstName:    "Dana",
+		LastName:     "Double",
+		StreetNumber: "12",
+		StreetName:   "Main Street",
+		Town:         "Town",
+		ZipCode:      "10001",
+		HomePhone:    "(555) 010-0001",
+		OtherPhone:   "",
+	})
+	require.NoError(t, err)
+
+	// Misspelled street at the same house number and zip
+	_, err = repo.CreateShrubForm(ctx, CreateShrubFormInput{
+		CreatedBy:    userID,
+		FirstName:    "Dana",
+		LastName:     "Double",
+		StreetNumber: "12",
+		StreetName:   "Mian St.",
+		Town:         "Town",
+		ZipCode:      "10001",
+	})
+	require.ErrorIs(t, err, ErrLikelyDuplicate)
+	var dupErr *DuplicateFormsError
+	require.True(t, errors.As(err, &dupErr))
+	require.Len(t, dupErr.Candidates, 1)
+	require.Equal(t, existingID, dupErr.Candidates[0].ID)
+	require.Equal(t, []string{DuplicateReasonAddress}, dupErr.Candidates[0].Reasons)
+
+	// Same phone and a similar name somewhere else
+	_, err = repo.CreateShrubForm(ctx, CreateShrubFormInput{
+		CreatedBy:    userID,
+		FirstName:    "Dana",
+		LastName:     "Doubel",
+		StreetNumber: "40",
+		StreetName:   "Other Rd",
+		Town:         "Town",
+		ZipCode:      "10002",
+		HomePhone:    "555-010-0001",
+	})
+	require.ErrorIs(t, err, ErrLikelyDuplicate)
+	require.True(t, errors.As(err, &dupErr))
+	require.Equal(t, []string{DuplicateReasonPhoneAndName}, dupErr.Candidates[0].Reasons)
+
+	// A lawn form for the same house is not a duplicate of the shrub form
+	_, err = repo.CreateLawnForm(ctx, CreateLawnFormInput{
+		CreatedBy:    userID,
+		FirstName:    "Dana",
+		LastName:     "Double",
+		StreetNumber: "12",
+		StreetName:   "Main Street",
+		Town:         "Town",
+		ZipCode:      "10001",
+		LawnAreaSqFt: 1000,
+	})
+	require.NoError(t, err)
+
+	// Neighbours are not duplicates
+	_, err = repo.CreateShrubForm(ctx, CreateShrubFormInput{
+		CreatedBy:    userID,
+		FirstName:    "Nick",
+		LastName:     "Next",
+		StreetNumber: "14",
+		StreetName:   "Main Street",
+		Town:         "Town",
+		ZipCode:      "10001",
+	})
+	require.NoError(t, err)
+
+	// Force creates it anyway
+	forcedID, err := repo.CreateShrubForm(ctx, CreateShrubFormInput{
+		CreatedBy:    userID,
+		FirstName:    "Dana",
+		LastName:     "Double",
+		StreetNumber: "12",
+		StreetName:   "Mian St.",
+		Town:         "Town",
+		ZipCode:      "10001",
+		Force:        true,
+	})
+	require.NoError(t, err)
+
+	// Trashed forms are not candidates
+	require.NoError(t, repo.DeleteFormById(ctx, forcedID, userID))
+	require.NoError(t, repo.DeleteFormById(ctx, existingID, userID))
+	_, err = repo.CreateShrubForm(ctx, CreateShrubFormInput{
+		CreatedBy:    userID,
+		FirstName:    "Dana",
+		LastName:     "Double",
+		StreetNumber: "12",
+		StreetName:   "Main St",
+		Town:         "Town",
+		ZipCode:      "10001",
+	})
+	require.NoError(t, err)
+}
+
+func TestListDuplicateClusters(t *testing.T) {
+	ctx := context.Background()
+	testDB := db.TestDB(t)
+	repo := NewFormsRepository(testDB)
+
+	userID := createTestUser(t, testDB)
+
+	create := func(firstName string, streetNumber string, streetName string, phone string) string {
+		t.Helper()
+		formID, err := repo.CreateLawnForm(ctx, CreateLawnFormInput{
+			CreatedBy:    userID,
+			FirstName:    firstName,
+			LastName:     "Cluster",
+			StreetNumber: streetNumber,
+			StreetName:   streetName,
+			Town:         "Town",
+			ZipCode:      "10001",
+			HomePhone:    phone,
+			LawnAreaSqFt: 1000,
+			Force:        true,
+		})
+		require.NoError(t, err)
+		return formID
+	}
+
+	// a and b share an address, b and c share a phone, so all three are one cluster
+	a := create("Avery", "5", "Birch Lane", "555-000-1111")
+	b := create("Blake", "5", "Brich Ln", "555-000-2222")
+	c := create("Blake", "99", "Elsewhere Ave", "555-000-2222")
+	create("Unrelated", "7", "Birch Lane", "555-000-3333")
+
+	var year int
+	err := testDB.QueryRow(`SELECT date_part('year', NOW())::int`).Scan(&year)
+	require.NoError(t, err)
+
+	clusters, err := repo.ListDuplicateClusters(ctx, year, "")
+	require.NoError(t, err)
+	require.Len(t, clusters, 1)
+	require.Equal(t, "lawn", clusters[0].FormType)
+
+	ids := make([]string, 0, len(clusters[0].Forms))
+	for _, form := range clusters[0].Forms {
+		ids = append(ids, form.ID)
+	}
+	require.Equal(t, []string{a, b, c}, ids)
+
+	clusters, err = repo.ListDuplicateClusters(ctx, year, "shrub")
+	require.NoError(t, err)
+	require.Empty(t, clusters)
+
+	clusters, err = repo.ListDuplicateClusters(ctx, year-1, "")
+	require.NoError(t, err)
+	require.Empty(t, clusters)
+}
@@ -0,0 +1,352 @@
+package forms
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Export layouts
+const (
+	// ExportLayoutApplications exports one row per pesticide application
+	ExportLayoutApplications = "applications"
+	// ExportLayoutForms exports one row per form
+	ExportLayoutForms = "forms"
+)
+
+// ErrInvalidExportLayout is returned when an export is asked for an unknown layout
+var ErrInvalidExportLayout = errors.New("layout must be 'applications' or 'forms'")
+
+// ExportRow is a single row of a form export.
+// Form carries the client fields and application dates but no applications or notes.
+type ExportRow struct {
+	Form         Form
+	FleaOnly     *bool
+	LawnAreaSqFt *int
+	FertOnly     *bool
+	// ApplicationCount is the number of applications on the form
+	ApplicationCount int
+	// Application is set on application rows; forms without applications export a single row without one
+	Application *ExportApplication
+}
+
+// ExportApplication is a pesticide application joined with its chemical
+type ExportApplication struct {
+	ID            int
+	AppTimestamp  time.Time
+	Rate          string
+	AmountApplied decimal.Decimal
+	LocationCode  string
+	ChemUsed      int
+	BrandName     string
+	ChemicalName  string
+	EpaRegNo      string
+	Unit          string
+	// Structured rate and label checks, see PestApp
+	StructuredRate *StructuredRate
+	ExpectedAmount *decimal.Decimal
+	OverLabelMax   bool
+	// Method and weather, see PestApp
+	ApplicationMethod string
+	Weather           Weather
+	// ApplicatorLicenseNumber is the license the application was recorded under, see PestApp
+	ApplicatorLicenseNumber string
+	// AppliedByName is the full name of the applicator, see PestApp
+	AppliedByName string
+}
+
+// ExportForms streams the forms matching opts to fn, one row at a time, in the order the same
+// listing would return them. userID restricts the export to the forms that user created or made an
+// application on; when empty every user's forms are exported. Pagination options are ignored, every matching form is exported.
+// In ExportLayoutApplications a form yields one row per application, oldest first.
+// It stops at and returns the first error returned by fn.
+// It returns ErrInvalidExportLayout for an unknown layout.
+func (r *FormsRepository) ExportForms(
+	ctx context.Context,
+	userID string,
+	opts ListFormsOptions,
+	layout string,
+	fn func(ExportRow) error,
+) error {
+	if layout != ExportLayoutApplications && layout != ExportLayoutForms {
+		return ErrInvalidExportLayout
+	}
+
+	whereConditions := []string{deletedCondition(opts)}
+	args := []any{}
+	argIndex := 1
+
+	if userID != "" {
+		whereConditions = append(whereConditions, visibleToCondition(fmt.Sprintf("$%d::uuid", argIndex)))
+		args = append(args, userID)
+		argIndex++
+	}
+
+	search := newFormSearch(opts.Query, argIndex)
+	if search != nil {
+		whereConditions = append(whereConditions, search.condition)
+		args = append(args, search.args...)
+		argIndex += len(search.args)
+	}
+	sort := resolveFormsSort(opts, search)
+
+	whereConditions, args, _ = appendFormFilters(opts, whereConditions, args, argIndex)
+
+	applicationColumns := `
+			NULL::int,
+			NULL::timestamptz,
+			NULL::text,
+			NULL::numeric,
+			NULL::text,
+			NULL::int,
+			NULL::text,
+			NULL::text,
+			NULL::text,
+			NULL::text,
+			NULL::numeric,
+			NULL::text,
+			NULL::text,
+			NULL::numeric,
+			NULL::boolean,
+			NULL::text,
+			NULL::numeric,
+			NULL::text,
+			NULL::numeric,
+			NULL::text,
+			NULL::text,
+			NULL::text`
+	applicationJoin := ""
+	orderBy := sort.orderClause(nil)
+	if layout == ExportLayoutApplications {
+		applicationColumns = `
+			pa.id,
+			pa.app_timestamp,
+			pa.rate,
+			pa.amount_applied,
+			pa.location_code,
+			c.id,
+			c.brand_name,
+			c.chemical_name,
+			c.epa_reg_no,
+			c.unit,
+			pa.rate_amount,
+			pa.rate_unit,
+			pa.rate_basis,
+			pa.expected_amount,
+			pa.over_label_max,
+			pa.application_method,
+			pa.wind_speed_mph,
+			pa.wind_direction,
+			pa.temperature_f,
+			pa.sky_conditions,
+			pa.applicator_license_number,
+			applicator.first_name || ' ' || applicator.last_name`
+		applicationJoin = `
+		LEFT JOIN pesticide_applications pa ON pa.form_id = f.id
+		LEFT JOIN chemicals c ON c.id = pa.chem_used
+		LEFT JOIN users applicator ON applicator.id = pa.applied_by`
+		orderBy += ", pa.app_timestamp, pa.id"
+	}
+
+	query := fmt.Sprintf(`
+		WITH form_app_dates AS (
+			SELECT
+				form_id,
+				MIN(app_timestamp) as first_app_date,
+				MAX(app_timestamp) as last_app_date,
+				COUNT(*) as app_count
+			FROM pesticide_applications
+			GROUP BY form_id
+		)
+		SELECT
+			f.id,
+			f.created_by,
+			f.created_at,
+			f.form_type,
+			f.updated_at,
+			f.first_name,
+			f.last_name,
+			f.street_number,
+			f.street_name,
+			f.town,
+			f.zip_code,
+			f.home_phone,
+			f.other_phone,
+			f.call_before,
+			f.is_holiday,
+			COALESCE(f.property_id::text, ''),
+			COALESCE(f.cloned_from::text, ''),
+			sf.flea_only,
+			lf.lawn_area_sq_ft,
+			lf.fert_only,
+			COALESCE(fad.first_app_date, '1970-01-01 00:00:00'::timestamp) as first_app_date,
+			COALESCE(fad.last_app_date, '1970-01-01 00:00:00'::timestamp) as last_app_date,
+			COALESCE(fad.app_count, 0),%s
+		FROM forms f
+		LEFT JOIN shrub_forms sf ON f.id = sf.form_id
+		LEFT JOIN lawn_forms lf ON f.id = lf.form_id
+		LEFT JOIN form_app_dates fad ON f.id = fad.form_id%s
+		WHERE %s
+		ORDER BY %s
+	`, applicationColumns, applicationJoin, strings.Join(whereConditions, " AND "), orderBy)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query rows for forms export: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			row   ExportRow
+			shrub shrubRow
+			lawn  lawnRow
+
+			appID          sql.NullInt64
+			appTimestamp   sql.NullTime
+			rate           sql.NullString
+			amountApplied  decimal.NullDecimal
+			locationCode   sql.NullString
+			chemID         sql.NullInt64
+			brandName      sql.NullString
+			chemicalName   sql.NullString
+			epaRegNo       sql.NullString
+			unit           sql.NullString
+			rateAmount     decimal.NullDecimal
+			rateUnit       sql.NullString
+			rateBasis      sql.NullString
+			expectedAmount decimal.NullDecimal
+			overLabelMax   sql.NullBool
+			appMethod      sql.NullString
+			windSpeedMph   decimal.NullDecimal
+			windDirection  sql.NullString
+			temperatureF   decimal.NullDecimal
+			skyConditions  sql.NullString
+			licenseNumber  sql.NullString
+			applicatorName sql.NullString
+		)
+		form := &row.Form
+		err := rows.Scan(
+			&form.ID,
+			&form.CreatedBy,
+			&form.CreatedAt,
+			&form.FormType,
+			&form.UpdatedAt,
+			&form.FirstName,
+			&form.LastName,
+			&form.StreetNumber,
+			&form.StreetName,
+			&form.Town,
+			&form.ZipCode,
+			&form.HomePhone,
+			&form.OtherPhone,
+			&form.CallBefore,
+			&form.IsHoliday,
+			&form.PropertyID,
+			&form.ClonedFrom,
+			&shrub.FleaOnly,
+			&lawn.LawnAreaSqFt,
+			&lawn.FertOnly,
+			&form.FirstAppDate,
+			&form.LastAppDate,
+			&row.ApplicationCount,
+			&appID,
+			&appTimestamp,
+			&rate,
+			&amountApplied,
+			&locationCode,
+			&chemID,
+			&brandName,
+			&chemicalName,
+			&epaRegNo,
+			&unit,
+			&rateAmount,
+			&rateUnit,
+			&rateBasis,
+			&expectedAmount,
+			&overLabelMax,
+			&appMethod,
+			&windSpeedMph,
+			&windDirection,
+			&temperatureF,
+			&skyConditions,
+			&licenseNumber,
+			&applicatorName,
+		)
+		if err != nil {
+			return fmt.Errorf("error scanning forms export row: %w", err)
+		}
+
+		switch form.FormType {
+		case "shrub":
+			details, err := shrub.ToDomain()
+			if err != nil {
+				return fmt.Errorf("error casting row to shrub form: %w", err)
+			}
+			row.FleaOnly = &details.FleaOnly
+		case "lawn":
+			details, err := lawn.ToDomain()
+			if err != nil {
+				return fmt.Errorf("error casting row to lawn form: %w", err)
+			}
+			row.LawnAreaSqFt = &details.LawnAreaSqFt
+			row.FertOnly = &details.FertOnly
+		default:
+			return fmt.Errorf("unknown form_type: %s", form.FormType)
+		}
+
+		if appID.Valid {
+			row.Application = &ExportApplication{
+				ID:                      int(appID.Int64),
+				AppTimestamp:            appTimestamp.Time,
+				Rate:                    rate.String,
+				AmountApplied:           amountApplied.Decimal,
+				LocationCode:            locationCode.String,
+				ChemUsed:                int(chemID.Int64),
+				BrandName:               brandName.String,
+				ChemicalName:            chemicalName.String,
+				EpaRegNo:                epaRegNo.String,
+				Unit:                    unit.String,
+				OverLabelMax:            overLabelMax.Bool,
+				ApplicationMethod:       appMethod.String,
+				ApplicatorLicenseNumber: licenseNumber.String,
+				AppliedByName:           applicatorName.String,
+				Weather: Weather{
+					WindDirection: windDirection.String,
+					SkyConditions: skyConditions.String,
+				},
+			}
+			if rateAmount.Valid {
+				row.Application.StructuredRate = &StructuredRate{
+					Amount: rateAmount.Decimal,
+					Unit:   rateUnit.String,
+					Basis:  rateBasis.String,
+				}
+			}
+			if expectedAmount.Valid {
+				row.Application.ExpectedAmount = &expectedAmount.Decimal
+			}
+			if windSpeedMph.Valid {
+				row.Application.Weather.WindSpeedMph = &windSpeedMph.Decimal
+			}
+			if temperatureF.Valid {
+				row.Application.Weather.TemperatureF = &temperatureF.Decimal
+			}
+		}
+
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error after forms export query: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package forms
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/db"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportForms(t *testing.T) {
+	ctx := context.Background()
+	testDB := db.TestDB(t)
+	repo := NewFormsRepository(testDB)
+
+	userID := createTestUser(t, testDB)
+	otherUserID := createTestUser(t, testDB)
+	chemID := createTestChemical(t, testDB, "lawn")
+
+	appTime := time.Now().Add(-time.Hour)
+	lawnID, err := repo.CreateLawnForm(ctx, CreateLawnFormInput{
+		CreatedBy:    userID,
+		FirstName:    "Eli",
+		LastName:     "Export",
+		StreetNumber: "30",
+		StreetName:   "Sheet St",
+		Town:         "Town",
+		ZipCode:      "10001",
+		HomePhone:    "555-3000",
+		LawnAreaSqFt: 1500,
+		Applications: []PestApp{
+			{
+				ChemUsed:      chemID,
+				AppTimestamp:  appTime,
+				Rate:          "2 oz/1000 sq ft",
+				AmountApplied: decimal.NewFromFloat(3.0),
+				LocationCode:  "1A",
+			},
+			{
+				ChemUsed:      chemID,
+				AppTimestamp:  appTime.Add(time.Minute),
+				Rate:          "2 oz/1000 sq ft",
+				AmountApplied: decimal.NewFromFloat(1.5),
+				LocationCode:  "1A",
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	shrubID, err := repo.CreateShrubForm(ctx, CreateShrubFormInput{
+		CreatedBy:    userID,
+		FirstName:    "Fern",
+		LastName:     "Export",
+		StreetNumber: "31",
+		StreetName:   "Column Ave",
+		Town:         "Town",
+		ZipCode:      "10002",
+		HomePhone:    "555-3100",
+		FleaOnly:     true,
+	})
+	require.NoError(t, err)
+
+	_, err = repo.CreateShrubForm(ctx, CreateShrubFormInput{
+		CreatedBy:    otherUserID,
+		FirstName:    "Gale",
+		LastName:     "Elsewhere",
+		StreetNumber: "99",
+		StreetName:   "Far Rd",
+		Town:         "Town",
+		ZipCode:      "10003",
+		HomePhone:    "555-9900",
+	})
+	require.NoError(t, err)
+
+	collect := func(userID string, opts ListFormsOptions, layout string) []ExportRow {
+		var rows []ExportRow
+		err := repo.ExportForms(ctx, userID, opts, layout, func(row ExportRow) error {
+			rows = append(rows, row)
+			return nil
+		})
+		require.NoError(t, err)
+		return rows
+	}
+
+	// One row per application, plus one bare row for the form without applications
+	rows := collect(userID, ListFormsOptions{SortBy: "first_name", Order: "ASC"}, ExportLayoutApplications)
+	require.Len(t, rows, 3)
+	require.Equal(t, lawnID, rows[0].Form.ID)
+	require.Equal(t, "1.5", rows[1].Application.AmountApplied.String())
+	require.Equal(t, "Test Brand", rows[0].Application.BrandName)
+	require.Equal(t, 1500, *rows[0].LawnAreaSqFt)
+	require.Equal(t, shrubID, rows[2].Form.ID)
+	require.Nil(t, rows[2].Application)
+	require.True(t, *rows[2].FleaOnly)
+
+	rows = collect(userID, ListFormsOptions{}, ExportLayoutForms)
+	require.Len(t, rows, 2)
+
+	rows = collect(userID, ListFormsOptions{FormType: "lawn"}, ExportLayoutForms)
+	require.Len(t, rows, 1)
+	require.Equal(t, 2, rows[0].ApplicationCount)
+
+	rows = collect("", ListFormsOptions{}, ExportLayoutForms)
+	require.Len(t, rows, 3)
+
+	err = repo.ExportForms(ctx, userID, ListFormsOptions{}, "pdf", func(ExportRow) error { return nil })
+	require.ErrorIs(t, err, ErrInvalidExportLayout)
+}
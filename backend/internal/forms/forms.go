@@ -25,7 +25,10 @@ func NewFormsRepository(database *sql.DB) *FormsRepository {
 
 // CreateFormInput contains the common fields required to create a new form.
 type CreateShrubFormInput struct {
-	CreatedBy    string
+	CreatedBy string
+	// PropertyID links the form to a property; when set, the client fields
+	// are taken from the property and its customer
+	PropertyID   string
 	FirstName    string
 	LastName     string
 	StreetNumber string
@@ -38,9 +41,20 @@ type CreateShrubFormInput struct {
 	IsHoliday    bool
 	FleaOnly     bool
 	Applications []PestApp
+	// Call is logged along with the form. With CallBefore set, applications are only accepted
+	// when it reached the customer within the call window before each of them.
+	Call *CallLog
+	// Force skips the check for a likely duplicate of a form created this season
+	Force bool
+	// imported marks a record from ImportForms, whose applications were made before it was
+	// entered here, so the call-before and holiday rules are not applied
+	imported bool
 }
 type CreateLawnFormInput struct {
-	CreatedBy    string
+	CreatedBy string
+	// PropertyID links the form to a property; when set, the client fields
+	// are taken from the property and its customer
+	PropertyID   string
 	FirstName    string
 	LastName     string
 	StreetNumber string
@@ -54,6 +68,14 @@ type CreateLawnFormInput struct {
 	LawnAreaSqFt int
 	FertOnly     bool
 	Applications []PestApp
+	// Call is logged along with the form. With CallBefore set, applications are only accepted
+	// when it reached the customer within the call window before each of them.
+	Call *CallLog
+	// Force skips the check for a likely duplicate of a form created this season
+	Force bool
+	// imported marks a record from ImportForms, whose applications were made before it was
+	// entered here, so the call-before and holiday rules are not applied
+	imported bool
 }
 
 // UpdateFormInput contains the fields that may be updated on an existing form.
@@ -69,6 +91,10 @@ type UpdateShrubFormInput struct {
 	CallBefore   bool
 	IsHoliday    bool
 	FleaOnly     bool
+	// Applications replaces the form's pesticide applications when non-nil.
+	// Entries with an ID are updated, entries without one are inserted and
+	// stored applications missing from the slice are deleted.
+	Applications []PestApp
 }
 type UpdateLawnFormInput struct {
 	FirstName    string
@@ -83,10 +109,16 @@ type UpdateLawnFormInput struct {
 	IsHoliday    bool
 	LawnAreaSqFt int
 	FertOnly     bool
+	// Applications replaces the form's pesticide applications when non-nil.
+	// See UpdateShrubFormInput.Applications.
+	Applications []PestApp
 }
 
 // CreateShrubForm creates a new shrub form and its associated shrub details.
 // Returns the created form's ID upon success
+// Unless Force is set, it returns a DuplicateFormsError if the client already has a shrub form this season.
+// Applications are checked by validatePestApps, checkRateUnits, checkApplicators,
+// checkCallBefore and checkHolidayBlackout; Call is logged along with the form.
 // The operation is atomic and will fail if shrub details are not provided.
 func (r *FormsRepository) CreateShrubForm(
 	ctx context.Context,
@@ -98,8 +130,81 @@ func (r *FormsRepository) CreateShrubForm(
 	}
 	defer tx.Rollback()
 
+	formID, err := createShrubForm(ctx, tx, shrubFormInput)
+	if err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("Failed to commit transaction for inserting shrub form: %s %s, %w", shrubFormInput.FirstName, shrubFormInput.LastName, err)
+	}
+
+	return formID, nil
+}
+
+// createShrubForm inserts a shrub form inside tx, see CreateShrubForm.
+func createShrubForm(ctx context.Context, tx *sql.Tx, shrubFormInput CreateShrubFormInput) (string, error) {
+	if err := validatePestApps(shrubFormInput.Applications, nil); err != nil {
+		return "", err
+	}
+	call, fields := normalizeCallLog(shrubFormInput.Call)
+	if len(fields) > 0 {
+		return "", &ValidationError{Fields: fields}
+	}
+	shrubFormInput.Applications = withApplicator(shrubFormInput.Applications, shrubFormInput.CreatedBy)
+	if err := checkChemicalsActive(ctx, tx, shrubFormInput.Applications, nil, appFieldPrefix); err != nil {
+		return "", err
+	}
+	if err := checkRateUnits(ctx, tx, shrubFormInput.Applications, appFieldPrefix); err != nil {
+		return "", err
+	}
+	if err := checkApplicators(ctx, tx, shrubFormInput.Applications, appFieldPrefix); err != nil {
+		return "", err
+	}
+	if !shrubFormInput.imported {
+		if err := checkCallBefore(ctx, tx, "", shrubFormInput.CallBefore, shrubFormInput.Applications, nil, call); err != nil {
+			return "", err
+		}
+		if err := checkHolidayBlackout(shrubFormInput.IsHoliday, shrubFormInput.Applications, nil); err != nil {
+			return "", err
+		}
+	}
+
+	if shrubFormInput.PropertyID != "" {
+		client, err := loadPropertyClient(ctx, tx, shrubFormInput.PropertyID)
+		if err != nil {
+			return "", err
+		}
+		client.copyTo(
+			&shrubFormInput.FirstName,
+			&shrubFormInput.LastName,
+			&shrubFormInput.StreetNumber,
+			&shrubFormInput.StreetName,
+			&shrubFormInput.Town,
+			&shrubFormInput.ZipCode,
+			&shrubFormInput.HomePhone,
+			&shrubFormInput.OtherPhone,
+		)
+	}
+
+	if !shrubFormInput.Force {
+		err := checkDuplicates(ctx, tx, "shrub", propertyClient{
+			FirstName:    shrubFormInput.FirstName,
+			LastName:     shrubFormInput.LastName,
+			StreetNumber: shrubFormInput.StreetNumber,
+			StreetName:   shrubFormInput.StreetName,
+			Town:         shrubFormInput.Town,
+			ZipCode:      shrubFormInput.ZipCode,
+			HomePhone:    shrubFormInput.HomePhone,
+			OtherPhone:   shrubFormInput.OtherPhone,
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
 	var formID string
-	err = tx.QueryRowContext(ctx, `
+	err := tx.QueryRowContext(ctx, `
 		INSERT INTO forms (
 			created_by,
 			form_type,
@@ -112,9 +217,10 @@ func (r *FormsRepository) CreateShrubForm(
 			home_phone,
 			other_phone,
 			call_before,
-			is_holiday
+			is_holiday,
+			property_id
 		)
-		VALUES ($1, 'shrub', $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		VALUES ($1, 'shrub', $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NULLIF($12, '')::uuid)
 		RETURNING id
 	`,
 		shrubFormInput.CreatedBy,
@@ -128,6 +234,7 @@ func (r *FormsRepository) CreateShrubForm(
 		shrubFormInput.OtherPhone,
 		shrubFormInput.CallBefore,
 		shrubFormInput.IsHoliday,
+		shrubFormInput.PropertyID,
 	).Scan(
 		&formID,
 	)
@@ -149,33 +256,25 @@ func (r *FormsRepository) CreateShrubForm(
 		return "", fmt.Errorf("Failed to insert shrub form: %s %s, %w", shrubFormInput.FirstName, shrubFormInput.LastName, err)
 	}
 
+	if call != nil {
+		if err := insertCall(ctx, tx, formID, shrubFormInput.CreatedBy, *call); err != nil {
+			return "", err
+		}
+	}
+
 	// Insert pesticide applications if any
 	for _, app := range shrubFormInput.Applications {
-		_, err = tx.ExecContext(ctx, `
-			INSERT INTO pesticide_applications (
-				form_id,
-				chem_used,
-				app_timestamp,
-				rate,
-				amount_applied,
-				location_code
-			)
-			VALUES ($1, $2, $3, $4, $5, $6)
-		`,
-			formID,
-			app.ChemUsed,
-			app.AppTimestamp,
-			app.Rate,
-			app.AmountApplied,
-			app.LocationCode,
-		)
+		_, err = insertPestApp(ctx, tx, formID, app)
 		if err != nil {
 			return "", fmt.Errorf("Failed to insert pesticide application for form %s %s: %w", shrubFormInput.FirstName, shrubFormInput.LastName, err)
 		}
 	}
+	if err := refreshLabelChecks(ctx, tx, formID); err != nil {
+		return "", err
+	}
 
-	if err := tx.Commit(); err != nil {
-		return "", fmt.Errorf("Failed to commit transaction for inserting shrub form: %s %s, %w", shrubFormInput.FirstName, shrubFormInput.LastName, err)
+	if _, err := recordRevision(ctx, tx, formID, shrubFormInput.CreatedBy, RevisionActionCreate, nil); err != nil {
+		return "", err
 	}
 
 	return formID, nil
@@ -183,6 +282,9 @@ func (r *FormsRepository) CreateShrubForm(
 
 // CreateLawnForm creates a new lawn form and its associated lawn details.
 // Returns the created form's ID upon success
+// Unless Force is set, it returns a DuplicateFormsError if the client already has a lawn form this season.
+// Applications are checked by validatePestApps, checkRateUnits, checkApplicators,
+// checkCallBefore and checkHolidayBlackout; Call is logged along with the form.
 // The operation is atomic and will fail if lawn details are not provided.
 func (r *FormsRepository) CreateLawnForm(
 	ctx context.Context,
@@ -194,8 +296,81 @@ func (r *FormsRepository) CreateLawnForm(
 	}
 	defer tx.Rollback()
 
+	formID, err := createLawnForm(ctx, tx, lawnFormInput)
+	if err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("Failed to commit transaction for inserting lawn form: %s %s, %w", lawnFormInput.FirstName, lawnFormInput.LastName, err)
+	}
+
+	return formID, nil
+}
+
+// createLawnForm inserts a lawn form inside tx, see CreateLawnForm.
+func createLawnForm(ctx context.Context, tx *sql.Tx, lawnFormInput CreateLawnFormInput) (string, error) {
+	if err := validatePestApps(lawnFormInput.Applications, nil); err != nil {
+		return "", err
+	}
+	call, fields := normalizeCallLog(lawnFormInput.Call)
+	if len(fields) > 0 {
+		return "", &ValidationError{Fields: fields}
+	}
+	lawnFormInput.Applications = withApplicator(lawnFormInput.Applications, lawnFormInput.CreatedBy)
+	if err := checkChemicalsActive(ctx, tx, lawnFormInput.Applications, nil, appFieldPrefix); err != nil {
+		return "", err
+	}
+	if err := checkRateUnits(ctx, tx, lawnFormInput.Applications, appFieldPrefix); err != nil {
+		return "", err
+	}
+	if err := checkApplicators(ctx, tx, lawnFormInput.Applications, appFieldPrefix); err != nil {
+		return "", err
+	}
+	if !lawnFormInput.imported {
+		if err := checkCallBefore(ctx, tx, "", lawnFormInput.CallBefore, lawnFormInput.Applications, nil, call); err != nil {
+			return "", err
+		}
+		if err := checkHolidayBlackout(lawnFormInput.IsHoliday, lawnFormInput.Applications, nil); err != nil {
+			return "", err
+		}
+	}
+
+	if lawnFormInput.PropertyID != "" {
+		client, err := loadPropertyClient(ctx, tx, lawnFormInput.PropertyID)
+		if err != nil {
+			return "", err
+		}
+		client.copyTo(
+			&lawnFormInput.FirstName,
+			&lawnFormInput.LastName,
+			&lawnFormInput.StreetNumber,
+			&lawnFormInput.StreetName,
+			&lawnFormInput.Town,
+			&lawnFormInput.ZipCode,
+			&lawnFormInput.HomePhone,
+			&lawnFormInput.OtherPhone,
+		)
+	}
+
+	if !lawnFormInput.Force {
+		err := checkDuplicates(ctx, tx, "lawn", propertyClient{
+			FirstName:    lawnFormInput.FirstName,
+			LastName:     lawnFormInput.LastName,
+			StreetNumber: lawnFormInput.StreetNumber,
+			StreetName:   lawnFormInput.StreetName,
+			Town:         lawnFormInput.Town,
+			ZipCode:      lawnFormInput.ZipCode,
+			HomePhone:    lawnFormInput.HomePhone,
+			OtherPhone:   lawnFormInput.OtherPhone,
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
 	var formID string
-	err = tx.QueryRowContext(ctx, `
+	err := tx.QueryRowContext(ctx, `
 		INSERT INTO forms (
 			created_by,
 			form_type,
@@ -208,9 +383,10 @@ func (r *FormsRepository) CreateLawnForm(
 			home_phone,
 			other_phone,
 			call_before,
-			is_holiday
+			is_holiday,
+			property_id
 		)
-		VALUES ($1, 'lawn', $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		VALUES ($1, 'lawn', $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NULLIF($12, '')::uuid)
 		RETURNING id
 	`,
 		lawnFormInput.CreatedBy,
@@ -224,6 +400,7 @@ func (r *FormsRepository) CreateLawnForm(
 		lawnFormInput.OtherPhone,
 		lawnFormInput.CallBefore,
 		lawnFormInput.IsHoliday,
+		lawnFormInput.PropertyID,
 	).Scan(
 		&formID,
 	)
@@ -248,33 +425,25 @@ func (r *FormsRepository) CreateLawnForm(
 		return "", fmt.Errorf("Failed to insert lawn form: %s %s, %w", lawnFormInput.FirstName, lawnFormInput.LastName, err)
 	}
 
+	if call != nil {
+		if err := insertCall(ctx, tx, formID, lawnFormInput.CreatedBy, *call); err != nil {
+			return "", err
+		}
+	}
+
 	// Insert pesticide applications if any
 	for _, app := range lawnFormInput.Applications {
-		_, err = tx.ExecContext(ctx, `
-			INSERT INTO pesticide_applications (
-				form_id,
-				chem_used,
-				app_timestamp,
-				rate,
-				amount_applied,
-				location_code
-			)
-			VALUES ($1, $2, $3, $4, $5, $6)
-		`,
-			formID,
-			app.ChemUsed,
-			app.AppTimestamp,
-			app.Rate,
-			app.AmountApplied,
-			app.LocationCode,
-		)
+		_, err = insertPestApp(ctx, tx, formID, app)
 		if err != nil {
 			return "", fmt.Errorf("Failed to insert pesticide application for form %s %s: %w", lawnFormInput.FirstName, lawnFormInput.LastName, err)
 		}
 	}
+	if err := refreshLabelChecks(ctx, tx, formID); err != nil {
+		return "", err
+	}
 
-	if err := tx.Commit(); err != nil {
-		return "", fmt.Errorf("Failed to commit transaction for inserting lawn form: %s %s, %w", lawnFormInput.FirstName, lawnFormInput.LastName, err)
+	if _, err := recordRevision(ctx, tx, formID, lawnFormInput.CreatedBy, RevisionActionCreate, nil); err != nil {
+		return "", err
 	}
 
 	return formID, nil
@@ -285,6 +454,8 @@ type ListFormsOptions struct {
 	// Pagination
 	Limit  int
 	Offset int
+	// Cursor is an opaque next_cursor or prev_cursor from a previous page; Offset is ignored when set
+	Cursor string
 
 	// Filtering
 	FormType      string
@@ -294,62 +465,65 @@ type ListFormsOptions struct {
 	DateLow       time.Time
 	DateHigh      time.Time
 	ZipCode       string
+	// CreatedBy restricts the listing to forms created by this user
+	CreatedBy string
+	// AppliedBy restricts the listing to forms with an application made by this user
+	AppliedBy string
+
+	// Query is a full-text search over name, address, phones and note text
+	Query string
+
+	// Deleted lists trashed forms instead of live ones
+	Deleted bool
 
 	// Sorting
 	SortBy string
 	Order  string
 }
 
-// ListFormsByUserId returns all forms owned by the given user with pagination and filtering.
-// Results may be sorted by first name, last name, or creation time.
-// Each returned FormView is fully hydrated with its subtype details.
-func (r *FormsRepository) ListFormsByUserId(
-	ctx context.Context,
-	userID string,
-	opts ListFormsOptions,
-) ([]*FormView, error) {
-
-	allowedSorts := map[string]string{
-		"first_name":     "f.first_name",
-		"last_name":      "f.last_name",
-		"first_app_date": "fad.first_app_date",
-	}
-
-	var sortColumn string
-
-	sortColumn, ok := allowedSorts[opts.SortBy]
-	if !ok {
-		sortColumn = "f.created_at"
-	}
-
-	order := strings.ToUpper(opts.Order)
-	if order != "ASC" && order != "DESC" {
-		order = "DESC"
-	}
-
-	// Add NULL handling for date sorting
-	orderClause := fmt.Sprintf("%s %s", sortColumn, order)
-	if sortColumn == "fad.first_app_date" {
-		// Put forms without applications at the end regardless of sort order
-		orderClause = fmt.Sprintf("%s %s NULLS LAST", sortColumn, order)
+// deletedCondition restricts a form listing to live forms, or to trashed forms when opts.Deleted is set.
+func deletedCondition(opts ListFormsOptions) string {
+	if opts.Deleted {
+		return "f.deleted_at IS NOT NULL"
 	}
+	return "f.deleted_at IS NULL"
+}
 
-	whereConditions := []string{"f.created_by = $1"}
-	args := []any{userID}
-	argIndex := 2
+// visibleToCondition restricts a form listing to the forms a user created or made an application on,
+// with the user's ID given by the SQL expression userID
+func visibleToCondition(userID string) string {
+	return fmt.Sprintf(`(f.created_by = %[1]s OR EXISTS (
+		SELECT 1
+		FROM pesticide_applications pa_by
+		WHERE pa_by.form_id = f.id AND pa_by.applied_by = %[1]s
+	))`, userID)
+}
 
+// appendFormFilters adds the conditions for the type, name, chemical, application date, zip code,
+// creator, applicator and holiday filters of opts to a form listing, numbering placeholders from argIndex.
+// The conditions may refer to fad, the listing's per-form application dates.
+// It returns the extended conditions and arguments and the next free placeholder index.
+func appendFormFilters(
+	opts ListFormsOptions,
+	whereConditions []string,
+	args []any,
+	argIndex int,
+) ([]string, []any, int) {
+	// Add form type filter
 	if opts.FormType != "" {
 		whereConditions = append(whereConditions, fmt.Sprintf("f.form_type = $%d", argIndex))
 		args = append(args, opts.FormType)
 		argIndex++
 	}
 
+	// Add name search filter
 	if opts.SearchName != "" {
 		whereConditions = append(whereConditions, fmt.Sprintf("(f.first_name ILIKE $%d OR f.last_name ILIKE $%d)", argIndex, argIndex))
 		args = append(args, "%"+opts.SearchName+"%")
 		argIndex++
 	}
 
+	// Add chemical filter - find forms that have applications using any of the specified chemicals
 	if len(opts.ChemicalIDs) > 0 {
 		placeholders := make([]string, len(opts.ChemicalIDs))
 		for i, chemID := range opts.ChemicalIDs {
@@ -384,6 +558,23 @@ func (r *FormsRepository) ListFormsByUserId(
 		argIndex++
 	}
 
+	// Add creator filter
+	if opts.CreatedBy != "" {
+		whereConditions = append(whereConditions, fmt.Sprintf("f.created_by::text = $%d", argIndex))
+		args = append(args, opts.CreatedBy)
+		argIndex++
+	}
+
+	// Add applicator filter - find forms with an application made by the user
+	if opts.AppliedBy != "" {
+		whereConditions = append(whereConditions, fmt.Sprintf(
+			"f.id IN (SELECT DISTINCT form_id FROM pesticide_applications WHERE applied_by::text = $%d)",
+			argIndex,
+		))
+		args = append(args, opts.AppliedBy)
+		argIndex++
+	}
+
 	// Add Jewish holiday filter
 	if opts.JewishHoliday != "" {
 		switch opts.JewishHoliday {
@@ -394,8 +585,114 @@ func (r *FormsRepository) ListFormsByUserId(
 		}
 	}
 
+	return whereConditions, args, argIndex
+}
+
+// countForms returns how many forms match the given WHERE conditions, ignoring pagination
+func (r *FormsRepository) countForms(ctx context.Context, whereClause string, args []any) (int, error) {
+	query := fmt.Sprintf(`
+		WITH form_app_dates AS (
+			SELECT
+				form_id,
+				MIN(app_timestamp) as first_app_date,
+				MAX(app_timestamp) as last_app_date
+			FROM pesticide_applications
+			GROUP BY form_id
+		)
+		SELECT COUNT(*)
+		FROM forms f
+		LEFT JOIN form_app_dates fad ON f.id = fad.form_id
+		WHERE %s
+	`, whereClause)
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&total); err != nil {
+		return 0, fmt.Errorf("failed to count forms for forms list: %w", err)
+	}
+	return total, nil
+}
+
+// hydrateFormViews attaches pesticide applications and notes to every listed form.
+// Children are loaded with one batched query each, so a page costs the same number
+// of queries regardless of how many forms it holds.
+func (r *FormsRepository) hydrateFormViews(ctx context.Context, views []*FormView) error {
+	if len(views) == 0 {
+		return nil
+	}
+
+	formIDs := make([]string, 0, len(views))
+	for _, view := range views {
+		formIDs = append(formIDs, view.form().ID)
+	}
+
+	pestApps, err := r.listPestAppsByFormIds(ctx, formIDs)
+	if err != nil {
+		return err
+	}
+	notes, err := r.listNotesByFormIds(ctx, formIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, view := range views {
+		form := view.form()
+		form.AppTimes = pestApps[form.ID]
+		form.Notes = notes[form.ID]
+	}
+	return nil
+}
+
+// ListFormsByUserId returns all forms owned by the given user, or with an application made by them,
+// with pagination and filtering.
+// Results may be sorted by first name, last name, or creation time.
+// Each returned FormView is fully hydrated with its subtype details.
+// The page also carries the total number of matching forms and cursors for the neighbouring pages.
+// It returns ErrInvalidCursor if opts.Cursor is malformed or was issued for a different sort.
+func (r *FormsRepository) ListFormsByUserId(
+	ctx context.Context,
+	userID string,
+	opts ListFormsOptions,
+) (FormsPage, error) {
+
+	whereConditions := []string{visibleToCondition("$1::uuid"), deletedCondition(opts)}
+	args := []any{userID}
+	argIndex := 2
+
+	// Add full-text search first, relevance ordering refers to its arguments
+	search := newFormSearch(opts.Query, argIndex)
+	if search != nil {
+		whereConditions = append(whereConditions, search.condition)
+		args = append(args, search.args...)
+		argIndex += len(search.args)
+	}
+
+	sort := resolveFormsSort(opts, search)
+	cursor, err := decodeFormsCursor(opts.Cursor, sort)
+	if err != nil {
+		return FormsPage{}, err
+	}
+
+	whereConditions, args, argIndex = appendFormFilters(opts, whereConditions, args, argIndex)
+
+	total, err := r.countForms(ctx, strings.Join(whereConditions, " AND "), args)
+	if err != nil {
+		return FormsPage{}, err
+	}
+
+	// Continue from the cursor position instead of skipping rows
+	if cursor != nil {
+		whereConditions = append(whereConditions, sort.keysetCondition(cursor, argIndex, argIndex+1))
+		args = append(args, cursor.Key, cursor.ID)
+		argIndex += 2
+	}
+
 	whereClause := strings.Join(whereConditions, " AND ")
 
+	snippet := "''"
+	if search != nil {
+		snippet = search.headline
+	}
+
 	// Build query with pagination
 	// Use a CTE to compute first and last application dates per form
 	query := fmt.Sprintf(`
@@ -423,43 +720,52 @@ func (r *FormsRepository) ListFormsByUserId(
 			f.other_phone,
 			f.call_before,
 			f.is_holiday,
+			COALESCE(f.property_id::text, ''),
+			COALESCE(f.cloned_from::text, ''),
+			f.deleted_at,
+			COALESCE(f.deleted_by::text, ''),
 			sf.flea_only,
 			lf.lawn_area_sq_ft,
 			lf.fert_only,
 			COALESCE(fad.first_app_date, '1970-01-01 00:00:00'::timestamp) as first_app_date,
-			COALESCE(fad.last_app_date, '1970-01-01 00:00:00'::timestamp) as last_app_date
+			COALESCE(fad.last_app_date, '1970-01-01 00:00:00'::timestamp) as last_app_date,
+			(%s)::text as sort_key,
+			%s as search_snippet
 		FROM forms f
 		LEFT JOIN shrub_forms sf ON f.id = sf.form_id
 		LEFT JOIN lawn_forms lf ON f.id = lf.form_id
 		LEFT JOIN form_app_dates fad ON f.id = fad.form_id
 		WHERE %s
 		ORDER BY %s
-	`, whereClause, orderClause)
+	`, sort.expr, snippet, whereClause, sort.orderClause(cursor))
 
-	// Add pagination
+	// Add pagination, fetching one extra row to tell whether another page follows
 	if opts.Limit > 0 {
 		query += fmt.Sprintf(" LIMIT $%d", argIndex)
-		args = append(args, opts.Limit)
+		args = append(args, opts.Limit+1)
 		argIndex++
 	}
-	if opts.Offset > 0 {
+	if cursor == nil && opts.Offset > 0 {
 		query += fmt.Sprintf(" OFFSET $%d", argIndex)
 		args = append(args, opts.Offset)
 	}
 
 	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query rows for forms list: %w", err)
+		return FormsPage{}, fmt.Errorf("failed to query rows for forms list: %w", err)
 	}
 	defer rows.Close()
 
-	var forms []*FormView
+	var (
+		forms    []*FormView
+		sortKeys []string
+	)
 	for rows.Next() {
 		var (
 			form    Form
 			shrub   shrubRow
 			lawn    lawnRow
-			pestApp PestApp
+			sortKey string
 		)
 
 		err := rows.Scan(
@@ -478,54 +784,28 @@ func (r *FormsRepository) ListFormsByUserId(
 			&form.OtherPhone,
 			&form.CallBefore,
 			&form.IsHoliday,
+			&form.PropertyID,
+			&form.ClonedFrom,
+			&form.DeletedAt,
+			&form.DeletedBy,
 			&shrub.FleaOnly,
 			&lawn.LawnAreaSqFt,
 			&lawn.FertOnly,
 			&form.FirstAppDate,
 			&form.LastAppDate,
+			&sortKey,
+			&form.SearchSnippet,
 		)
 		if err != nil {
-			return nil, fmt.Errorf("error scanning rows: %w", err)
+			return FormsPage{}, fmt.Errorf("error scanning rows: %w", err)
 		}
 
-		query = `
-		    SELECT
-			    pa.id,
-				pa.chem_used,
-				pa.app_timestamp,
-				pa.rate,
-				pa.amount_applied,
-				pa.location_code
-			FROM pesticide_applications pa
-			WHERE pa.form_id = $1
-		`
-		appRows, err := r.db.QueryContext(ctx, query, form.ID)
-		if err != nil {
-			return nil, fmt.Errorf("error fetching pesticide applications for form: %s. %w", form.ID, err)
-		}
-		var pestApps []PestApp
-		for appRows.Next() {
-			err = appRows.Scan(
-				&pestApp.ID,
-				&pestApp.ChemUsed,
-				&pestApp.AppTimestamp,
-				&pestApp.Rate,
-				&pestApp.AmountApplied,
-				&pestApp.LocationCode,
-			)
-			if err != nil {
-				return nil, fmt.Errorf("Error scanning pesticide application fo form: %s. %w", form.ID, err)
-			}
-			pestApps = append(pestApps, pestApp)
-		}
-		form.AppTimes = pestApps
-
 		var view *FormView
 		switch form.FormType {
 		case "shrub":
 			shrubDetails, err := shrub.ToDomain()
 			if err != nil {
-				return nil, fmt.Errorf("error casting row to shrub form %w", err)
+				return FormsPage{}, fmt.Errorf("error casting row to shrub form %w", err)
 			}
 			view = NewShrubFormView(
 				ShrubForm{
@@ -537,7 +817,7 @@ func (r *FormsRepository) ListFormsByUserId(
 		case "lawn":
 			lawnDetails, err := lawn.ToDomain()
 			if err != nil {
-				return nil, fmt.Errorf("error casting row to lawn form: %w", err)
+				return FormsPage{}, fmt.Errorf("error casting row to lawn form: %w", err)
 			}
 			view = NewLawnFormView(
 				LawnForm{
@@ -546,117 +826,72 @@ func (r *FormsRepository) ListFormsByUserId(
 				},
 			)
 		default:
-			return nil, fmt.Errorf("unknown form_type: %s", form.FormType)
+			return FormsPage{}, fmt.Errorf("unknown form_type: %s", form.FormType)
 		}
 		forms = append(forms, view)
+		sortKeys = append(sortKeys, sortKey)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error after list forms queries: %w", err)
+		return FormsPage{}, fmt.Errorf("error after list forms queries: %w", err)
 	}
 
-	return forms, nil
+	page := newFormsPage(forms, sortKeys, sort, opts, cursor, total)
+	if err := r.hydrateFormViews(ctx, page.Forms); err != nil {
+		return FormsPage{}, err
+	}
+
+	return page, nil
 }
 
 // ListAllForms returns all forms (admin only) with pagination and filtering.
 // Does NOT filter by user - returns forms from all users.
 // Each returned FormView is fully hydrated with its subtype details.
+// The page also carries the total number of matching forms and cursors for the neighbouring pages.
+// It returns ErrInvalidCursor if opts.Cursor is malformed or was issued for a different sort.
 func (r *FormsRepository) ListAllForms(
 	ctx context.Context,
 	opts ListFormsOptions,
-) ([]*FormView, error) {
-
-	allowedSorts := map[string]string{
-		"first_name":     "f.first_name",
-		"last_name":      "f.last_name",
-		"created_at":     "f.created_at",
-		"first_app_date": "fad.first_app_date",
-	}
-
-	sortColumn, ok := allowedSorts[opts.SortBy]
-	if !ok {
-		sortColumn = "f.created_at"
-	}
-
-	order := strings.ToUpper(opts.Order)
-	if order != "ASC" && order != "DESC" {
-		order = "DESC"
-	}
-
-	// Add NULL handling for date sorting
-	orderClause := fmt.Sprintf("%s %s", sortColumn, order)
-	if sortColumn == "fad.first_app_date" {
-		// Put forms without applications at the end regardless of sort order
-		orderClause = fmt.Sprintf("%s %s NULLS LAST", sortColumn, order)
-	}
+) (FormsPage, error) {
 
 	// Build WHERE clause
-	whereConditions := []string{}
+	whereConditions := []string{deletedCondition(opts)}
 	args := []any{}
 	argIndex := 1
 
-	// Add form type filter
-	if opts.FormType != "" {
-		whereConditions = append(whereConditions, fmt.Sprintf("f.form_type = $%d", argIndex))
-		args = append(args, opts.FormType)
-		argIndex++
-	}
-
-	// Add name search filter
-	if opts.SearchName != "" {
-		whereConditions = append(whereConditions, fmt.Sprintf("(f.first_name ILIKE $%d OR f.last_name ILIKE $%d)", argIndex, argIndex))
-		args = append(args, "%"+opts.SearchName+"%")
-		argIndex++
+	// Add full-text search first, relevance ordering refers to its arguments
+	search := newFormSearch(opts.Query, argIndex)
+	if search != nil {
+		whereConditions = append(whereConditions, search.condition)
+		args = append(args, search.args...)
+		argIndex += len(search.args)
 	}
 
-	// Add chemical filter - find forms that have applications using any of the specified chemicals
-	if len(opts.ChemicalIDs) > 0 {
-		placeholders := make([]string, len(opts.ChemicalIDs))
-		for i, chemID := range opts.ChemicalIDs {
-			placeholders[i] = fmt.Sprintf("$%d", argIndex)
-			args = append(args, chemID)
-			argIndex++
-		}
-		whereConditions = append(whereConditions, fmt.Sprintf(
-			"f.id IN (SELECT DISTINCT form_id FROM pesticide_applications WHERE chem_used IN (%s))",
-			strings.Join(placeholders, ", "),
-		))
+	sort := resolveFormsSort(opts, search)
+	cursor, err := decodeFormsCursor(opts.Cursor, sort)
+	if err != nil {
+		return FormsPage{}, err
 	}
 
-	// Add date filter for first application date
-	if !opts.DateLow.IsZero() {
-		whereConditions = append(whereConditions, fmt.Sprintf("fad.first_app_date >= $%d", argIndex))
-		args = append(args, opts.DateLow)
-		argIndex++
-	}
+	whereConditions, args, argIndex = appendFormFilters(opts, whereConditions, args, argIndex)
 
-	// Add date filter for last application date
-	if !opts.DateHigh.IsZero() {
-		whereConditions = append(whereConditions, fmt.Sprintf("fad.last_app_date <= $%d", argIndex))
-		args = append(args, opts.DateHigh)
-		argIndex++
+	total, err := r.countForms(ctx, strings.Join(whereConditions, " AND "), args)
+	if err != nil {
+		return FormsPage{}, err
 	}
 
-	// Add zip code filter
-	if opts.ZipCode != "" {
-		whereConditions = append(whereConditions, fmt.Sprintf("f.zip_code = $%d", argIndex))
-		args = append(args, opts.ZipCode)
-		argIndex++
+	// Continue from the cursor position instead of skipping rows
+	if cursor != nil {
+		whereConditions = append(whereConditions, sort.keysetCondition(cursor, argIndex, argIndex+1))
+		args = append(args, cursor.Key, cursor.ID)
+		argIndex += 2
 	}
 
-	// Add Jewish holiday filter
-	if opts.JewishHoliday != "" {
-		switch opts.JewishHoliday {
-		case "yes":
-			whereConditions = append(whereConditions, "f.is_holiday = true")
-		case "no":
-			whereConditions = append(whereConditions, "f.is_holiday = false")
-		}
-	}
+	whereClause := strings.Join(whereConditions, " AND ")
 
-	whereClause := ""
-	if len(whereConditions) > 0 {
-		whereClause = "WHERE " + strings.Join(whereConditions, " AND ")
+	snippet := "''"
+	if search != nil {
+		snippet = search.headline
 	}
 
 	// Build query with pagination
@@ -686,43 +921,52 @@ func (r *FormsRepository) ListAllForms(
 			f.other_phone,
 			f.call_before,
 			f.is_holiday,
+			COALESCE(f.property_id::text, ''),
+			COALESCE(f.cloned_from::text, ''),
+			f.deleted_at,
+			COALESCE(f.deleted_by::text, ''),
 			sf.flea_only,
 			lf.lawn_area_sq_ft,
 			lf.fert_only,
 			COALESCE(fad.first_app_date, '1970-01-01 00:00:00'::timestamp) as first_app_date,
-			COALESCE(fad.last_app_date, '1970-01-01 00:00:00'::timestamp) as last_app_date
+			COALESCE(fad.last_app_date, '1970-01-01 00:00:00'::timestamp) as last_app_date,
+			(%s)::text as sort_key,
+			%s as search_snippet
 		FROM forms f
 		LEFT JOIN shrub_forms sf ON f.id = sf.form_id
 		LEFT JOIN lawn_forms lf ON f.id = lf.form_id
 		LEFT JOIN form_app_dates fad ON f.id = fad.form_id
-		%s
+		WHERE %s
 		ORDER BY %s
-	`, whereClause, orderClause)
+	`, sort.expr, snippet, whereClause, sort.orderClause(cursor))
 
-	// Add pagination
+	// Add pagination, fetching one extra row to tell whether another page follows
 	if opts.Limit > 0 {
 		query += fmt.Sprintf(" LIMIT $%d", argIndex)
-		args = append(args, opts.Limit)
+		args = append(args, opts.Limit+1)
 		argIndex++
 	}
-	if opts.Offset > 0 {
+	if cursor == nil && opts.Offset > 0 {
 		query += fmt.Sprintf(" OFFSET $%d", argIndex)
 		args = append(args, opts.Offset)
 	}
 
 	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("error querying rows for forms list: %w", err)
+		return FormsPage{}, fmt.Errorf("error querying rows for forms list: %w", err)
 	}
 	defer rows.Close()
 
-	var forms []*FormView
+	var (
+		forms    []*FormView
+		sortKeys []string
+	)
 	for rows.Next() {
 		var (
 			form    Form
 			shrub   shrubRow
 			lawn    lawnRow
-			pestApp PestApp
+			sortKey string
 		)
 
 		err := rows.Scan(
@@ -741,53 +985,28 @@ func (r *FormsRepository) ListAllForms(
 			&form.OtherPhone,
 			&form.CallBefore,
 			&form.IsHoliday,
+			&form.PropertyID,
+			&form.ClonedFrom,
+			&form.DeletedAt,
+			&form.DeletedBy,
 			&shrub.FleaOnly,
 			&lawn.LawnAreaSqFt,
 			&lawn.FertOnly,
 			&form.FirstAppDate,
 			&form.LastAppDate,
+			&sortKey,
+			&form.SearchSnippet,
 		)
 		if err != nil {
-			return nil, fmt.Errorf("error scanning rows: %w", err)
+			return FormsPage{}, fmt.Errorf("error scanning rows: %w", err)
 		}
 
-		query = `
-		    SELECT
-			    pa.id,
-				pa.chem_used,
-				pa.app_timestamp,
-				pa.rate,
-				pa.amount_applied,
-				pa.location_code
-			FROM pesticide_applications pa
-			WHERE pa.form_id = $1
-		`
-		appRows, err := r.db.QueryContext(ctx, query, form.ID)
-		if err != nil {
-			return nil, fmt.Errorf("error fetching pesticide applications for form: %s. %w", form.ID, err)
-		}
-		var pestApps []PestApp
-		for appRows.Next() {
-			err = appRows.Scan(
-				&pestApp.ID,
-				&pestApp.ChemUsed,
-				&pestApp.AppTimestamp,
-				&pestApp.Rate,
-				&pestApp.AmountApplied,
-				&pestApp.LocationCode,
-			)
-			if err != nil {
-				return nil, fmt.Errorf("Error scanning pesticide application fo form: %s. %w", form.ID, err)
-			}
-			pestApps = append(pestApps, pestApp)
-		}
-		form.AppTimes = pestApps
 		var view *FormView
 		switch form.FormType {
 		case "shrub":
 			shrubDetails, err := shrub.ToDomain()
 			if err != nil {
-				return nil, fmt.Errorf("error casting row to shrub form: %w", err)
+				return FormsPage{}, fmt.Errorf("error casting row to shrub form: %w", err)
 			}
 			view = NewShrubFormView(
 				ShrubForm{
@@ -799,7 +1018,7 @@ func (r *FormsRepository) ListAllForms(
 		case "lawn":
 			lawnDetails, err := lawn.ToDomain()
 			if err != nil {
-				return nil, fmt.Errorf("error casting row to lawn form: %w", err)
+				return FormsPage{}, fmt.Errorf("error casting row to lawn form: %w", err)
 			}
 			view = NewLawnFormView(
 				LawnForm{
@@ -808,20 +1027,26 @@ func (r *FormsRepository) ListAllForms(
 				},
 			)
 		default:
-			return nil, fmt.Errorf("unknown form_type: %s", form.FormType)
+			return FormsPage{}, fmt.Errorf("unknown form_type: %s", form.FormType)
 		}
 		forms = append(forms, view)
+		sortKeys = append(sortKeys, sortKey)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error after queries for forms list: %w", err)
+		return FormsPage{}, fmt.Errorf("error after queries for forms list: %w", err)
 	}
 
-	return forms, nil
+	page := newFormsPage(forms, sortKeys, sort, opts, cursor, total)
+	if err := r.hydrateFormViews(ctx, page.Forms); err != nil {
+		return FormsPage{}, err
+	}
+
+	return page, nil
 }
 
-// GetFormViewById returns a single form owned by the given user.
-// It returns sql.ErrNoRows if the form does not exist or is not owned by the user.
+// GetFormViewById returns a single form owned by the given user or with an application made by them.
+// It returns sql.ErrNoRows if the form does not exist, is in the trash or is not visible to the user.
 func (r *FormsRepository) GetFormViewById(
 	ctx context.Context,
 	formID string,
@@ -854,6 +1079,10 @@ func (r *FormsRepository) GetFormViewById(
 			f.other_phone,
 			f.call_before,
 			f.is_holiday,
+			COALESCE(f.property_id::text, ''),
+			COALESCE(f.cloned_from::text, ''),
+			f.deleted_at,
+			COALESCE(f.deleted_by::text, ''),
 			sf.flea_only,
 			lf.lawn_area_sq_ft,
 			lf.fert_only,
@@ -864,14 +1093,13 @@ func (r *FormsRepository) GetFormViewById(
 		LEFT JOIN lawn_forms lf ON f.id = lf.form_id
 		LEFT JOIN form_app_dates fad ON f.id = fad.form_id
 		WHERE f.id = $1
-		  AND f.created_by = $2
-	`
+		  AND f.deleted_at IS NULL
+		  AND ` + visibleToCondition("$2::uuid")
 
 	var (
-		form    Form
-		shrub   shrubRow
-		lawn    lawnRow
-		pestApp PestApp
+		form  Form
+		shrub shrubRow
+		lawn  lawnRow
 	)
 
 	err := r.db.QueryRowContext(ctx, query, formID, userID).Scan(
@@ -890,6 +1118,10 @@ func (r *FormsRepository) GetFormViewById(
 		&form.OtherPhone,
 		&form.CallBefore,
 		&form.IsHoliday,
+		&form.PropertyID,
+		&form.ClonedFrom,
+		&form.DeletedAt,
+		&form.DeletedBy,
 		&shrub.FleaOnly,
 		&lawn.LawnAreaSqFt,
 		&lawn.FertOnly,
@@ -902,13 +1134,7 @@ func (r *FormsRepository) GetFormViewById(
 	}
 
 	query = `
-		SELECT
-			pa.id,
-			pa.chem_used,
-			pa.app_timestamp,
-			pa.rate,
-			pa.amount_applied,
-			pa.location_code
+		SELECT` + pestAppColumns + `
 		FROM pesticide_applications pa
 		WHERE pa.form_id = $1
 	`
@@ -918,21 +1144,21 @@ func (r *FormsRepository) GetFormViewById(
 	}
 	var pestApps []PestApp
 	for appRows.Next() {
-		err = appRows.Scan(
-			&pestApp.ID,
-			&pestApp.ChemUsed,
-			&pestApp.AppTimestamp,
-			&pestApp.Rate,
-			&pestApp.AmountApplied,
-			&pestApp.LocationCode,
-		)
+		var row pestAppRow
+		err = appRows.Scan(row.dest()...)
 		if err != nil {
 			return nil, fmt.Errorf("Error scanning pesticide application fo form: %s. %w", form.ID, err)
 		}
-		pestApps = append(pestApps, pestApp)
+		pestApps = append(pestApps, row.toDomain())
 	}
 	form.AppTimes = pestApps
 
+	notes, err := r.listNotes(ctx, form.ID)
+	if err != nil {
+		return nil, err
+	}
+	form.Notes = notes
+
 	var view *FormView
 	switch form.FormType {
 	case "shrub":
@@ -965,8 +1191,8 @@ func (r *FormsRepository) GetFormViewById(
 	return view, nil
 }
 
-// GetShrubFormById returns a single shrub form owned by the given user.
-// It returns sql.ErrNoRows if the form does not exist or is not owned by the user.
+// GetShrubFormById returns a single shrub form owned by the given user or with an application made by them.
+// It returns sql.ErrNoRows if the form does not exist, is in the trash or is not visible to the user.
 func (r *FormsRepository) GetShrubFormById(
 	ctx context.Context,
 	formID string,
@@ -999,6 +1225,8 @@ func (r *FormsRepository) GetShrubFormById(
 			f.other_phone,
 			f.call_before,
 			f.is_holiday,
+			COALESCE(f.property_id::text, ''),
+			COALESCE(f.cloned_from::text, ''),
 			COALESCE(fad.first_app_date, '1970-01-01 00:00:00'::timestamp) as first_app_date,
 			COALESCE(fad.last_app_date, '1970-01-01 00:00:00'::timestamp) as last_app_date,
 			sf.flea_only
@@ -1006,8 +1234,8 @@ func (r *FormsRepository) GetShrubFormById(
 		LEFT JOIN shrub_forms sf ON f.id = sf.form_id
 		LEFT JOIN form_app_dates fad ON f.id = fad.form_id
 		WHERE f.id = $1
-		  AND f.created_by = $2
-	`
+		  AND f.deleted_at IS NULL
+		  AND ` + visibleToCondition("$2::uuid")
 
 	var shrubForm ShrubForm
 
@@ -1027,6 +1255,8 @@ func (r *FormsRepository) GetShrubFormById(
 		&shrubForm.OtherPhone,
 		&shrubForm.CallBefore,
 		&shrubForm.IsHoliday,
+		&shrubForm.PropertyID,
+		&shrubForm.ClonedFrom,
 		&shrubForm.FirstAppDate,
 		&shrubForm.LastAppDate,
 		&shrubForm.FleaOnly,
@@ -1038,13 +1268,7 @@ func (r *FormsRepository) GetShrubFormById(
 
 	// Load pesticide applications
 	query = `
-		SELECT
-			pa.id,
-			pa.chem_used,
-			pa.app_timestamp,
-			pa.rate,
-			pa.amount_applied,
-			pa.location_code
+		SELECT` + pestAppColumns + `
 		FROM pesticide_applications pa
 		WHERE pa.form_id = $1
 	`
@@ -1056,27 +1280,26 @@ func (r *FormsRepository) GetShrubFormById(
 
 	var pestApps []PestApp
 	for appRows.Next() {
-		var pestApp PestApp
-		err = appRows.Scan(
-			&pestApp.ID,
-			&pestApp.ChemUsed,
-			&pestApp.AppTimestamp,
-			&pestApp.Rate,
-			&pestApp.AmountApplied,
-			&pestApp.LocationCode,
-		)
+		var row pestAppRow
+		err = appRows.Scan(row.dest()...)
 		if err != nil {
 			return ShrubForm{}, fmt.Errorf("error scanning pesticide application for form: %s. %w", shrubForm.ID, err)
 		}
-		pestApps = append(pestApps, pestApp)
+		pestApps = append(pestApps, row.toDomain())
 	}
 	shrubForm.AppTimes = pestApps
 
+	notes, err := r.listNotes(ctx, shrubForm.ID)
+	if err != nil {
+		return ShrubForm{}, err
+	}
+	shrubForm.Notes = notes
+
 	return shrubForm, nil
 }
 
-// GetLawnFormById returns a single lawn form owned by the given user.
-// It returns sql.ErrNoRows if the form does not exist or is not owned by the user.
+// GetLawnFormById returns a single lawn form owned by the given user or with an application made by them.
+// It returns sql.ErrNoRows if the form does not exist, is in the trash or is not visible to the user.
 func (r *FormsRepository) GetLawnFormById(
 	ctx context.Context,
 	formID string,
@@ -1109,6 +1332,8 @@ func (r *FormsRepository) GetLawnFormById(
 			f.other_phone,
 			f.call_before,
 			f.is_holiday,
+			COALESCE(f.property_id::text, ''),
+			COALESCE(f.cloned_from::text, ''),
 			COALESCE(fad.first_app_date, '1970-01-01 00:00:00'::timestamp) as first_app_date,
 			COALESCE(fad.last_app_date, '1970-01-01 00:00:00'::timestamp) as last_app_date,
 			lf.lawn_area_sq_ft,
@@ -1117,8 +1342,8 @@ func (r *FormsRepository) GetLawnFormById(
 		LEFT JOIN lawn_forms lf ON f.id = lf.form_id
 		LEFT JOIN form_app_dates fad ON f.id = fad.form_id
 		WHERE f.id = $1
-		  AND f.created_by = $2
-	`
+		  AND f.deleted_at IS NULL
+		  AND ` + visibleToCondition("$2::uuid")
 
 	var lawnForm LawnForm
 
@@ -1138,6 +1363,8 @@ func (r *FormsRepository) GetLawnFormById(
 		&lawnForm.OtherPhone,
 		&lawnForm.CallBefore,
 		&lawnForm.IsHoliday,
+		&lawnForm.PropertyID,
+		&lawnForm.ClonedFrom,
 		&lawnForm.FirstAppDate,
 		&lawnForm.LastAppDate,
 		&lawnForm.LawnAreaSqFt,
@@ -1150,13 +1377,7 @@ func (r *FormsRepository) GetLawnFormById(
 
 	// Load pesticide applications
 	query = `
-		SELECT
-			pa.id,
-			pa.chem_used,
-			pa.app_timestamp,
-			pa.rate,
-			pa.amount_applied,
-			pa.location_code
+		SELECT` + pestAppColumns + `
 		FROM pesticide_applications pa
 		WHERE pa.form_id = $1
 	`
@@ -1168,27 +1389,51 @@ func (r *FormsRepository) GetLawnFormById(
 
 	var pestApps []PestApp
 	for appRows.Next() {
-		var pestApp PestApp
-		err = appRows.Scan(
-			&pestApp.ID,
-			&pestApp.ChemUsed,
-			&pestApp.AppTimestamp,
-			&pestApp.Rate,
-			&pestApp.AmountApplied,
-			&pestApp.LocationCode,
-		)
+		var row pestAppRow
+		err = appRows.Scan(row.dest()...)
 		if err != nil {
 			return LawnForm{}, fmt.Errorf("error scanning pesticide application for form: %s. %w", lawnForm.ID, err)
 		}
-		pestApps = append(pestApps, pestApp)
+		pestApps = append(pestApps, row.toDomain())
 	}
 	lawnForm.AppTimes = pestApps
 
+	notes, err := r.listNotes(ctx, lawnForm.ID)
+	if err != nil {
+		return LawnForm{}, err
+	}
+	lawnForm.Notes = notes
+
 	return lawnForm, nil
 }
 
+// lockOwnedForm locks the row of a form owned by the given user inside tx,
+// before anything else about the form is read or validated.
+// The form must be in the trash when trashed is set, and live otherwise.
+// It returns sql.ErrNoRows if there is no such form.
+func lockOwnedForm(ctx context.Context, tx *sql.Tx, formID string, userID string, trashed bool) error {
+	deleted := "deleted_at IS NULL"
+	if trashed {
+		deleted = "deleted_at IS NOT NULL"
+	}
+	return tx.QueryRowContext(ctx, `
+		SELECT id
+		FROM forms
+		WHERE id = $1 AND created_by = $2 AND `+deleted+`
+		FOR UPDATE
+	`, formID, userID).Scan(&formID)
+}
+
 // UpdateShrubFormById updates a shrub form
-// It returns sql.ErrNoRows if the form does not exist or is not owned by the user.
+// Only the form's creator may replace the whole form, as it rewrites every application;
+// applicators add and change their own applications with CreatePestApp and UpdatePestAppById.
+// New applications are attributed to the user unless they name another applicator.
+// It returns sql.ErrNoRows if the form does not exist, is in the trash or is not owned by the user.
+// It returns a ValidationError if an application is invalid or names an unknown applicator,
+// ErrLicenseExpired if the applicator of a new application has an expired license,
+// ErrCallRequired if call_before is set and the customer was not reached before a new or moved
+// application, and ErrHolidayBlackout if is_holiday is set and a new or moved application falls
+// on a Jewish holiday or Shabbat.
 func (r *FormsRepository) UpdateShrubFormById(
 	ctx context.Context,
 	formID string,
@@ -1201,6 +1446,21 @@ func (r *FormsRepository) UpdateShrubFormById(
 	}
 	defer tx.Rollback()
 
+	if err := lockOwnedForm(ctx, tx, formID, userID, false); err != nil {
+		//sql.ErrNoRows
+		return ShrubForm{}, err
+	}
+
+	before, err := loadFormSnapshot(ctx, tx, formID)
+	if err != nil {
+		//sql.ErrNoRows
+		return ShrubForm{}, err
+	}
+
+	if err := validatePestApps(shrubFormInput.Applications, before.Applications); err != nil {
+		return ShrubForm{}, err
+	}
+
 	var shrubForm ShrubForm
 
 	err = tx.QueryRowContext(ctx, `
@@ -1215,7 +1475,7 @@ func (r *FormsRepository) UpdateShrubFormById(
 			other_phone = $8,
 			call_before = $9,
 			is_holiday = $10
-		WHERE id = $11 AND created_by = $12
+		WHERE id = $11 AND created_by = $12 AND deleted_at IS NULL
 		RETURNING
 			id,
 			created_by,
@@ -1231,7 +1491,9 @@ func (r *FormsRepository) UpdateShrubFormById(
 			home_phone,
 			other_phone,
 			call_before,
-			is_holiday
+			is_holiday,
+			COALESCE(property_id::text, ''),
+			COALESCE(cloned_from::text, '')
 	`,
 		shrubFormInput.FirstName,
 		shrubFormInput.LastName,
@@ -1261,6 +1523,8 @@ func (r *FormsRepository) UpdateShrubFormById(
 		&shrubForm.OtherPhone,
 		&shrubForm.CallBefore,
 		&shrubForm.IsHoliday,
+		&shrubForm.PropertyID,
+		&shrubForm.ClonedFrom,
 	)
 	if err != nil {
 		//sql.ErrNoRows
@@ -1282,6 +1546,32 @@ func (r *FormsRepository) UpdateShrubFormById(
 		return ShrubForm{}, err
 	}
 
+	if shrubFormInput.Applications != nil {
+		shrubFormInput.Applications = withApplicator(shrubFormInput.Applications, userID)
+		if err := checkChemicalsActive(ctx, tx, shrubFormInput.Applications, before.Applications, appFieldPrefix); err != nil {
+			return ShrubForm{}, err
+		}
+		if err := checkRateUnits(ctx, tx, shrubFormInput.Applications, appFieldPrefix); err != nil {
+			return ShrubForm{}, err
+		}
+		if err := checkApplicators(ctx, tx, shrubFormInput.Applications, appFieldPrefix); err != nil {
+			return ShrubForm{}, err
+		}
+		if err := checkCallBefore(ctx, tx, formID, shrubFormInput.CallBefore, shrubFormInput.Applications, before.Applications, nil); err != nil {
+			return ShrubForm{}, err
+		}
+		if err := checkHolidayBlackout(shrubFormInput.IsHoliday, shrubFormInput.Applications, before.Applications); err != nil {
+			return ShrubForm{}, err
+		}
+		if err := syncPestApps(ctx, tx, formID, shrubFormInput.Applications); err != nil {
+			return ShrubForm{}, err
+		}
+	}
+
+	if _, err := recordRevision(ctx, tx, formID, userID, RevisionActionUpdate, before); err != nil {
+		return ShrubForm{}, err
+	}
+
 	if err := tx.Commit(); err != nil {
 		return ShrubForm{}, fmt.Errorf("error committing transaction: %w", err)
 	}
@@ -1291,7 +1581,15 @@ func (r *FormsRepository) UpdateShrubFormById(
 }
 
 // UpdateLawnFormById updates a lawn form
-// It returns sql.ErrNoRows if the form does not exist or is not owned by the user.
+// Only the form's creator may replace the whole form, as it rewrites every application;
+// applicators add and change their own applications with CreatePestApp and UpdatePestAppById.
+// New applications are attributed to the user unless they name another applicator.
+// It returns sql.ErrNoRows if the form does not exist, is in the trash or is not owned by the user.
+// It returns a ValidationError if an application is invalid or names an unknown applicator,
+// ErrLicenseExpired if the applicator of a new application has an expired license,
+// ErrCallRequired if call_before is set and the customer was not reached before a new or moved
+// application, and ErrHolidayBlackout if is_holiday is set and a new or moved application falls
+// on a Jewish holiday or Shabbat.
 func (r *FormsRepository) UpdateLawnFormById(
 	ctx context.Context,
 	formID string,
@@ -1304,6 +1602,21 @@ func (r *FormsRepository) UpdateLawnFormById(
 	}
 	defer tx.Rollback()
 
+	if err := lockOwnedForm(ctx, tx, formID, userID, false); err != nil {
+		//sql.ErrNoRows
+		return LawnForm{}, err
+	}
+
+	before, err := loadFormSnapshot(ctx, tx, formID)
+	if err != nil {
+		//sql.ErrNoRows
+		return LawnForm{}, err
+	}
+
+	if err := validatePestApps(lawnFormInput.Applications, before.Applications); err != nil {
+		return LawnForm{}, err
+	}
+
 	var lawnForm LawnForm
 
 	err = tx.QueryRowContext(ctx, `
@@ -1318,7 +1631,7 @@ func (r *FormsRepository) UpdateLawnFormById(
 			other_phone = $8,
 			call_before = $9,
 			is_holiday = $10
-		WHERE id = $11 AND created_by = $12
+		WHERE id = $11 AND created_by = $12 AND deleted_at IS NULL
 		RETURNING
 			id,
 			created_by,
@@ -1334,7 +1647,9 @@ func (r *FormsRepository) UpdateLawnFormById(
 			home_phone,
 			other_phone,
 			call_before,
-			is_holiday
+			is_holiday,
+			COALESCE(property_id::text, ''),
+			COALESCE(cloned_from::text, '')
 	`,
 		lawnFormInput.FirstName,
 		lawnFormInput.LastName,
@@ -1364,6 +1679,8 @@ func (r *FormsRepository) UpdateLawnFormById(
 		&lawnForm.OtherPhone,
 		&lawnForm.CallBefore,
 		&lawnForm.IsHoliday,
+		&lawnForm.PropertyID,
+		&lawnForm.ClonedFrom,
 	)
 	if err != nil {
 		//sql.ErrNoRows
@@ -1387,6 +1704,35 @@ func (r *FormsRepository) UpdateLawnFormById(
 		return LawnForm{}, err
 	}
 
+	if lawnFormInput.Applications != nil {
+		lawnFormInput.Applications = withApplicator(lawnFormInput.Applications, userID)
+		if err := checkChemicalsActive(ctx, tx, lawnFormInput.Applications, before.Applications, appFieldPrefix); err != nil {
+			return LawnForm{}, err
+		}
+		if err := checkRateUnits(ctx, tx, lawnFormInput.Applications, appFieldPrefix); err != nil {
+			return LawnForm{}, err
+		}
+		if err := checkApplicators(ctx, tx, lawnFormInput.Applications, appFieldPrefix); err != nil {
+			return LawnForm{}, err
+		}
+		if err := checkCallBefore(ctx, tx, formID, lawnFormInput.CallBefore, lawnFormInput.Applications, before.Applications, nil); err != nil {
+			return LawnForm{}, err
+		}
+		if err := checkHolidayBlackout(lawnFormInput.IsHoliday, lawnFormInput.Applications, before.Applications); err != nil {
+			return LawnForm{}, err
+		}
+		if err := syncPestApps(ctx, tx, formID, lawnFormInput.Applications); err != nil {
+			return LawnForm{}, err
+		}
+	} else if err := refreshLabelChecks(ctx, tx, formID); err != nil {
+		// The expected amounts follow the lawn area
+		return LawnForm{}, err
+	}
+
+	if _, err := recordRevision(ctx, tx, formID, userID, RevisionActionUpdate, before); err != nil {
+		return LawnForm{}, err
+	}
+
 	if err := tx.Commit(); err != nil {
 		return LawnForm{}, fmt.Errorf("error committing transaction: %w", err)
 	}
@@ -1395,18 +1741,37 @@ func (r *FormsRepository) UpdateLawnFormById(
 	return r.GetLawnFormById(ctx, formID, userID)
 }
 
-// DeleteFormById deletes a form owned by the given user.
-// Associated subtype records are removed via ON DELETE CASCADE.
-// It returns sql.ErrNoRows if the form does not exist or is not owned by the user.
+// DeleteFormById moves a form owned by the given user to the trash.
+// The form and its applications and notes are kept until purged and can be
+// brought back with RestoreFormById.
+// It returns sql.ErrNoRows if the form does not exist, is already trashed or is not owned by the user.
 func (r *FormsRepository) DeleteFormById(
 	ctx context.Context,
 	formID string,
 	userID string,
 ) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
 
-	err := r.db.QueryRowContext(ctx, `
-		DELETE FROM forms
-		WHERE id = $1 AND created_by = $2
+	if err := lockOwnedForm(ctx, tx, formID, userID, false); err != nil {
+		//sql.ErrNoRows
+		return err
+	}
+
+	before, err := loadFormSnapshot(ctx, tx, formID)
+	if err != nil {
+		//sql.ErrNoRows
+		return err
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		UPDATE forms
+		SET deleted_at = NOW(),
+			deleted_by = $2
+		WHERE id = $1 AND created_by = $2 AND deleted_at IS NULL
 		RETURNING id
 	`, formID, userID).Scan(&formID)
 
@@ -1415,5 +1780,127 @@ func (r *FormsRepository) DeleteFormById(
 		return err
 	}
 
+	if _, err := recordRevision(ctx, tx, formID, userID, RevisionActionDelete, before); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+// RestoreFormById brings a trashed form owned by the given user back from the trash.
+// It returns sql.ErrNoRows if the form does not exist, is not trashed or is not owned by the user.
+func (r *FormsRepository) RestoreFormById(
+	ctx context.Context,
+	formID string,
+	userID string,
+) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := lockOwnedForm(ctx, tx, formID, userID, true); err != nil {
+		//sql.ErrNoRows
+		return err
+	}
+
+	before, err := loadFormSnapshot(ctx, tx, formID)
+	if err != nil {
+		//sql.ErrNoRows
+		return err
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		UPDATE forms
+		SET deleted_at = NULL,
+			deleted_by = NULL
+		WHERE id = $1 AND created_by = $2 AND deleted_at IS NOT NULL
+		RETURNING id
+	`, formID, userID).Scan(&formID)
+	if err != nil {
+		// sql.ErrNoRows → not found, not trashed or not owned
+		return err
+	}
+
+	if _, err := recordRevision(ctx, tx, formID, userID, RevisionActionRestore, before); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
 	return nil
 }
+
+// PurgeDeletedForms permanently removes every form trashed before the given cutoff (admin only).
+// Associated subtype records, applications and notes are removed via ON DELETE CASCADE,
+// while each form's final state is kept in its revision history.
+// Returns the IDs of the purged forms.
+func (r *FormsRepository) PurgeDeletedForms(
+	ctx context.Context,
+	deletedBefore time.Time,
+	adminID string,
+) ([]string, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id
+		FROM forms
+		WHERE deleted_at IS NOT NULL
+		  AND deleted_at < $1
+		ORDER BY deleted_at
+		FOR UPDATE
+	`, deletedBefore)
+	if err != nil {
+		return nil, fmt.Errorf("error querying trashed forms: %w", err)
+	}
+
+	formIDs := []string{}
+	for rows.Next() {
+		var formID string
+		if err := rows.Scan(&formID); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("error scanning trashed form: %w", err)
+		}
+		formIDs = append(formIDs, formID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after trashed forms query: %w", err)
+	}
+
+	for _, formID := range formIDs {
+		before, err := loadFormSnapshot(ctx, tx, formID)
+		if err != nil {
+			return nil, fmt.Errorf("error snapshotting form %s for purge: %w", formID, err)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			DELETE FROM forms
+			WHERE id = $1
+		`, formID)
+		if err != nil {
+			return nil, fmt.Errorf("error purging form %s: %w", formID, err)
+		}
+
+		if _, err := recordDeletion(ctx, tx, formID, adminID, RevisionActionPurge, before); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return formIDs, nil
+}
@@ -3,16 +3,21 @@ package forms
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"os"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/BiryaniJedi/LandscapeForm-backend/internal/db"
+	"github.com/lib/pq"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/require"
 )
 
 // Helper to create a test chemical
-func createTestChemical(t *testing.T, db *sql.DB, category string) int {
+func createTestChemical(t testing.TB, db *sql.DB, category string) int {
 	t.Helper()
 
 	var id int
@@ -73,7 +78,7 @@ func TestListFormsByUserId_SortByFirstAppDate(t *testing.T) {
 				AppTimestamp:  now.Add(-72 * time.Hour),
 				Rate:          "2 oz/1000 sq ft",
 				AmountApplied: decimal.NewFromFloat(2.0),
-				LocationCode:  "FL",
+				LocationCode:  "1A",
 			},
 		},
 	})
@@ -100,7 +105,7 @@ func TestListFormsByUserId_SortByFirstAppDate(t *testing.T) {
 				AppTimestamp:  now.Add(-24 * time.Hour),
 				Rate:          "2 oz/1000 sq ft",
 				AmountApplied: decimal.NewFromFloat(2.0),
-				LocationCode:  "FL",
+				LocationCode:  "1A",
 			},
 		},
 	})
@@ -127,7 +132,7 @@ func TestListFormsByUserId_SortByFirstAppDate(t *testing.T) {
 				AppTimestamp:  now.Add(-48 * time.Hour),
 				Rate:          "2 oz/1000 sq ft",
 				AmountApplied: decimal.NewFromFloat(2.0),
-				LocationCode:  "FL",
+				LocationCode:  "1A",
 			},
 		},
 	})
@@ -157,8 +162,9 @@ func TestListFormsByUserId_SortByFirstAppDate(t *testing.T) {
 		SortBy: "first_app_date",
 		Order:  "ASC",
 	}
-	forms, err := repo.ListFormsByUserId(ctx, userID, listOptions)
+	page, err := repo.ListFormsByUserId(ctx, userID, listOptions)
 	require.NoError(t, err)
+	forms := page.Forms
 	require.Len(t, forms, 4)
 
 	require.Equal(t, "Early", getFirstName(forms[0]))   // 3 days ago
@@ -168,8 +174,9 @@ func TestListFormsByUserId_SortByFirstAppDate(t *testing.T) {
 
 	// Test DESC order (newest first, nulls last)
 	listOptions.Order = "DESC"
-	forms, err = repo.ListFormsByUserId(ctx, userID, listOptions)
+	page, err = repo.ListFormsByUserId(ctx, userID, listOptions)
 	require.NoError(t, err)
+	forms = page.Forms
 	require.Len(t, forms, 4)
 
 	require.Equal(t, "Late", getFirstName(forms[0]))    // 1 day ago
@@ -210,7 +217,7 @@ func TestListFormsByUserId_FilterByDateLow(t *testing.T) {
 				AppTimestamp:  now.Add(-72 * time.Hour),
 				Rate:          "2 oz/1000 sq ft",
 				AmountApplied: decimal.NewFromFloat(2.0),
-				LocationCode:  "FL",
+				LocationCode:  "1A",
 			},
 		},
 	})
@@ -237,7 +244,7 @@ func TestListFormsByUserId_FilterByDateLow(t *testing.T) {
 				AppTimestamp:  now.Add(-24 * time.Hour),
 				Rate:          "2 oz/1000 sq ft",
 				AmountApplied: decimal.NewFromFloat(2.0),
-				LocationCode:  "FL",
+				LocationCode:  "1A",
 			},
 		},
 	})
@@ -249,8 +256,9 @@ func TestListFormsByUserId_FilterByDateLow(t *testing.T) {
 		SortBy:  "created_at",
 		Order:   "DESC",
 	}
-	forms, err := repo.ListFormsByUserId(ctx, userID, listOptions)
+	page, err := repo.ListFormsByUserId(ctx, userID, listOptions)
 	require.NoError(t, err)
+	forms := page.Forms
 
 	// Should only get the recent form
 	require.Len(t, forms, 1)
@@ -289,7 +297,7 @@ func TestListFormsByUserId_FilterByDateHigh(t *testing.T) {
 				AppTimestamp:  now.Add(-72 * time.Hour),
 				Rate:          "2 oz/1000 sq ft",
 				AmountApplied: decimal.NewFromFloat(2.0),
-				LocationCode:  "FL",
+				LocationCode:  "1A",
 			},
 		},
 	})
@@ -316,7 +324,7 @@ func TestListFormsByUserId_FilterByDateHigh(t *testing.T) {
 				AppTimestamp:  now.Add(-24 * time.Hour),
 				Rate:          "2 oz/1000 sq ft",
 				AmountApplied: decimal.NewFromFloat(2.0),
-				LocationCode:  "FL",
+				LocationCode:  "1A",
 			},
 		},
 	})
@@ -328,8 +336,9 @@ func TestListFormsByUserId_FilterByDateHigh(t *testing.T) {
 		SortBy:   "created_at",
 		Order:    "DESC",
 	}
-	forms, err := repo.ListFormsByUserId(ctx, userID, listOptions)
+	page, err := repo.ListFormsByUserId(ctx, userID, listOptions)
 	require.NoError(t, err)
+	forms := page.Forms
 
 	// Should only get the old form
 	require.Len(t, forms, 1)
@@ -367,7 +376,7 @@ func TestListFormsByUserId_FilterByDateRange(t *testing.T) {
 				AppTimestamp:  now.Add(-120 * time.Hour),
 				Rate:          "2 oz/1000 sq ft",
 				AmountApplied: decimal.NewFromFloat(2.0),
-				LocationCode:  "FL",
+				LocationCode:  "1A",
 			},
 		},
 	})
@@ -394,14 +403,14 @@ func TestListFormsByUserId_FilterByDateRange(t *testing.T) {
 				AppTimestamp:  now.Add(-72 * time.Hour), // 3 days ago
 				Rate:          "2 oz/1000 sq ft",
 				AmountApplied: decimal.NewFromFloat(2.0),
-				LocationCode:  "FL",
+				LocationCode:  "1A",
 			},
 			{
 				ChemUsed:      chemID,
 				AppTimestamp:  now.Add(-48 * time.Hour), // 2 days ago
 				Rate:          "2 oz/1000 sq ft",
 				AmountApplied: decimal.NewFromFloat(2.0),
-				LocationCode:  "FL",
+				LocationCode:  "1A",
 			},
 		},
 	})
@@ -428,7 +437,7 @@ func TestListFormsByUserId_FilterByDateRange(t *testing.T) {
 				AppTimestamp:  now.Add(-12 * time.Hour),
 				Rate:          "2 oz/1000 sq ft",
 				AmountApplied: decimal.NewFromFloat(2.0),
-				LocationCode:  "FL",
+				LocationCode:  "1A",
 			},
 		},
 	})
@@ -441,8 +450,9 @@ func TestListFormsByUserId_FilterByDateRange(t *testing.T) {
 		SortBy:   "created_at",
 		Order:    "DESC",
 	}
-	forms, err := repo.ListFormsByUserId(ctx, userID, listOptions)
+	page, err := repo.ListFormsByUserId(ctx, userID, listOptions)
 	require.NoError(t, err)
+	forms := page.Forms
 
 	// Should only get the InRange form
 	require.Len(t, forms, 1)
@@ -513,8 +523,9 @@ func TestListFormsByUserId_FilterByZipCode(t *testing.T) {
 		SortBy:  "first_name",
 		Order:   "ASC",
 	}
-	forms, err := repo.ListFormsByUserId(ctx, userID, listOptions)
+	page, err := repo.ListFormsByUserId(ctx, userID, listOptions)
 	require.NoError(t, err)
+	forms := page.Forms
 
 	// Should get only Boston forms
 	require.Len(t, forms, 2)
@@ -571,8 +582,9 @@ func TestListFormsByUserId_FilterByJewishHolidayYes(t *testing.T) {
 		SortBy:        "created_at",
 		Order:         "DESC",
 	}
-	forms, err := repo.ListFormsByUserId(ctx, userID, listOptions)
+	page, err := repo.ListFormsByUserId(ctx, userID, listOptions)
 	require.NoError(t, err)
+	forms := page.Forms
 
 	// Should only get holiday form
 	require.Len(t, forms, 1)
@@ -628,8 +640,9 @@ func TestListFormsByUserId_FilterByJewishHolidayNo(t *testing.T) {
 		SortBy:        "created_at",
 		Order:         "DESC",
 	}
-	forms, err := repo.ListFormsByUserId(ctx, userID, listOptions)
+	page, err := repo.ListFormsByUserId(ctx, userID, listOptions)
 	require.NoError(t, err)
+	forms := page.Forms
 
 	// Should only get regular form
 	require.Len(t, forms, 1)
@@ -684,8 +697,9 @@ func TestListFormsByUserId_FilterByFormType(t *testing.T) {
 		SortBy:   "created_at",
 		Order:    "DESC",
 	}
-	forms, err := repo.ListFormsByUserId(ctx, userID, listOptions)
+	page, err := repo.ListFormsByUserId(ctx, userID, listOptions)
 	require.NoError(t, err)
+	forms := page.Forms
 
 	require.Len(t, forms, 1)
 	require.Equal(t, "Lawn", getFirstName(forms[0]))
@@ -693,8 +707,9 @@ func TestListFormsByUserId_FilterByFormType(t *testing.T) {
 
 	// Filter for shrub forms only
 	listOptions.FormType = "shrub"
-	forms, err = repo.ListFormsByUserId(ctx, userID, listOptions)
+	page, err = repo.ListFormsByUserId(ctx, userID, listOptions)
 	require.NoError(t, err)
+	forms = page.Forms
 
 	require.Len(t, forms, 1)
 	require.Equal(t, "Shrub", getFirstName(forms[0]))
@@ -765,8 +780,9 @@ func TestListFormsByUserId_FilterBySearchName(t *testing.T) {
 		SortBy:     "first_name",
 		Order:      "ASC",
 	}
-	forms, err := repo.ListFormsByUserId(ctx, userID, listOptions)
+	page, err := repo.ListFormsByUserId(ctx, userID, listOptions)
 	require.NoError(t, err)
+	forms := page.Forms
 
 	require.Len(t, forms, 2)
 	require.Equal(t, "Alice", getFirstName(forms[0]))
@@ -774,8 +790,9 @@ func TestListFormsByUserId_FilterBySearchName(t *testing.T) {
 
 	// Search by first name "bob" (case insensitive)
 	listOptions.SearchName = "BoB"
-	forms, err = repo.ListFormsByUserId(ctx, userID, listOptions)
+	page, err = repo.ListFormsByUserId(ctx, userID, listOptions)
 	require.NoError(t, err)
+	forms = page.Forms
 
 	require.Len(t, forms, 1)
 	require.Equal(t, "Bob", getFirstName(forms[0]))
@@ -814,7 +831,7 @@ func TestListFormsByUserId_FilterByChemicals(t *testing.T) {
 				AppTimestamp:  now,
 				Rate:          "2 oz/1000 sq ft",
 				AmountApplied: decimal.NewFromFloat(2.0),
-				LocationCode:  "FL",
+				LocationCode:  "1A",
 			},
 		},
 	})
@@ -841,7 +858,7 @@ func TestListFormsByUserId_FilterByChemicals(t *testing.T) {
 				AppTimestamp:  now,
 				Rate:          "2 oz/1000 sq ft",
 				AmountApplied: decimal.NewFromFloat(2.0),
-				LocationCode:  "FL",
+				LocationCode:  "1A",
 			},
 		},
 	})
@@ -867,14 +884,14 @@ func TestListFormsByUserId_FilterByChemicals(t *testing.T) {
 				AppTimestamp:  now,
 				Rate:          "2 oz/1000 sq ft",
 				AmountApplied: decimal.NewFromFloat(2.0),
-				LocationCode:  "FL",
+				LocationCode:  "1A",
 			},
 			{
 				ChemUsed:      chem3,
 				AppTimestamp:  now,
 				Rate:          "2 oz/1000 sq ft",
 				AmountApplied: decimal.NewFromFloat(2.0),
-				LocationCode:  "FL",
+				LocationCode:  "1A",
 			},
 		},
 	})
@@ -886,8 +903,9 @@ func TestListFormsByUserId_FilterByChemicals(t *testing.T) {
 		SortBy:      "first_name",
 		Order:       "ASC",
 	}
-	forms, err := repo.ListFormsByUserId(ctx, userID, listOptions)
+	page, err := repo.ListFormsByUserId(ctx, userID, listOptions)
 	require.NoError(t, err)
+	forms := page.Forms
 
 	require.Len(t, forms, 2)
 	require.Equal(t, "User1", getFirstName(forms[0]))
@@ -895,8 +913,9 @@ func TestListFormsByUserId_FilterByChemicals(t *testing.T) {
 
 	// Filter by chem1 OR chem2
 	listOptions.ChemicalIDs = []int{chem1, chem2}
-	forms, err = repo.ListFormsByUserId(ctx, userID, listOptions)
+	page, err = repo.ListFormsByUserId(ctx, userID, listOptions)
 	require.NoError(t, err)
+	forms = page.Forms
 
 	require.Len(t, forms, 3)
 	require.Equal(t, "User1", getFirstName(forms[0]))
@@ -905,8 +924,9 @@ func TestListFormsByUserId_FilterByChemicals(t *testing.T) {
 
 	// Filter by chem3 only
 	listOptions.ChemicalIDs = []int{chem3}
-	forms, err = repo.ListFormsByUserId(ctx, userID, listOptions)
+	page, err = repo.ListFormsByUserId(ctx, userID, listOptions)
 	require.NoError(t, err)
+	forms = page.Forms
 
 	require.Len(t, forms, 1)
 	require.Equal(t, "User3", getFirstName(forms[0]))
@@ -943,7 +963,7 @@ func TestListFormsByUserId_CombinedFilters(t *testing.T) {
 				AppTimestamp:  now.Add(-24 * time.Hour),
 				Rate:          "2 oz/1000 sq ft",
 				AmountApplied: decimal.NewFromFloat(2.0),
-				LocationCode:  "FL",
+				LocationCode:  "1A",
 			},
 		},
 	})
@@ -970,7 +990,7 @@ func TestListFormsByUserId_CombinedFilters(t *testing.T) {
 				AppTimestamp:  now.Add(-24 * time.Hour),
 				Rate:          "2 oz/1000 sq ft",
 				AmountApplied: decimal.NewFromFloat(2.0),
-				LocationCode:  "FL",
+				LocationCode:  "1A",
 			},
 		},
 	})
@@ -996,7 +1016,7 @@ func TestListFormsByUserId_CombinedFilters(t *testing.T) {
 				AppTimestamp:  now.Add(-24 * time.Hour),
 				Rate:          "2 oz/1000 sq ft",
 				AmountApplied: decimal.NewFromFloat(2.0),
-				LocationCode:  "FL",
+				LocationCode:  "1A",
 			},
 		},
 	})
@@ -1023,7 +1043,7 @@ func TestListFormsByUserId_CombinedFilters(t *testing.T) {
 				AppTimestamp:  now.Add(-24 * time.Hour),
 				Rate:          "2 oz/1000 sq ft",
 				AmountApplied: decimal.NewFromFloat(2.0),
-				LocationCode:  "FL",
+				LocationCode:  "1A",
 			},
 		},
 	})
@@ -1039,8 +1059,9 @@ func TestListFormsByUserId_CombinedFilters(t *testing.T) {
 		SortBy:        "first_app_date",
 		Order:         "DESC",
 	}
-	forms, err := repo.ListFormsByUserId(ctx, userID, listOptions)
+	page, err := repo.ListFormsByUserId(ctx, userID, listOptions)
 	require.NoError(t, err)
+	forms := page.Forms
 
 	// Should only match the first form
 	require.Len(t, forms, 1)
@@ -1105,22 +1126,139 @@ func TestListAllForms_WithFilters(t *testing.T) {
 		SortBy: "first_name",
 		Order:  "ASC",
 	}
-	forms, err := repo.ListAllForms(ctx, listOptions)
+	page, err := repo.ListAllForms(ctx, listOptions)
 	require.NoError(t, err)
+	forms := page.Forms
 	require.Len(t, forms, 2)
 
 	// Filter by zip code in ListAllForms
 	listOptions.ZipCode = "02134"
-	forms, err = repo.ListAllForms(ctx, listOptions)
+	page, err = repo.ListAllForms(ctx, listOptions)
 	require.NoError(t, err)
+	forms = page.Forms
 	require.Len(t, forms, 1)
 	require.Equal(t, "User1", getFirstName(forms[0]))
 
 	// Filter by jewish_holiday = no in ListAllForms
 	listOptions.ZipCode = ""
 	listOptions.JewishHoliday = "no"
-	forms, err = repo.ListAllForms(ctx, listOptions)
+	page, err = repo.ListAllForms(ctx, listOptions)
 	require.NoError(t, err)
+	forms = page.Forms
 	require.Len(t, forms, 1)
 	require.Equal(t, "User2", getFirstName(forms[0]))
 }
+
+// queryCounter wraps the postgres connector and counts every statement sent to the database
+type queryCounter struct {
+	driver.Connector
+	queries atomic.Int64
+}
+
+func (c *queryCounter) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &countingConn{Conn: conn, counter: c}, nil
+}
+
+type countingConn struct {
+	driver.Conn
+	counter *queryCounter
+}
+
+func (c *countingConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.counter.queries.Add(1)
+	return c.Conn.(driver.QueryerContext).QueryContext(ctx, query, args)
+}
+
+func (c *countingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.counter.queries.Add(1)
+	return c.Conn.(driver.ExecerContext).ExecContext(ctx, query, args)
+}
+
+func BenchmarkListForms_QueriesPerPage(b *testing.B) {
+	ctx := context.Background()
+	testDB := db.TestDB(b)
+	repo := NewFormsRepository(testDB)
+
+	userID := createTestUser(b, testDB)
+	chemID := createTestChemical(b, testDB, "lawn")
+
+	now := time.Now()
+	for i := 0; i < 100; i++ {
+		formID, err := repo.CreateLawnForm(ctx, CreateLawnFormInput{
+			CreatedBy:    userID,
+			FirstName:    fmt.Sprintf("Bench%03d", i),
+			LastName:     "Customer",
+			StreetNumber: fmt.Sprintf("%d", i),
+			StreetName:   "Bench St",
+			Town:         "Town",
+			ZipCode:      "10001",
+			HomePhone:    "555-0001",
+			OtherPhone:   "555-0002",
+			LawnAreaSqFt: 1000,
+			Applications: []PestApp{
+				{
+					ChemUsed:      chemID,
+					AppTimestamp:  now.Add(-48 * time.Hour),
+					Rate:          "2 oz/1000 sq ft",
+					AmountApplied: decimal.NewFromFloat(2.0),
+					LocationCode:  "1A",
+				},
+				{
+					ChemUsed:      chemID,
+					AppTimestamp:  now.Add(-24 * time.Hour),
+					Rate:          "2 oz/1000 sq ft",
+					AmountApplied: decimal.NewFromFloat(2.0),
+					LocationCode:  "1A",
+				},
+			},
+			// The forms share phones and near-identical names on purpose
+			Force: true,
+		})
+		require.NoError(b, err)
+
+		_, err = repo.CreateNote(ctx, formID, userID, "gate code 1234")
+		require.NoError(b, err)
+	}
+
+	connector, err := pq.NewConnector(os.Getenv("DATABASE_URL"))
+	require.NoError(b, err)
+	counter := &queryCounter{Connector: connector}
+	countedDB := sql.OpenDB(counter)
+	b.Cleanup(func() {
+		_ = countedDB.Close()
+	})
+	countedRepo := NewFormsRepository(countedDB)
+
+	for _, pageSize := range []int{10, 50, 100} {
+		b.Run(fmt.Sprintf("ListFormsByUserId/%d", pageSize), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				counter.queries.Store(0)
+				page, err := countedRepo.ListFormsByUserId(ctx, userID, ListFormsOptions{Limit: pageSize})
+				require.NoError(b, err)
+				require.Len(b, page.Forms, pageSize)
+				require.Len(b, page.Forms[0].Lawn.AppTimes, 2)
+				require.Len(b, page.Forms[0].Lawn.Notes, 1)
+
+				// One query for the total, one for the page, one for its applications and one for its notes
+				require.EqualValues(b, 4, counter.queries.Load())
+			}
+			b.ReportMetric(float64(counter.queries.Load()), "queries/op")
+		})
+
+		b.Run(fmt.Sprintf("ListAllForms/%d", pageSize), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				counter.queries.Store(0)
+				page, err := countedRepo.ListAllForms(ctx, ListFormsOptions{Limit: pageSize})
+				require.NoError(b, err)
+				require.Len(b, page.Forms, pageSize)
+
+				require.EqualValues(b, 4, counter.queries.Load())
+			}
+			b.ReportMetric(float64(counter.queries.Load()), "queries/op")
+		})
+	}
+}
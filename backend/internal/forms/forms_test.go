@@ -18,7 +18,7 @@ func TestMain(m *testing.M) {
 	os.Exit(m.Run())
 }
 
-func createTestUser(t *testing.T, db *sql.DB) string {
+func createTestUser(t testing.TB, db *sql.DB) string {
 	t.Helper()
 
 	var id string
@@ -33,6 +33,55 @@ func createTestUser(t *testing.T, db *sql.DB) string {
 	return id
 }
 
+func setTestDefault(field *string, value string) {
+	if *field == "" {
+		*field = value
+	}
+}
+
+// createTestShrubForm creates a shrub form, filling in the client fields input leaves empty.
+// Duplicate detection is skipped, as test forms often share an address or phones.
+func createTestShrubForm(t testing.TB, repo *FormsRepository, input CreateShrubFormInput) string {
+	t.Helper()
+
+	setTestDefault(&input.FirstName, "Test")
+	setTestDefault(&input.LastName, "Customer")
+	setTestDefault(&input.StreetNumber, "1")
+	setTestDefault(&input.StreetName, "Test St")
+	setTestDefault(&input.Town, "Town")
+	setTestDefault(&input.ZipCode, "10001")
+	setTestDefault(&input.HomePhone, "555-0001")
+	setTestDefault(&input.OtherPhone, "555-0000")
+	input.Force = true
+
+	formID, err := repo.CreateShrubForm(context.Background(), input)
+	require.NoError(t, err)
+	return formID
+}
+
+// createTestLawnForm creates a lawn form like createTestShrubForm, with a 1000 sq ft lawn
+// unless input sets the area
+func createTestLawnForm(t testing.TB, repo *FormsRepository, input CreateLawnFormInput) string {
+	t.Helper()
+
+	setTestDefault(&input.FirstName, "Test")
+	setTestDefault(&input.LastName, "Customer")
+	setTestDefault(&input.StreetNumber, "1")
+	setTestDefault(&input.StreetName, "Test St")
+	setTestDefault(&input.Town, "Town")
+	setTestDefault(&input.ZipCode, "10001")
+	setTestDefault(&input.HomePhone, "555-0001")
+	setTestDefault(&input.OtherPhone, "555-0000")
+	if input.LawnAreaSqFt == 0 {
+		input.LawnAreaSqFt = 1000
+	}
+	input.Force = true
+
+	formID, err := repo.CreateLawnForm(context.Background(), input)
+	require.NoError(t, err)
+	return formID
+}
+
 func TestCreateAndGetShrubForm(t *testing.T) {
 	ctx := context.Background()
 
@@ -140,8 +189,9 @@ func TestListFormsByUserId_Empty(t *testing.T) {
 		Order:  "DESC",
 	}
 
-	forms, err := repo.ListFormsByUserId(ctx, userID, listOptions)
+	page, err := repo.ListFormsByUserId(ctx, userID, listOptions)
 	require.NoError(t, err)
+	forms := page.Forms
 	require.Empty(t, forms)
 }
 
@@ -208,8 +258,9 @@ func TestListFormsByUserId_MultipleForms(t *testing.T) {
 		SortBy: "created_at",
 		Order:  "DESC",
 	}
-	forms, err := repo.ListFormsByUserId(ctx, userID, listOptions)
+	page, err := repo.ListFormsByUserId(ctx, userID, listOptions)
 	require.NoError(t, err)
+	forms := page.Forms
 	require.Len(t, forms, 3)
 
 	// Check types are correct
@@ -281,8 +332,9 @@ func TestListFormsByUserId_SortByFirstName(t *testing.T) {
 		SortBy: "first_name",
 		Order:  "ASC",
 	}
-	forms, err := repo.ListFormsByUserId(ctx, userID, listOptions)
+	page, err := repo.ListFormsByUserId(ctx, userID, listOptions)
 	require.NoError(t, err)
+	forms := page.Forms
 	require.Len(t, forms, 3)
 
 	// Helper to get first name from FormView
@@ -299,8 +351,9 @@ func TestListFormsByUserId_SortByFirstName(t *testing.T) {
 
 	// Sort by first_name DESC
 	listOptions.Order = "DESC"
-	forms, err = repo.ListFormsByUserId(ctx, userID, listOptions)
+	page, err = repo.ListFormsByUserId(ctx, userID, listOptions)
 	require.NoError(t, err)
+	forms = page.Forms
 	require.Len(t, forms, 3)
 	require.Equal(t, "Zoe", getFirstName(forms[0]))
 	require.Equal(t, "Michael", getFirstName(forms[1]))
@@ -351,8 +404,9 @@ func TestListFormsByUserId_SortByLastName(t *testing.T) {
 		SortBy: "last_name",
 		Order:  "ASC",
 	}
-	forms, err := repo.ListFormsByUserId(ctx, userID, listOptions)
+	page, err := repo.ListFormsByUserId(ctx, userID, listOptions)
 	require.NoError(t, err)
+	forms := page.Forms
 	require.Len(t, forms, 2)
 
 	// Helper to get last name from FormView
@@ -427,14 +481,16 @@ func TestListFormsByUserId_OnlyOwnForms(t *testing.T) {
 	}
 
 	// User 1 should only see their own form
-	user1Forms, err := repo.ListFormsByUserId(ctx, user1ID, ListFormsOptions{SortBy: "created_at", Order: "DESC"})
+	user1Page, err := repo.ListFormsByUserId(ctx, user1ID, ListFormsOptions{SortBy: "created_at", Order: "DESC"})
 	require.NoError(t, err)
+	user1Forms := user1Page.Forms
 	require.Len(t, user1Forms, 1)
 	require.Equal(t, "User1", getFirstName(user1Forms[0]))
 
 	// User 2 should only see their own form
-	user2Forms, err := repo.ListFormsByUserId(ctx, user2ID, ListFormsOptions{SortBy: "created_at", Order: "DESC"})
+	user2Page, err := repo.ListFormsByUserId(ctx, user2ID, ListFormsOptions{SortBy: "created_at", Order: "DESC"})
 	require.NoError(t, err)
+	user2Forms := user2Page.Forms
 	require.Len(t, user2Forms, 1)
 	require.Equal(t, "User2", getFirstName(user2Forms[0]))
 }
@@ -665,6 +721,13 @@ func TestUpdateShrubFormById_WrongUser(t *testing.T) {
 	)
 	require.Error(t, err)
 	require.Equal(t, sql.ErrNoRows, err)
+
+	// Nor does an invalid application reveal that the form exists
+	_, err = repo.UpdateShrubFormById(ctx, shrubFormId, user2ID, UpdateShrubFormInput{
+		FirstName:    "Hacked",
+		Applications: []PestApp{{}},
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
 }
 
 func TestUpdateLawnFormById_WrongUser(t *testing.T) {
@@ -757,11 +820,16 @@ func TestDeleteFormById_Success(t *testing.T) {
 	require.Error(t, err)
 	require.Equal(t, sql.ErrNoRows, err)
 
-	// Verify shrub details also deleted (cascade)
+	// Verify the form and its shrub details are kept in the trash
 	var count int
 	err = db.QueryRow(`SELECT COUNT(*) FROM shrub_forms WHERE form_id = $1`, shrubFormId).Scan(&count)
 	require.NoError(t, err)
-	require.Equal(t, 0, count)
+	require.Equal(t, 1, count)
+
+	var deletedBy string
+	err = db.QueryRow(`SELECT deleted_by FROM forms WHERE id = $1 AND deleted_at IS NOT NULL`, shrubFormId).Scan(&deletedBy)
+	require.NoError(t, err)
+	require.Equal(t, userID, deletedBy)
 }
 
 func TestDeleteFormById_WrongUser(t *testing.T) {
@@ -0,0 +1,295 @@
+package forms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Import modes, chosen by the caller of ImportForms
+const (
+	// ImportModeAll commits the import only if every row is valid
+	ImportModeAll = "all"
+	// ImportModeValidOnly commits the valid rows and reports the others
+	ImportModeValidOnly = "valid_only"
+)
+
+// ErrInvalidImportMode is returned when ImportForms is given an unknown mode
+var ErrInvalidImportMode = errors.New("import mode must be 'all' or 'valid_only'")
+
+var zipCodePattern = regexp.MustCompile(`^\d{5}(-\d{4})?$`)
+
+// ImportRow is a single form read from an import file
+type ImportRow struct {
+	// Row is the row's position in the source file, reported with its errors
+	Row          int
+	FormType     string
+	PropertyID   string
+	FirstName    string
+	LastName     string
+	StreetNumber string
+	StreetName   string
+	Town         string
+	ZipCode      string
+	HomePhone    string
+	OtherPhone   string
+	CallBefore   bool
+	IsHoliday    bool
+	FleaOnly     bool
+	LawnAreaSqFt int
+	FertOnly     bool
+	Applications []PestApp
+	// Errors holds problems found while reading the row; a row with errors is never imported
+	Errors []ImportError
+}
+
+// ImportError is a problem with one field of an import row.
+// Field is empty when the problem concerns the row as a whole.
+type ImportError struct {
+	Row     int
+	Field   string
+	Message string
+}
+
+// ImportFormsOptions controls how ImportForms treats the rows
+type ImportFormsOptions struct {
+	CreatedBy string
+	// Mode is ImportModeAll or ImportModeValidOnly
+	Mode string
+	// DryRun validates every row, including against the database, without saving anything
+	DryRun bool
+	// Force skips duplicate detection, as on CreateShrubForm and CreateLawnForm
+	Force bool
+}
+
+// ImportedForm is a form created from an import row.
+// FormID is empty unless the import was committed.
+type ImportedForm struct {
+	Row    int
+	FormID string
+}
+
+// ImportResult reports the outcome of an import.
+// Imported lists the rows that were, or in a dry run would have been, created.
+type ImportResult struct {
+	Total     int
+	Imported  []ImportedForm
+	Errors    []ImportError
+	Committed bool
+}
+
+// validateImportRow checks the fields of a row that can be checked without writing it.
+// chemicals holds the IDs of every chemical that has not been deleted.
+func validateImportRow(row ImportRow, chemicals map[int]bool) []ImportError {
+	var errs []ImportError
+	fail := func(field, format string, args ...any) {
+		errs = append(errs, ImportError{Row: row.Row, Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if row.FormType != "shrub" && row.FormType != "lawn" {
+		fail("form_type", "form type must be 'shrub' or 'lawn'")
+	}
+
+	// Forms linked to a property take their client fields from it
+	if row.PropertyID == "" {
+		required := []struct{ field, value string }{
+			{"first_name", row.FirstName},
+			{"last_name", row.LastName},
+			{"street_number", row.StreetNumber},
+			{"street_name", row.StreetName},
+			{"town", row.Town},
+		}
+		for _, r := range required {
+			if strings.TrimSpace(r.value) == "" {
+				fail(r.field, "%s is required", r.field)
+			}
+		}
+		if !zipCodePattern.MatchString(row.ZipCode) {
+			fail("zip_code", "invalid zip code %q", row.ZipCode)
+		}
+	}
+
+	if row.FormType == "lawn" && row.LawnAreaSqFt < 0 {
+		fail("lawn_area_sq_ft", "lawn area cannot be negative")
+	}
+
+	for i, app := range row.Applications {
+		prefix := fmt.Sprintf("applications[%d].", i)
+		if !chemicals[app.ChemUsed] {
+			fail(prefix+"chem_used", "unknown chemical %d", app.ChemUsed)
+		}
+		if app.AppTimestamp.IsZero() {
+			fail(prefix+"app_timestamp", "application time is required")
+		}
+		if strings.TrimSpace(app.Rate) == "" {
+			fail(prefix+"rate", "rate is required")
+		}
+		if app.AmountApplied.IsNegative() {
+			fail(prefix+"amount_applied", "amount applied cannot be negative")
+		}
+		for _, field := range validatePestApp(app, nil, prefix) {
+			fail(field.Field, "%s", field.Message)
+		}
+	}
+
+	return errs
+}
+
+// importRowErrors converts an error from creating a row's form into import errors
+func importRowErrors(row int, err error) []ImportError {
+	var valErr *ValidationError
+	if errors.As(err, &valErr) {
+		errs := make([]ImportError, 0, len(valErr.Fields))
+		for _, field := range valErr.Fields {
+			errs = append(errs, ImportError{Row: row, Field: field.Field, Message: field.Message})
+		}
+		return errs
+	}
+	return []ImportError{importRowError(row, err)}
+}
+
+// importRowError converts an error from creating a row's form into an import error
+func importRowError(row int, err error) ImportError {
+	var dupErr *DuplicateFormsError
+	if errors.As(err, &dupErr) {
+		ids := make([]string, 0, len(dupErr.Candidates))
+		for _, candidate := range dupErr.Candidates {
+			ids = append(ids, candidate.ID)
+		}
+		return ImportError{Row: row, Message: fmt.Sprintf("%s: %s", ErrLikelyDuplicate, strings.Join(ids, ", "))}
+	}
+	if errors.Is(err, ErrPropertyNotFound) {
+		return ImportError{Row: row, Field: "property_id", Message: err.Error()}
+	}
+	return ImportError{Row: row, Message: err.Error()}
+}
+
+// ImportForms creates a form for every row through the same logic as CreateShrubForm and
+// CreateLawnForm, in a single transaction. Each row is validated first and then written
+// under its own savepoint, so database checks and duplicates within the file are reported
+// against the row that caused them.
+// In ImportModeAll nothing is saved if any row fails; in ImportModeValidOnly the valid rows
+// are saved. A dry run reports the same result but never saves.
+// It returns ErrInvalidImportMode for an unknown mode; row problems are reported in the result.
+func (r *FormsRepository) ImportForms(
+	ctx context.Context,
+	rows []ImportRow,
+	opts ImportFormsOptions,
+) (ImportResult, error) {
+	if opts.Mode != ImportModeAll && opts.Mode != ImportModeValidOnly {
+		return ImportResult{}, ErrInvalidImportMode
+	}
+
+	result := ImportResult{
+		Total:    len(rows),
+		Imported: []ImportedForm{},
+		Errors:   []ImportError{},
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return ImportResult{}, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	chemicals := map[int]bool{}
+	chemRows, err := tx.QueryContext(ctx, `SELECT id FROM chemicals WHERE deleted_at IS NULL`)
+	if err != nil {
+		return ImportResult{}, fmt.Errorf("error listing chemicals: %w", err)
+	}
+	for chemRows.Next() {
+		var id int
+		if err := chemRows.Scan(&id); err != nil {
+			chemRows.Close()
+			return ImportResult{}, fmt.Errorf("error scanning chemical: %w", err)
+		}
+		chemicals[id] = true
+	}
+	chemRows.Close()
+	if err := chemRows.Err(); err != nil {
+		return ImportResult{}, fmt.Errorf("error after listing chemicals: %w", err)
+	}
+
+	for _, row := range rows {
+		rowErrs := append(append([]ImportError{}, row.Errors...), validateImportRow(row, chemicals)...)
+		if len(rowErrs) > 0 {
+			result.Errors = append(result.Errors, rowErrs...)
+			continue
+		}
+
+		if _, err := tx.ExecContext(ctx, `SAVEPOINT import_row`); err != nil {
+			return ImportResult{}, fmt.Errorf("error creating savepoint for row %d: %w", row.Row, err)
+		}
+
+		var formID string
+		if row.FormType == "shrub" {
+			formID, err = createShrubForm(ctx, tx, CreateShrubFormInput{
+				CreatedBy:    opts.CreatedBy,
+				PropertyID:   row.PropertyID,
+				FirstName:    row.FirstName,
+				LastName:     row.LastName,
+				StreetNumber: row.StreetNumber,
+				StreetName:   row.StreetName,
+				Town:         row.Town,
+				ZipCode:      row.ZipCode,
+				HomePhone:    row.HomePhone,
+				OtherPhone:   row.OtherPhone,
+				CallBefore:   row.CallBefore,
+				IsHoliday:    row.IsHoliday,
+				FleaOnly:     row.FleaOnly,
+				Applications: row.Applications,
+				Force:        opts.Force,
+				imported:     true,
+			})
+		} else {
+			formID, err = createLawnForm(ctx, tx, CreateLawnFormInput{
+				CreatedBy:    opts.CreatedBy,
+				PropertyID:   row.PropertyID,
+				FirstName:    row.FirstName,
+				LastName:     row.LastName,
+				StreetNumber: row.StreetNumber,
+				StreetName:   row.StreetName,
+				Town:         row.Town,
+				ZipCode:      row.ZipCode,
+				HomePhone:    row.HomePhone,
+				OtherPhone:   row.OtherPhone,
+				CallBefore:   row.CallBefore,
+				IsHoliday:    row.IsHoliday,
+				LawnAreaSqFt: row.LawnAreaSqFt,
+				FertOnly:     row.FertOnly,
+				Applications: row.Applications,
+				Force:        opts.Force,
+				imported:     true,
+			})
+		}
+		if err != nil {
+			if _, rbErr := tx.ExecContext(ctx, `ROLLBACK TO SAVEPOINT import_row`); rbErr != nil {
+				return ImportResult{}, fmt.Errorf("error rolling back row %d: %w", row.Row, rbErr)
+			}
+			result.Errors = append(result.Errors, importRowErrors(row.Row, err)...)
+			continue
+		}
+
+		if _, err := tx.ExecContext(ctx, `RELEASE SAVEPOINT import_row`); err != nil {
+			return ImportResult{}, fmt.Errorf("error releasing savepoint for row %d: %w", row.Row, err)
+		}
+		result.Imported = append(result.Imported, ImportedForm{Row: row.Row, FormID: formID})
+	}
+
+	if opts.DryRun || (opts.Mode == ImportModeAll && len(result.Errors) > 0) {
+		// Nothing is saved, so the IDs handed out inside the transaction mean nothing
+		for i := range result.Imported {
+			result.Imported[i].FormID = ""
+		}
+		return result, nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		return ImportResult{}, fmt.Errorf("error committing transaction: %w", err)
+	}
+	result.Committed = true
+
+	return result, nil
+}
@@ -0,0 +1,212 @@
+package forms
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/db"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+func importTestRows(chemID int) []ImportRow {
+	return []ImportRow{
+		{
+			Row:          2,
+			FormType:     "lawn",
+			FirstName:    "Indigo",
+			LastName:     "Import",
+			StreetNumber: "21",
+			StreetName:   "Ledger Ln",
+			Town:         "Town",
+			ZipCode:      "10001",
+			HomePhone:    "555-2100",
+			LawnAreaSqFt: 4000,
+			Applications: []PestApp{
+				{
+					ChemUsed:      chemID,
+					AppTimestamp:  time.Now(),
+					Rate:          "2 oz/1000 sq ft",
+					AmountApplied: decimal.NewFromFloat(8.0),
+					LocationCode:  "1A",
+				},
+			},
+		},
+		{
+			Row:          3,
+			FormType:     "shrub",
+			FirstName:    "Jules",
+			LastName:     "Paper",
+			StreetNumber: "7",
+			StreetName:   "Card Ct",
+			Town:         "Town",
+			ZipCode:      "1000",
+			HomePhone:    "555-0700",
+			Applications: []PestApp{
+				{
+					ChemUsed:      chemID + 100,
+					AppTimestamp:  time.Now(),
+					Rate:          "1 oz/gal",
+					AmountApplied: decimal.NewFromFloat(1.0),
+					LocationCode:  "ABC",
+				},
+			},
+		},
+	}
+}
+
+func TestImportForms_DryRunAndModes(t *testing.T) {
+	ctx := context.Background()
+	testDB := db.TestDB(t)
+	repo := NewFormsRepository(testDB)
+
+	adminID := createTestUser(t, testDB)
+	chemID := createTestChemical(t, testDB, "lawn")
+
+	countForms := func() int {
+		var count int
+		require.NoError(t, testDB.QueryRow(`SELECT COUNT(*) FROM forms`).Scan(&count))
+		return count
+	}
+
+	result, err := repo.ImportForms(ctx, importTestRows(chemID), ImportFormsOptions{
+		CreatedBy: adminID,
+		Mode:      ImportModeValidOnly,
+		DryRun:    true,
+	})
+	require.NoError(t, err)
+	require.False(t, result.Committed)
+	require.Equal(t, 2, result.Total)
+	require.Len(t, result.Imported, 1)
+	require.Equal(t, 2, result.Imported[0].Row)
+	require.Empty(t, result.Imported[0].FormID)
+
+	fields := map[string]bool{}
+	for _, importErr := range result.Errors {
+		require.Equal(t, 3, importErr.Row)
+		fields[importErr.Field] = true
+	}
+	require.True(t, fields["zip_code"])
+	require.True(t, fields["applications[0].chem_used"])
+	require.True(t, fields["applications[0].location_code"])
+	require.Equal(t, 0, countForms())
+
+	// All-or-nothing saves nothing while a row is invalid
+	result, err = repo.ImportForms(ctx, importTestRows(chemID), ImportFormsOptions{
+		CreatedBy: adminID,
+		Mode:      ImportModeAll,
+	})
+	require.NoError(t, err)
+	require.False(t, result.Committed)
+	require.NotEmpty(t, result.Errors)
+	require.Equal(t, 0, countForms())
+
+	result, err = repo.ImportForms(ctx, importTestRows(chemID), ImportFormsOptions{
+		CreatedBy: adminID,
+		Mode:      ImportModeValidOnly,
+	})
+	require.NoError(t, err)
+	require.True(t, result.Committed)
+	require.Len(t, result.Imported, 1)
+	require.Equal(t, 1, countForms())
+
+	form, err := repo.GetLawnFormById(ctx, result.Imported[0].FormID, adminID)
+	require.NoError(t, err)
+	require.Equal(t, "Indigo", form.FirstName)
+	require.Equal(t, 4000, form.LawnAreaSqFt)
+	require.Len(t, form.AppTimes, 1)
+
+	_, err = repo.ImportForms(ctx, importTestRows(chemID), ImportFormsOptions{
+		CreatedBy: adminID,
+		Mode:      "some",
+	})
+	require.ErrorIs(t, err, ErrInvalidImportMode)
+}
+
+func TestImportForms_DuplicatesWithinFile(t *testing.T) {
+	ctx := context.Background()
+	testDB := db.TestDB(t)
+	repo := NewFormsRepository(testDB)
+
+	adminID := createTestUser(t, testDB)
+
+	row := ImportRow{
+		FormType:     "shrub",
+		FirstName:    "Dana",
+		LastName:     "Twice",
+		StreetNumber: "5",
+		StreetName:   "Copy St",
+		Town:         "Town",
+		ZipCode:      "10001",
+		HomePhone:    "555-0505",
+	}
+	first, second := row, row
+	first.Row, second.Row = 2, 3
+	second.StreetName = "Copy Street"
+
+	result, err := repo.ImportForms(ctx, []ImportRow{first, second}, ImportFormsOptions{
+		CreatedBy: adminID,
+		Mode:      ImportModeValidOnly,
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Imported, 1)
+	require.Len(t, result.Errors, 1)
+	require.Equal(t, 3, result.Errors[0].Row)
+	require.Contains(t, result.Errors[0].Message, result.Imported[0].FormID)
+
+	// Forcing skips duplicate detection
+	second.StreetName = "Copy Str"
+	result, err = repo.ImportForms(ctx, []ImportRow{second}, ImportFormsOptions{
+		CreatedBy: adminID,
+		Mode:      ImportModeAll,
+		Force:     true,
+	})
+	require.NoError(t, err)
+	require.True(t, result.Committed)
+	require.Empty(t, result.Errors)
+}
+
+func TestImportForms_HistoricalCallBefore(t *testing.T) {
+	ctx := context.Background()
+	testDB := db.TestDB(t)
+	repo := NewFormsRepository(testDB)
+
+	adminID := createTestUser(t, testDB)
+	chemID := createTestChemical(t, testDB, "lawn")
+
+	row := importTestRows(chemID)[0]
+	row.CallBefore = true
+	row.Applications[0].AppTimestamp = time.Date(2024, 5, 7, 10, 0, 0, 0, time.UTC)
+
+	// Paper records carry no logged calls, so the call-before rule does not apply
+	result, err := repo.ImportForms(ctx, []ImportRow{row}, ImportFormsOptions{
+		CreatedBy: adminID,
+		Mode:      ImportModeAll,
+	})
+	require.NoError(t, err)
+	require.True(t, result.Committed)
+	require.Empty(t, result.Errors)
+}
+
+func TestImportForms_HistoricalHoliday(t *testing.T) {
+	ctx := context.Background()
+	testDB := db.TestDB(t)
+	repo := NewFormsRepository(testDB)
+
+	adminID := createTestUser(t, testDB)
+	chemID := createTestChemical(t, testDB, "lawn")
+
+	row := importTestRows(chemID)[0]
+	row.IsHoliday = true
+	// Saturday midday
+	row.Applications[0].AppTimestamp = time.Date(2024, 5, 4, 16, 0, 0, 0, time.UTC)
+
+	result, err := repo.ImportForms(ctx, []ImportRow{row}, ImportFormsOptions{
+		CreatedBy: adminID,
+		Mode:      ImportModeAll,
+	})
+	require.NoError(t, err)
+	require.True(t, result.Committed)
+	require.Empty(t, result.Errors)
+}
@@ -0,0 +1,301 @@
+package forms
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/chemicals"
+	"github.com/lib/pq"
+)
+
+// ErrNotOverLabelMax is returned when acknowledging an application that is within its label rate
+var ErrNotOverLabelMax = errors.New("application does not exceed the label maximum")
+
+// validateStructuredRate returns the problems with an application's structured rate, naming them with prefix
+func validateStructuredRate(rate *StructuredRate, prefix string) []FieldError {
+	if rate == nil {
+		return nil
+	}
+	var fields []FieldError
+	if !rate.Amount.IsPositive() {
+		fields = append(fields, FieldError{Field: prefix + "rate_amount", Message: "must be positive"})
+	}
+	if strings.TrimSpace(rate.Unit) == "" {
+		fields = append(fields, FieldError{Field: prefix + "rate_unit", Message: "is required with rate_amount"})
+	}
+	if !chemicals.ValidRateBasis(rate.Basis) {
+		fields = append(fields, FieldError{
+			Field:   prefix + "rate_basis",
+			Message: fmt.Sprintf("must be '%s' or '%s'", chemicals.RateBasisPer1000SqFt, chemicals.RateBasisPerGallon),
+		})
+	}
+	return fields
+}
+
+// noFieldPrefix names the fields of a single application, which are not nested in a form
+func noFieldPrefix(int) string {
+	return ""
+}
+
+// checkRateUnits returns a ValidationError if a structured rate is not in the unit and basis of its
+// chemical's label rate, which it must be to be compared with the label maximum.
+// prefix names the fields of the i-th application.
+func checkRateUnits(ctx context.Context, tx *sql.Tx, apps []PestApp, prefix func(i int) string) error {
+	chemIDs := []int64{}
+	for _, app := range apps {
+		if app.StructuredRate != nil {
+			chemIDs = append(chemIDs, int64(app.ChemUsed))
+		}
+	}
+	if len(chemIDs) == 0 {
+		return nil
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, label_rate_unit, label_rate_basis
+		FROM chemicals
+		WHERE id = ANY($1::int[]) AND label_rate_max IS NOT NULL
+	`, pq.Array(chemIDs))
+	if err != nil {
+		return fmt.Errorf("error fetching label rates: %w", err)
+	}
+	defer rows.Close()
+
+	labels := map[int]chemicals.LabelRate{}
+	for rows.Next() {
+		var (
+			chemID int
+			label  chemicals.LabelRate
+		)
+		if err := rows.Scan(&chemID, &label.Unit, &label.Basis); err != nil {
+			return fmt.Errorf("error scanning label rate: %w", err)
+		}
+		labels[chemID] = label
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error after label rates query: %w", err)
+	}
+
+	var fields []FieldError
+	for i, app := range apps {
+		label, ok := labels[app.ChemUsed]
+		if app.StructuredRate == nil || !ok {
+			continue
+		}
+		if !strings.EqualFold(app.StructuredRate.Unit, label.Unit) || app.StructuredRate.Basis != label.Basis {
+			fields = append(fields, FieldError{
+				Field:   prefix(i) + "rate_unit",
+				Message: fmt.Sprintf("must be %s %s to match the chemical's label rate", label.Unit, label.Basis),
+			})
+		}
+	}
+	if len(fields) > 0 {
+		return &ValidationError{Fields: fields}
+	}
+	return nil
+}
+
+// refreshLabelChecks recomputes the expected amount and label maximum flag of every application on a form.
+// An application is over the label maximum when its structured rate exceeds the maximum, or when a lawn
+// form's amount applied exceeds the most the label allows on the lawn's area. Supervisor acknowledgements
+// of applications no longer over the maximum are withdrawn.
+func refreshLabelChecks(ctx context.Context, tx *sql.Tx, formID string) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE pesticide_applications pa
+		SET expected_amount = CASE
+				WHEN pa.rate_basis = 'per_1000_sq_ft' AND lf.lawn_area_sq_ft IS NOT NULL
+				THEN ROUND(pa.rate_amount * lf.lawn_area_sq_ft / 1000, 2)
+			END,
+			over_label_max = COALESCE(
+				lower(pa.rate_unit) = lower(c.label_rate_unit)
+					AND pa.rate_basis = c.label_rate_basis
+					AND pa.rate_amount > c.label_rate_max,
+				FALSE
+			) OR COALESCE(
+				c.label_rate_basis = 'per_1000_sq_ft'
+					AND lower(c.unit) = lower(c.label_rate_unit)
+					AND lf.lawn_area_sq_ft > 0
+					AND pa.amount_applied > c.label_rate_max * lf.lawn_area_sq_ft / 1000,
+				FALSE
+			)
+		FROM chemicals c, forms f
+		LEFT JOIN lawn_forms lf ON lf.form_id = f.id
+		WHERE pa.form_id = $1
+		  AND f.id = pa.form_id
+		  AND c.id = pa.chem_used
+	`, formID)
+	if err != nil {
+		return fmt.Errorf("error checking label rates for form %s: %w", formID, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE pesticide_applications
+		SET over_label_acknowledged_by = NULL,
+			over_label_acknowledged_at = NULL
+		WHERE form_id = $1
+		  AND NOT over_label_max
+		  AND over_label_acknowledged_at IS NOT NULL
+	`, formID)
+	if err != nil {
+		return fmt.Errorf("error withdrawing label acknowledgements for form %s: %w", formID, err)
+	}
+	return nil
+}
+
+// getPestApp reads a single pesticide application of a form inside tx.
+// It returns sql.ErrNoRows if the application does not exist on the form.
+func getPestApp(ctx context.Context, tx *sql.Tx, formID string, appID int) (PestApp, error) {
+	var row pestAppRow
+	err := tx.QueryRowContext(ctx, `
+		SELECT`+pestAppColumns+`
+		FROM pesticide_applications pa
+		WHERE pa.id = $1 AND pa.form_id = $2
+	`, appID, formID).Scan(row.dest()...)
+	if err != nil {
+		return PestApp{}, err
+	}
+	return row.toDomain(), nil
+}
+
+// AcknowledgeOverLabelMax records a supervisor's acknowledgement of an application over its label maximum.
+// Returns the acknowledged application upon success.
+// It returns sql.ErrNoRows if the application is not on the form,
+// and ErrNotOverLabelMax if the application is within its label rate.
+func (r *FormsRepository) AcknowledgeOverLabelMax(
+	ctx context.Context,
+	formID string,
+	appID int,
+	supervisorID string,
+) (PestApp, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return PestApp{}, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var overLabelMax bool
+	err = tx.QueryRowContext(ctx, `
+		SELECT over_label_max
+		FROM pesticide_applications
+		WHERE id = $1 AND form_id = $2
+		FOR UPDATE
+	`, appID, formID).Scan(&overLabelMax)
+	if err != nil {
+		//sql.ErrNoRows
+		return PestApp{}, err
+	}
+	if !overLabelMax {
+		return PestApp{}, ErrNotOverLabelMax
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE pesticide_applications
+		SET over_label_acknowledged_by = $1,
+			over_label_acknowledged_at = NOW()
+		WHERE id = $2
+	`, supervisorID, appID)
+	if err != nil {
+		return PestApp{}, fmt.Errorf("error acknowledging pesticide application %d: %w", appID, err)
+	}
+
+	app, err := getPestApp(ctx, tx, formID, appID)
+	if err != nil {
+		return PestApp{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return PestApp{}, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return app, nil
+}
+
+// OverLabelApp is an application over its chemical's label maximum, with the form and label it belongs to
+type OverLabelApp struct {
+	PestApp
+	FormID        string
+	FormType      string
+	FirstName     string
+	LastName      string
+	StreetNumber  string
+	StreetName    string
+	Town          string
+	CreatedBy     string
+	CreatedByName string
+	BrandName     string
+	Unit          string
+	LabelRate     chemicals.LabelRate
+}
+
+// ListOverLabelApps returns the applications on live forms that exceed their label maximum,
+// newest first. Acknowledged applications are left out unless includeAcknowledged is set.
+func (r *FormsRepository) ListOverLabelApps(ctx context.Context, includeAcknowledged bool) ([]OverLabelApp, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT`+pestAppColumns+`,
+			f.id,
+			f.form_type,
+			f.first_name,
+			f.last_name,
+			f.street_number,
+			f.street_name,
+			f.town,
+			f.created_by,
+			u.first_name || ' ' || u.last_name,
+			c.brand_name,
+			c.unit,
+			COALESCE(c.label_rate_min, 0),
+			COALESCE(c.label_rate_max, 0),
+			COALESCE(c.label_rate_unit, ''),
+			COALESCE(c.label_rate_basis, '')
+		FROM pesticide_applications pa
+		JOIN forms f ON f.id = pa.form_id
+		JOIN users u ON u.id = f.created_by
+		JOIN chemicals c ON c.id = pa.chem_used
+		WHERE pa.over_label_max
+		  AND f.deleted_at IS NULL
+		  AND ($1 OR pa.over_label_acknowledged_at IS NULL)
+		ORDER BY pa.app_timestamp DESC, pa.id DESC
+	`, includeAcknowledged)
+	if err != nil {
+		return nil, fmt.Errorf("error listing applications over the label maximum: %w", err)
+	}
+	defer rows.Close()
+
+	apps := []OverLabelApp{}
+	for rows.Next() {
+		var (
+			row pestAppRow
+			app OverLabelApp
+		)
+		dest := append(row.dest(),
+			&app.FormID,
+			&app.FormType,
+			&app.FirstName,
+			&app.LastName,
+			&app.StreetNumber,
+			&app.StreetName,
+			&app.Town,
+			&app.CreatedBy,
+			&app.CreatedByName,
+			&app.BrandName,
+			&app.Unit,
+			&app.LabelRate.Min,
+			&app.LabelRate.Max,
+			&app.LabelRate.Unit,
+			&app.LabelRate.Basis,
+		)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("error scanning application over the label maximum: %w", err)
+		}
+		app.PestApp = row.toDomain()
+		apps = append(apps, app)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after applications over the label maximum query: %w", err)
+	}
+
+	return apps, nil
+}
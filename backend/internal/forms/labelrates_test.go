@@ -0,0 +1,189 @@
+package forms
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/db"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+// setTestLabelRate gives a test chemical a label rate of 1 to 2 oz per 1,000 sq ft
+func setTestLabelRate(t *testing.T, db *sql.DB, chemID int) {
+	t.Helper()
+
+	_, err := db.Exec(`
+		UPDATE chemicals
+		SET label_rate_min = 1, label_rate_max = 2, label_rate_unit = 'oz', label_rate_basis = 'per_1000_sq_ft'
+		WHERE id = $1
+	`, chemID)
+	require.NoError(t, err)
+}
+
+func TestLabelRateChecks(t *testing.T) {
+	ctx := context.Background()
+	testDB := db.TestDB(t)
+	repo := NewFormsRepository(testDB)
+
+	userID := createTestUser(t, testDB)
+	adminID := createTestUser(t, testDB)
+	chemID := createTestChemical(t, testDB, "lawn")
+	setTestLabelRate(t, testDB, chemID)
+
+	rate := func(amount string) *StructuredRate {
+		return &StructuredRate{Amount: decimal.RequireFromString(amount), Unit: "oz", Basis: "per_1000_sq_ft"}
+	}
+	app := func(rateAmount string, amountApplied string) PestApp {
+		return PestApp{
+			ChemUsed:       chemID,
+			AppTimestamp:   time.Now(),
+			Rate:           rateAmount + " oz/1000 sq ft",
+			AmountApplied:  decimal.RequireFromString(amountApplied),
+			LocationCode:   "1A",
+			StructuredRate: rate(rateAmount),
+		}
+	}
+
+	formID, err := repo.CreateLawnForm(ctx, CreateLawnFormInput{
+		CreatedBy:    userID,
+		FirstName:    "Label",
+		LastName:     "Rate",
+		StreetNumber: "50",
+		StreetName:   "Label St",
+		Town:         "Town",
+		ZipCode:      "10005",
+		HomePhone:    "555-0009",
+		OtherPhone:   "555-0010",
+		LawnAreaSqFt: 5000,
+		Applications: []PestApp{app("1.5", "7.5")},
+	})
+	require.NoError(t, err)
+
+	// The expected amount follows the lawn area
+	lawn, err := repo.GetLawnFormById(ctx, formID, userID)
+	require.NoError(t, err)
+	require.Len(t, lawn.AppTimes, 1)
+	within := lawn.AppTimes[0]
+	require.NotNil(t, within.StructuredRate)
+	require.True(t, decimal.RequireFromString("1.5").Equal(within.StructuredRate.Amount))
+	require.NotNil(t, within.ExpectedAmount)
+	require.Equal(t, "7.5", within.ExpectedAmount.String())
+	require.False(t, within.OverLabelMax)
+
+	// A rate over the label maximum is saved and flagged
+	over, err := repo.CreatePestApp(ctx, formID, userID, app("2.5", "12.5"))
+	require.NoError(t, err)
+	require.True(t, over.OverLabelMax)
+	require.Nil(t, over.OverLabelAcknowledgedAt)
+
+	// So is an amount over the most the label allows on the lawn, whatever the rate says
+	within.AmountApplied = decimal.RequireFromString("11")
+	updated, err := repo.UpdatePestAppById(ctx, formID, userID, within)
+	require.NoError(t, err)
+	require.True(t, updated.OverLabelMax)
+
+	pending, err := repo.ListOverLabelApps(ctx, false)
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	require.Equal(t, formID, pending[0].FormID)
+	require.True(t, decimal.RequireFromString("2").Equal(pending[0].LabelRate.Max))
+
+	// A supervisor acknowledges the over-rate application
+	acknowledged, err := repo.AcknowledgeOverLabelMax(ctx, formID, over.ID, adminID)
+	require.NoError(t, err)
+	require.Equal(t, adminID, acknowledged.OverLabelAcknowledgedBy)
+	require.NotNil(t, acknowledged.OverLabelAcknowledgedAt)
+
+	pending, err = repo.ListOverLabelApps(ctx, false)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	require.Equal(t, within.ID, pending[0].ID)
+
+	all, err := repo.ListOverLabelApps(ctx, true)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+
+	// Changing the rate withdraws the acknowledgement
+	acknowledged.StructuredRate = rate("3")
+	changed, err := repo.UpdatePestAppById(ctx, formID, userID, acknowledged)
+	require.NoError(t, err)
+	require.True(t, changed.OverLabelMax)
+	require.Nil(t, changed.OverLabelAcknowledgedAt)
+
+	// Bringing the amount back within the label clears the flag
+	updated.AmountApplied = decimal.RequireFromString("7.5")
+	updated, err = repo.UpdatePestAppById(ctx, formID, userID, updated)
+	require.NoError(t, err)
+	require.False(t, updated.OverLabelMax)
+
+	_, err = repo.AcknowledgeOverLabelMax(ctx, formID, updated.ID, adminID)
+	require.ErrorIs(t, err, ErrNotOverLabelMax)
+
+	_, err = repo.AcknowledgeOverLabelMax(ctx, formID, 9999, adminID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	// Shrinking the lawn recomputes the expected amounts
+	_, err = repo.UpdateLawnFormById(ctx, formID, userID, UpdateLawnFormInput{
+		FirstName:    "Label",
+		LastName:     "Rate",
+		StreetNumber: "50",
+		StreetName:   "Label St",
+		Town:         "Town",
+		ZipCode:      "10005",
+		HomePhone:    "555-0009",
+		OtherPhone:   "555-0010",
+		LawnAreaSqFt: 2000,
+	})
+	require.NoError(t, err)
+	lawn, err = repo.GetLawnFormById(ctx, formID, userID)
+	require.NoError(t, err)
+	for _, got := range lawn.AppTimes {
+		if got.ID == updated.ID {
+			require.Equal(t, "3", got.ExpectedAmount.String())
+			require.True(t, got.OverLabelMax)
+		}
+	}
+}
+
+func TestLabelRateUnitsMustMatch(t *testing.T) {
+	ctx := context.Background()
+	testDB := db.TestDB(t)
+	repo := NewFormsRepository(testDB)
+
+	userID := createTestUser(t, testDB)
+	chemID := createTestChemical(t, testDB, "lawn")
+	setTestLabelRate(t, testDB, chemID)
+
+	_, err := repo.CreateLawnForm(ctx, CreateLawnFormInput{
+		CreatedBy:    userID,
+		FirstName:    "Unit",
+		LastName:     "Mismatch",
+		StreetNumber: "60",
+		StreetName:   "Unit St",
+		Town:         "Town",
+		ZipCode:      "10006",
+		HomePhone:    "555-0011",
+		OtherPhone:   "555-0012",
+		LawnAreaSqFt: 1000,
+		Applications: []PestApp{{
+			ChemUsed:      chemID,
+			AppTimestamp:  time.Now(),
+			Rate:          "1 oz/gal",
+			AmountApplied: decimal.NewFromFloat(1),
+			LocationCode:  "1A",
+			StructuredRate: &StructuredRate{
+				Amount: decimal.NewFromFloat(1),
+				Unit:   "oz",
+				Basis:  "per_gallon",
+			},
+		}},
+	})
+	require.ErrorIs(t, err, ErrInvalidInput)
+	var valErr *ValidationError
+	require.True(t, errors.As(err, &valErr))
+	require.Equal(t, "applications[0].rate_unit", valErr.Fields[0].Field)
+}
@@ -0,0 +1,117 @@
+package forms
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// ErrLicenseExpired is returned when an applicator whose license has expired records applications
+var ErrLicenseExpired = errors.New("applicator license has expired")
+
+// ApplicatorLicense is the license an application was recorded under
+type ApplicatorLicense struct {
+	Number    string
+	Category  string
+	State     string
+	ExpiresOn time.Time
+}
+
+// withApplicator returns apps with the applicator of every new application that names none
+// set to userID. Existing applications keep their stored applicator when none is named.
+func withApplicator(apps []PestApp, userID string) []PestApp {
+	if apps == nil {
+		return nil
+	}
+	defaulted := make([]PestApp, len(apps))
+	for i, app := range apps {
+		if app.ID == 0 && app.AppliedBy == "" {
+			app.AppliedBy = userID
+		}
+		defaulted[i] = app
+	}
+	return defaulted
+}
+
+// checkApplicators returns a ValidationError if an application names an applicator who is not a user,
+// and ErrLicenseExpired if the license of a new application's applicator expired before today.
+// Applicators without a license on file are let through.
+// prefix names the fields of the i-th application.
+func checkApplicators(ctx context.Context, tx *sql.Tx, apps []PestApp, prefix func(i int) string) error {
+	userIDs := []string{}
+	for _, app := range apps {
+		if app.AppliedBy != "" {
+			userIDs = append(userIDs, app.AppliedBy)
+		}
+	}
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id::text, COALESCE(license_expires_on < CURRENT_DATE, FALSE)
+		FROM users
+		WHERE id::text = ANY($1::text[])
+	`, pq.Array(userIDs))
+	if err != nil {
+		return fmt.Errorf("error checking applicator licenses: %w", err)
+	}
+	defer rows.Close()
+
+	expired := map[string]bool{}
+	for rows.Next() {
+		var (
+			userID string
+			lapsed bool
+		)
+		if err := rows.Scan(&userID, &lapsed); err != nil {
+			return fmt.Errorf("error scanning applicator license: %w", err)
+		}
+		expired[userID] = lapsed
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error after applicator licenses query: %w", err)
+	}
+
+	var fields []FieldError
+	for i, app := range apps {
+		if app.AppliedBy == "" {
+			continue
+		}
+		lapsed, ok := expired[app.AppliedBy]
+		if !ok {
+			fields = append(fields, FieldError{Field: prefix(i) + "applied_by", Message: "must be an existing user"})
+			continue
+		}
+		if lapsed && app.ID == 0 {
+			return ErrLicenseExpired
+		}
+	}
+	if len(fields) > 0 {
+		return &ValidationError{Fields: fields}
+	}
+	return nil
+}
+
+// stampApplicatorLicense copies the license of an application's applicator onto it,
+// so it keeps the license in effect when it was recorded
+func stampApplicatorLicense(ctx context.Context, tx *sql.Tx, appID int) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE pesticide_applications pa
+		SET applicator_license_number = u.license_number,
+			applicator_license_category = u.license_category,
+			applicator_license_state = u.license_state,
+			applicator_license_expires_on = u.license_expires_on
+		FROM users u
+		WHERE pa.id = $1
+		  AND u.id = pa.applied_by
+	`, appID)
+	if err != nil {
+		return fmt.Errorf("error stamping license on pesticide application %d: %w", appID, err)
+	}
+	return nil
+}
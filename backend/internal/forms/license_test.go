@@ -0,0 +1,101 @@
+package forms
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/db"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+// setTestLicense gives a test user an applicator license expiring on expiresOn
+func setTestLicense(t *testing.T, db *sql.DB, userID string, number string, expiresOn time.Time) {
+	t.Helper()
+
+	_, err := db.Exec(`
+		UPDATE users
+		SET license_number = $1, license_category = '3B', license_state = 'NJ', license_expires_on = $2
+		WHERE id = $3
+	`, number, expiresOn, userID)
+	require.NoError(t, err)
+}
+
+func TestApplicatorLicense(t *testing.T) {
+	ctx := context.Background()
+	testDB := db.TestDB(t)
+	repo := NewFormsRepository(testDB)
+
+	userID := createTestUser(t, testDB)
+	chemID := createTestChemical(t, testDB, "shrub")
+	nextYear := time.Now().AddDate(1, 0, 0).UTC().Truncate(24 * time.Hour)
+	setTestLicense(t, testDB, userID, "57123A", nextYear)
+
+	app := PestApp{
+		ChemUsed:      chemID,
+		AppTimestamp:  time.Now(),
+		Rate:          "1 oz/gal",
+		AmountApplied: decimal.NewFromFloat(1.0),
+		LocationCode:  "1B",
+	}
+	input := CreateShrubFormInput{
+		CreatedBy:    userID,
+		FirstName:    "License",
+		LastName:     "Stamp",
+		StreetNumber: "80",
+		StreetName:   "Permit St",
+		Town:         "Town",
+		ZipCode:      "10008",
+		HomePhone:    "555-0015",
+		OtherPhone:   "555-0016",
+		Applications: []PestApp{app},
+	}
+
+	formID, err := repo.CreateShrubForm(ctx, input)
+	require.NoError(t, err)
+
+	// Applications carry the license in effect when they were recorded
+	setTestLicense(t, testDB, userID, "57999Z", nextYear)
+	added, err := repo.CreatePestApp(ctx, formID, userID, app)
+	require.NoError(t, err)
+	require.NotNil(t, added.ApplicatorLicense)
+	require.Equal(t, "57999Z", added.ApplicatorLicense.Number)
+
+	shrub, err := repo.GetShrubFormById(ctx, formID, userID)
+	require.NoError(t, err)
+	require.Len(t, shrub.AppTimes, 2)
+	first := shrub.AppTimes[0]
+	if first.ID == added.ID {
+		first = shrub.AppTimes[1]
+	}
+	require.NotNil(t, first.ApplicatorLicense)
+	require.Equal(t, "57123A", first.ApplicatorLicense.Number)
+	require.Equal(t, "3B", first.ApplicatorLicense.Category)
+	require.Equal(t, "NJ", first.ApplicatorLicense.State)
+	require.True(t, nextYear.Equal(first.ApplicatorLicense.ExpiresOn))
+
+	// An expired license blocks recording applications, but not forms without any
+	setTestLicense(t, testDB, userID, "57999Z", time.Now().AddDate(0, 0, -2))
+	input.Force = true
+	_, err = repo.CreateShrubForm(ctx, input)
+	require.ErrorIs(t, err, ErrLicenseExpired)
+
+	_, err = repo.CreatePestApp(ctx, formID, userID, app)
+	require.ErrorIs(t, err, ErrLicenseExpired)
+
+	input.Applications = nil
+	_, err = repo.CreateShrubForm(ctx, input)
+	require.NoError(t, err)
+
+	// Applicators without a license on file are not blocked, and their applications carry none
+	unlicensedID := createTestUser(t, testDB)
+	input.CreatedBy = unlicensedID
+	input.Applications = []PestApp{app}
+	formID, err = repo.CreateShrubForm(ctx, input)
+	require.NoError(t, err)
+	shrub, err = repo.GetShrubFormById(ctx, formID, unlicensedID)
+	require.NoError(t, err)
+	require.Nil(t, shrub.AppTimes[0].ApplicatorLicense)
+}
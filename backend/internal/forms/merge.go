@@ -0,0 +1,301 @@
+package forms
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// ErrInvalidMerge is returned when a merge names no duplicates, repeats a form, mixes
+// form types or takes a field from a form outside the merge
+var ErrInvalidMerge = errors.New("invalid merge")
+
+// MergeFormsInput describes duplicate forms to fold into a surviving form.
+type MergeFormsInput struct {
+	SurvivorID   string
+	DuplicateIDs []string
+	// FieldSources picks, by JSON field name (e.g. "home_phone" or "lawn_area_sq_ft"),
+	// the form whose value the survivor keeps. Fields not listed keep the survivor's value.
+	FieldSources map[string]string
+	MergedBy     string
+}
+
+// FormMerge is the audit entry recorded for a merge
+type FormMerge struct {
+	ID           int64
+	SurvivorID   string
+	MergedIDs    []string
+	MergedBy     string
+	MergedAt     time.Time
+	FieldSources map[string]string
+	// ApplicationIDs and NoteIDs are the applications and notes moved onto the survivor
+	ApplicationIDs []int
+	NoteIDs        []int
+}
+
+// mergeSnapshots returns the survivor's snapshot with the chosen fields taken from other forms.
+// It returns ErrInvalidMerge if a field cannot be merged or its source is not part of the merge.
+func mergeSnapshots(
+	survivorID string,
+	snapshots map[string]*formSnapshot,
+	fieldSources map[string]string,
+) (*formSnapshot, error) {
+	merged, err := toFieldMap(snapshots[survivorID])
+	if err != nil {
+		return nil, err
+	}
+
+	for field, sourceID := range fieldSources {
+		// Subtype fields of the other form type are omitted from the map, so they are rejected here too
+		if _, ok := merged[field]; !ok || field == "form_type" || field == "applications" {
+			return nil, fmt.Errorf("%w: %q is not a mergeable field", ErrInvalidMerge, field)
+		}
+		source, ok := snapshots[sourceID]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s field source %s is not one of the merged forms", ErrInvalidMerge, field, sourceID)
+		}
+		sourceFields, err := toFieldMap(source)
+		if err != nil {
+			return nil, err
+		}
+		merged[field] = sourceFields[field]
+	}
+
+	raw, err := json.Marshal(merged)
+	if err != nil {
+		return nil, err
+	}
+	var snap formSnapshot
+	if err := json.Unmarshal(raw, &snap); err != nil {
+		return nil, err
+	}
+	return &snap, nil
+}
+
+// moveToSurvivor re-parents the rows of table belonging to the duplicates onto the survivor,
+// returning the moved row IDs in ascending order.
+func moveToSurvivor(
+	ctx context.Context,
+	tx *sql.Tx,
+	table string,
+	survivorID string,
+	duplicateIDs []string,
+) ([]int, error) {
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf(`
+		UPDATE %s
+		SET form_id = $1
+		WHERE form_id = ANY($2::uuid[])
+		RETURNING id
+	`, table), survivorID, pq.Array(duplicateIDs))
+	if err != nil {
+		return nil, fmt.Errorf("error moving %s onto form %s: %w", table, survivorID, err)
+	}
+	defer rows.Close()
+
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("error scanning moved %s: %w", table, err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after moving %s: %w", table, err)
+	}
+
+	sort.Ints(ids)
+	return ids, nil
+}
+
+// MergeForms folds duplicate forms into a surviving form of the same type (admin only).
+// The duplicates' pesticide applications, notes and call log move to the survivor, the survivor keeps
+// its own client and subtype fields except those picked from a duplicate in FieldSources,
+// and the duplicates are then deleted. If the survivor is not linked to a property it takes
+// the first linked duplicate's property.
+// The merge is recorded as a revision on every form involved and as a FormMerge audit entry,
+// which is returned. The operation is atomic.
+// It returns sql.ErrNoRows if any of the forms does not exist or is in the trash,
+// and ErrInvalidMerge if the request is inconsistent.
+func (r *FormsRepository) MergeForms(
+	ctx context.Context,
+	input MergeFormsInput,
+) (FormMerge, error) {
+	if len(input.DuplicateIDs) == 0 {
+		return FormMerge{}, fmt.Errorf("%w: at least one duplicate is required", ErrInvalidMerge)
+	}
+	formIDs := []string{input.SurvivorID}
+	seen := map[string]bool{input.SurvivorID: true}
+	for _, id := range input.DuplicateIDs {
+		if seen[id] {
+			return FormMerge{}, fmt.Errorf("%w: form %s is listed more than once", ErrInvalidMerge, id)
+		}
+		seen[id] = true
+		formIDs = append(formIDs, id)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormMerge{}, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock every form up front, in a fixed order so concurrent merges cannot deadlock
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, form_type
+		FROM forms
+		WHERE id = ANY($1::uuid[]) AND deleted_at IS NULL
+		ORDER BY id
+		FOR UPDATE
+	`, pq.Array(formIDs))
+	if err != nil {
+		return FormMerge{}, fmt.Errorf("error locking forms for merge: %w", err)
+	}
+	formTypes := map[string]string{}
+	for rows.Next() {
+		var id, formType string
+		if err := rows.Scan(&id, &formType); err != nil {
+			rows.Close()
+			return FormMerge{}, fmt.Errorf("error scanning form for merge: %w", err)
+		}
+		formTypes[id] = formType
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return FormMerge{}, fmt.Errorf("error after locking forms for merge: %w", err)
+	}
+	if len(formTypes) != len(formIDs) {
+		return FormMerge{}, sql.ErrNoRows
+	}
+	for _, id := range input.DuplicateIDs {
+		if formTypes[id] != formTypes[input.SurvivorID] {
+			return FormMerge{}, fmt.Errorf("%w: form %s is not a %s form", ErrInvalidMerge, id, formTypes[input.SurvivorID])
+		}
+	}
+
+	snapshots := make(map[string]*formSnapshot, len(formIDs))
+	for _, id := range formIDs {
+		snap, err := loadFormSnapshot(ctx, tx, id)
+		if err != nil {
+			return FormMerge{}, fmt.Errorf("error snapshotting form %s for merge: %w", id, err)
+		}
+		snapshots[id] = snap
+	}
+
+	merged, err := mergeSnapshots(input.SurvivorID, snapshots, input.FieldSources)
+	if err != nil {
+		return FormMerge{}, err
+	}
+	if err := applySnapshotFields(ctx, tx, input.SurvivorID, merged); err != nil {
+		return FormMerge{}, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE forms
+		SET property_id = (
+			SELECT d.property_id
+			FROM forms d
+			WHERE d.id = ANY($2::uuid[]) AND d.property_id IS NOT NULL
+			ORDER BY array_position($2::uuid[], d.id)
+			LIMIT 1
+		)
+		WHERE id = $1 AND property_id IS NULL
+	`, input.SurvivorID, pq.Array(input.DuplicateIDs))
+	if err != nil {
+		return FormMerge{}, fmt.Errorf("error linking form %s to a property: %w", input.SurvivorID, err)
+	}
+
+	applicationIDs, err := moveToSurvivor(ctx, tx, "pesticide_applications", input.SurvivorID, input.DuplicateIDs)
+	if err != nil {
+		return FormMerge{}, err
+	}
+	if err := refreshLabelChecks(ctx, tx, input.SurvivorID); err != nil {
+		return FormMerge{}, err
+	}
+	noteIDs, err := moveToSurvivor(ctx, tx, "notes", input.SurvivorID, input.DuplicateIDs)
+	if err != nil {
+		return FormMerge{}, err
+	}
+	if _, err := moveToSurvivor(ctx, tx, "form_calls", input.SurvivorID, input.DuplicateIDs); err != nil {
+		return FormMerge{}, err
+	}
+
+	// Forms cloned from a duplicate are now cloned from the survivor, rather than losing their source
+	_, err = tx.ExecContext(ctx, `
+		UPDATE forms
+		SET cloned_from = $1
+		WHERE cloned_from = ANY($2::uuid[]) AND id <> $1
+	`, input.SurvivorID, pq.Array(input.DuplicateIDs))
+	if err != nil {
+		return FormMerge{}, fmt.Errorf("error moving clones onto form %s: %w", input.SurvivorID, err)
+	}
+
+	for _, id := range input.DuplicateIDs {
+		if _, err := recordDeletion(ctx, tx, id, input.MergedBy, RevisionActionMerge, snapshots[id]); err != nil {
+			return FormMerge{}, err
+		}
+	}
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM forms
+		WHERE id = ANY($1::uuid[])
+	`, pq.Array(input.DuplicateIDs))
+	if err != nil {
+		return FormMerge{}, fmt.Errorf("error deleting merged forms: %w", err)
+	}
+
+	if _, err := recordRevision(ctx, tx, input.SurvivorID, input.MergedBy, RevisionActionMerge, snapshots[input.SurvivorID]); err != nil {
+		return FormMerge{}, err
+	}
+
+	fieldSources := input.FieldSources
+	if fieldSources == nil {
+		fieldSources = map[string]string{}
+	}
+	fieldSourcesJSON, err := json.Marshal(fieldSources)
+	if err != nil {
+		return FormMerge{}, fmt.Errorf("error encoding merge field sources: %w", err)
+	}
+
+	merge := FormMerge{
+		SurvivorID:     input.SurvivorID,
+		MergedIDs:      input.DuplicateIDs,
+		MergedBy:       input.MergedBy,
+		FieldSources:   fieldSources,
+		ApplicationIDs: applicationIDs,
+		NoteIDs:        noteIDs,
+	}
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO form_merges (
+			survivor_id,
+			merged_ids,
+			merged_by,
+			field_sources,
+			application_ids,
+			note_ids
+		)
+		VALUES ($1, $2::uuid[], $3, $4, $5::int[], $6::int[])
+		RETURNING id, merged_at
+	`,
+		merge.SurvivorID,
+		pq.Array(merge.MergedIDs),
+		merge.MergedBy,
+		fieldSourcesJSON,
+		pq.Array(merge.ApplicationIDs),
+		pq.Array(merge.NoteIDs),
+	).Scan(&merge.ID, &merge.MergedAt)
+	if err != nil {
+		return FormMerge{}, fmt.Errorf("error recording merge into form %s: %w", input.SurvivorID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return FormMerge{}, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return merge, nil
+}
@@ -0,0 +1,181 @@
+package forms
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/db"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeForms(t *testing.T) {
+	ctx := context.Background()
+	testDB := db.TestDB(t)
+	repo := NewFormsRepository(testDB)
+
+	userID := createTestUser(t, testDB)
+	adminID := createTestUser(t, testDB)
+	chemID := createTestChemical(t, testDB, "lawn")
+
+	newApp := func() []PestApp {
+		return []PestApp{
+			{
+				ChemUsed:      chemID,
+				AppTimestamp:  time.Now(),
+				Rate:          "2 oz/1000 sq ft",
+				AmountApplied: decimal.NewFromFloat(4.0),
+				LocationCode:  "1A",
+			},
+		}
+	}
+
+	survivorID, err := repo.CreateLawnForm(ctx, CreateLawnFormInput{
+		CreatedBy:    userID,
+		FirstName:    "Morgan",
+		LastName:     "Merge",
+		StreetNumber: "8",
+		StreetName:   "Union St",
+		Town:         "Town",
+		ZipCode:      "10001",
+		HomePhone:    "555-0001",
+		OtherPhone:   "",
+		LawnAreaSqFt: 1000,
+		Applications: newApp(),
+	})
+	require.NoError(t, err)
+
+	duplicateID, err := repo.CreateLawnForm(ctx, CreateLawnFormInput{
+		CreatedBy:    userID,
+		FirstName:    "Morgan",
+		LastName:     "Merge",
+		StreetNumber: "8",
+		StreetName:   "Unoin Street",
+		Town:         "Town",
+		ZipCode:      "10001",
+		HomePhone:    "555-0001",
+		OtherPhone:   "555-0099",
+		LawnAreaSqFt: 2500,
+		Applications: newApp(),
+		Force:        true,
+	})
+	require.NoError(t, err)
+	note, err := repo.CreateNote(ctx, duplicateID, userID, "Dog in back yard")
+	require.NoError(t, err)
+	cloneID, err := repo.CloneFormById(ctx, duplicateID, userID, true)
+	require.NoError(t, err)
+
+	merge, err := repo.MergeForms(ctx, MergeFormsInput{
+		SurvivorID:   survivorID,
+		DuplicateIDs: []string{duplicateID},
+		FieldSources: map[string]string{
+			"other_phone":     duplicateID,
+			"lawn_area_sq_ft": duplicateID,
+		},
+		MergedBy: adminID,
+	})
+	require.NoError(t, err)
+	require.NotZero(t, merge.ID)
+	require.Equal(t, []string{duplicateID}, merge.MergedIDs)
+	require.Len(t, merge.ApplicationIDs, 1)
+	require.Equal(t, []int{note.ID}, merge.NoteIDs)
+
+	survivor, err := repo.GetLawnFormById(ctx, survivorID, userID)
+	require.NoError(t, err)
+	require.Equal(t, "Union St", survivor.StreetName)
+	require.Equal(t, "555-0099", survivor.OtherPhone)
+	require.Equal(t, 2500, survivor.LawnAreaSqFt)
+	require.Len(t, survivor.AppTimes, 2)
+	require.Len(t, survivor.Notes, 1)
+	require.Equal(t, "Dog in back yard", survivor.Notes[0].Message)
+
+	_, err = repo.GetLawnFormById(ctx, duplicateID, userID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	// The duplicate's clone keeps its lineage through the survivor
+	clone, err := repo.GetLawnFormById(ctx, cloneID, userID)
+	require.NoError(t, err)
+	require.Equal(t, survivorID, clone.ClonedFrom)
+
+	// Both forms' histories record the merge
+	revisions, err := repo.ListAllFormRevisions(ctx, survivorID)
+	require.NoError(t, err)
+	last := revisions[len(revisions)-1]
+	require.Equal(t, RevisionActionMerge, last.Action)
+	require.Equal(t, adminID, last.ChangedBy)
+	require.Len(t, last.Diff.ApplicationsAdded, 1)
+	require.Equal(t, float64(2500), last.Diff.Fields["lawn_area_sq_ft"].New)
+
+	revisions, err = repo.ListAllFormRevisions(ctx, duplicateID)
+	require.NoError(t, err)
+	require.Equal(t, RevisionActionMerge, revisions[len(revisions)-1].Action)
+
+	var mergedBy string
+	err = testDB.QueryRow(`SELECT merged_by FROM form_merges WHERE survivor_id = $1`, survivorID).Scan(&mergedBy)
+	require.NoError(t, err)
+	require.Equal(t, adminID, mergedBy)
+}
+
+func TestMergeForms_Invalid(t *testing.T) {
+	ctx := context.Background()
+	testDB := db.TestDB(t)
+	repo := NewFormsRepository(testDB)
+
+	userID := createTestUser(t, testDB)
+
+	lawnID, err := repo.CreateLawnForm(ctx, CreateLawnFormInput{
+		CreatedBy:    userID,
+		FirstName:    "Lawn",
+		LastName:     "Only",
+		StreetNumber: "1",
+		StreetName:   "Grass Rd",
+		Town:         "Town",
+		ZipCode:      "10001",
+		LawnAreaSqFt: 1000,
+	})
+	require.NoError(t, err)
+	shrubID, err := repo.CreateShrubForm(ctx, CreateShrubFormInput{
+		CreatedBy:    userID,
+		FirstName:    "Shrub",
+		LastName:     "Only",
+		StreetNumber: "2",
+		StreetName:   "Hedge Rd",
+		Town:         "Town",
+		ZipCode:      "10001",
+	})
+	require.NoError(t, err)
+	otherLawnID, err := repo.CreateLawnForm(ctx, CreateLawnFormInput{
+		CreatedBy:    userID,
+		FirstName:    "Lawn",
+		LastName:     "Other",
+		StreetNumber: "3",
+		StreetName:   "Grass Rd",
+		Town:         "Town",
+		ZipCode:      "10001",
+		LawnAreaSqFt: 1000,
+	})
+	require.NoError(t, err)
+
+	merge := func(survivorID string, duplicateIDs []string, fieldSources map[string]string) error {
+		_, err := repo.MergeForms(ctx, MergeFormsInput{
+			SurvivorID:   survivorID,
+			DuplicateIDs: duplicateIDs,
+			FieldSources: fieldSources,
+			MergedBy:     userID,
+		})
+		return err
+	}
+
+	require.ErrorIs(t, merge(lawnID, nil, nil), ErrInvalidMerge)
+	require.ErrorIs(t, merge(lawnID, []string{lawnID}, nil), ErrInvalidMerge)
+	require.ErrorIs(t, merge(lawnID, []string{shrubID}, nil), ErrInvalidMerge)
+	require.ErrorIs(t, merge(lawnID, []string{otherLawnID}, map[string]string{"flea_only": otherLawnID}), ErrInvalidMerge)
+	require.ErrorIs(t, merge(lawnID, []string{otherLawnID}, map[string]string{"first_name": shrubID}), ErrInvalidMerge)
+	require.ErrorIs(t, merge(lawnID, []string{"00000000-0000-0000-0000-000000000000"}, nil), sql.ErrNoRows)
+
+	// Nothing was changed by the failed merges
+	_, err = repo.GetLawnFormById(ctx, otherLawnID, userID)
+	require.NoError(t, err)
+}
@@ -24,10 +24,21 @@ type Form struct {
 	OtherPhone   string
 	CallBefore   bool
 	IsHoliday    bool
+	// PropertyID is empty for forms not linked to a property
+	PropertyID string
+	// ClonedFrom is the form this one was cloned from in an earlier season
+	ClonedFrom string
 
 	FirstAppDate time.Time
 	LastAppDate  time.Time
 
+	// Set while the form is in the trash
+	DeletedAt *time.Time
+	DeletedBy string
+
+	// Query terms highlighted in the form's text, set when listed by a search
+	SearchSnippet string
+
 	AppTimes []PestApp
 	Notes    []Note
 }
@@ -39,11 +50,89 @@ type PestApp struct {
 	Rate          string
 	AmountApplied decimal.Decimal
 	LocationCode  string
+	// StructuredRate is nil for applications recorded with only the free-text Rate
+	StructuredRate *StructuredRate
+	// ApplicationMethod is one of ApplicationMethods, or empty when not recorded
+	ApplicationMethod string
+	Weather           Weather
+	// AppliedBy is the user who made the application. New applications default to the user
+	// recording them; an existing application keeps its applicator when none is given.
+	AppliedBy string
+	// AppliedByName is the applicator's full name, set when read and ignored on input
+	AppliedByName string
+
+	// The label checks below are computed when the application is saved and ignored on input.
+
+	// ExpectedAmount is the amount the structured rate calls for on a lawn form's area.
+	// It is nil unless the rate is per 1,000 sq ft and the form is a lawn form.
+	ExpectedAmount *decimal.Decimal
+	// OverLabelMax is set when the rate or amount exceeds the chemical's label maximum
+	OverLabelMax bool
+	// Set once a supervisor acknowledges an application over the label maximum
+	OverLabelAcknowledgedBy string
+	OverLabelAcknowledgedAt *time.Time
+
+	// ApplicatorLicense is stamped when the application is recorded or reassigned to another
+	// applicator and ignored on input. It is nil if the applicator had no license on file.
+	ApplicatorLicense *ApplicatorLicense
+}
+
+// StructuredRate is an application rate as an amount of product per 1,000 sq ft or per gallon,
+// see chemicals.RateBasisPer1000SqFt and chemicals.RateBasisPerGallon
+type StructuredRate struct {
+	Amount decimal.Decimal
+	Unit   string
+	Basis  string
 }
 
 type Note struct {
-	ID      int
-	Message string
+	ID        int
+	CreatedBy string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Message   string
+}
+
+// Revision actions recorded in form_revisions
+const (
+	RevisionActionCreate   = "create"
+	RevisionActionUpdate   = "update"
+	RevisionActionDelete   = "delete"
+	RevisionActionRollback = "rollback"
+	RevisionActionRestore  = "restore"
+	RevisionActionPurge    = "purge"
+	RevisionActionMerge    = "merge"
+)
+
+// FormRevision is a single entry in a form's change history
+type FormRevision struct {
+	ID            int64
+	FormID        string
+	ChangedBy     string
+	ChangedByName string
+	ChangedAt     time.Time
+	Action        string
+	Diff          RevisionDiff
+}
+
+// RevisionDiff describes the client and application fields changed by a revision
+type RevisionDiff struct {
+	Fields              map[string]FieldChange `json:"fields"`
+	ApplicationsAdded   []map[string]any       `json:"applications_added,omitempty"`
+	ApplicationsRemoved []map[string]any       `json:"applications_removed,omitempty"`
+	ApplicationsChanged []ApplicationChange    `json:"applications_changed,omitempty"`
+}
+
+// FieldChange holds the old and new value of a single field
+type FieldChange struct {
+	Old any `json:"old"`
+	New any `json:"new"`
+}
+
+// ApplicationChange holds the changed fields of a single pesticide application
+type ApplicationChange struct {
+	ID     int                    `json:"id"`
+	Fields map[string]FieldChange `json:"fields"`
 }
 
 type ShrubForm struct {
@@ -100,6 +189,14 @@ func (r lawnRow) ToDomain() (LawnDetails, error) {
 	}, nil
 }
 
+// form returns the common form fields of the view, whichever subtype it holds
+func (v *FormView) form() *Form {
+	if v.Shrub != nil {
+		return &v.Shrub.Form
+	}
+	return &v.Lawn.Form
+}
+
 func NewShrubFormView(form ShrubForm) *FormView {
 	return &FormView{
 		FormType: "shrub",
@@ -0,0 +1,228 @@
+package forms
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// listNotes returns the notes attached to a form, oldest first.
+func (r *FormsRepository) listNotes(ctx context.Context, formID string) ([]Note, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT
+			n.id,
+			n.created_by,
+			n.created_at,
+			n.updated_at,
+			n.note
+		FROM notes n
+		WHERE n.form_id = $1
+		ORDER BY n.created_at, n.id
+	`, formID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching notes for form: %s. %w", formID, err)
+	}
+	defer rows.Close()
+
+	var notes []Note
+	for rows.Next() {
+		var note Note
+		err := rows.Scan(
+			&note.ID,
+			&note.CreatedBy,
+			&note.CreatedAt,
+			&note.UpdatedAt,
+			&note.Message,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning note for form: %s. %w", formID, err)
+		}
+		notes = append(notes, note)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after notes query for form: %s. %w", formID, err)
+	}
+
+	return notes, nil
+}
+
+// listNotesByFormIds returns the notes of every given form in one query, keyed by form ID, oldest first.
+func (r *FormsRepository) listNotesByFormIds(ctx context.Context, formIDs []string) (map[string][]Note, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT
+			n.form_id,
+			n.id,
+			n.created_by,
+			n.created_at,
+			n.updated_at,
+			n.note
+		FROM notes n
+		WHERE n.form_id = ANY($1::uuid[])
+		ORDER BY n.created_at, n.id
+	`, pq.Array(formIDs))
+	if err != nil {
+		return nil, fmt.Errorf("error fetching notes for forms list: %w", err)
+	}
+	defer rows.Close()
+
+	notes := make(map[string][]Note, len(formIDs))
+	for rows.Next() {
+		var (
+			formID string
+			note   Note
+		)
+		err := rows.Scan(
+			&formID,
+			&note.ID,
+			&note.CreatedBy,
+			&note.CreatedAt,
+			&note.UpdatedAt,
+			&note.Message,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning note for forms list: %w", err)
+		}
+		notes[formID] = append(notes[formID], note)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after notes query for forms list: %w", err)
+	}
+
+	return notes, nil
+}
+
+// CreateNote attaches a note written by the given user to a form they created or made an
+// application on.
+// Returns the created note upon success.
+// It returns sql.ErrNoRows if the form does not exist or is not visible to the user.
+func (r *FormsRepository) CreateNote(
+	ctx context.Context,
+	formID string,
+	userID string,
+	message string,
+) (Note, error) {
+	var note Note
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO notes (
+			form_id,
+			created_by,
+			note
+		)
+		SELECT f.id, $2::uuid, $3
+		FROM forms f
+		WHERE f.id = $1 AND f.deleted_at IS NULL AND `+visibleToCondition("$2::uuid")+`
+		RETURNING
+			id,
+			created_by,
+			created_at,
+			updated_at,
+			note
+	`, formID, userID, message).Scan(
+		&note.ID,
+		&note.CreatedBy,
+		&note.CreatedAt,
+		&note.UpdatedAt,
+		&note.Message,
+	)
+	if err != nil {
+		//sql.ErrNoRows
+		return Note{}, err
+	}
+
+	return note, nil
+}
+
+// ListNotesByFormId returns every note on a form the given user created or made an application on,
+// oldest first.
+// It returns sql.ErrNoRows if the form does not exist or is not visible to the user.
+func (r *FormsRepository) ListNotesByFormId(
+	ctx context.Context,
+	formID string,
+	userID string,
+) ([]Note, error) {
+	err := r.db.QueryRowContext(ctx, `
+		SELECT f.id
+		FROM forms f
+		WHERE f.id = $1 AND f.deleted_at IS NULL AND `+visibleToCondition("$2::uuid")+`
+	`, formID, userID).Scan(&formID)
+	if err != nil {
+		//sql.ErrNoRows
+		return nil, err
+	}
+
+	return r.listNotes(ctx, formID)
+}
+
+// UpdateNoteById replaces the text of a note the given user wrote, or of any note on a form they created.
+// Returns the updated note upon success.
+// It returns sql.ErrNoRows if the form is not visible to the user or the note is not on the form
+// or not theirs to change.
+func (r *FormsRepository) UpdateNoteById(
+	ctx context.Context,
+	formID string,
+	userID string,
+	noteID int,
+	message string,
+) (Note, error) {
+	var note Note
+	err := r.db.QueryRowContext(ctx, `
+		UPDATE notes n
+		SET note = $1
+		FROM forms f
+		WHERE n.id = $2
+		  AND n.form_id = $3
+		  AND f.id = n.form_id
+		  AND (f.created_by = $4 OR n.created_by = $4)
+		  AND f.deleted_at IS NULL
+		  AND `+visibleToCondition("$4::uuid")+`
+		RETURNING
+			n.id,
+			n.created_by,
+			n.created_at,
+			n.updated_at,
+			n.note
+	`, message, noteID, formID, userID).Scan(
+		&note.ID,
+		&note.CreatedBy,
+		&note.CreatedAt,
+		&note.UpdatedAt,
+		&note.Message,
+	)
+	if err != nil {
+		//sql.ErrNoRows
+		return Note{}, err
+	}
+
+	return note, nil
+}
+
+// DeleteNoteById removes a note the given user wrote, or any note on a form they created.
+// It returns sql.ErrNoRows if the form is not visible to the user or the note is not on the form
+// or not theirs to remove.
+func (r *FormsRepository) DeleteNoteById(
+	ctx context.Context,
+	formID string,
+	userID string,
+	noteID int,
+) error {
+	err := r.db.QueryRowContext(ctx, `
+		DELETE FROM notes n
+		USING forms f
+		WHERE n.id = $1
+		  AND n.form_id = $2
+		  AND f.id = n.form_id
+		  AND (f.created_by = $3 OR n.created_by = $3)
+		  AND f.deleted_at IS NULL
+		  AND `+visibleToCondition("$3::uuid")+`
+		RETURNING n.id
+	`, noteID, formID, userID).Scan(&noteID)
+	if err != nil {
+		// sql.ErrNoRows → not found or not owned
+		return err
+	}
+
+	return nil
+}
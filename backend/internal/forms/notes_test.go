@@ -0,0 +1,100 @@
+package forms
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/db"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateAndListNotes(t *testing.T) {
+	ctx := context.Background()
+	testDB := db.TestDB(t)
+	repo := NewFormsRepository(testDB)
+
+	userID := createTestUser(t, testDB)
+	formID := createTestShrubForm(t, repo, CreateShrubFormInput{CreatedBy: userID})
+
+	first, err := repo.CreateNote(ctx, formID, userID, "dog in yard")
+	require.NoError(t, err)
+	require.Equal(t, userID, first.CreatedBy)
+	require.False(t, first.CreatedAt.IsZero())
+
+	_, err = repo.CreateNote(ctx, formID, userID, "gate code 1234")
+	require.NoError(t, err)
+
+	notes, err := repo.ListNotesByFormId(ctx, formID, userID)
+	require.NoError(t, err)
+	require.Len(t, notes, 2)
+	require.Equal(t, "dog in yard", notes[0].Message)
+	require.Equal(t, "gate code 1234", notes[1].Message)
+
+	// Notes are hydrated on every read path
+	shrubForm, err := repo.GetShrubFormById(ctx, formID, userID)
+	require.NoError(t, err)
+	require.Len(t, shrubForm.Notes, 2)
+
+	view, err := repo.GetFormViewById(ctx, formID, userID)
+	require.NoError(t, err)
+	require.Len(t, view.Shrub.Notes, 2)
+
+	page, err := repo.ListFormsByUserId(ctx, userID, ListFormsOptions{})
+	require.NoError(t, err)
+	views := page.Forms
+	require.Len(t, views, 1)
+	require.Len(t, views[0].Shrub.Notes, 2)
+}
+
+func TestUpdateAndDeleteNote(t *testing.T) {
+	ctx := context.Background()
+	testDB := db.TestDB(t)
+	repo := NewFormsRepository(testDB)
+
+	userID := createTestUser(t, testDB)
+	formID := createTestShrubForm(t, repo, CreateShrubFormInput{CreatedBy: userID})
+
+	note, err := repo.CreateNote(ctx, formID, userID, "gate code 1234")
+	require.NoError(t, err)
+
+	updated, err := repo.UpdateNoteById(ctx, formID, userID, note.ID, "gate code 4321")
+	require.NoError(t, err)
+	require.Equal(t, "gate code 4321", updated.Message)
+	require.False(t, updated.UpdatedAt.Before(note.UpdatedAt))
+
+	err = repo.DeleteNoteById(ctx, formID, userID, note.ID)
+	require.NoError(t, err)
+
+	notes, err := repo.ListNotesByFormId(ctx, formID, userID)
+	require.NoError(t, err)
+	require.Empty(t, notes)
+
+	err = repo.DeleteNoteById(ctx, formID, userID, note.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestNotes_WrongUser(t *testing.T) {
+	ctx := context.Background()
+	testDB := db.TestDB(t)
+	repo := NewFormsRepository(testDB)
+
+	ownerID := createTestUser(t, testDB)
+	otherID := createTestUser(t, testDB)
+	formID := createTestShrubForm(t, repo, CreateShrubFormInput{CreatedBy: ownerID})
+
+	note, err := repo.CreateNote(ctx, formID, ownerID, "dog in yard")
+	require.NoError(t, err)
+
+	_, err = repo.CreateNote(ctx, formID, otherID, "not mine")
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	_, err = repo.ListNotesByFormId(ctx, formID, otherID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	_, err = repo.UpdateNoteById(ctx, formID, otherID, note.ID, "changed")
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	err = repo.DeleteNoteById(ctx, formID, otherID, note.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
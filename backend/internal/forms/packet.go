@@ -0,0 +1,262 @@
+package forms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+var (
+	// ErrEmptyPacketSelection means a packet named neither forms nor filters
+	ErrEmptyPacketSelection = errors.New("form_ids or at least one of date_low, date_high, created_by and zip_code is required")
+	// ErrPacketFormNotFound means a form listed for a packet does not exist
+	ErrPacketFormNotFound = errors.New("form not found")
+)
+
+// PDF packet statuses
+const (
+	PacketStatusPending = "pending"
+	PacketStatusReady   = "ready"
+	PacketStatusFailed  = "failed"
+)
+
+// PacketSelection picks the forms printed in a PDF packet: the listed forms in the given order,
+// or else the live forms matching the date, creator and zip code filters, in route order
+type PacketSelection struct {
+	FormIDs []string
+	// Filters uses DateLow, DateHigh, CreatedBy and ZipCode; other options are ignored
+	Filters ListFormsOptions
+}
+
+// PdfPacket is a PDF packet rendered in the background
+type PdfPacket struct {
+	ID          string
+	RequestedBy string
+	CreatedAt   time.Time
+	CompletedAt *time.Time
+	FormIDs     []string
+	Status      string
+	// Error is set when the packet failed
+	Error string
+}
+
+// SelectPacketForms returns the IDs of the forms in a packet.
+// It returns ErrEmptyPacketSelection if the selection names neither forms nor filters,
+// and ErrPacketFormNotFound if a listed form does not exist.
+func (r *FormsRepository) SelectPacketForms(ctx context.Context, sel PacketSelection) ([]string, error) {
+	if len(sel.FormIDs) > 0 {
+		// Keep the caller's order, printing each form once
+		seen := make(map[string]bool, len(sel.FormIDs))
+		formIDs := make([]string, 0, len(sel.FormIDs))
+		for _, id := range sel.FormIDs {
+			if !seen[id] {
+				seen[id] = true
+				formIDs = append(formIDs, id)
+			}
+		}
+
+		rows, err := r.db.QueryContext(ctx, `
+			SELECT id::text FROM forms WHERE id::text = ANY($1)
+		`, pq.Array(formIDs))
+		if err != nil {
+			return nil, fmt.Errorf("error checking packet forms: %w", err)
+		}
+		defer rows.Close()
+
+		found := make(map[string]bool, len(formIDs))
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				return nil, fmt.Errorf("error scanning packet form: %w", err)
+			}
+			found[id] = true
+		}
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("error after packet forms query: %w", err)
+		}
+		for _, id := range formIDs {
+			if !found[id] {
+				return nil, fmt.Errorf("%w: %s", ErrPacketFormNotFound, id)
+			}
+		}
+		return formIDs, nil
+	}
+
+	filters := ListFormsOptions{
+		DateLow:   sel.Filters.DateLow,
+		DateHigh:  sel.Filters.DateHigh,
+		CreatedBy: sel.Filters.CreatedBy,
+		ZipCode:   sel.Filters.ZipCode,
+	}
+	if filters.DateLow.IsZero() && filters.DateHigh.IsZero() && filters.CreatedBy == "" && filters.ZipCode == "" {
+		return nil, ErrEmptyPacketSelection
+	}
+
+	whereConditions, args, _ := appendFormFilters(filters, []string{deletedCondition(filters)}, []any{}, 1)
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(`
+		WITH form_app_dates AS (
+			SELECT
+				form_id,
+				MIN(app_timestamp) as first_app_date,
+				MAX(app_timestamp) as last_app_date
+			FROM pesticide_applications
+			GROUP BY form_id
+		)
+		SELECT f.id
+		FROM forms f
+		LEFT JOIN form_app_dates fad ON f.id = fad.form_id
+		WHERE %s
+		ORDER BY f.zip_code, normalize_street(f.street_name), f.street_number, f.last_name, f.id
+	`, strings.Join(whereConditions, " AND ")), args...)
+	if err != nil {
+		return nil, fmt.Errorf("error selecting packet forms: %w", err)
+	}
+	defer rows.Close()
+
+	formIDs := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("error scanning packet form: %w", err)
+		}
+		formIDs = append(formIDs, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after packet forms query: %w", err)
+	}
+	return formIDs, nil
+}
+
+// CreatePdfPacket records a pending packet of the given forms and removes packets created
+// more than retention ago. It returns the new packet.
+func (r *FormsRepository) CreatePdfPacket(
+	ctx context.Context,
+	requestedBy string,
+	formIDs []string,
+	retention time.Duration,
+) (PdfPacket, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return PdfPacket{}, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM pdf_packets WHERE created_at < NOW() - make_interval(secs => $1)
+	`, retention.Seconds())
+	if err != nil {
+		return PdfPacket{}, fmt.Errorf("error removing expired packets: %w", err)
+	}
+
+	packet := PdfPacket{
+		RequestedBy: requestedBy,
+		FormIDs:     formIDs,
+		Status:      PacketStatusPending,
+	}
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO pdf_packets (requested_by, form_ids)
+		VALUES ($1, $2)
+		RETURNING id, created_at
+	`, requestedBy, pq.Array(formIDs)).Scan(&packet.ID, &packet.CreatedAt)
+	if err != nil {
+		return PdfPacket{}, fmt.Errorf("error creating packet: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return PdfPacket{}, fmt.Errorf("error committing transaction: %w", err)
+	}
+	return packet, nil
+}
+
+// GetPdfPacketById returns a packet without its document.
+// It returns sql.ErrNoRows if the packet does not exist or was removed after expiring.
+func (r *FormsRepository) GetPdfPacketById(ctx context.Context, packetID string) (PdfPacket, error) {
+	var packet PdfPacket
+	err := r.db.QueryRowContext(ctx, `
+		SELECT
+			id,
+			COALESCE(requested_by::text, ''),
+			created_at,
+			completed_at,
+			form_ids::text[],
+			status,
+			error
+		FROM pdf_packets
+		WHERE id = $1
+	`, packetID).Scan(
+		&packet.ID,
+		&packet.RequestedBy,
+		&packet.CreatedAt,
+		&packet.CompletedAt,
+		pq.Array(&packet.FormIDs),
+		&packet.Status,
+		&packet.Error,
+	)
+	if err != nil {
+		// Important: let sql.ErrNoRows propagate
+		return PdfPacket{}, err
+	}
+	return packet, nil
+}
+
+// GetPdfPacketDocument returns the rendered document of a ready packet.
+// It returns sql.ErrNoRows if the packet does not exist or is not ready.
+func (r *FormsRepository) GetPdfPacketDocument(ctx context.Context, packetID string) ([]byte, error) {
+	var document []byte
+	err := r.db.QueryRowContext(ctx, `
+		SELECT document FROM pdf_packets WHERE id = $1 AND status = $2
+	`, packetID, PacketStatusReady).Scan(&document)
+	if err != nil {
+		return nil, err
+	}
+	return document, nil
+}
+
+// CompletePdfPacket stores a packet's rendered document and marks it ready
+func (r *FormsRepository) CompletePdfPacket(ctx context.Context, packetID string, document []byte) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE pdf_packets
+		SET status = $2, document = $3, completed_at = NOW()
+		WHERE id = $1
+	`, packetID, PacketStatusReady, document)
+	if err != nil {
+		return fmt.Errorf("error completing packet: %w", err)
+	}
+	return nil
+}
+
+// FailPdfPacket marks a packet failed with the given reason
+func (r *FormsRepository) FailPdfPacket(ctx context.Context, packetID string, reason string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE pdf_packets
+		SET status = $2, error = $3, completed_at = NOW()
+		WHERE id = $1
+	`, packetID, PacketStatusFailed, reason)
+	if err != nil {
+		return fmt.Errorf("error failing packet: %w", err)
+	}
+	return nil
+}
+
+// FailInterruptedPdfPackets marks packets still pending as failed. It is run at startup,
+// when no packet can be in progress, so packets interrupted by a restart don't wait forever.
+// It returns the number of packets marked failed.
+func (r *FormsRepository) FailInterruptedPdfPackets(ctx context.Context) (int, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE pdf_packets
+		SET status = $1, error = 'interrupted by a server restart', completed_at = NOW()
+		WHERE status = $2
+	`, PacketStatusFailed, PacketStatusPending)
+	if err != nil {
+		return 0, fmt.Errorf("error failing interrupted packets: %w", err)
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("error counting interrupted packets: %w", err)
+	}
+	return int(count), nil
+}
@@ -0,0 +1,137 @@
+package forms
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/db"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectPacketForms(t *testing.T) {
+	ctx := context.Background()
+	testDB := db.TestDB(t)
+	repo := NewFormsRepository(testDB)
+
+	userID := createTestUser(t, testDB)
+	otherUserID := createTestUser(t, testDB)
+	chemID := createTestChemical(t, testDB, "lawn")
+
+	appTime := time.Now().Add(-time.Hour)
+	createLawn := func(createdBy, lastName, streetName, zipCode string, at time.Time) string {
+		id, err := repo.CreateLawnForm(ctx, CreateLawnFormInput{
+			CreatedBy:    createdBy,
+			FirstName:    "Pat",
+			LastName:     lastName,
+			StreetNumber: "5",
+			StreetName:   streetName,
+			Town:         "Town",
+			ZipCode:      zipCode,
+			HomePhone:    "555-" + lastName,
+			LawnAreaSqFt: 1000,
+			Applications: []PestApp{{
+				ChemUsed:      chemID,
+				AppTimestamp:  at,
+				Rate:          "1 oz/1000 sq ft",
+				AmountApplied: decimal.NewFromFloat(1),
+				LocationCode:  "1A",
+			}},
+		})
+		require.NoError(t, err)
+		return id
+	}
+	elmID := createLawn(userID, "Elm", "Elm St", "10001", appTime)
+	ashID := createLawn(userID, "Ash", "Ash St", "10001", appTime)
+	farID := createLawn(otherUserID, "Far", "Oak St", "10002", appTime)
+	oldID := createLawn(userID, "Old", "Old St", "10001", appTime.AddDate(0, -1, 0))
+
+	// Filters select live forms in route order
+	formIDs, err := repo.SelectPacketForms(ctx, PacketSelection{Filters: ListFormsOptions{ZipCode: "10001"}})
+	require.NoError(t, err)
+	require.Equal(t, []string{ashID, elmID, oldID}, formIDs)
+
+	formIDs, err = repo.SelectPacketForms(ctx, PacketSelection{Filters: ListFormsOptions{
+		DateLow:   appTime.Add(-time.Minute),
+		DateHigh:  appTime.Add(time.Minute),
+		CreatedBy: userID,
+	}})
+	require.NoError(t, err)
+	require.Equal(t, []string{ashID, elmID}, formIDs)
+
+	require.NoError(t, repo.DeleteFormById(ctx, ashID, userID))
+	formIDs, err = repo.SelectPacketForms(ctx, PacketSelection{Filters: ListFormsOptions{ZipCode: "10001"}})
+	require.NoError(t, err)
+	require.Equal(t, []string{elmID, oldID}, formIDs)
+
+	// Listed forms keep their order and are printed once
+	formIDs, err = repo.SelectPacketForms(ctx, PacketSelection{FormIDs: []string{farID, elmID, farID}})
+	require.NoError(t, err)
+	require.Equal(t, []string{farID, elmID}, formIDs)
+
+	_, err = repo.SelectPacketForms(ctx, PacketSelection{FormIDs: []string{elmID, "00000000-0000-0000-0000-000000000000"}})
+	require.ErrorIs(t, err, ErrPacketFormNotFound)
+
+	// Other list options alone do not select anything
+	_, err = repo.SelectPacketForms(ctx, PacketSelection{Filters: ListFormsOptions{FormType: "lawn"}})
+	require.ErrorIs(t, err, ErrEmptyPacketSelection)
+}
+
+func TestPdfPacketLifecycle(t *testing.T) {
+	ctx := context.Background()
+	testDB := db.TestDB(t)
+	repo := NewFormsRepository(testDB)
+
+	userID := createTestUser(t, testDB)
+	formID, err := repo.CreateShrubForm(ctx, CreateShrubFormInput{
+		CreatedBy:    userID,
+		FirstName:    "Quinn",
+		LastName:     "Queue",
+		StreetNumber: "8",
+		StreetName:   "Batch Blvd",
+		Town:         "Town",
+		ZipCode:      "10001",
+		HomePhone:    "555-8000",
+	})
+	require.NoError(t, err)
+
+	packet, err := repo.CreatePdfPacket(ctx, userID, []string{formID}, time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, PacketStatusPending, packet.Status)
+
+	// Not downloadable until it is ready
+	_, err = repo.GetPdfPacketDocument(ctx, packet.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	require.NoError(t, repo.CompletePdfPacket(ctx, packet.ID, []byte("%PDF-1.3")))
+	packet, err = repo.GetPdfPacketById(ctx, packet.ID)
+	require.NoError(t, err)
+	require.Equal(t, PacketStatusReady, packet.Status)
+	require.Equal(t, []string{formID}, packet.FormIDs)
+	require.NotNil(t, packet.CompletedAt)
+
+	document, err := repo.GetPdfPacketDocument(ctx, packet.ID)
+	require.NoError(t, err)
+	require.Equal(t, []byte("%PDF-1.3"), document)
+
+	// Pending packets are failed after a restart
+	pending, err := repo.CreatePdfPacket(ctx, userID, []string{formID}, time.Hour)
+	require.NoError(t, err)
+	count, err := repo.FailInterruptedPdfPackets(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+	pending, err = repo.GetPdfPacketById(ctx, pending.ID)
+	require.NoError(t, err)
+	require.Equal(t, PacketStatusFailed, pending.Status)
+	require.NotEmpty(t, pending.Error)
+
+	// Expired packets are removed when the next one is created
+	_, err = testDB.Exec(`UPDATE pdf_packets SET created_at = NOW() - INTERVAL '2 hours' WHERE id = $1`, packet.ID)
+	require.NoError(t, err)
+	_, err = repo.CreatePdfPacket(ctx, userID, []string{formID}, time.Hour)
+	require.NoError(t, err)
+	_, err = repo.GetPdfPacketById(ctx, packet.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
@@ -0,0 +1,267 @@
+package forms
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded
+// or was issued for a different sort order than the one requested.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// FormsPage is one page of a form listing
+type FormsPage struct {
+	Forms []*FormView
+	// Total is the number of forms matching the filters across all pages
+	Total int
+	// NextCursor and PrevCursor are empty when there is no page in that direction
+	NextCursor string
+	PrevCursor string
+}
+
+// sortColumn is a column a form listing may be sorted by.
+// Nullable columns are flagged so they can be coalesced past either end of the range,
+// which keeps forms without a value at the end of the list in both directions
+// and gives the keyset comparison a non-NULL value to work with.
+type sortColumn struct {
+	column   string
+	keyType  string
+	nullable bool
+}
+
+var allowedSorts = map[string]sortColumn{
+	"created_at":     {column: "f.created_at", keyType: "timestamptz"},
+	"first_name":     {column: "f.first_name", keyType: "text"},
+	"last_name":      {column: "f.last_name", keyType: "text"},
+	"first_app_date": {column: "fad.first_app_date", keyType: "timestamptz", nullable: true},
+	"deleted_at":     {column: "f.deleted_at", keyType: "timestamptz", nullable: true},
+}
+
+// formsSort is the resolved ordering of a form listing. Rows are ordered by the sort
+// expression with the form ID as a tiebreaker, so every row has a unique position.
+type formsSort struct {
+	SortBy string
+	Order  string
+	expr   string
+	column sortColumn
+}
+
+// resolveFormsSort validates the requested sort, falling back to newest first.
+// Searches may also be sorted by relevance, which is their default.
+func resolveFormsSort(opts ListFormsOptions, search *formSearch) formsSort {
+	sortBy := opts.SortBy
+	if sortBy == "" && search != nil {
+		sortBy = "relevance"
+	}
+
+	column, ok := allowedSorts[sortBy]
+	if sortBy == "relevance" && search != nil {
+		column, ok = sortColumn{column: search.rank, keyType: "real"}, true
+	}
+	if !ok {
+		sortBy = "created_at"
+		column = allowedSorts[sortBy]
+	}
+
+	order := strings.ToUpper(opts.Order)
+	if order != "ASC" && order != "DESC" {
+		order = "DESC"
+	}
+
+	expr := column.column
+	if column.nullable {
+		// Put forms without a value at the end regardless of sort order
+		missing := "infinity"
+		if order == "DESC" {
+			missing = "-infinity"
+		}
+		expr = fmt.Sprintf("COALESCE(%s, '%s'::%s)", column.column, missing, column.keyType)
+	}
+
+	return formsSort{
+		SortBy: sortBy,
+		Order:  order,
+		expr:   expr,
+		column: column,
+	}
+}
+
+// orderClause returns the ORDER BY clause, reversed when walking backwards from a cursor
+func (s formsSort) orderClause(cursor *formsCursor) string {
+	order := s.Order
+	if cursor != nil && cursor.Before {
+		order = reverseOrder(order)
+	}
+	return fmt.Sprintf("%s %s, f.id %s", s.expr, order, order)
+}
+
+// keysetCondition returns the WHERE condition selecting rows after (or before) the cursor.
+// keyArg and idArg are the placeholder indexes of the cursor's key and form ID.
+func (s formsSort) keysetCondition(cursor *formsCursor, keyArg int, idArg int) string {
+	ascending := s.Order == "ASC"
+	if cursor.Before {
+		ascending = !ascending
+	}
+
+	operator := "<"
+	if ascending {
+		operator = ">"
+	}
+	return fmt.Sprintf("(%s, f.id) %s ($%d::%s, $%d::uuid)", s.expr, operator, keyArg, s.column.keyType, idArg)
+}
+
+func reverseOrder(order string) string {
+	if order == "ASC" {
+		return "DESC"
+	}
+	return "ASC"
+}
+
+// uuidPattern matches the text form of a UUID
+var uuidPattern = regexp.MustCompile(`^(?i)[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
+// timestampKeyLayouts are the ISO text forms Postgres gives a timestamptz, by time zone offset
+var timestampKeyLayouts = []string{
+	"2006-01-02 15:04:05.999999999Z07",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999Z07:00:00",
+}
+
+// validCursorKey reports whether key can be cast to keyType, so a tampered or stale cursor
+// is refused before it reaches the query
+func validCursorKey(key string, keyType string) bool {
+	switch keyType {
+	case "timestamptz":
+		if key == "infinity" || key == "-infinity" {
+			// The stand-ins for forms without a value
+			return true
+		}
+		for _, layout := range timestampKeyLayouts {
+			if _, err := time.Parse(layout, key); err == nil {
+				return true
+			}
+		}
+		return false
+	case "real":
+		_, err := strconv.ParseFloat(key, 32)
+		return err == nil
+	case "text":
+		return utf8.ValidString(key) && !strings.ContainsRune(key, 0)
+	default:
+		return false
+	}
+}
+
+// formsCursor marks a position in a sorted form listing.
+// Before is set on cursors that page backwards from the marked row.
+type formsCursor struct {
+	SortBy string `json:"s"`
+	Order  string `json:"o"`
+	Key    string `json:"k"`
+	ID     string `json:"id"`
+	Before bool   `json:"b,omitempty"`
+}
+
+func encodeFormsCursor(cursor formsCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeFormsCursor parses an opaque cursor, returning nil when none was given.
+// It returns ErrInvalidCursor if the cursor is malformed, belongs to another sort order
+// or carries a key that is not of the sort's type.
+func decodeFormsCursor(encoded string, sort formsSort) (*formsCursor, error) {
+	if encoded == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor formsCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == "" {
+		return nil, ErrInvalidCursor
+	}
+	if cursor.SortBy != sort.SortBy || cursor.Order != sort.Order {
+		return nil, fmt.Errorf("%w: cursor was issued for sort_by=%s order=%s", ErrInvalidCursor, cursor.SortBy, cursor.Order)
+	}
+	if !uuidPattern.MatchString(cursor.ID) || !validCursorKey(cursor.Key, sort.column.keyType) {
+		return nil, ErrInvalidCursor
+	}
+
+	return &cursor, nil
+}
+
+// newFormsPage builds a page from rows fetched with one extra look-ahead row.
+// sortKeys holds the sort expression of each row as text, parallel to views.
+func newFormsPage(
+	views []*FormView,
+	sortKeys []string,
+	sort formsSort,
+	opts ListFormsOptions,
+	cursor *formsCursor,
+	total int,
+) FormsPage {
+	page := FormsPage{
+		Forms: views,
+		Total: total,
+	}
+	if opts.Limit <= 0 {
+		return page
+	}
+
+	hasMore := len(views) > opts.Limit
+	if hasMore {
+		views = views[:opts.Limit]
+		sortKeys = sortKeys[:opts.Limit]
+	}
+
+	var hasNext, hasPrev bool
+	if cursor != nil && cursor.Before {
+		// Rows were fetched in reverse; put them back in display order
+		for i, j := 0, len(views)-1; i < j; i, j = i+1, j-1 {
+			views[i], views[j] = views[j], views[i]
+			sortKeys[i], sortKeys[j] = sortKeys[j], sortKeys[i]
+		}
+		hasNext = true
+		hasPrev = hasMore
+	} else {
+		hasNext = hasMore
+		hasPrev = cursor != nil || opts.Offset > 0
+	}
+
+	page.Forms = views
+	if len(views) == 0 {
+		return page
+	}
+
+	if hasNext {
+		last := len(views) - 1
+		page.NextCursor = encodeFormsCursor(formsCursor{
+			SortBy: sort.SortBy,
+			Order:  sort.Order,
+			Key:    sortKeys[last],
+			ID:     views[last].form().ID,
+		})
+	}
+	if hasPrev {
+		page.PrevCursor = encodeFormsCursor(formsCursor{
+			SortBy: sort.SortBy,
+			Order:  sort.Order,
+			Key:    sortKeys[0],
+			ID:     views[0].form().ID,
+			Before: true,
+		})
+	}
+
+	return page
+}
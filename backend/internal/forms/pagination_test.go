@@ -0,0 +1,202 @@
+package forms
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/db"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+// createPaginationTestForms creates forms with repeated names and application dates,
+// so every sort has ties that only the form ID can break. Every third form has no applications.
+func createPaginationTestForms(t *testing.T, repo *FormsRepository, userID string, chemID int, count int) {
+	t.Helper()
+
+	now := time.Now()
+	for i := 0; i < count; i++ {
+		var applications []PestApp
+		if i%3 != 0 {
+			applications = []PestApp{
+				{
+					ChemUsed:      chemID,
+					AppTimestamp:  now.Add(-time.Duration(i%4) * 24 * time.Hour),
+					Rate:          "2 oz/1000 sq ft",
+					AmountApplied: decimal.NewFromFloat(2.0),
+					LocationCode:  "1A",
+				},
+			}
+		}
+
+		createTestLawnForm(t, repo, CreateLawnFormInput{
+			CreatedBy:    userID,
+			FirstName:    fmt.Sprintf("Page%d", i%3),
+			LastName:     fmt.Sprintf("Customer%d", i%2),
+			StreetNumber: fmt.Sprintf("%d", i),
+			StreetName:   "Cursor Ct",
+			Town:         "Town",
+			ZipCode:      "10001",
+			HomePhone:    "555-0001",
+			OtherPhone:   "555-0002",
+			LawnAreaSqFt: 1000,
+			Applications: applications,
+		})
+	}
+}
+
+func formIDs(views []*FormView) []string {
+	ids := make([]string, 0, len(views))
+	for _, view := range views {
+		ids = append(ids, view.form().ID)
+	}
+	return ids
+}
+
+func TestListFormsByUserId_CursorPaginationEverySort(t *testing.T) {
+	ctx := context.Background()
+	testDB := db.TestDB(t)
+	repo := NewFormsRepository(testDB)
+
+	userID := createTestUser(t, testDB)
+	chemID := createTestChemical(t, testDB, "lawn")
+	createPaginationTestForms(t, repo, userID, chemID, 11)
+
+	for sortBy := range allowedSorts {
+		for _, order := range []string{"ASC", "DESC"} {
+			t.Run(sortBy+"_"+order, func(t *testing.T) {
+				opts := ListFormsOptions{SortBy: sortBy, Order: order}
+
+				unpaged, err := repo.ListFormsByUserId(ctx, userID, opts)
+				require.NoError(t, err)
+				require.Equal(t, 11, unpaged.Total)
+				require.Empty(t, unpaged.NextCursor)
+				expected := formIDs(unpaged.Forms)
+
+				// Walk forward through every page
+				opts.Limit = 4
+				var (
+					walked []string
+					pages  []FormsPage
+				)
+				for {
+					page, err := repo.ListFormsByUserId(ctx, userID, opts)
+					require.NoError(t, err)
+					require.Equal(t, 11, page.Total)
+					walked = append(walked, formIDs(page.Forms)...)
+					pages = append(pages, page)
+					if page.NextCursor == "" {
+						break
+					}
+					opts.Cursor = page.NextCursor
+				}
+				require.Equal(t, expected, walked)
+				require.Len(t, pages, 3)
+				require.Empty(t, pages[0].PrevCursor)
+
+				// Walk back from the last page
+				opts.Cursor = pages[2].PrevCursor
+				page, err := repo.ListFormsByUserId(ctx, userID, opts)
+				require.NoError(t, err)
+				require.Equal(t, formIDs(pages[1].Forms), formIDs(page.Forms))
+
+				opts.Cursor = page.PrevCursor
+				page, err = repo.ListFormsByUserId(ctx, userID, opts)
+				require.NoError(t, err)
+				require.Equal(t, formIDs(pages[0].Forms), formIDs(page.Forms))
+				require.Empty(t, page.PrevCursor)
+				require.NotEmpty(t, page.NextCursor)
+			})
+		}
+	}
+}
+
+func TestListAllForms_OffsetStillSupported(t *testing.T) {
+	ctx := context.Background()
+	testDB := db.TestDB(t)
+	repo := NewFormsRepository(testDB)
+
+	userID := createTestUser(t, testDB)
+	chemID := createTestChemical(t, testDB, "lawn")
+	createPaginationTestForms(t, repo, userID, chemID, 5)
+
+	opts := ListFormsOptions{SortBy: "first_name", Order: "ASC"}
+	all, err := repo.ListAllForms(ctx, opts)
+	require.NoError(t, err)
+
+	opts.Limit = 2
+	opts.Offset = 2
+	page, err := repo.ListAllForms(ctx, opts)
+	require.NoError(t, err)
+	require.Equal(t, 5, page.Total)
+	require.Equal(t, formIDs(all.Forms[2:4]), formIDs(page.Forms))
+	require.NotEmpty(t, page.PrevCursor)
+
+	// A cursor issued by an offset page continues from it
+	opts.Cursor = page.NextCursor
+	page, err = repo.ListAllForms(ctx, opts)
+	require.NoError(t, err)
+	require.Equal(t, formIDs(all.Forms[4:]), formIDs(page.Forms))
+	require.Empty(t, page.NextCursor)
+}
+
+func TestListFormsByUserId_InvalidCursor(t *testing.T) {
+	ctx := context.Background()
+	testDB := db.TestDB(t)
+	repo := NewFormsRepository(testDB)
+
+	userID := createTestUser(t, testDB)
+	chemID := createTestChemical(t, testDB, "lawn")
+	createPaginationTestForms(t, repo, userID, chemID, 3)
+
+	_, err := repo.ListFormsByUserId(ctx, userID, ListFormsOptions{Limit: 1, Cursor: "not-a-cursor"})
+	require.ErrorIs(t, err, ErrInvalidCursor)
+
+	page, err := repo.ListFormsByUserId(ctx, userID, ListFormsOptions{Limit: 1, SortBy: "last_name", Order: "ASC"})
+	require.NoError(t, err)
+	require.NotEmpty(t, page.NextCursor)
+
+	// Cursors only apply to the sort they were issued for
+	_, err = repo.ListFormsByUserId(ctx, userID, ListFormsOptions{Limit: 1, SortBy: "first_name", Order: "ASC", Cursor: page.NextCursor})
+	require.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+func TestDecodeFormsCursor_ChecksKeyType(t *testing.T) {
+	byCreatedAt := resolveFormsSort(ListFormsOptions{SortBy: "created_at", Order: "DESC"}, nil)
+	byFirstApp := resolveFormsSort(ListFormsOptions{SortBy: "first_app_date", Order: "ASC"}, nil)
+	byName := resolveFormsSort(ListFormsOptions{SortBy: "last_name", Order: "ASC"}, nil)
+	formID := "6f1c2b9e-3d4a-4b5c-8d7e-9f0a1b2c3d4e"
+
+	cursorFor := func(sort formsSort, key string, id string) string {
+		return encodeFormsCursor(formsCursor{SortBy: sort.SortBy, Order: sort.Order, Key: key, ID: id})
+	}
+
+	tests := []struct {
+		name  string
+		sort  formsSort
+		key   string
+		id    string
+		valid bool
+	}{
+		{"timestamp", byCreatedAt, "2026-05-01 14:03:22.123456+00", formID, true},
+		{"timestamp with minutes offset", byCreatedAt, "2026-05-01 14:03:22+05:30", formID, true},
+		{"form without a value", byFirstApp, "infinity", formID, true},
+		{"name", byName, "O'Brien", formID, true},
+		{"name as a date", byCreatedAt, "O'Brien", formID, false},
+		{"empty date", byCreatedAt, "", formID, false},
+		{"bad form id", byName, "Smith", "not-a-uuid", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor, err := decodeFormsCursor(cursorFor(tt.sort, tt.key, tt.id), tt.sort)
+			if tt.valid {
+				require.NoError(t, err)
+				require.Equal(t, tt.key, cursor.Key)
+				return
+			}
+			require.ErrorIs(t, err, ErrInvalidCursor)
+		})
+	}
+}
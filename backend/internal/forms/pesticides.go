@@ -1 +1,627 @@
 package forms
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/shopspring/decimal"
+)
+
+// pestAppColumns lists the pesticide_applications columns scanned by pestAppRow, aliased pa
+const pestAppColumns = `
+			pa.id,
+			pa.chem_used,
+			pa.app_timestamp,
+			pa.rate,
+			pa.amount_applied,
+			pa.location_code,
+			pa.rate_amount,
+			pa.rate_unit,
+			pa.rate_basis,
+			pa.expected_amount,
+			pa.over_label_max,
+			COALESCE(pa.over_label_acknowledged_by::text, ''),
+			pa.over_label_acknowledged_at,
+			COALESCE(pa.application_method, ''),
+			pa.wind_speed_mph,
+			COALESCE(pa.wind_direction, ''),
+			pa.temperature_f,
+			COALESCE(pa.sky_conditions, ''),
+			pa.applicator_license_number,
+			pa.applicator_license_category,
+			pa.applicator_license_state,
+			pa.applicator_license_expires_on,
+			COALESCE(pa.applied_by::text, ''),
+			COALESCE((
+				SELECT u.first_name || ' ' || u.last_name
+				FROM users u
+				WHERE u.id = pa.applied_by
+			), '')`
+
+// pestAppRow scans the columns of pestAppColumns
+type pestAppRow struct {
+	PestApp
+	RateAmount       decimal.NullDecimal
+	RateUnit         sql.NullString
+	RateBasis        sql.NullString
+	ExpectedAmount   decimal.NullDecimal
+	WindSpeedMph     decimal.NullDecimal
+	TemperatureF     decimal.NullDecimal
+	LicenseNumber    sql.NullString
+	LicenseCategory  sql.NullString
+	LicenseState     sql.NullString
+	LicenseExpiresOn sql.NullTime
+}
+
+// dest returns the scan destinations for pestAppColumns
+func (row *pestAppRow) dest() []any {
+	return []any{
+		&row.ID,
+		&row.ChemUsed,
+		&row.AppTimestamp,
+		&row.Rate,
+		&row.AmountApplied,
+		&row.LocationCode,
+		&row.RateAmount,
+		&row.RateUnit,
+		&row.RateBasis,
+		&row.ExpectedAmount,
+		&row.OverLabelMax,
+		&row.OverLabelAcknowledgedBy,
+		&row.OverLabelAcknowledgedAt,
+		&row.ApplicationMethod,
+		&row.WindSpeedMph,
+		&row.Weather.WindDirection,
+		&row.TemperatureF,
+		&row.Weather.SkyConditions,
+		&row.LicenseNumber,
+		&row.LicenseCategory,
+		&row.LicenseState,
+		&row.LicenseExpiresOn,
+		&row.AppliedBy,
+		&row.AppliedByName,
+	}
+}
+
+func (row pestAppRow) toDomain() PestApp {
+	app := row.PestApp
+	if row.RateAmount.Valid {
+		app.StructuredRate = &StructuredRate{
+			Amount: row.RateAmount.Decimal,
+			Unit:   row.RateUnit.String,
+			Basis:  row.RateBasis.String,
+		}
+	}
+	if row.ExpectedAmount.Valid {
+		app.ExpectedAmount = &row.ExpectedAmount.Decimal
+	}
+	if row.WindSpeedMph.Valid {
+		app.Weather.WindSpeedMph = &row.WindSpeedMph.Decimal
+	}
+	if row.TemperatureF.Valid {
+		app.Weather.TemperatureF = &row.TemperatureF.Decimal
+	}
+	if row.LicenseNumber.Valid {
+		app.ApplicatorLicense = &ApplicatorLicense{
+			Number:    row.LicenseNumber.String,
+			Category:  row.LicenseCategory.String,
+			State:     row.LicenseState.String,
+			ExpiresOn: row.LicenseExpiresOn.Time,
+		}
+	}
+	return app
+}
+
+// structuredRateArgs returns the structured rate column values, all NULL for an application without one
+func structuredRateArgs(rate *StructuredRate) (amount, unit, basis any) {
+	if rate == nil {
+		return nil, nil, nil
+	}
+	return rate.Amount, rate.Unit, rate.Basis
+}
+
+// touchForm bumps updated_at on a live form the given user created or made an application on,
+// locking the row for the rest of the transaction, and reports whether the user created it.
+// It returns sql.ErrNoRows if the form does not exist, is in the trash or is not visible to the user.
+func touchForm(ctx context.Context, tx *sql.Tx, formID string, userID string) (bool, error) {
+	var owner bool
+	err := tx.QueryRowContext(ctx, `
+		UPDATE forms f
+		SET updated_at = NOW()
+		WHERE f.id = $1 AND f.deleted_at IS NULL AND `+visibleToCondition("$2::uuid")+`
+		RETURNING f.created_by = $2::uuid
+	`, formID, userID).Scan(&owner)
+	return owner, err
+}
+
+// ownsPestApp reports whether the user may change a stored application: the form's creator may
+// change any of them, an applicator only their own
+func ownsPestApp(owner bool, userID string, appID int, stored []appSnapshot) bool {
+	if owner {
+		return true
+	}
+	for _, app := range stored {
+		if app.ID == appID {
+			return app.AppliedBy == userID
+		}
+	}
+	return false
+}
+
+// checkChemicalsActive returns a ValidationError if a new application, or one changed to another
+// chemical, uses a chemical that has been retired.
+// stored are the form's applications before the change, nil for a form being created; applications
+// left on their stored chemical are not checked, so forms recorded before a chemical was retired
+// can still be edited.
+// prefix names the fields of the i-th application.
+func checkChemicalsActive(ctx context.Context, tx *sql.Tx, apps []PestApp, stored []appSnapshot, prefix func(i int) string) error {
+	storedChems := make(map[int]int, len(stored))
+	for _, app := range stored {
+		storedChems[app.ID] = app.ChemUsed
+	}
+	unchanged := func(app PestApp) bool {
+		chemUsed, ok := storedChems[app.ID]
+		return ok && app.ID != 0 && chemUsed == app.ChemUsed
+	}
+
+	chemIDs := []int64{}
+	for _, app := range apps {
+		if unchanged(app) {
+			continue
+		}
+		chemIDs = append(chemIDs, int64(app.ChemUsed))
+	}
+	if len(chemIDs) == 0 {
+		return nil
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id
+		FROM chemicals
+		WHERE id = ANY($1::int[]) AND deleted_at IS NOT NULL
+	`, pq.Array(chemIDs))
+	if err != nil {
+		return fmt.Errorf("error fetching retired chemicals: %w", err)
+	}
+	defer rows.Close()
+
+	retired := map[int]bool{}
+	for rows.Next() {
+		var chemID int
+		if err := rows.Scan(&chemID); err != nil {
+			return fmt.Errorf("error scanning retired chemical: %w", err)
+		}
+		retired[chemID] = true
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error after retired chemicals query: %w", err)
+	}
+
+	var fields []FieldError
+	for i, app := range apps {
+		if !retired[app.ChemUsed] || unchanged(app) {
+			continue
+		}
+		fields = append(fields, FieldError{
+			Field:   prefix(i) + "chem_used",
+			Message: fmt.Sprintf("chemical %d has been retired", app.ChemUsed),
+		})
+	}
+	if len(fields) > 0 {
+		return &ValidationError{Fields: fields}
+	}
+	return nil
+}
+
+// insertPestApp inserts a single pesticide application for the given form, stamped with
+// its applicator's license, and returns it with its generated ID.
+// An application without an applicator is attributed to the form's creator.
+func insertPestApp(ctx context.Context, tx *sql.Tx, formID string, app PestApp) (PestApp, error) {
+	rateAmount, rateUnit, rateBasis := structuredRateArgs(app.StructuredRate)
+	err := tx.QueryRowContext(ctx, `
+		INSERT INTO pesticide_applications (
+			form_id,
+			chem_used,
+			app_timestamp,
+			rate,
+			amount_applied,
+			location_code,
+			rate_amount,
+			rate_unit,
+			rate_basis,
+			application_method,
+			wind_speed_mph,
+			wind_direction,
+			temperature_f,
+			sky_conditions,
+			applied_by
+		)
+		VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, NULLIF($10, ''), $11, NULLIF($12, ''), $13, NULLIF($14, ''),
+			COALESCE(NULLIF($15, '')::uuid, (SELECT created_by FROM forms WHERE id = $1))
+		)
+		RETURNING id
+	`,
+		formID,
+		app.ChemUsed,
+		app.AppTimestamp,
+		app.Rate,
+		app.AmountApplied,
+		app.LocationCode,
+		rateAmount,
+		rateUnit,
+		rateBasis,
+		app.ApplicationMethod,
+		app.Weather.WindSpeedMph,
+		app.Weather.WindDirection,
+		app.Weather.TemperatureF,
+		app.Weather.SkyConditions,
+		app.AppliedBy,
+	).Scan(&app.ID)
+	if err != nil {
+		return PestApp{}, err
+	}
+	if err := stampApplicatorLicense(ctx, tx, app.ID); err != nil {
+		return PestApp{}, err
+	}
+	return app, nil
+}
+
+// updatePestApp overwrites a single pesticide application belonging to the given form.
+// Changing the chemical, amount or structured rate withdraws any supervisor acknowledgement.
+// The application keeps its applicator unless app names one; a new applicator's license is stamped on it.
+// It returns sql.ErrNoRows if the application does not exist on the form.
+func updatePestApp(ctx context.Context, tx *sql.Tx, formID string, app PestApp) (PestApp, error) {
+	rateAmount, rateUnit, rateBasis := structuredRateArgs(app.StructuredRate)
+	var reassigned bool
+	err := tx.QueryRowContext(ctx, `
+		WITH previous AS (
+			SELECT applied_by
+			FROM pesticide_applications
+			WHERE id = $9 AND form_id = $10
+		)
+		UPDATE pesticide_applications
+		SET chem_used = $1,
+			app_timestamp = $2,
+			rate = $3,
+			amount_applied = $4,
+			location_code = $5,
+			rate_amount = $6,
+			rate_unit = $7,
+			rate_basis = $8,
+			application_method = NULLIF($11, ''),
+			wind_speed_mph = $12,
+			wind_direction = NULLIF($13, ''),
+			temperature_f = $14,
+			sky_conditions = NULLIF($15, ''),
+			applied_by = COALESCE(NULLIF($16, '')::uuid, applied_by),
+			over_label_acknowledged_by = CASE
+				WHEN (chem_used, amount_applied, rate_amount, rate_unit, rate_basis)
+					IS DISTINCT FROM ($1, $4, $6::numeric, $7::text, $8::text)
+				THEN NULL
+				ELSE over_label_acknowledged_by
+			END,
+			over_label_acknowledged_at = CASE
+				WHEN (chem_used, amount_applied, rate_amount, rate_unit, rate_basis)
+					IS DISTINCT FROM ($1, $4, $6::numeric, $7::text, $8::text)
+				THEN NULL
+				ELSE over_label_acknowledged_at
+			END
+		WHERE id = $9 AND form_id = $10
+		RETURNING id, applied_by IS DISTINCT FROM (SELECT applied_by FROM previous)
+	`,
+		app.ChemUsed,
+		app.AppTimestamp,
+		app.Rate,
+		app.AmountApplied,
+		app.LocationCode,
+		rateAmount,
+		rateUnit,
+		rateBasis,
+		app.ID,
+		formID,
+		app.ApplicationMethod,
+		app.Weather.WindSpeedMph,
+		app.Weather.WindDirection,
+		app.Weather.TemperatureF,
+		app.Weather.SkyConditions,
+		app.AppliedBy,
+	).Scan(&app.ID, &reassigned)
+	if err != nil {
+		return PestApp{}, err
+	}
+	if reassigned {
+		if err := stampApplicatorLicense(ctx, tx, app.ID); err != nil {
+			return PestApp{}, err
+		}
+	}
+	return app, nil
+}
+
+// syncPestApps reconciles the pesticide applications stored for a form with apps.
+// Applications with an ID are updated in place, applications without one are
+// inserted, and stored applications whose ID is omitted from apps are deleted.
+// An ID that does not belong to the form yields an error wrapping sql.ErrNoRows.
+// The label checks of every application are refreshed afterwards.
+func syncPestApps(ctx context.Context, tx *sql.Tx, formID string, apps []PestApp) error {
+	keep := []int64{}
+	for _, app := range apps {
+		if app.ID == 0 {
+			continue
+		}
+		if _, err := updatePestApp(ctx, tx, formID, app); err != nil {
+			return fmt.Errorf("error updating pesticide application %d for form %s: %w", app.ID, formID, err)
+		}
+		keep = append(keep, int64(app.ID))
+	}
+
+	_, err := tx.ExecContext(ctx, `
+		DELETE FROM pesticide_applications
+		WHERE form_id = $1
+		  AND NOT (id = ANY($2::int[]))
+	`, formID, pq.Array(keep))
+	if err != nil {
+		return fmt.Errorf("error deleting pesticide applications for form %s: %w", formID, err)
+	}
+
+	for _, app := range apps {
+		if app.ID != 0 {
+			continue
+		}
+		if _, err := insertPestApp(ctx, tx, formID, app); err != nil {
+			return fmt.Errorf("error inserting pesticide application for form %s: %w", formID, err)
+		}
+	}
+
+	return refreshLabelChecks(ctx, tx, formID)
+}
+
+// CreatePestApp adds a single pesticide application to a form the given user created or made an
+// application on.
+// Returns the created application upon success.
+// The returned application carries its label checks; one over the label maximum is saved
+// and flagged for supervisor acknowledgement.
+// The application is attributed to the given user unless app names another applicator.
+// It returns sql.ErrNoRows if the form does not exist or is not visible to the user,
+// a ValidationError if the application is invalid, names an unknown applicator or uses a retired chemical,
+// ErrLicenseExpired if the applicator's license has expired,
+// ErrCallRequired if the customer asked to be called first and was not reached within the
+// call window before the application, see CallWindowFromEnv,
+// and ErrHolidayBlackout if the customer observes Jewish holidays and the application falls on
+// a holiday or Shabbat.
+func (r *FormsRepository) CreatePestApp(
+	ctx context.Context,
+	formID string,
+	userID string,
+	app PestApp,
+) (PestApp, error) {
+	if fields := validatePestApp(app, nil, ""); len(fields) > 0 {
+		return PestApp{}, &ValidationError{Fields: fields}
+	}
+	// The application is new whatever ID it was sent with
+	app.ID = 0
+	if app.AppliedBy == "" {
+		app.AppliedBy = userID
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return PestApp{}, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := touchForm(ctx, tx, formID, userID); err != nil {
+		//sql.ErrNoRows
+		return PestApp{}, err
+	}
+
+	before, err := loadFormSnapshot(ctx, tx, formID)
+	if err != nil {
+		return PestApp{}, err
+	}
+
+	if err := checkChemicalsActive(ctx, tx, []PestApp{app}, nil, noFieldPrefix); err != nil {
+		return PestApp{}, err
+	}
+	if err := checkRateUnits(ctx, tx, []PestApp{app}, noFieldPrefix); err != nil {
+		return PestApp{}, err
+	}
+	if err := checkApplicators(ctx, tx, []PestApp{app}, noFieldPrefix); err != nil {
+		return PestApp{}, err
+	}
+	if err := checkCallBefore(ctx, tx, formID, before.CallBefore, []PestApp{app}, before.Applications, nil); err != nil {
+		return PestApp{}, err
+	}
+	if err := checkHolidayBlackout(before.IsHoliday, []PestApp{app}, nil); err != nil {
+		return PestApp{}, err
+	}
+
+	inserted, err := insertPestApp(ctx, tx, formID, app)
+	if err != nil {
+		return PestApp{}, fmt.Errorf("error inserting pesticide application for form %s: %w", formID, err)
+	}
+	if err := refreshLabelChecks(ctx, tx, formID); err != nil {
+		return PestApp{}, err
+	}
+	created, err := getPestApp(ctx, tx, formID, inserted.ID)
+	if err != nil {
+		return PestApp{}, err
+	}
+
+	if _, err := recordRevision(ctx, tx, formID, userID, RevisionActionUpdate, before); err != nil {
+		return PestApp{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return PestApp{}, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return created, nil
+}
+
+// UpdatePestAppById overwrites a single pesticide application. The form's creator may change any
+// of its applications, other users only the ones they made.
+// Returns the updated application upon success.
+// See CreatePestApp for the label checks.
+// The application keeps its applicator unless app names another one.
+// It returns sql.ErrNoRows if the form is not visible to the user or the application is not on the
+// form or not theirs to change,
+// a ValidationError if the application is invalid, names an unknown applicator or is moved onto a
+// retired chemical, ErrCallRequired if the customer asked to be called and was not reached before
+// the application's new time, and ErrHolidayBlackout if the customer observes Jewish holidays and
+// the application is moved onto a holiday or Shabbat.
+func (r *FormsRepository) UpdatePestAppById(
+	ctx context.Context,
+	formID string,
+	userID string,
+	app PestApp,
+) (PestApp, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return PestApp{}, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	owner, err := touchForm(ctx, tx, formID, userID)
+	if err != nil {
+		//sql.ErrNoRows
+		return PestApp{}, err
+	}
+
+	before, err := loadFormSnapshot(ctx, tx, formID)
+	if err != nil {
+		return PestApp{}, err
+	}
+	if !ownsPestApp(owner, userID, app.ID, before.Applications) {
+		return PestApp{}, sql.ErrNoRows
+	}
+
+	if fields := validatePestApp(app, before.Applications, ""); len(fields) > 0 {
+		return PestApp{}, &ValidationError{Fields: fields}
+	}
+	if err := checkChemicalsActive(ctx, tx, []PestApp{app}, before.Applications, noFieldPrefix); err != nil {
+		return PestApp{}, err
+	}
+	if err := checkRateUnits(ctx, tx, []PestApp{app}, noFieldPrefix); err != nil {
+		return PestApp{}, err
+	}
+	if err := checkApplicators(ctx, tx, []PestApp{app}, noFieldPrefix); err != nil {
+		return PestApp{}, err
+	}
+	if err := checkCallBefore(ctx, tx, formID, before.CallBefore, []PestApp{app}, before.Applications, nil); err != nil {
+		return PestApp{}, err
+	}
+	if err := checkHolidayBlackout(before.IsHoliday, []PestApp{app}, before.Applications); err != nil {
+		return PestApp{}, err
+	}
+
+	if _, err := updatePestApp(ctx, tx, formID, app); err != nil {
+		//sql.ErrNoRows
+		return PestApp{}, err
+	}
+	if err := refreshLabelChecks(ctx, tx, formID); err != nil {
+		return PestApp{}, err
+	}
+	updated, err := getPestApp(ctx, tx, formID, app.ID)
+	if err != nil {
+		return PestApp{}, err
+	}
+
+	if _, err := recordRevision(ctx, tx, formID, userID, RevisionActionUpdate, before); err != nil {
+		return PestApp{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return PestApp{}, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return updated, nil
+}
+
+// DeletePestAppById removes a single pesticide application, which like UpdatePestAppById must be on
+// a form the given user created or be one they made.
+// It returns sql.ErrNoRows if the form is not visible to the user or the application is not on the
+// form or not theirs to remove.
+func (r *FormsRepository) DeletePestAppById(
+	ctx context.Context,
+	formID string,
+	userID string,
+	appID int,
+) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	owner, err := touchForm(ctx, tx, formID, userID)
+	if err != nil {
+		//sql.ErrNoRows
+		return err
+	}
+
+	before, err := loadFormSnapshot(ctx, tx, formID)
+	if err != nil {
+		return err
+	}
+	if !ownsPestApp(owner, userID, appID, before.Applications) {
+		return sql.ErrNoRows
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		DELETE FROM pesticide_applications
+		WHERE id = $1 AND form_id = $2
+		RETURNING id
+	`, appID, formID).Scan(&appID)
+	if err != nil {
+		//sql.ErrNoRows
+		return err
+	}
+
+	if _, err := recordRevision(ctx, tx, formID, userID, RevisionActionUpdate, before); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+// listPestAppsByFormIds returns the pesticide applications of every given form in one query,
+// keyed by form ID.
+func (r *FormsRepository) listPestAppsByFormIds(ctx context.Context, formIDs []string) (map[string][]PestApp, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT
+			pa.form_id,`+pestAppColumns+`
+		FROM pesticide_applications pa
+		WHERE pa.form_id = ANY($1::uuid[])
+		ORDER BY pa.form_id, pa.id
+	`, pq.Array(formIDs))
+	if err != nil {
+		return nil, fmt.Errorf("error fetching pesticide applications for forms list: %w", err)
+	}
+	defer rows.Close()
+
+	pestApps := make(map[string][]PestApp, len(formIDs))
+	for rows.Next() {
+		var (
+			formID string
+			row    pestAppRow
+		)
+		err := rows.Scan(append([]any{&formID}, row.dest()...)...)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning pesticide application for forms list: %w", err)
+		}
+		pestApps[formID] = append(pestApps[formID], row.toDomain())
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after pesticide applications query for forms list: %w", err)
+	}
+
+	return pestApps, nil
+}
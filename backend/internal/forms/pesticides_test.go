@@ -0,0 +1,437 @@
+package forms
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/db"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateShrubFormById_ReconcilesApplications(t *testing.T) {
+	ctx := context.Background()
+	testDB := db.TestDB(t)
+	repo := NewFormsRepository(testDB)
+
+	userID := createTestUser(t, testDB)
+	chemID := createTestChemical(t, testDB, "shrub")
+
+	now := time.Now()
+
+	formID, err := repo.CreateShrubForm(ctx, CreateShrubFormInput{
+		CreatedBy:    userID,
+		FirstName:    "Reconcile",
+		LastName:     "Me",
+		StreetNumber: "10",
+		StreetName:   "Sync St",
+		Town:         "Town",
+		ZipCode:      "10001",
+		HomePhone:    "555-0001",
+		OtherPhone:   "555-0002",
+		FleaOnly:     false,
+		Applications: []PestApp{
+			{
+				ChemUsed:      chemID,
+				AppTimestamp:  now.Add(-72 * time.Hour),
+				Rate:          "1 oz/gal",
+				AmountApplied: decimal.NewFromFloat(1.0),
+				LocationCode:  "1B",
+			},
+			{
+				ChemUsed:      chemID,
+				AppTimestamp:  now.Add(-48 * time.Hour),
+				Rate:          "1 oz/gal",
+				AmountApplied: decimal.NewFromFloat(2.0),
+				LocationCode:  "2B",
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	original, err := repo.GetShrubFormById(ctx, formID, userID)
+	require.NoError(t, err)
+	require.Len(t, original.AppTimes, 2)
+
+	kept := original.AppTimes[0]
+	dropped := original.AppTimes[1]
+	if kept.LocationCode != "1B" {
+		kept, dropped = dropped, kept
+	}
+
+	// Fix the rate on the first application, drop the second and add a new one
+	kept.Rate = "2 oz/gal"
+	kept.AmountApplied = decimal.NewFromFloat(4.5)
+
+	updated, err := repo.UpdateShrubFormById(ctx, formID, userID, UpdateShrubFormInput{
+		FirstName:    "Reconcile",
+		LastName:     "Me",
+		StreetNumber: "10",
+		StreetName:   "Sync St",
+		Town:         "Town",
+		ZipCode:      "10001",
+		HomePhone:    "555-0001",
+		OtherPhone:   "555-0002",
+		FleaOnly:     false,
+		Applications: []PestApp{
+			kept,
+			{
+				ChemUsed:      chemID,
+				AppTimestamp:  now,
+				Rate:          "1 oz/gal",
+				AmountApplied: decimal.NewFromFloat(3.0),
+				LocationCode:  "3B",
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, updated.AppTimes, 2)
+
+	byID := map[int]PestApp{}
+	for _, app := range updated.AppTimes {
+		byID[app.ID] = app
+	}
+	require.Contains(t, byID, kept.ID)
+	require.NotContains(t, byID, dropped.ID)
+	require.Equal(t, "2 oz/gal", byID[kept.ID].Rate)
+	require.True(t, decimal.NewFromFloat(4.5).Equal(byID[kept.ID].AmountApplied))
+}
+
+func TestUpdateLawnFormById_NilApplicationsLeavesApplications(t *testing.T) {
+	ctx := context.Background()
+	testDB := db.TestDB(t)
+	repo := NewFormsRepository(testDB)
+
+	userID := createTestUser(t, testDB)
+	chemID := createTestChemical(t, testDB, "lawn")
+
+	formID, err := repo.CreateLawnForm(ctx, CreateLawnFormInput{
+		CreatedBy:    userID,
+		FirstName:    "Keep",
+		LastName:     "Apps",
+		StreetNumber: "20",
+		StreetName:   "Lawn St",
+		Town:         "Town",
+		ZipCode:      "10002",
+		HomePhone:    "555-0003",
+		OtherPhone:   "555-0004",
+		LawnAreaSqFt: 4000,
+		Applications: []PestApp{
+			{
+				ChemUsed:      chemID,
+				AppTimestamp:  time.Now(),
+				Rate:          "2 oz/1000 sq ft",
+				AmountApplied: decimal.NewFromFloat(8.0),
+				LocationCode:  "1A",
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	updated, err := repo.UpdateLawnFormById(ctx, formID, userID, UpdateLawnFormInput{
+		FirstName:    "Keep",
+		LastName:     "Apps",
+		StreetNumber: "20",
+		StreetName:   "Lawn St",
+		Town:         "Town",
+		ZipCode:      "10002",
+		HomePhone:    "555-0003",
+		OtherPhone:   "555-0004",
+		LawnAreaSqFt: 4500,
+	})
+	require.NoError(t, err)
+	require.Len(t, updated.AppTimes, 1)
+
+	// An unknown application ID must not be silently inserted
+	_, err = repo.UpdateLawnFormById(ctx, formID, userID, UpdateLawnFormInput{
+		FirstName:    "Keep",
+		LastName:     "Apps",
+		StreetNumber: "20",
+		StreetName:   "Lawn St",
+		Town:         "Town",
+		ZipCode:      "10002",
+		HomePhone:    "555-0003",
+		OtherPhone:   "555-0004",
+		LawnAreaSqFt: 4500,
+		Applications: []PestApp{
+			{
+				ID:            9999,
+				ChemUsed:      chemID,
+				AppTimestamp:  time.Now(),
+				Rate:          "2 oz/1000 sq ft",
+				AmountApplied: decimal.NewFromFloat(8.0),
+				LocationCode:  "1A",
+			},
+		},
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestPestAppSubResource(t *testing.T) {
+	ctx := context.Background()
+	testDB := db.TestDB(t)
+	repo := NewFormsRepository(testDB)
+
+	userID := createTestUser(t, testDB)
+	otherUserID := createTestUser(t, testDB)
+	chemID := createTestChemical(t, testDB, "lawn")
+
+	formID, err := repo.CreateLawnForm(ctx, CreateLawnFormInput{
+		CreatedBy:    userID,
+		FirstName:    "Single",
+		LastName:     "Row",
+		StreetNumber: "30",
+		StreetName:   "Row St",
+		Town:         "Town",
+		ZipCode:      "10003",
+		HomePhone:    "555-0005",
+		OtherPhone:   "555-0006",
+		LawnAreaSqFt: 2000,
+	})
+	require.NoError(t, err)
+
+	app := PestApp{
+		ChemUsed:      chemID,
+		AppTimestamp:  time.Now(),
+		Rate:          "2 oz/1000 sq ft",
+		AmountApplied: decimal.NewFromFloat(4.0),
+		LocationCode:  "4A",
+	}
+
+	// Other users cannot add to the form
+	_, err = repo.CreatePestApp(ctx, formID, otherUserID, app)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	created, err := repo.CreatePestApp(ctx, formID, userID, app)
+	require.NoError(t, err)
+	require.NotZero(t, created.ID)
+
+	created.Rate = "3 oz/1000 sq ft"
+	updated, err := repo.UpdatePestAppById(ctx, formID, userID, created)
+	require.NoError(t, err)
+	require.Equal(t, "3 oz/1000 sq ft", updated.Rate)
+
+	got, err := repo.GetLawnFormById(ctx, formID, userID)
+	require.NoError(t, err)
+	require.Len(t, got.AppTimes, 1)
+	require.Equal(t, "3 oz/1000 sq ft", got.AppTimes[0].Rate)
+
+	err = repo.DeletePestAppById(ctx, formID, otherUserID, created.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	err = repo.DeletePestAppById(ctx, formID, userID, created.ID)
+	require.NoError(t, err)
+
+	err = repo.DeletePestAppById(ctx, formID, userID, created.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	got, err = repo.GetLawnFormById(ctx, formID, userID)
+	require.NoError(t, err)
+	require.Empty(t, got.AppTimes)
+}
+
+func TestPestAppLocationCodeValidation(t *testing.T) {
+	ctx := context.Background()
+	testDB := db.TestDB(t)
+	repo := NewFormsRepository(testDB)
+
+	userID := createTestUser(t, testDB)
+	chemID := createTestChemical(t, testDB, "lawn")
+
+	badApp := PestApp{
+		ChemUsed:      chemID,
+		AppTimestamp:  time.Now(),
+		Rate:          "2 oz/1000 sq ft",
+		AmountApplied: decimal.NewFromFloat(4.0),
+		LocationCode:  "9Z",
+	}
+	input := CreateLawnFormInput{
+		CreatedBy:    userID,
+		FirstName:    "Site",
+		LastName:     "Code",
+		StreetNumber: "40",
+		StreetName:   "Code St",
+		Town:         "Town",
+		ZipCode:      "10004",
+		HomePhone:    "555-0007",
+		OtherPhone:   "555-0008",
+		LawnAreaSqFt: 2000,
+		Applications: []PestApp{badApp},
+	}
+
+	requireInvalidField := func(err error, field string) {
+		t.Helper()
+		require.ErrorIs(t, err, ErrInvalidInput)
+		var valErr *ValidationError
+		require.True(t, errors.As(err, &valErr))
+		require.Len(t, valErr.Fields, 1)
+		require.Equal(t, field, valErr.Fields[0].Field)
+	}
+
+	// Unknown codes are rejected when creating a form
+	_, err := repo.CreateLawnForm(ctx, input)
+	requireInvalidField(err, "applications[0].location_code")
+
+	// Codes are matched exactly
+	input.Applications[0].LocationCode = "1a"
+	_, err = repo.CreateLawnForm(ctx, input)
+	requireInvalidField(err, "applications[0].location_code")
+
+	input.Applications[0].LocationCode = "1A"
+	formID, err := repo.CreateLawnForm(ctx, input)
+	require.NoError(t, err)
+
+	// ...when updating it
+	_, err = repo.UpdateLawnFormById(ctx, formID, userID, UpdateLawnFormInput{
+		FirstName:    input.FirstName,
+		LastName:     input.LastName,
+		StreetNumber: input.StreetNumber,
+		StreetName:   input.StreetName,
+		Town:         input.Town,
+		ZipCode:      input.ZipCode,
+		HomePhone:    input.HomePhone,
+		OtherPhone:   input.OtherPhone,
+		LawnAreaSqFt: input.LawnAreaSqFt,
+		Applications: []PestApp{input.Applications[0], badApp},
+	})
+	requireInvalidField(err, "applications[1].location_code")
+
+	// ...and when adding or changing a single application
+	_, err = repo.CreatePestApp(ctx, formID, userID, badApp)
+	requireInvalidField(err, "location_code")
+
+	got, err := repo.GetLawnFormById(ctx, formID, userID)
+	require.NoError(t, err)
+	require.Len(t, got.AppTimes, 1)
+
+	app := got.AppTimes[0]
+	app.LocationCode = "6A"
+	_, err = repo.UpdatePestAppById(ctx, formID, userID, app)
+	requireInvalidField(err, "location_code")
+
+	// Applications recorded with a legacy code can still be edited while they keep it
+	_, err = testDB.Exec(`UPDATE pesticide_applications SET location_code = 'FL' WHERE id = $1`, app.ID)
+	require.NoError(t, err)
+	app.LocationCode = "FL"
+	app.Rate = "3 oz/1000 sq ft"
+	app, err = repo.UpdatePestAppById(ctx, formID, userID, app)
+	require.NoError(t, err)
+	require.Equal(t, "FL", app.LocationCode)
+
+	update := UpdateLawnFormInput{
+		FirstName:    input.FirstName,
+		LastName:     input.LastName,
+		StreetNumber: input.StreetNumber,
+		StreetName:   input.StreetName,
+		Town:         input.Town,
+		ZipCode:      input.ZipCode,
+		HomePhone:    input.HomePhone,
+		OtherPhone:   input.OtherPhone,
+		LawnAreaSqFt: input.LawnAreaSqFt,
+		Applications: []PestApp{app},
+	}
+	_, err = repo.UpdateLawnFormById(ctx, formID, userID, update)
+	require.NoError(t, err)
+
+	// ...but not given another unknown one
+	app.LocationCode = "FA"
+	update.Applications = []PestApp{app}
+	_, err = repo.UpdateLawnFormById(ctx, formID, userID, update)
+	requireInvalidField(err, "applications[0].location_code")
+}
+
+func TestPestAppRetiredChemical(t *testing.T) {
+	ctx := context.Background()
+	testDB := db.TestDB(t)
+	repo := NewFormsRepository(testDB)
+
+	userID := createTestUser(t, testDB)
+	chemID := createTestChemical(t, testDB, "lawn")
+	retiredID := createTestChemical(t, testDB, "lawn")
+
+	app := PestApp{
+		ChemUsed:      retiredID,
+		AppTimestamp:  time.Now(),
+		Rate:          "2 oz/1000 sq ft",
+		AmountApplied: decimal.NewFromFloat(4.0),
+		LocationCode:  "1A",
+	}
+	formID := createTestLawnForm(t, repo, CreateLawnFormInput{
+		CreatedBy:    userID,
+		Applications: []PestApp{app},
+	})
+
+	_, err := testDB.Exec(`UPDATE chemicals SET deleted_at = NOW() WHERE id = $1`, retiredID)
+	require.NoError(t, err)
+
+	requireRetired := func(err error, field string) {
+		t.Helper()
+		require.ErrorIs(t, err, ErrInvalidInput)
+		var valErr *ValidationError
+		require.True(t, errors.As(err, &valErr))
+		require.Len(t, valErr.Fields, 1)
+		require.Equal(t, field, valErr.Fields[0].Field)
+	}
+
+	// Retired chemicals cannot be applied on a new form...
+	_, err = repo.CreateLawnForm(ctx, CreateLawnFormInput{
+		CreatedBy:    userID,
+		FirstName:    "Retired",
+		LastName:     "Chemical",
+		StreetNumber: "50",
+		StreetName:   "Retired St",
+		Town:         "Town",
+		ZipCode:      "10005",
+		HomePhone:    "555-0009",
+		OtherPhone:   "555-0010",
+		LawnAreaSqFt: 2000,
+		Applications: []PestApp{app},
+		Force:        true,
+	})
+	requireRetired(err, "applications[0].chem_used")
+
+	// ...or added to an existing one
+	_, err = repo.CreatePestApp(ctx, formID, userID, app)
+	requireRetired(err, "chem_used")
+
+	got, err := repo.GetLawnFormById(ctx, formID, userID)
+	require.NoError(t, err)
+	require.Len(t, got.AppTimes, 1)
+	stored := got.AppTimes[0]
+
+	// Applications recorded before the chemical was retired can still be edited
+	stored.Rate = "3 oz/1000 sq ft"
+	stored, err = repo.UpdatePestAppById(ctx, formID, userID, stored)
+	require.NoError(t, err)
+
+	update := UpdateLawnFormInput{
+		FirstName:    "Test",
+		LastName:     "Customer",
+		StreetNumber: "1",
+		StreetName:   "Test St",
+		Town:         "Town",
+		ZipCode:      "10001",
+		HomePhone:    "555-0001",
+		OtherPhone:   "555-0000",
+		LawnAreaSqFt: 1000,
+		Applications: []PestApp{stored},
+	}
+	_, err = repo.UpdateLawnFormById(ctx, formID, userID, update)
+	require.NoError(t, err)
+
+	// But not switched onto a retired chemical
+	stored.ChemUsed = chemID
+	stored, err = repo.UpdatePestAppById(ctx, formID, userID, stored)
+	require.NoError(t, err)
+
+	stored.ChemUsed = retiredID
+	_, err = repo.UpdatePestAppById(ctx, formID, userID, stored)
+	requireRetired(err, "chem_used")
+
+	update.Applications = []PestApp{stored}
+	_, err = repo.UpdateLawnFormById(ctx, formID, userID, update)
+	requireRetired(err, "applications[0].chem_used")
+}
@@ -0,0 +1,103 @@
+package forms
+
+import (
+	"context"
+	"fmt"
+)
+
+// PrintoutApplication is a pesticide application with the chemical details printed beside it
+type PrintoutApplication struct {
+	PestApp
+	BrandName    string
+	ChemicalName string
+	EpaRegNo     string
+	Unit         string
+}
+
+// Printout holds everything printed on a form's card
+type Printout struct {
+	View *FormView
+	// CreatedByName is the full name of the employee who created the form
+	CreatedByName string
+	// Applications are ordered oldest first
+	Applications []PrintoutApplication
+}
+
+// Form returns the shared fields of the printed form
+func (p *Printout) Form() Form {
+	if p.View.Shrub != nil {
+		return p.View.Shrub.Form
+	}
+	return p.View.Lawn.Form
+}
+
+// GetFormPrintoutById loads a form for printing.
+// userID restricts the lookup to the forms that user created or made an application on,
+// or is empty to allow any form.
+// It returns sql.ErrNoRows if the form does not exist or is not visible to the user.
+func (r *FormsRepository) GetFormPrintoutById(
+	ctx context.Context,
+	formID string,
+	userID string,
+) (*Printout, error) {
+	var (
+		createdBy string
+		printout  Printout
+	)
+	err := r.db.QueryRowContext(ctx, `
+		SELECT f.created_by, u.first_name || ' ' || u.last_name
+		FROM forms f
+		JOIN users u ON u.id = f.created_by
+		WHERE f.id = $1
+		  AND ($2 = '' OR `+visibleToCondition("NULLIF($2, '')::uuid")+`)
+	`, formID, userID).Scan(&createdBy, &printout.CreatedByName)
+	if err != nil {
+		// Important: let sql.ErrNoRows propagate
+		return nil, err
+	}
+
+	printout.View, err = r.GetFormViewById(ctx, formID, createdBy)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT`+pestAppColumns+`,
+			c.brand_name,
+			c.chemical_name,
+			c.epa_reg_no,
+			c.unit
+		FROM pesticide_applications pa
+		JOIN chemicals c ON c.id = pa.chem_used
+		WHERE pa.form_id = $1
+		ORDER BY pa.app_timestamp, pa.id
+	`, formID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching pesticide applications for form: %s. %w", formID, err)
+	}
+	defer rows.Close()
+
+	printout.Applications = []PrintoutApplication{}
+	for rows.Next() {
+		var (
+			row pestAppRow
+			app PrintoutApplication
+		)
+		err := rows.Scan(append(row.dest(),
+			&app.BrandName,
+			&app.ChemicalName,
+			&app.EpaRegNo,
+			&app.Unit,
+		)...)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning pesticide application for form: %s. %w", formID, err)
+		}
+		app.PestApp = row.toDomain()
+		printout.Applications = append(printout.Applications, app)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after pesticide applications query: %w", err)
+	}
+
+	return &printout, nil
+}
@@ -0,0 +1,71 @@
+package forms
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/db"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetFormPrintoutById(t *testing.T) {
+	ctx := context.Background()
+	testDB := db.TestDB(t)
+	repo := NewFormsRepository(testDB)
+
+	userID := createTestUser(t, testDB)
+	otherUserID := createTestUser(t, testDB)
+	chemID := createTestChemical(t, testDB, "lawn")
+
+	appTime := time.Now().Add(-time.Hour)
+	formID, err := repo.CreateLawnForm(ctx, CreateLawnFormInput{
+		CreatedBy:    userID,
+		FirstName:    "Paula",
+		LastName:     "Printout",
+		StreetNumber: "40",
+		StreetName:   "Paper St",
+		Town:         "Town",
+		ZipCode:      "10001",
+		HomePhone:    "555-4000",
+		LawnAreaSqFt: 2500,
+		Applications: []PestApp{
+			{
+				ChemUsed:      chemID,
+				AppTimestamp:  appTime,
+				Rate:          "2 oz/1000 sq ft",
+				AmountApplied: decimal.NewFromFloat(5),
+				LocationCode:  "1A",
+			},
+			{
+				ChemUsed:      chemID,
+				AppTimestamp:  appTime.Add(-24 * time.Hour),
+				Rate:          "2 oz/1000 sq ft",
+				AmountApplied: decimal.NewFromFloat(4),
+				LocationCode:  "3A",
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	printout, err := repo.GetFormPrintoutById(ctx, formID, userID)
+	require.NoError(t, err)
+	require.Equal(t, "Test User", printout.CreatedByName)
+	require.Equal(t, "Paula", printout.Form().FirstName)
+	require.Equal(t, 2500, printout.View.Lawn.LawnDetails.LawnAreaSqFt)
+	require.Len(t, printout.Applications, 2)
+	require.Equal(t, "3A", printout.Applications[0].LocationCode)
+	require.Equal(t, "Test Brand", printout.Applications[0].BrandName)
+	require.Equal(t, "12345-67", printout.Applications[0].EpaRegNo)
+	require.Equal(t, "oz", printout.Applications[0].Unit)
+
+	// Other users cannot print the form, an empty user ID prints any form
+	_, err = repo.GetFormPrintoutById(ctx, formID, otherUserID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	printout, err = repo.GetFormPrintoutById(ctx, formID, "")
+	require.NoError(t, err)
+	require.Equal(t, formID, printout.Form().ID)
+}
@@ -0,0 +1,149 @@
+package forms
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// ErrPropertyNotFound is returned when a new form references a property that does not exist
+var ErrPropertyNotFound = errors.New("property not found")
+
+// propertyClient holds the customer and address on file for a property
+type propertyClient struct {
+	FirstName    string
+	LastName     string
+	StreetNumber string
+	StreetName   string
+	Town         string
+	ZipCode      string
+	HomePhone    string
+	OtherPhone   string
+}
+
+// loadPropertyClient returns the customer and address of a property.
+// It returns ErrPropertyNotFound if the property does not exist.
+func loadPropertyClient(ctx context.Context, tx *sql.Tx, propertyID string) (propertyClient, error) {
+	var client propertyClient
+	err := tx.QueryRowContext(ctx, `
+		SELECT
+			c.first_name,
+			c.last_name,
+			p.street_number,
+			p.street_name,
+			p.town,
+			p.zip_code,
+			c.home_phone,
+			c.other_phone
+		FROM properties p
+		JOIN customers c ON c.id = p.customer_id
+		WHERE p.id = $1
+	`, propertyID).Scan(
+		&client.FirstName,
+		&client.LastName,
+		&client.StreetNumber,
+		&client.StreetName,
+		&client.Town,
+		&client.ZipCode,
+		&client.HomePhone,
+		&client.OtherPhone,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return propertyClient{}, ErrPropertyNotFound
+	}
+	if err != nil {
+		return propertyClient{}, fmt.Errorf("error loading property %s: %w", propertyID, err)
+	}
+
+	return client, nil
+}
+
+// copyTo overwrites a form input's client fields with the ones on file
+func (c propertyClient) copyTo(
+	firstName *string,
+	lastName *string,
+	streetNumber *string,
+	streetName *string,
+	town *string,
+	zipCode *string,
+	homePhone *string,
+	otherPhone *string,
+) {
+	*firstName = c.FirstName
+	*lastName = c.LastName
+	*streetNumber = c.StreetNumber
+	*streetName = c.StreetName
+	*town = c.Town
+	*zipCode = c.ZipCode
+	*homePhone = c.HomePhone
+	*otherPhone = c.OtherPhone
+}
+
+// SyncPropertyForms copies the customer and address on file for each property onto the live forms
+// written there, inside tx, and records an update revision by userID for every form it changes.
+// Forms in the trash keep the values they had when deleted.
+// Returns the IDs of the forms changed.
+func SyncPropertyForms(ctx context.Context, tx *sql.Tx, userID string, propertyIDs []string) ([]string, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT f.id
+		FROM forms f
+		JOIN properties p ON p.id = f.property_id
+		JOIN customers c ON c.id = p.customer_id
+		WHERE f.property_id = ANY($1::uuid[])
+		  AND f.deleted_at IS NULL
+		  AND (f.first_name, f.last_name, f.street_number, f.street_name, f.town, f.zip_code, f.home_phone, f.other_phone)
+		      IS DISTINCT FROM (c.first_name, c.last_name, p.street_number, p.street_name, p.town, p.zip_code, c.home_phone, c.other_phone)
+		ORDER BY f.id
+		FOR UPDATE OF f
+	`, pq.Array(propertyIDs))
+	if err != nil {
+		return nil, fmt.Errorf("error finding forms to sync with properties: %w", err)
+	}
+	formIDs := []string{}
+	for rows.Next() {
+		var formID string
+		if err := rows.Scan(&formID); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("error scanning form to sync with properties: %w", err)
+		}
+		formIDs = append(formIDs, formID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after forms to sync query: %w", err)
+	}
+
+	for _, formID := range formIDs {
+		before, err := loadFormSnapshot(ctx, tx, formID)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			UPDATE forms f
+			SET first_name = c.first_name,
+				last_name = c.last_name,
+				street_number = p.street_number,
+				street_name = p.street_name,
+				town = p.town,
+				zip_code = p.zip_code,
+				home_phone = c.home_phone,
+				other_phone = c.other_phone
+			FROM properties p
+			JOIN customers c ON c.id = p.customer_id
+			WHERE f.id = $1 AND p.id = f.property_id
+		`, formID)
+		if err != nil {
+			return nil, fmt.Errorf("error syncing form %s with its property: %w", formID, err)
+		}
+
+		if _, err := recordRevision(ctx, tx, formID, userID, RevisionActionUpdate, before); err != nil {
+			return nil, err
+		}
+	}
+
+	return formIDs, nil
+}
@@ -0,0 +1,644 @@
+package forms
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// formSnapshot is the JSON document stored with each revision.
+// It captures the client, subtype and application fields of a form at a point in time.
+type formSnapshot struct {
+	FormType     string `json:"form_type"`
+	FirstName    string `json:"first_name"`
+	LastName     string `json:"last_name"`
+	StreetNumber string `json:"street_number"`
+	StreetName   string `json:"street_name"`
+	Town         string `json:"town"`
+	ZipCode      string `json:"zip_code"`
+	HomePhone    string `json:"home_phone"`
+	OtherPhone   string `json:"other_phone"`
+	CallBefore   bool   `json:"call_before"`
+	IsHoliday    bool   `json:"is_holiday"`
+
+	FleaOnly     *bool `json:"flea_only,omitempty"`
+	LawnAreaSqFt *int  `json:"lawn_area_sq_ft,omitempty"`
+	FertOnly     *bool `json:"fert_only,omitempty"`
+
+	Applications []appSnapshot `json:"applications"`
+}
+
+type appSnapshot struct {
+	ID            int             `json:"id"`
+	ChemUsed      int             `json:"chem_used"`
+	AppTimestamp  time.Time       `json:"app_timestamp"`
+	Rate          string          `json:"rate"`
+	AmountApplied decimal.Decimal `json:"amount_applied"`
+	LocationCode  string          `json:"location_code"`
+	// The structured rate is left out of applications without one
+	RateAmount *decimal.Decimal `json:"rate_amount,omitempty"`
+	RateUnit   string           `json:"rate_unit,omitempty"`
+	RateBasis  string           `json:"rate_basis,omitempty"`
+	// As are the method and weather when not recorded
+	ApplicationMethod string           `json:"application_method,omitempty"`
+	WindSpeedMph      *decimal.Decimal `json:"wind_speed_mph,omitempty"`
+	WindDirection     string           `json:"wind_direction,omitempty"`
+	TemperatureF      *decimal.Decimal `json:"temperature_f,omitempty"`
+	SkyConditions     string           `json:"sky_conditions,omitempty"`
+	// AppliedBy is missing from snapshots taken before applicators were recorded
+	AppliedBy string `json:"applied_by,omitempty"`
+}
+
+func (a appSnapshot) toPestApp() PestApp {
+	app := PestApp{
+		ID:                a.ID,
+		ChemUsed:          a.ChemUsed,
+		AppTimestamp:      a.AppTimestamp,
+		Rate:              a.Rate,
+		AmountApplied:     a.AmountApplied,
+		LocationCode:      a.LocationCode,
+		ApplicationMethod: a.ApplicationMethod,
+		AppliedBy:         a.AppliedBy,
+		Weather: Weather{
+			WindSpeedMph:  a.WindSpeedMph,
+			WindDirection: a.WindDirection,
+			TemperatureF:  a.TemperatureF,
+			SkyConditions: a.SkyConditions,
+		},
+	}
+	if a.RateAmount != nil {
+		app.StructuredRate = &StructuredRate{
+			Amount: *a.RateAmount,
+			Unit:   a.RateUnit,
+			Basis:  a.RateBasis,
+		}
+	}
+	return app
+}
+
+// loadFormSnapshot reads the current state of a form inside tx, locking its row.
+// It returns sql.ErrNoRows if the form does not exist.
+func loadFormSnapshot(ctx context.Context, tx *sql.Tx, formID string) (*formSnapshot, error) {
+	var (
+		snap  formSnapshot
+		shrub shrubRow
+		lawn  lawnRow
+	)
+
+	err := tx.QueryRowContext(ctx, `
+		SELECT
+			f.form_type,
+			f.first_name,
+			f.last_name,
+			f.street_number,
+			f.street_name,
+			f.town,
+			f.zip_code,
+			f.home_phone,
+			f.other_phone,
+			f.call_before,
+			f.is_holiday,
+			sf.flea_only,
+			lf.lawn_area_sq_ft,
+			lf.fert_only
+		FROM forms f
+		LEFT JOIN shrub_forms sf ON f.id = sf.form_id
+		LEFT JOIN lawn_forms lf ON f.id = lf.form_id
+		WHERE f.id = $1
+		FOR UPDATE OF f
+	`, formID).Scan(
+		&snap.FormType,
+		&snap.FirstName,
+		&snap.LastName,
+		&snap.StreetNumber,
+		&snap.StreetName,
+		&snap.Town,
+		&snap.ZipCode,
+		&snap.HomePhone,
+		&snap.OtherPhone,
+		&snap.CallBefore,
+		&snap.IsHoliday,
+		&shrub.FleaOnly,
+		&lawn.LawnAreaSqFt,
+		&lawn.FertOnly,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if shrub.FleaOnly.Valid {
+		snap.FleaOnly = &shrub.FleaOnly.Bool
+	}
+	if lawn.LawnAreaSqFt.Valid {
+		area := int(lawn.LawnAreaSqFt.Int32)
+		snap.LawnAreaSqFt = &area
+	}
+	if lawn.FertOnly.Valid {
+		snap.FertOnly = &lawn.FertOnly.Bool
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			pa.id,
+			pa.chem_used,
+			pa.app_timestamp,
+			pa.rate,
+			pa.amount_applied,
+			pa.location_code,
+			pa.rate_amount,
+			COALESCE(pa.rate_unit, ''),
+			COALESCE(pa.rate_basis, ''),
+			COALESCE(pa.application_method, ''),
+			pa.wind_speed_mph,
+			COALESCE(pa.wind_direction, ''),
+			pa.temperature_f,
+			COALESCE(pa.sky_conditions, ''),
+			COALESCE(pa.applied_by::text, '')
+		FROM pesticide_applications pa
+		WHERE pa.form_id = $1
+		ORDER BY pa.id
+	`, formID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching pesticide applications for form: %s. %w", formID, err)
+	}
+	defer rows.Close()
+
+	snap.Applications = []appSnapshot{}
+	for rows.Next() {
+		var (
+			app          appSnapshot
+			rateAmount   decimal.NullDecimal
+			windSpeedMph decimal.NullDecimal
+			temperatureF decimal.NullDecimal
+		)
+		err := rows.Scan(
+			&app.ID,
+			&app.ChemUsed,
+			&app.AppTimestamp,
+			&app.Rate,
+			&app.AmountApplied,
+			&app.LocationCode,
+			&rateAmount,
+			&app.RateUnit,
+			&app.RateBasis,
+			&app.ApplicationMethod,
+			&windSpeedMph,
+			&app.WindDirection,
+			&temperatureF,
+			&app.SkyConditions,
+			&app.AppliedBy,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning pesticide application for form: %s. %w", formID, err)
+		}
+		if rateAmount.Valid {
+			app.RateAmount = &rateAmount.Decimal
+		}
+		if windSpeedMph.Valid {
+			app.WindSpeedMph = &windSpeedMph.Decimal
+		}
+		if temperatureF.Valid {
+			app.TemperatureF = &temperatureF.Decimal
+		}
+		app.AppTimestamp = app.AppTimestamp.UTC()
+		snap.Applications = append(snap.Applications, app)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after pesticide applications query for form: %s. %w", formID, err)
+	}
+
+	return &snap, nil
+}
+
+// toFieldMap flattens a JSON-tagged struct into a map keyed by its JSON field names.
+func toFieldMap(v any) (map[string]any, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]any{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// diffFieldMaps returns the fields whose values differ between before and after.
+func diffFieldMaps(before, after map[string]any) map[string]FieldChange {
+	changes := map[string]FieldChange{}
+	for key, newValue := range after {
+		if oldValue, ok := before[key]; !ok || !reflect.DeepEqual(oldValue, newValue) {
+			changes[key] = FieldChange{Old: before[key], New: newValue}
+		}
+	}
+	for key, oldValue := range before {
+		if _, ok := after[key]; !ok {
+			changes[key] = FieldChange{Old: oldValue, New: nil}
+		}
+	}
+	return changes
+}
+
+// diffSnapshots builds a field-level diff between two snapshots.
+// A nil before describes a newly created form and a nil after a deleted one.
+func diffSnapshots(before, after *formSnapshot) (RevisionDiff, error) {
+	diff := RevisionDiff{
+		Fields: map[string]FieldChange{},
+	}
+
+	clientFields := func(snap *formSnapshot) (map[string]any, map[int]map[string]any, error) {
+		if snap == nil {
+			return map[string]any{}, map[int]map[string]any{}, nil
+		}
+		fields, err := toFieldMap(snap)
+		if err != nil {
+			return nil, nil, err
+		}
+		delete(fields, "applications")
+
+		apps := map[int]map[string]any{}
+		for _, app := range snap.Applications {
+			appFields, err := toFieldMap(app)
+			if err != nil {
+				return nil, nil, err
+			}
+			apps[app.ID] = appFields
+		}
+		return fields, apps, nil
+	}
+
+	beforeFields, beforeApps, err := clientFields(before)
+	if err != nil {
+		return RevisionDiff{}, err
+	}
+	afterFields, afterApps, err := clientFields(after)
+	if err != nil {
+		return RevisionDiff{}, err
+	}
+
+	diff.Fields = diffFieldMaps(beforeFields, afterFields)
+
+	for id, appFields := range afterApps {
+		oldFields, ok := beforeApps[id]
+		if !ok {
+			diff.ApplicationsAdded = append(diff.ApplicationsAdded, appFields)
+			continue
+		}
+		if changes := diffFieldMaps(oldFields, appFields); len(changes) > 0 {
+			diff.ApplicationsChanged = append(diff.ApplicationsChanged, ApplicationChange{
+				ID:     id,
+				Fields: changes,
+			})
+		}
+	}
+	for id, appFields := range beforeApps {
+		if _, ok := afterApps[id]; !ok {
+			diff.ApplicationsRemoved = append(diff.ApplicationsRemoved, appFields)
+		}
+	}
+
+	// Keep the stored JSON stable regardless of map iteration order
+	byID := func(apps []map[string]any) {
+		sort.Slice(apps, func(i, j int) bool {
+			return apps[i]["id"].(float64) < apps[j]["id"].(float64)
+		})
+	}
+	byID(diff.ApplicationsAdded)
+	byID(diff.ApplicationsRemoved)
+	sort.Slice(diff.ApplicationsChanged, func(i, j int) bool {
+		return diff.ApplicationsChanged[i].ID < diff.ApplicationsChanged[j].ID
+	})
+
+	return diff, nil
+}
+
+// recordRevision stores a revision for a form inside tx.
+// The form's current state is snapshotted and diffed against before; pass a nil
+// before when the form was just created. Purges should use recordDeletion.
+func recordRevision(
+	ctx context.Context,
+	tx *sql.Tx,
+	formID string,
+	userID string,
+	action string,
+	before *formSnapshot,
+) (int64, error) {
+	after, err := loadFormSnapshot(ctx, tx, formID)
+	if err != nil {
+		return 0, fmt.Errorf("error snapshotting form %s for revision: %w", formID, err)
+	}
+	return insertRevision(ctx, tx, formID, userID, action, before, after, after)
+}
+
+// recordDeletion stores the revision for a form that is being permanently removed,
+// either purged from the trash or merged into another form.
+// before must be the form's final state, which is kept as the revision snapshot.
+func recordDeletion(
+	ctx context.Context,
+	tx *sql.Tx,
+	formID string,
+	userID string,
+	action string,
+	before *formSnapshot,
+) (int64, error) {
+	return insertRevision(ctx, tx, formID, userID, action, before, nil, before)
+}
+
+func insertRevision(
+	ctx context.Context,
+	tx *sql.Tx,
+	formID string,
+	userID string,
+	action string,
+	before *formSnapshot,
+	after *formSnapshot,
+	snapshot *formSnapshot,
+) (int64, error) {
+	diff, err := diffSnapshots(before, after)
+	if err != nil {
+		return 0, fmt.Errorf("error diffing form %s for revision: %w", formID, err)
+	}
+
+	diffJSON, err := json.Marshal(diff)
+	if err != nil {
+		return 0, fmt.Errorf("error encoding diff for form %s: %w", formID, err)
+	}
+	snapshotJSON, err := json.Marshal(snapshot)
+	if err != nil {
+		return 0, fmt.Errorf("error encoding snapshot for form %s: %w", formID, err)
+	}
+
+	var revisionID int64
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO form_revisions (
+			form_id,
+			changed_by,
+			action,
+			diff,
+			snapshot
+		)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`,
+		formID,
+		userID,
+		action,
+		diffJSON,
+		snapshotJSON,
+	).Scan(&revisionID)
+	if err != nil {
+		return 0, fmt.Errorf("error inserting revision for form %s: %w", formID, err)
+	}
+
+	return revisionID, nil
+}
+
+// listRevisions returns every revision of a form, oldest first.
+func (r *FormsRepository) listRevisions(ctx context.Context, formID string) ([]FormRevision, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT
+			fr.id,
+			fr.form_id,
+			COALESCE(fr.changed_by::text, ''),
+			COALESCE(u.first_name || ' ' || u.last_name, ''),
+			fr.changed_at,
+			fr.action,
+			fr.diff
+		FROM form_revisions fr
+		LEFT JOIN users u ON u.id = fr.changed_by
+		WHERE fr.form_id = $1
+		ORDER BY fr.changed_at, fr.id
+	`, formID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching revisions for form: %s. %w", formID, err)
+	}
+	defer rows.Close()
+
+	var revisions []FormRevision
+	for rows.Next() {
+		var (
+			revision FormRevision
+			diffJSON []byte
+		)
+		err := rows.Scan(
+			&revision.ID,
+			&revision.FormID,
+			&revision.ChangedBy,
+			&revision.ChangedByName,
+			&revision.ChangedAt,
+			&revision.Action,
+			&diffJSON,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning revision for form: %s. %w", formID, err)
+		}
+		if err := json.Unmarshal(diffJSON, &revision.Diff); err != nil {
+			return nil, fmt.Errorf("error decoding revision %d diff: %w", revision.ID, err)
+		}
+		revisions = append(revisions, revision)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error after revisions query for form: %s. %w", formID, err)
+	}
+
+	return revisions, nil
+}
+
+// ListFormRevisions returns the revision history of a form the given user created or made
+// an application on, oldest first.
+// It returns sql.ErrNoRows if the form does not exist or is not visible to the user.
+func (r *FormsRepository) ListFormRevisions(
+	ctx context.Context,
+	formID string,
+	userID string,
+) ([]FormRevision, error) {
+	err := r.db.QueryRowContext(ctx, `
+		SELECT f.id
+		FROM forms f
+		WHERE f.id = $1 AND `+visibleToCondition("$2::uuid"), formID, userID).Scan(&formID)
+	if err != nil {
+		//sql.ErrNoRows
+		return nil, err
+	}
+
+	return r.listRevisions(ctx, formID)
+}
+
+// ListAllFormRevisions returns the revision history of any form (admin only), oldest first.
+// Revisions outlive their form, so history is returned even after deletion.
+// It returns sql.ErrNoRows if the form has no recorded revisions.
+func (r *FormsRepository) ListAllFormRevisions(
+	ctx context.Context,
+	formID string,
+) ([]FormRevision, error) {
+	revisions, err := r.listRevisions(ctx, formID)
+	if err != nil {
+		return nil, err
+	}
+	if len(revisions) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return revisions, nil
+}
+
+// applySnapshotFields overwrites a form's client and subtype fields with the ones in snap inside tx.
+// Applications are left alone.
+func applySnapshotFields(ctx context.Context, tx *sql.Tx, formID string, snap *formSnapshot) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE forms
+		SET first_name = $1,
+			last_name = $2,
+			street_number = $3,
+			street_name = $4,
+			town = $5,
+			zip_code = $6,
+			home_phone = $7,
+			other_phone = $8,
+			call_before = $9,
+			is_holiday = $10
+		WHERE id = $11
+	`,
+		snap.FirstName,
+		snap.LastName,
+		snap.StreetNumber,
+		snap.StreetName,
+		snap.Town,
+		snap.ZipCode,
+		snap.HomePhone,
+		snap.OtherPhone,
+		snap.CallBefore,
+		snap.IsHoliday,
+		formID,
+	)
+	if err != nil {
+		return fmt.Errorf("error updating fields of form %s: %w", formID, err)
+	}
+
+	switch snap.FormType {
+	case "shrub":
+		if snap.FleaOnly != nil {
+			_, err = tx.ExecContext(ctx, `
+				UPDATE shrub_forms
+				SET flea_only = $1
+				WHERE form_id = $2
+			`, *snap.FleaOnly, formID)
+		}
+	case "lawn":
+		if snap.LawnAreaSqFt != nil && snap.FertOnly != nil {
+			_, err = tx.ExecContext(ctx, `
+				UPDATE lawn_forms
+				SET lawn_area_sq_ft = $1,
+					fert_only = $2
+				WHERE form_id = $3
+			`, *snap.LawnAreaSqFt, *snap.FertOnly, formID)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("error updating %s details for form %s: %w", snap.FormType, formID, err)
+	}
+
+	return nil
+}
+
+// RollbackFormToRevision restores a form's client, subtype and application fields
+// to the state captured by one of its revisions (admin only).
+// Applications deleted since that revision are re-created with new IDs.
+// The rollback itself is recorded as a new revision, which is returned.
+// It returns sql.ErrNoRows if the form or the revision does not exist, or if the form is in the trash.
+func (r *FormsRepository) RollbackFormToRevision(
+	ctx context.Context,
+	formID string,
+	revisionID int64,
+	adminID string,
+) (FormRevision, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormRevision{}, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Trashed forms must be restored before they are rolled back
+	err = tx.QueryRowContext(ctx, `
+		SELECT id
+		FROM forms
+		WHERE id = $1 AND deleted_at IS NULL
+		FOR UPDATE
+	`, formID).Scan(&formID)
+	if err != nil {
+		//sql.ErrNoRows
+		return FormRevision{}, err
+	}
+
+	var snapshotJSON []byte
+	err = tx.QueryRowContext(ctx, `
+		SELECT snapshot
+		FROM form_revisions
+		WHERE id = $1 AND form_id = $2
+	`, revisionID, formID).Scan(&snapshotJSON)
+	if err != nil {
+		//sql.ErrNoRows
+		return FormRevision{}, err
+	}
+
+	var target formSnapshot
+	if err := json.Unmarshal(snapshotJSON, &target); err != nil {
+		return FormRevision{}, fmt.Errorf("error decoding revision %d snapshot: %w", revisionID, err)
+	}
+
+	before, err := loadFormSnapshot(ctx, tx, formID)
+	if err != nil {
+		//sql.ErrNoRows
+		return FormRevision{}, err
+	}
+	if before.FormType != target.FormType {
+		return FormRevision{}, errors.New("revision form_type does not match form")
+	}
+
+	if err := applySnapshotFields(ctx, tx, formID, &target); err != nil {
+		return FormRevision{}, err
+	}
+
+	existing := map[int]bool{}
+	for _, app := range before.Applications {
+		existing[app.ID] = true
+	}
+	apps := make([]PestApp, 0, len(target.Applications))
+	for _, app := range target.Applications {
+		pestApp := app.toPestApp()
+		if !existing[pestApp.ID] {
+			pestApp.ID = 0
+		}
+		apps = append(apps, pestApp)
+	}
+	if err := syncPestApps(ctx, tx, formID, apps); err != nil {
+		return FormRevision{}, err
+	}
+
+	rollbackID, err := recordRevision(ctx, tx, formID, adminID, RevisionActionRollback, before)
+	if err != nil {
+		return FormRevision{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return FormRevision{}, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	revisions, err := r.listRevisions(ctx, formID)
+	if err != nil {
+		return FormRevision{}, err
+	}
+	for _, revision := range revisions {
+		if revision.ID == rollbackID {
+			return revision, nil
+		}
+	}
+	return FormRevision{}, fmt.Errorf("rollback revision %d for form %s not found", rollbackID, formID)
+}
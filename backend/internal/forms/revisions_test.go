@@ -0,0 +1,189 @@
+package forms
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/db"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormRevisions_CreateAndUpdate(t *testing.T) {
+	ctx := context.Background()
+	testDB := db.TestDB(t)
+	repo := NewFormsRepository(testDB)
+
+	userID := createTestUser(t, testDB)
+	chemID := createTestChemical(t, testDB, "shrub")
+
+	formID, err := repo.CreateShrubForm(ctx, CreateShrubFormInput{
+		CreatedBy:    userID,
+		FirstName:    "History",
+		LastName:     "Buff",
+		StreetNumber: "1",
+		StreetName:   "Audit Rd",
+		Town:         "Town",
+		ZipCode:      "10001",
+		HomePhone:    "555-0001",
+		OtherPhone:   "555-0002",
+		Applications: []PestApp{
+			{
+				ChemUsed:      chemID,
+				AppTimestamp:  time.Now(),
+				Rate:          "1 oz/gal",
+				AmountApplied: decimal.NewFromFloat(1.0),
+				LocationCode:  "1B",
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	original, err := repo.GetShrubFormById(ctx, formID, userID)
+	require.NoError(t, err)
+	app := original.AppTimes[0]
+	app.Rate = "2 oz/gal"
+
+	_, err = repo.UpdateShrubFormById(ctx, formID, userID, UpdateShrubFormInput{
+		FirstName:    "History",
+		LastName:     "Buff",
+		StreetNumber: "1",
+		StreetName:   "Audit Rd",
+		Town:         "Town",
+		ZipCode:      "10001",
+		HomePhone:    "555-9999",
+		OtherPhone:   "555-0002",
+		Applications: []PestApp{app},
+	})
+	require.NoError(t, err)
+
+	revisions, err := repo.ListFormRevisions(ctx, formID, userID)
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+
+	require.Equal(t, RevisionActionCreate, revisions[0].Action)
+	require.Equal(t, userID, revisions[0].ChangedBy)
+	require.Len(t, revisions[0].Diff.ApplicationsAdded, 1)
+
+	update := revisions[1]
+	require.Equal(t, RevisionActionUpdate, update.Action)
+	require.Len(t, update.Diff.Fields, 1)
+	require.Equal(t, "555-0001", update.Diff.Fields["home_phone"].Old)
+	require.Equal(t, "555-9999", update.Diff.Fields["home_phone"].New)
+	require.Len(t, update.Diff.ApplicationsChanged, 1)
+	require.Equal(t, "1 oz/gal", update.Diff.ApplicationsChanged[0].Fields["rate"].Old)
+	require.Equal(t, "2 oz/gal", update.Diff.ApplicationsChanged[0].Fields["rate"].New)
+
+	// Other users cannot read the history
+	otherID := createTestUser(t, testDB)
+	_, err = repo.ListFormRevisions(ctx, formID, otherID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestRollbackFormToRevision(t *testing.T) {
+	ctx := context.Background()
+	testDB := db.TestDB(t)
+	repo := NewFormsRepository(testDB)
+
+	userID := createTestUser(t, testDB)
+	adminID := createTestUser(t, testDB)
+	chemID := createTestChemical(t, testDB, "lawn")
+
+	formID, err := repo.CreateLawnForm(ctx, CreateLawnFormInput{
+		CreatedBy:    userID,
+		FirstName:    "Roll",
+		LastName:     "Back",
+		StreetNumber: "2",
+		StreetName:   "Undo Ave",
+		Town:         "Town",
+		ZipCode:      "10002",
+		HomePhone:    "555-0003",
+		OtherPhone:   "555-0004",
+		LawnAreaSqFt: 3000,
+		Applications: []PestApp{
+			{
+				ChemUsed:      chemID,
+				AppTimestamp:  time.Now(),
+				Rate:          "2 oz/1000 sq ft",
+				AmountApplied: decimal.NewFromFloat(6.0),
+				LocationCode:  "1A",
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	_, err = repo.UpdateLawnFormById(ctx, formID, userID, UpdateLawnFormInput{
+		FirstName:    "Wrong",
+		LastName:     "Back",
+		StreetNumber: "2",
+		StreetName:   "Undo Ave",
+		Town:         "Town",
+		ZipCode:      "10002",
+		HomePhone:    "555-0003",
+		OtherPhone:   "555-0004",
+		LawnAreaSqFt: 9000,
+		Applications: []PestApp{},
+	})
+	require.NoError(t, err)
+
+	revisions, err := repo.ListFormRevisions(ctx, formID, userID)
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+
+	rollback, err := repo.RollbackFormToRevision(ctx, formID, revisions[0].ID, adminID)
+	require.NoError(t, err)
+	require.Equal(t, RevisionActionRollback, rollback.Action)
+	require.Equal(t, adminID, rollback.ChangedBy)
+
+	restored, err := repo.GetLawnFormById(ctx, formID, userID)
+	require.NoError(t, err)
+	require.Equal(t, "Roll", restored.FirstName)
+	require.Equal(t, 3000, restored.LawnAreaSqFt)
+	require.Len(t, restored.AppTimes, 1)
+	require.Equal(t, "2 oz/1000 sq ft", restored.AppTimes[0].Rate)
+
+	_, err = repo.RollbackFormToRevision(ctx, formID, 999999, adminID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	// Trashed forms are left alone
+	require.NoError(t, repo.DeleteFormById(ctx, formID, userID))
+	_, err = repo.RollbackFormToRevision(ctx, formID, revisions[0].ID, adminID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestFormRevisions_SurviveDelete(t *testing.T) {
+	ctx := context.Background()
+	testDB := db.TestDB(t)
+	repo := NewFormsRepository(testDB)
+
+	userID := createTestUser(t, testDB)
+
+	formID, err := repo.CreateShrubForm(ctx, CreateShrubFormInput{
+		CreatedBy:    userID,
+		FirstName:    "Gone",
+		LastName:     "Soon",
+		StreetNumber: "3",
+		StreetName:   "Delete Dr",
+		Town:         "Town",
+		ZipCode:      "10003",
+		HomePhone:    "555-0005",
+		OtherPhone:   "555-0006",
+	})
+	require.NoError(t, err)
+
+	err = repo.DeleteFormById(ctx, formID, userID)
+	require.NoError(t, err)
+
+	purged, err := repo.PurgeDeletedForms(ctx, time.Now().Add(time.Minute), userID)
+	require.NoError(t, err)
+	require.Equal(t, []string{formID}, purged)
+
+	revisions, err := repo.ListAllFormRevisions(ctx, formID)
+	require.NoError(t, err)
+	require.Len(t, revisions, 3)
+	require.Equal(t, RevisionActionDelete, revisions[1].Action)
+	require.Equal(t, RevisionActionPurge, revisions[2].Action)
+	require.Equal(t, "Gone", revisions[2].Diff.Fields["first_name"].Old)
+}
@@ -0,0 +1,89 @@
+package forms
+
+import (
+	"fmt"
+	"strings"
+)
+
+// minPhoneDigits is the shortest digit run matched against phone numbers,
+// shorter runs match too many unrelated numbers to be useful
+const minPhoneDigits = 3
+
+// likeEscaper escapes LIKE wildcards so user input is matched literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// formSearch is a full-text search over client name, address, phones and note text.
+// Its SQL fragments refer to the list query's arguments starting at the index it was built with.
+type formSearch struct {
+	// condition matches forms whose search vector, notes, phones or house number match the query
+	condition string
+	// rank scores a matching form, higher is more relevant
+	rank string
+	// headline is the matched text with the query terms wrapped in <mark> tags
+	headline string
+	args     []any
+}
+
+// newFormSearch builds a search for the given query, returning nil when the query is blank.
+// argIndex is the placeholder index of the first search argument.
+func newFormSearch(query string, argIndex int) *formSearch {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil
+	}
+
+	tsQuery := fmt.Sprintf("websearch_to_tsquery('simple', $%d)", argIndex)
+	pattern := fmt.Sprintf("$%d", argIndex+1)
+	search := &formSearch{
+		args: []any{query, "%" + likeEscaper.Replace(query) + "%"},
+	}
+
+	matches := []string{
+		fmt.Sprintf("f.search_vector @@ %s", tsQuery),
+		fmt.Sprintf("EXISTS (SELECT 1 FROM notes n WHERE n.form_id = f.id AND n.search_vector @@ %s)", tsQuery),
+		fmt.Sprintf("f.street_number ILIKE %s", pattern),
+	}
+	similarities := []string{
+		fmt.Sprintf("similarity(f.street_number, $%d)", argIndex),
+	}
+
+	// Match phone numbers on their digits alone so "5550001" finds "555-0001"
+	if digits := digitsOnly(query); len(digits) >= minPhoneDigits {
+		search.args = append(search.args, digits)
+		digitsArg := argIndex + 2
+		for _, column := range []string{"f.home_phone", "f.other_phone"} {
+			phoneDigits := fmt.Sprintf(`regexp_replace(%s, '\D', '', 'g')`, column)
+			matches = append(matches, fmt.Sprintf("%s LIKE '%%' || $%d || '%%'", phoneDigits, digitsArg))
+			similarities = append(similarities, fmt.Sprintf("similarity(%s, $%d)", phoneDigits, digitsArg))
+		}
+	}
+
+	search.condition = "(" + strings.Join(matches, " OR ") + ")"
+
+	search.rank = fmt.Sprintf(`(
+		ts_rank(f.search_vector, %[1]s)
+		+ COALESCE((SELECT MAX(ts_rank(n.search_vector, %[1]s)) FROM notes n WHERE n.form_id = f.id), 0)
+		+ GREATEST(%[2]s)
+	)`, tsQuery, strings.Join(similarities, ", "))
+
+	search.headline = fmt.Sprintf(`ts_headline(
+		'simple',
+		f.first_name || ' ' || f.last_name
+			|| ' | ' || f.street_number || ' ' || f.street_name || ', ' || f.town || ' ' || f.zip_code
+			|| ' | ' || f.home_phone || ' | ' || f.other_phone
+			|| COALESCE(' | ' || (SELECT string_agg(n.note, ' | ' ORDER BY n.created_at, n.id) FROM notes n WHERE n.form_id = f.id), ''),
+		%s,
+		'StartSel=<mark>, StopSel=</mark>, MaxFragments=3, MaxWords=12, MinWords=4'
+	)`, tsQuery)
+
+	return search
+}
+
+func digitsOnly(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, s)
+}
@@ -0,0 +1,124 @@
+package forms
+
+import (
+	"context"
+	"testing"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/db"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListFormsByUserId_FullTextSearch(t *testing.T) {
+	ctx := context.Background()
+	testDB := db.TestDB(t)
+	repo := NewFormsRepository(testDB)
+
+	userID := createTestUser(t, testDB)
+
+	mapleID := createTestShrubForm(t, repo, CreateShrubFormInput{
+		CreatedBy:    userID,
+		FirstName:    "Ada",
+		LastName:     "Lovelace",
+		StreetNumber: "1428",
+		StreetName:   "Maple",
+		HomePhone:    "(201) 555-7788",
+	})
+	noteID := createTestShrubForm(t, repo, CreateShrubFormInput{
+		CreatedBy:    userID,
+		FirstName:    "Grace",
+		LastName:     "Hopper",
+		StreetNumber: "7",
+		StreetName:   "Oak",
+		HomePhone:    "555-1234",
+	})
+	_, err := repo.CreateNote(ctx, noteID, userID, "Beware of the dog behind the gate")
+	require.NoError(t, err)
+	createTestShrubForm(t, repo, CreateShrubFormInput{
+		CreatedBy:    userID,
+		FirstName:    "Alan",
+		LastName:     "Turing",
+		StreetNumber: "9",
+		StreetName:   "Elm",
+		HomePhone:    "555-9999",
+	})
+
+	search := func(q string) FormsPage {
+		t.Helper()
+		page, err := repo.ListFormsByUserId(ctx, userID, ListFormsOptions{Query: q})
+		require.NoError(t, err)
+		return page
+	}
+
+	// Street name
+	page := search("maple")
+	require.Equal(t, []string{mapleID}, formIDs(page.Forms))
+	require.Contains(t, page.Forms[0].Shrub.SearchSnippet, "<mark>Maple</mark>")
+
+	// Partial phone number, ignoring punctuation
+	page = search("5557788")
+	require.Equal(t, []string{mapleID}, formIDs(page.Forms))
+
+	// Partial house number
+	page = search("142")
+	require.Equal(t, []string{mapleID}, formIDs(page.Forms))
+
+	// Note text
+	page = search("dog")
+	require.Equal(t, []string{noteID}, formIDs(page.Forms))
+	require.Contains(t, page.Forms[0].Shrub.SearchSnippet, "<mark>dog</mark>")
+
+	// No match
+	page = search("willow")
+	require.Empty(t, page.Forms)
+	require.Zero(t, page.Total)
+
+	// Listings without a search carry no snippet
+	page = search("")
+	require.Len(t, page.Forms, 3)
+	require.Empty(t, page.Forms[0].Shrub.SearchSnippet)
+}
+
+func TestListAllForms_SearchRankedByRelevance(t *testing.T) {
+	ctx := context.Background()
+	testDB := db.TestDB(t)
+	repo := NewFormsRepository(testDB)
+
+	userID := createTestUser(t, testDB)
+
+	// Mentioned only in a note
+	noteID := createTestShrubForm(t, repo, CreateShrubFormInput{
+		CreatedBy:    userID,
+		FirstName:    "Mary",
+		LastName:     "Jackson",
+		StreetNumber: "3",
+		StreetName:   "Birch",
+		HomePhone:    "555-0003",
+	})
+	_, err := repo.CreateNote(ctx, noteID, userID, "Neighbour Walnut asked for a quote")
+	require.NoError(t, err)
+
+	// Matches on the client's name
+	nameID := createTestShrubForm(t, repo, CreateShrubFormInput{
+		CreatedBy:    userID,
+		FirstName:    "Dorothy",
+		LastName:     "Walnut",
+		StreetNumber: "4",
+		StreetName:   "Cedar",
+		HomePhone:    "555-0004",
+	})
+
+	page, err := repo.ListAllForms(ctx, ListFormsOptions{Query: "walnut"})
+	require.NoError(t, err)
+	require.Equal(t, []string{nameID, noteID}, formIDs(page.Forms))
+
+	// Relevance order pages with cursors like any other sort
+	page, err = repo.ListAllForms(ctx, ListFormsOptions{Query: "walnut", Limit: 1})
+	require.NoError(t, err)
+	require.Equal(t, []string{nameID}, formIDs(page.Forms))
+	require.Equal(t, 2, page.Total)
+
+	page, err = repo.ListAllForms(ctx, ListFormsOptions{Query: "walnut", Limit: 1, Cursor: page.NextCursor})
+	require.NoError(t, err)
+	require.Equal(t, []string{noteID}, formIDs(page.Forms))
+	require.Empty(t, page.NextCursor)
+}
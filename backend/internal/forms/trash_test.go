@@ -0,0 +1,114 @@
+package forms
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/db"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteFormById_MovesToTrash(t *testing.T) {
+	ctx := context.Background()
+	testDB := db.TestDB(t)
+	repo := NewFormsRepository(testDB)
+
+	userID := createTestUser(t, testDB)
+	keptID := createTestLawnForm(t, repo, CreateLawnFormInput{CreatedBy: userID, FirstName: "Kept"})
+	trashedID := createTestLawnForm(t, repo, CreateLawnFormInput{CreatedBy: userID, FirstName: "Trashed"})
+
+	_, err := repo.CreateNote(ctx, trashedID, userID, "gate code 1234")
+	require.NoError(t, err)
+
+	err = repo.DeleteFormById(ctx, trashedID, userID)
+	require.NoError(t, err)
+
+	// Deleting twice reports not found
+	err = repo.DeleteFormById(ctx, trashedID, userID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	live, err := repo.ListFormsByUserId(ctx, userID, ListFormsOptions{})
+	require.NoError(t, err)
+	require.Len(t, live.Forms, 1)
+	require.Equal(t, keptID, live.Forms[0].Lawn.ID)
+
+	all, err := repo.ListAllForms(ctx, ListFormsOptions{})
+	require.NoError(t, err)
+	require.Equal(t, 1, all.Total)
+
+	trash, err := repo.ListFormsByUserId(ctx, userID, ListFormsOptions{Deleted: true})
+	require.NoError(t, err)
+	require.Len(t, trash.Forms, 1)
+	require.Equal(t, trashedID, trash.Forms[0].Lawn.ID)
+	require.NotNil(t, trash.Forms[0].Lawn.DeletedAt)
+	require.Equal(t, userID, trash.Forms[0].Lawn.DeletedBy)
+	require.Len(t, trash.Forms[0].Lawn.Notes, 1)
+
+	_, err = repo.GetLawnFormById(ctx, trashedID, userID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	// Trashed forms cannot be edited
+	_, err = repo.CreateNote(ctx, trashedID, userID, "too late")
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestRestoreFormById(t *testing.T) {
+	ctx := context.Background()
+	testDB := db.TestDB(t)
+	repo := NewFormsRepository(testDB)
+
+	userID := createTestUser(t, testDB)
+	otherID := createTestUser(t, testDB)
+	formID := createTestLawnForm(t, repo, CreateLawnFormInput{CreatedBy: userID, FirstName: "Restore"})
+
+	// Live forms cannot be restored
+	err := repo.RestoreFormById(ctx, formID, userID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	require.NoError(t, repo.DeleteFormById(ctx, formID, userID))
+
+	err = repo.RestoreFormById(ctx, formID, otherID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	require.NoError(t, repo.RestoreFormById(ctx, formID, userID))
+
+	view, err := repo.GetFormViewById(ctx, formID, userID)
+	require.NoError(t, err)
+	require.Nil(t, view.Lawn.DeletedAt)
+
+	revisions, err := repo.ListFormRevisions(ctx, formID, userID)
+	require.NoError(t, err)
+	require.Equal(t, RevisionActionRestore, revisions[len(revisions)-1].Action)
+}
+
+func TestPurgeDeletedForms_HonorsCutoff(t *testing.T) {
+	ctx := context.Background()
+	testDB := db.TestDB(t)
+	repo := NewFormsRepository(testDB)
+
+	userID := createTestUser(t, testDB)
+	oldID := createTestLawnForm(t, repo, CreateLawnFormInput{CreatedBy: userID, FirstName: "Old"})
+	recentID := createTestLawnForm(t, repo, CreateLawnFormInput{CreatedBy: userID, FirstName: "Recent"})
+	liveID := createTestLawnForm(t, repo, CreateLawnFormInput{CreatedBy: userID, FirstName: "Live"})
+
+	require.NoError(t, repo.DeleteFormById(ctx, oldID, userID))
+	require.NoError(t, repo.DeleteFormById(ctx, recentID, userID))
+
+	_, err := testDB.Exec(`UPDATE forms SET deleted_at = NOW() - INTERVAL '100 days' WHERE id = $1`, oldID)
+	require.NoError(t, err)
+
+	purged, err := repo.PurgeDeletedForms(ctx, time.Now().Add(-30*24*time.Hour), userID)
+	require.NoError(t, err)
+	require.Equal(t, []string{oldID}, purged)
+
+	var count int
+	err = testDB.QueryRow(`SELECT COUNT(*) FROM forms WHERE id = ANY($1::uuid[])`, "{"+recentID+","+liveID+"}").Scan(&count)
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+
+	err = testDB.QueryRow(`SELECT COUNT(*) FROM lawn_forms WHERE form_id = $1`, oldID).Scan(&count)
+	require.NoError(t, err)
+	require.Equal(t, 0, count)
+}
@@ -0,0 +1,85 @@
+package forms
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/sitecodes"
+)
+
+// ErrInvalidInput is wrapped by ValidationError
+var ErrInvalidInput = errors.New("invalid input")
+
+// FieldError is a problem with one field of the input, named like the request field,
+// e.g. "applications[0].location_code"
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// ValidationError lists the fields of the input that failed validation.
+// It unwraps to ErrInvalidInput.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	problems := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		problems = append(problems, field.Field+": "+field.Message)
+	}
+	return fmt.Sprintf("%s: %s", ErrInvalidInput, strings.Join(problems, "; "))
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidInput
+}
+
+// validatePestApp returns the problems with an application's fields, naming them with prefix.
+// stored are the form's applications before the change, nil for new applications; an application
+// left at its stored site code is not checked against the site codes, so forms recorded with
+// codes from before they were validated can still be edited.
+func validatePestApp(app PestApp, stored []appSnapshot, prefix string) []FieldError {
+	var fields []FieldError
+	if !keepsSiteCode(app, stored) && !sitecodes.Valid(app.LocationCode) {
+		fields = append(fields, FieldError{
+			Field:   prefix + "location_code",
+			Message: fmt.Sprintf("unknown site code %q", app.LocationCode),
+		})
+	}
+	fields = append(fields, validateStructuredRate(app.StructuredRate, prefix)...)
+	fields = append(fields, validateWeather(app.ApplicationMethod, app.Weather, prefix)...)
+	return fields
+}
+
+// keepsSiteCode reports whether app is a stored application left at its stored site code
+func keepsSiteCode(app PestApp, stored []appSnapshot) bool {
+	if app.ID == 0 {
+		return false
+	}
+	for _, previous := range stored {
+		if previous.ID == app.ID {
+			return previous.LocationCode == app.LocationCode
+		}
+	}
+	return false
+}
+
+// appFieldPrefix names the fields of a form's i-th application
+func appFieldPrefix(i int) string {
+	return fmt.Sprintf("applications[%d].", i)
+}
+
+// validatePestApps returns a *ValidationError if any of a form's applications is invalid.
+// stored are the form's applications before the change, see validatePestApp.
+func validatePestApps(apps []PestApp, stored []appSnapshot) error {
+	var fields []FieldError
+	for i, app := range apps {
+		fields = append(fields, validatePestApp(app, stored, appFieldPrefix(i))...)
+	}
+	if len(fields) > 0 {
+		return &ValidationError{Fields: fields}
+	}
+	return nil
+}
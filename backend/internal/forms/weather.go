@@ -0,0 +1,155 @@
+package forms
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Application methods
+const (
+	ApplicationMethodSpray     = "spray"
+	ApplicationMethodGranular  = "granular"
+	ApplicationMethodInjection = "injection"
+	ApplicationMethodBait      = "bait"
+)
+
+// ApplicationMethods lists the accepted application methods
+var ApplicationMethods = []string{
+	ApplicationMethodSpray,
+	ApplicationMethodGranular,
+	ApplicationMethodInjection,
+	ApplicationMethodBait,
+}
+
+// WindDirections lists the accepted wind directions, the 16 points of the compass
+var WindDirections = []string{
+	"N", "NNE", "NE", "ENE", "E", "ESE", "SE", "SSE",
+	"S", "SSW", "SW", "WSW", "W", "WNW", "NW", "NNW",
+}
+
+// SkyConditions lists the accepted sky conditions
+var SkyConditions = []string{"clear", "partly_cloudy", "overcast", "fog", "drizzle", "rain"}
+
+// Accepted ranges of the weather readings
+var (
+	maxWindSpeedMph = decimal.NewFromInt(100)
+	minTemperatureF = decimal.NewFromInt(-40)
+	maxTemperatureF = decimal.NewFromInt(130)
+)
+
+// Weather is the conditions recorded at the time of an application. Every field is optional.
+type Weather struct {
+	WindSpeedMph  *decimal.Decimal
+	WindDirection string
+	TemperatureF  *decimal.Decimal
+	SkyConditions string
+}
+
+// IsZero reports whether no weather was recorded
+func (w Weather) IsZero() bool {
+	return w.WindSpeedMph == nil && w.WindDirection == "" && w.TemperatureF == nil && w.SkyConditions == ""
+}
+
+// String summarizes the weather, e.g. "8 mph NW, 72°F, partly cloudy"
+func (w Weather) String() string {
+	var parts []string
+	switch {
+	case w.WindSpeedMph != nil:
+		parts = append(parts, strings.TrimSpace(w.WindSpeedMph.String()+" mph "+w.WindDirection))
+	case w.WindDirection != "":
+		parts = append(parts, "wind "+w.WindDirection)
+	}
+	if w.TemperatureF != nil {
+		parts = append(parts, w.TemperatureF.String()+"°F")
+	}
+	if w.SkyConditions != "" {
+		parts = append(parts, strings.ReplaceAll(w.SkyConditions, "_", " "))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// validateWeather returns the problems with an application's method and weather, naming them with prefix
+func validateWeather(method string, weather Weather, prefix string) []FieldError {
+	var fields []FieldError
+	fail := func(field, message string) {
+		fields = append(fields, FieldError{Field: prefix + field, Message: message})
+	}
+
+	if method != "" && !contains(ApplicationMethods, method) {
+		fail("application_method", fmt.Sprintf("must be one of %s", strings.Join(ApplicationMethods, ", ")))
+	}
+	if speed := weather.WindSpeedMph; speed != nil && (speed.IsNegative() || speed.GreaterThan(maxWindSpeedMph)) {
+		fail("wind_speed_mph", fmt.Sprintf("must be between 0 and %s", maxWindSpeedMph))
+	}
+	if weather.WindDirection != "" && !contains(WindDirections, weather.WindDirection) {
+		fail("wind_direction", "must be a compass point such as N, NNE or NE")
+	}
+	if temp := weather.TemperatureF; temp != nil && (temp.LessThan(minTemperatureF) || temp.GreaterThan(maxTemperatureF)) {
+		fail("temperature_f", fmt.Sprintf("must be between %s and %s", minTemperatureF, maxTemperatureF))
+	}
+	if weather.SkyConditions != "" && !contains(SkyConditions, weather.SkyConditions) {
+		fail("sky_conditions", fmt.Sprintf("must be one of %s", strings.Join(SkyConditions, ", ")))
+	}
+	return fields
+}
+
+// WindRules maps an application method to the wind speed in mph above which an application
+// draws a warning. The "default" rule covers methods without their own rule, including
+// applications recorded without a method.
+type WindRules map[string]decimal.Decimal
+
+// windRuleDefault is the WindRules key used for methods without their own rule
+const windRuleDefault = "default"
+
+// ParseWindRules parses rules written as comma-separated method=mph pairs, e.g. "spray=10,default=15"
+func ParseWindRules(s string) (WindRules, error) {
+	rules := WindRules{}
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		method, mph, ok := strings.Cut(pair, "=")
+		method = strings.ToLower(strings.TrimSpace(method))
+		if !ok || (method != windRuleDefault && !contains(ApplicationMethods, method)) {
+			return nil, fmt.Errorf("invalid wind rule %q", pair)
+		}
+		limit, err := decimal.NewFromString(strings.TrimSpace(mph))
+		if err != nil || limit.IsNegative() {
+			return nil, fmt.Errorf("invalid wind speed in rule %q", pair)
+		}
+		rules[method] = limit
+	}
+	return rules, nil
+}
+
+// Warning returns a warning if the application's wind speed exceeds the rule for its method,
+// or an empty string
+func (rules WindRules) Warning(app PestApp) string {
+	if app.Weather.WindSpeedMph == nil {
+		return ""
+	}
+	limit, ok := rules[app.ApplicationMethod]
+	if !ok {
+		limit, ok = rules[windRuleDefault]
+	}
+	if !ok || !app.Weather.WindSpeedMph.GreaterThan(limit) {
+		return ""
+	}
+	applications := "applications"
+	if app.ApplicationMethod != "" {
+		applications = app.ApplicationMethod + " applications"
+	}
+	return fmt.Sprintf("Wind of %s mph exceeds the %s mph limit for %s", app.Weather.WindSpeedMph, limit, applications)
+}
@@ -0,0 +1,114 @@
+package forms
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/db"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPestAppWeather(t *testing.T) {
+	ctx := context.Background()
+	testDB := db.TestDB(t)
+	repo := NewFormsRepository(testDB)
+
+	userID := createTestUser(t, testDB)
+	chemID := createTestChemical(t, testDB, "shrub")
+
+	reading := func(value string) *decimal.Decimal {
+		d := decimal.RequireFromString(value)
+		return &d
+	}
+
+	formID, err := repo.CreateShrubForm(ctx, CreateShrubFormInput{
+		CreatedBy:    userID,
+		FirstName:    "Windy",
+		LastName:     "Day",
+		StreetNumber: "70",
+		StreetName:   "Breeze St",
+		Town:         "Town",
+		ZipCode:      "10007",
+		HomePhone:    "555-0013",
+		OtherPhone:   "555-0014",
+		Applications: []PestApp{{
+			ChemUsed:          chemID,
+			AppTimestamp:      time.Now(),
+			Rate:              "1 oz/gal",
+			AmountApplied:     decimal.NewFromFloat(1.0),
+			LocationCode:      "1B",
+			ApplicationMethod: ApplicationMethodSpray,
+			Weather: Weather{
+				WindSpeedMph:  reading("8.5"),
+				WindDirection: "NW",
+				TemperatureF:  reading("72"),
+				SkyConditions: "partly_cloudy",
+			},
+		}},
+	})
+	require.NoError(t, err)
+
+	shrub, err := repo.GetShrubFormById(ctx, formID, userID)
+	require.NoError(t, err)
+	require.Len(t, shrub.AppTimes, 1)
+	got := shrub.AppTimes[0]
+	require.Equal(t, ApplicationMethodSpray, got.ApplicationMethod)
+	require.Equal(t, "8.5 mph NW, 72°F, partly cloudy", got.Weather.String())
+
+	// Weather is optional, and clearing it stores nothing
+	got.ApplicationMethod = ""
+	got.Weather = Weather{}
+	updated, err := repo.UpdatePestAppById(ctx, formID, userID, got)
+	require.NoError(t, err)
+	require.Empty(t, updated.ApplicationMethod)
+	require.True(t, updated.Weather.IsZero())
+
+	// Readings out of range and unknown values are rejected
+	got.ApplicationMethod = "fogger"
+	got.Weather = Weather{
+		WindSpeedMph:  reading("120"),
+		WindDirection: "north",
+		TemperatureF:  reading("-50"),
+		SkyConditions: "snow",
+	}
+	_, err = repo.UpdatePestAppById(ctx, formID, userID, got)
+	require.ErrorIs(t, err, ErrInvalidInput)
+	var valErr *ValidationError
+	require.True(t, errors.As(err, &valErr))
+	fields := []string{}
+	for _, field := range valErr.Fields {
+		fields = append(fields, field.Field)
+	}
+	require.Equal(t, []string{"application_method", "wind_speed_mph", "wind_direction", "temperature_f", "sky_conditions"}, fields)
+}
+
+func TestWindRules(t *testing.T) {
+	rules, err := ParseWindRules(" spray=10, default=15 ")
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+
+	app := func(method string, mph string) PestApp {
+		speed := decimal.RequireFromString(mph)
+		return PestApp{ApplicationMethod: method, Weather: Weather{WindSpeedMph: &speed}}
+	}
+
+	require.Empty(t, rules.Warning(app(ApplicationMethodSpray, "10")))
+	require.Equal(t, "Wind of 12 mph exceeds the 10 mph limit for spray applications",
+		rules.Warning(app(ApplicationMethodSpray, "12")))
+	require.Empty(t, rules.Warning(app(ApplicationMethodGranular, "12")))
+	require.Equal(t, "Wind of 16 mph exceeds the 15 mph limit for applications", rules.Warning(app("", "16")))
+	require.Empty(t, rules.Warning(PestApp{ApplicationMethod: ApplicationMethodSpray}))
+
+	// Without a default rule, only the listed methods are checked
+	rules, err = ParseWindRules("spray=10")
+	require.NoError(t, err)
+	require.Empty(t, rules.Warning(app(ApplicationMethodGranular, "40")))
+
+	for _, bad := range []string{"spray", "fogger=10", "spray=fast", "spray=-1"} {
+		_, err := ParseWindRules(bad)
+		require.Error(t, err, bad)
+	}
+}
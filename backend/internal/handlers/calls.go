@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/forms"
+	"github.com/go-chi/chi/v5"
+)
+
+// CallRequest is a call to log against a form; outcome is reached, voicemail or declined
+type CallRequest struct {
+	Outcome string `json:"outcome"`
+	Notes   string `json:"notes"`
+}
+
+// FormCallRequest is a call made before a form was created, logged along with it;
+// called_at defaults to now
+type FormCallRequest struct {
+	CallRequest
+	CalledAt time.Time `json:"called_at"`
+}
+
+// callLogFromRequest converts the call on a create form request, nil when there is none
+func callLogFromRequest(req *FormCallRequest) *forms.CallLog {
+	if req == nil {
+		return nil
+	}
+	return &forms.CallLog{
+		CalledAt: req.CalledAt,
+		Outcome:  req.Outcome,
+		Notes:    req.Notes,
+	}
+}
+
+// CallResponse represents a logged call
+type CallResponse struct {
+	ID           int64     `json:"id"`
+	FormID       string    `json:"form_id"`
+	CalledBy     string    `json:"called_by"`
+	CalledByName string    `json:"called_by_name"`
+	CalledAt     time.Time `json:"called_at"`
+	Outcome      string    `json:"outcome"`
+	Notes        string    `json:"notes"`
+}
+
+// ListCallsResponse lists the calls logged for a form
+type ListCallsResponse struct {
+	Calls []CallResponse `json:"calls"`
+	Count int            `json:"count"`
+}
+
+// LastApplicationResponse summarizes a form's most recent application
+type LastApplicationResponse struct {
+	ID            int       `json:"id"`
+	ChemUsed      int       `json:"chem_used"`
+	BrandName     string    `json:"brand_name"`
+	AppTimestamp  time.Time `json:"app_timestamp"`
+	AppliedBy     string    `json:"applied_by"`
+	AppliedByName string    `json:"applied_by_name"`
+}
+
+// CallQueueEntryResponse is a customer to call before the queue's day
+type CallQueueEntryResponse struct {
+	FormID          string                   `json:"form_id"`
+	FormType        string                   `json:"form_type"`
+	FirstName       string                   `json:"first_name"`
+	LastName        string                   `json:"last_name"`
+	StreetNumber    string                   `json:"street_number"`
+	StreetName      string                   `json:"street_name"`
+	Town            string                   `json:"town"`
+	ZipCode         string                   `json:"zip_code"`
+	HomePhone       string                   `json:"home_phone"`
+	OtherPhone      string                   `json:"other_phone"`
+	Reason          string                   `json:"reason"`
+	LastApplication *LastApplicationResponse `json:"last_application"`
+	LastCall        *CallResponse            `json:"last_call"`
+	Cleared         bool                     `json:"cleared"`
+}
+
+// CallQueueResponse lists the customers to call for a day
+type CallQueueResponse struct {
+	Date    string                   `json:"date"`
+	Entries []CallQueueEntryResponse `json:"entries"`
+	Count   int                      `json:"count"`
+}
+
+func callToResponse(call forms.FormCall) CallResponse {
+	return CallResponse{
+		ID:           call.ID,
+		FormID:       call.FormID,
+		CalledBy:     call.CalledBy,
+		CalledByName: call.CalledByName,
+		CalledAt:     call.CalledAt,
+		Outcome:      call.Outcome,
+		Notes:        call.Notes,
+	}
+}
+
+// GetCallQueue handles GET /api/admin/call-queue?date=2026-05-01 - customers who asked to be called
+// before applications and have one scheduled or due on date (default today)
+func (h *FormsHandler) GetCallQueue(w http.ResponseWriter, r *http.Request) {
+	day := time.Now()
+	if value := r.URL.Query().Get("date"); value != "" {
+		parsed, err := time.Parse(licenseDateLayout, value)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid date, expected YYYY-MM-DD")
+			return
+		}
+		day = parsed
+	}
+
+	entries, err := h.repo.GetCallQueue(r.Context(), day, forms.RoundDaysFromEnv())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp := CallQueueResponse{
+		Date:    day.Format(licenseDateLayout),
+		Entries: make([]CallQueueEntryResponse, 0, len(entries)),
+		Count:   len(entries),
+	}
+	for _, entry := range entries {
+		entryResp := CallQueueEntryResponse{
+			FormID:       entry.FormID,
+			FormType:     entry.FormType,
+			FirstName:    entry.FirstName,
+			LastName:     entry.LastName,
+			StreetNumber: entry.StreetNumber,
+			StreetName:   entry.StreetName,
+			Town:         entry.Town,
+			ZipCode:      entry.ZipCode,
+			HomePhone:    entry.HomePhone,
+			OtherPhone:   entry.OtherPhone,
+			Reason:       entry.Reason,
+			Cleared:      entry.Cleared,
+		}
+		if app := entry.LastApplication; app != nil {
+			entryResp.LastApplication = &LastApplicationResponse{
+				ID:            app.ID,
+				ChemUsed:      app.ChemUsed,
+				BrandName:     entry.LastApplicationBrandName,
+				AppTimestamp:  app.AppTimestamp,
+				AppliedBy:     app.AppliedBy,
+				AppliedByName: app.AppliedByName,
+			}
+		}
+		if entry.LastCall != nil {
+			call := callToResponse(*entry.LastCall)
+			entryResp.LastCall = &call
+		}
+		resp.Entries = append(resp.Entries, entryResp)
+	}
+
+	respondJSON(w, http.StatusOK, resp)
+}
+
+// ListCalls handles GET /api/admin/forms/{id}/calls - the calls logged for a form, most recent first
+func (h *FormsHandler) ListCalls(w http.ResponseWriter, r *http.Request) {
+	formID := chi.URLParam(r, "id")
+	if formID == "" {
+		respondError(w, http.StatusBadRequest, "Form ID is required")
+		return
+	}
+
+	calls, err := h.repo.ListCalls(r.Context(), formID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "Form not found")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp := ListCallsResponse{
+		Calls: make([]CallResponse, 0, len(calls)),
+		Count: len(calls),
+	}
+	for _, call := range calls {
+		resp.Calls = append(resp.Calls, callToResponse(call))
+	}
+
+	respondJSON(w, http.StatusOK, resp)
+}
+
+// LogCall handles POST /api/admin/forms/{id}/calls - records the outcome of a call to the form's customer
+func (h *FormsHandler) LogCall(w http.ResponseWriter, r *http.Request) {
+	userID := getUserID(r)
+
+	formID := chi.URLParam(r, "id")
+	if formID == "" {
+		respondError(w, http.StatusBadRequest, "Form ID is required")
+		return
+	}
+
+	var req CallRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	call, err := h.repo.LogCall(r.Context(), formID, userID, req.Outcome, req.Notes)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			respondError(w, http.StatusNotFound, "Form not found")
+		case errors.Is(err, forms.ErrInvalidCallOutcome):
+			respondError(w, http.StatusBadRequest, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, callToResponse(call))
+}
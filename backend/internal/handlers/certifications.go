@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/certifications"
+	"github.com/go-chi/chi/v5"
+	"github.com/shopspring/decimal"
+)
+
+// CertificationsHandler handles the applicator certification endpoints
+type CertificationsHandler struct {
+	repo *certifications.CertificationsRepository
+}
+
+// NewCertificationsHandler creates a new certifications handler with the given repository
+func NewCertificationsHandler(repo *certifications.CertificationsRepository) *CertificationsHandler {
+	return &CertificationsHandler{repo: repo}
+}
+
+// CreditRequest is a continuing-education course to record; completed_on is a YYYY-MM-DD date
+type CreditRequest struct {
+	Course      string  `json:"course"`
+	CompletedOn string  `json:"completed_on"`
+	Credits     float64 `json:"credits"`
+	Category    string  `json:"category"`
+}
+
+// CreditResponse represents a credit entry
+type CreditResponse struct {
+	ID          int             `json:"id"`
+	Course      string          `json:"course"`
+	CompletedOn string          `json:"completed_on"`
+	Credits     decimal.Decimal `json:"credits"`
+	Category    string          `json:"category"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+// CategoryStandingResponse compares the credits earned in a category with those required
+type CategoryStandingResponse struct {
+	Category  string          `json:"category"`
+	Required  decimal.Decimal `json:"required"`
+	Earned    decimal.Decimal `json:"earned"`
+	Shortfall decimal.Decimal `json:"shortfall"`
+}
+
+// StandingResponse represents an applicator's progress toward renewing their license
+type StandingResponse struct {
+	UserID          string                     `json:"user_id"`
+	FirstName       string                     `json:"first_name"`
+	LastName        string                     `json:"last_name"`
+	License         *LicenseResponse           `json:"license"`
+	DaysUntilExpiry int                        `json:"days_until_expiry"`
+	CycleStart      string                     `json:"cycle_start"`
+	CycleEnd        string                     `json:"cycle_end"`
+	Categories      []CategoryStandingResponse `json:"categories"`
+	RequirementsMet bool                       `json:"requirements_met"`
+	// Credits lists the applicator's credit entries, set on a single applicator's standing
+	Credits []CreditResponse `json:"credits,omitempty"`
+}
+
+// ListStandingsResponse lists applicator standings
+type ListStandingsResponse struct {
+	Days      int                `json:"days"`
+	Standings []StandingResponse `json:"standings"`
+	Count     int                `json:"count"`
+}
+
+func creditToResponse(credit certifications.Credit) CreditResponse {
+	return CreditResponse{
+		ID:          credit.ID,
+		Course:      credit.Course,
+		CompletedOn: credit.CompletedOn.Format(licenseDateLayout),
+		Credits:     credit.Credits,
+		Category:    credit.Category,
+		CreatedAt:   credit.CreatedAt,
+	}
+}
+
+func standingToResponse(standing certifications.Standing) StandingResponse {
+	resp := StandingResponse{
+		UserID:          standing.UserID,
+		FirstName:       standing.FirstName,
+		LastName:        standing.LastName,
+		License:         licenseToResponse(standing.License, time.Now()),
+		DaysUntilExpiry: standing.DaysUntilExpiry,
+		CycleStart:      standing.CycleStart.Format(licenseDateLayout),
+		CycleEnd:        standing.CycleEnd.Format(licenseDateLayout),
+		Categories:      make([]CategoryStandingResponse, 0, len(standing.Categories)),
+		RequirementsMet: standing.RequirementsMet,
+	}
+	for _, category := range standing.Categories {
+		resp.Categories = append(resp.Categories, CategoryStandingResponse{
+			Category:  category.Category,
+			Required:  category.Required,
+			Earned:    category.Earned,
+			Shortfall: category.Shortfall(),
+		})
+	}
+	return resp
+}
+
+// ListExpiringLicenses handles GET /api/admin/certifications/expiring?days=60 - applicators
+// whose licenses expire within the next days days (default 60), with their renewal standing
+func (h *CertificationsHandler) ListExpiringLicenses(w http.ResponseWriter, r *http.Request) {
+	days := 60
+	if value := r.URL.Query().Get("days"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			respondError(w, http.StatusBadRequest, "days must be a non-negative whole number")
+			return
+		}
+		days = parsed
+	}
+
+	standings, err := h.repo.ListExpiring(r.Context(), days, certifications.RequirementsFromEnv())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp := ListStandingsResponse{
+		Days:      days,
+		Standings: make([]StandingResponse, 0, len(standings)),
+		Count:     len(standings),
+	}
+	for _, standing := range standings {
+		resp.Standings = append(resp.Standings, standingToResponse(standing))
+	}
+
+	respondJSON(w, http.StatusOK, resp)
+}
+
+// GetStanding handles GET /api/admin/certifications/users/{id} - an applicator's renewal
+// standing and credit entries
+func (h *CertificationsHandler) GetStanding(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "id")
+	if userID == "" {
+		respondError(w, http.StatusBadRequest, "User ID is required")
+		return
+	}
+
+	standing, err := h.repo.GetStanding(r.Context(), userID, certifications.RequirementsFromEnv())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "User not found")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	credits, err := h.repo.ListCredits(r.Context(), userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp := standingToResponse(standing)
+	resp.Credits = make([]CreditResponse, 0, len(credits))
+	for _, credit := range credits {
+		resp.Credits = append(resp.Credits, creditToResponse(credit))
+	}
+
+	respondJSON(w, http.StatusOK, resp)
+}
+
+// AddCredit handles POST /api/admin/certifications/users/{id}/credits - records a course
+// an applicator completed
+func (h *CertificationsHandler) AddCredit(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "id")
+	if userID == "" {
+		respondError(w, http.StatusBadRequest, "User ID is required")
+		return
+	}
+
+	var req CreditRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	completedOn, err := time.Parse(licenseDateLayout, req.CompletedOn)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid completed_on, expected YYYY-MM-DD")
+		return
+	}
+
+	credit, err := h.repo.AddCredit(r.Context(), userID, certifications.CreditInput{
+		Course:      req.Course,
+		CompletedOn: completedOn,
+		Credits:     decimal.NewFromFloat(req.Credits),
+		Category:    req.Category,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			respondError(w, http.StatusNotFound, "User not found")
+		case errors.Is(err, certifications.ErrInvalidCredit):
+			respondError(w, http.StatusBadRequest, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, creditToResponse(credit))
+}
+
+// DeleteCredit handles DELETE /api/admin/certifications/users/{id}/credits/{creditId}
+func (h *CertificationsHandler) DeleteCredit(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "id")
+	if userID == "" {
+		respondError(w, http.StatusBadRequest, "User ID is required")
+		return
+	}
+	creditID, err := strconv.Atoi(chi.URLParam(r, "creditId"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid credit ID")
+		return
+	}
+
+	if err := h.repo.DeleteCredit(r.Context(), userID, creditID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "Credit entry not found")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondSuccess(w, "Credit entry deleted successfully")
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/BiryaniJedi/LandscapeForm-backend/internal/chemicals"
 	"github.com/go-chi/chi/v5"
+	"github.com/shopspring/decimal"
 )
 
 // ChemicalsHandler handles all chemical-related HTTP requests
@@ -29,17 +30,74 @@ type CreateChemicalRequest struct {
 	EpaRegNo     string `json:"epa_reg_no"`
 	Recipe       string `json:"recipe"`
 	Unit         string `json:"unit"`
+	// LabelRate is optional; chemicals without one are not checked against a label maximum
+	LabelRate *LabelRateRequest `json:"label_rate,omitempty"`
+}
+
+// LabelRateRequest represents a chemical's label rate range, e.g. 1.5 to 3 oz per_1000_sq_ft
+type LabelRateRequest struct {
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Unit  string  `json:"unit"`
+	Basis string  `json:"basis"`
+}
+
+// LabelRateResponse represents a chemical's label rate range
+type LabelRateResponse struct {
+	Min   decimal.Decimal `json:"min"`
+	Max   decimal.Decimal `json:"max"`
+	Unit  string          `json:"unit"`
+	Basis string          `json:"basis"`
 }
 
 // ChemicalResponse represents the response for a chemical
 type ChemicalResponse struct {
-	ID           int    `json:"id"`
-	Category     string `json:"category"`
-	BrandName    string `json:"brand_name"`
-	ChemicalName string `json:"chemical_name"`
-	EpaRegNo     string `json:"epa_reg_no"`
-	Recipe       string `json:"recipe"`
-	Unit         string `json:"unit"`
+	ID           int                `json:"id"`
+	Category     string             `json:"category"`
+	BrandName    string             `json:"brand_name"`
+	ChemicalName string             `json:"chemical_name"`
+	EpaRegNo     string             `json:"epa_reg_no"`
+	Recipe       string             `json:"recipe"`
+	Unit         string             `json:"unit"`
+	LabelRate    *LabelRateResponse `json:"label_rate"`
+}
+
+// labelRateFromRequest converts and validates an optional label rate
+func labelRateFromRequest(req *LabelRateRequest) (*chemicals.LabelRate, error) {
+	if req == nil {
+		return nil, nil
+	}
+	labelRate := chemicals.LabelRate{
+		Min:   decimal.NewFromFloat(req.Min),
+		Max:   decimal.NewFromFloat(req.Max),
+		Unit:  req.Unit,
+		Basis: req.Basis,
+	}
+	if err := labelRate.Validate(); err != nil {
+		return nil, err
+	}
+	return &labelRate, nil
+}
+
+func chemicalToResponse(chem chemicals.Chemical) ChemicalResponse {
+	resp := ChemicalResponse{
+		ID:           chem.ID,
+		Category:     chem.Category,
+		BrandName:    chem.BrandName,
+		ChemicalName: chem.ChemicalName,
+		EpaRegNo:     chem.EpaRegNo,
+		Recipe:       chem.Recipe,
+		Unit:         chem.Unit,
+	}
+	if label := chem.LabelRate; label != nil {
+		resp.LabelRate = &LabelRateResponse{
+			Min:   label.Min,
+			Max:   label.Max,
+			Unit:  label.Unit,
+			Basis: label.Basis,
+		}
+	}
+	return resp
 }
 
 // ListChemicalsResponse represents the response for listing chemicals
@@ -68,6 +126,12 @@ func (h *ChemicalsHandler) CreateChemical(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	labelRate, err := labelRateFromRequest(req.LabelRate)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	chemicalInput := chemicals.ChemicalInput{
 		Category:     req.Category,
 		BrandName:    req.BrandName,
@@ -75,6 +139,7 @@ func (h *ChemicalsHandler) CreateChemical(w http.ResponseWriter, r *http.Request
 		EpaRegNo:     req.EpaRegNo,
 		Recipe:       req.Recipe,
 		Unit:         req.Unit,
+		LabelRate:    labelRate,
 	}
 
 	chemicalId, err := h.repo.CreateChemical(r.Context(), chemicalInput)
@@ -124,15 +189,7 @@ func (h *ChemicalsHandler) ListChemicals(w http.ResponseWriter, r *http.Request)
 
 	chemicalResponses := make([]ChemicalResponse, 0, len(allChemicals))
 	for _, chem := range allChemicals {
-		chemicalResponses = append(chemicalResponses, ChemicalResponse{
-			ID:           chem.ID,
-			Category:     chem.Category,
-			BrandName:    chem.BrandName,
-			ChemicalName: chem.ChemicalName,
-			EpaRegNo:     chem.EpaRegNo,
-			Recipe:       chem.Recipe,
-			Unit:         chem.Unit,
-		})
+		chemicalResponses = append(chemicalResponses, chemicalToResponse(chem))
 	}
 
 	respondJSON(w, http.StatusOK, ListChemicalsResponse{
@@ -163,15 +220,7 @@ func (h *ChemicalsHandler) ListChemicalsByCategory(w http.ResponseWriter, r *htt
 
 	chemicalResponses := make([]ChemicalResponse, 0, len(chemicalsList))
 	for _, chem := range chemicalsList {
-		chemicalResponses = append(chemicalResponses, ChemicalResponse{
-			ID:           chem.ID,
-			Category:     chem.Category,
-			BrandName:    chem.BrandName,
-			ChemicalName: chem.ChemicalName,
-			EpaRegNo:     chem.EpaRegNo,
-			Recipe:       chem.Recipe,
-			Unit:         chem.Unit,
-		})
+		chemicalResponses = append(chemicalResponses, chemicalToResponse(chem))
 	}
 
 	respondJSON(w, http.StatusOK, ListChemicalsResponse{
@@ -212,6 +261,12 @@ func (h *ChemicalsHandler) UpdateChemical(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	labelRate, err := labelRateFromRequest(req.LabelRate)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	chemicalInput := chemicals.ChemicalInput{
 		Category:     req.Category,
 		BrandName:    req.BrandName,
@@ -219,6 +274,7 @@ func (h *ChemicalsHandler) UpdateChemical(w http.ResponseWriter, r *http.Request
 		EpaRegNo:     req.EpaRegNo,
 		Recipe:       req.Recipe,
 		Unit:         req.Unit,
+		LabelRate:    labelRate,
 	}
 
 	chemical, err := h.repo.UpdateChemicalById(r.Context(), id, chemicalInput)
@@ -231,15 +287,7 @@ func (h *ChemicalsHandler) UpdateChemical(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	respondJSON(w, http.StatusOK, ChemicalResponse{
-		ID:           chemical.ID,
-		Category:     chemical.Category,
-		BrandName:    chemical.BrandName,
-		ChemicalName: chemical.ChemicalName,
-		EpaRegNo:     chemical.EpaRegNo,
-		Recipe:       chemical.Recipe,
-		Unit:         chemical.Unit,
-	})
+	respondJSON(w, http.StatusOK, chemicalToResponse(chemical))
 }
 
 // DeleteChemical handles DELETE /api/admin/chemicals/{id}
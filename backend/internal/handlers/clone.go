@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/forms"
+	"github.com/go-chi/chi/v5"
+)
+
+// CloneForm handles POST /api/forms/{id}/clone - starts a new form for the same client without applications.
+// Responds 409 with the candidate forms if the client looks like they already have one this season, unless ?force=true.
+func (h *FormsHandler) CloneForm(w http.ResponseWriter, r *http.Request) {
+	userID := getUserID(r)
+
+	formID := chi.URLParam(r, "id")
+	if formID == "" {
+		respondError(w, http.StatusBadRequest, "Form ID is required")
+		return
+	}
+
+	cloneID, err := h.repo.CloneFormById(r.Context(), formID, userID, parseForce(r))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "Form not found")
+			return
+		}
+		var dupErr *forms.DuplicateFormsError
+		if errors.As(err, &dupErr) {
+			respondDuplicateForms(w, r, dupErr)
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, CreateFormResponse{cloneID})
+}
+
+// CloneSeason handles POST /api/admin/forms/clone-season?year=2025 - clones every form with an
+// application in the given year into the next year. Year defaults to last year.
+func (h *FormsHandler) CloneSeason(w http.ResponseWriter, r *http.Request) {
+	adminID := getUserID(r)
+
+	year := time.Now().Year() - 1
+	if yearStr := r.URL.Query().Get("year"); yearStr != "" {
+		parsed, err := strconv.Atoi(yearStr)
+		if err != nil || parsed < 1 {
+			respondError(w, http.StatusBadRequest, "Invalid year")
+			return
+		}
+		year = parsed
+	}
+
+	result, err := h.repo.CloneFormsIntoNextYear(r.Context(), year, adminID)
+	if err != nil {
+		if errors.Is(err, forms.ErrInvalidCloneYear) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp := SeasonCloneResponse{
+		FromYear: result.FromYear,
+		ToYear:   result.ToYear,
+		Cloned:   make([]ClonedFormResponse, 0, len(result.Cloned)),
+		Skipped:  make([]SkippedCloneResponse, 0, len(result.Skipped)),
+	}
+	for _, cloned := range result.Cloned {
+		resp.Cloned = append(resp.Cloned, ClonedFormResponse{
+			SourceID: cloned.SourceID,
+			CloneID:  cloned.CloneID,
+		})
+	}
+	for _, skipped := range result.Skipped {
+		skippedResp := SkippedCloneResponse{
+			SourceID:     skipped.SourceID,
+			CandidateIDs: make([]string, 0, len(skipped.Candidates)),
+			Candidates:   make([]DuplicateCandidateResponse, 0, len(skipped.Candidates)),
+		}
+		for _, candidate := range skipped.Candidates {
+			skippedResp.CandidateIDs = append(skippedResp.CandidateIDs, candidate.ID)
+			skippedResp.Candidates = append(skippedResp.Candidates, duplicateCandidateToResponse(candidate))
+		}
+		resp.Skipped = append(resp.Skipped, skippedResp)
+	}
+	resp.ClonedCount = len(resp.Cloned)
+	resp.SkippedCount = len(resp.Skipped)
+
+	respondJSON(w, http.StatusOK, resp)
+}
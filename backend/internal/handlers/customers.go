@@ -0,0 +1,515 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/customers"
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/forms"
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/sitecodes"
+	"github.com/go-chi/chi/v5"
+	"github.com/shopspring/decimal"
+)
+
+// CustomersHandler handles all customer and property HTTP requests
+type CustomersHandler struct {
+	repo *customers.CustomersRepository
+}
+
+// NewCustomersHandler creates a new customers handler with the given repository
+func NewCustomersHandler(repo *customers.CustomersRepository) *CustomersHandler {
+	return &CustomersHandler{repo: repo}
+}
+
+// CustomerRequest represents the request body for creating or updating a customer.
+// Properties are only read when creating a customer.
+type CustomerRequest struct {
+	FirstName  string            `json:"first_name"`
+	LastName   string            `json:"last_name"`
+	HomePhone  string            `json:"home_phone"`
+	OtherPhone string            `json:"other_phone"`
+	Properties []PropertyRequest `json:"properties,omitempty"`
+}
+
+// PropertyRequest represents the request body for creating or updating a property
+type PropertyRequest struct {
+	StreetNumber string `json:"street_number"`
+	StreetName   string `json:"street_name"`
+	Town         string `json:"town"`
+	ZipCode      string `json:"zip_code"`
+}
+
+// CustomerResponse represents the response for a customer
+type CustomerResponse struct {
+	ID         string             `json:"id"`
+	CreatedAt  time.Time          `json:"created_at"`
+	UpdatedAt  time.Time          `json:"updated_at"`
+	FirstName  string             `json:"first_name"`
+	LastName   string             `json:"last_name"`
+	HomePhone  string             `json:"home_phone"`
+	OtherPhone string             `json:"other_phone"`
+	Properties []PropertyResponse `json:"properties"`
+}
+
+// PropertyResponse represents the response for a property
+type PropertyResponse struct {
+	ID           string    `json:"id"`
+	CustomerID   string    `json:"customer_id"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	StreetNumber string    `json:"street_number"`
+	StreetName   string    `json:"street_name"`
+	Town         string    `json:"town"`
+	ZipCode      string    `json:"zip_code"`
+}
+
+// ListCustomersResponse represents the response for listing customers
+type ListCustomersResponse struct {
+	Customers []CustomerResponse `json:"customers"`
+	Count     int                `json:"count"`
+}
+
+func (req CustomerRequest) validate() string {
+	if req.FirstName == "" || req.LastName == "" {
+		return "first_name and last_name are required"
+	}
+	for _, property := range req.Properties {
+		if msg := property.validate(); msg != "" {
+			return msg
+		}
+	}
+	return ""
+}
+
+func (req PropertyRequest) validate() string {
+	if req.StreetNumber == "" || req.StreetName == "" || req.Town == "" || req.ZipCode == "" {
+		return "street_number, street_name, town and zip_code are required"
+	}
+	return ""
+}
+
+func (req CustomerRequest) toInput() customers.CustomerInput {
+	return customers.CustomerInput{
+		FirstName:  req.FirstName,
+		LastName:   req.LastName,
+		HomePhone:  req.HomePhone,
+		OtherPhone: req.OtherPhone,
+	}
+}
+
+func (req PropertyRequest) toInput() customers.PropertyInput {
+	return customers.PropertyInput{
+		StreetNumber: req.StreetNumber,
+		StreetName:   req.StreetName,
+		Town:         req.Town,
+		ZipCode:      req.ZipCode,
+	}
+}
+
+func toCustomerResponse(customer customers.Customer) CustomerResponse {
+	properties := make([]PropertyResponse, 0, len(customer.Properties))
+	for _, property := range customer.Properties {
+		properties = append(properties, toPropertyResponse(property))
+	}
+
+	return CustomerResponse{
+		ID:         customer.ID,
+		CreatedAt:  customer.CreatedAt,
+		UpdatedAt:  customer.UpdatedAt,
+		FirstName:  customer.FirstName,
+		LastName:   customer.LastName,
+		HomePhone:  customer.HomePhone,
+		OtherPhone: customer.OtherPhone,
+		Properties: properties,
+	}
+}
+
+func toPropertyResponse(property customers.Property) PropertyResponse {
+	return PropertyResponse{
+		ID:           property.ID,
+		CustomerID:   property.CustomerID,
+		CreatedAt:    property.CreatedAt,
+		UpdatedAt:    property.UpdatedAt,
+		StreetNumber: property.StreetNumber,
+		StreetName:   property.StreetName,
+		Town:         property.Town,
+		ZipCode:      property.ZipCode,
+	}
+}
+
+// ListCustomers handles GET /api/customers?search=smith&limit=20&offset=0
+func (h *CustomersHandler) ListCustomers(w http.ResponseWriter, r *http.Request) {
+	opts := customers.ListCustomersOptions{
+		Search: r.URL.Query().Get("search"),
+	}
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if limit, err := strconv.Atoi(limitStr); err == nil && limit > 0 {
+			opts.Limit = limit
+		}
+	}
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		if offset, err := strconv.Atoi(offsetStr); err == nil && offset >= 0 {
+			opts.Offset = offset
+		}
+	}
+
+	customersList, err := h.repo.ListCustomers(r.Context(), opts)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	customerResponses := make([]CustomerResponse, 0, len(customersList))
+	for _, customer := range customersList {
+		customerResponses = append(customerResponses, toCustomerResponse(customer))
+	}
+
+	respondJSON(w, http.StatusOK, ListCustomersResponse{
+		Customers: customerResponses,
+		Count:     len(customerResponses),
+	})
+}
+
+// CreateCustomer handles POST /api/customers
+func (h *CustomersHandler) CreateCustomer(w http.ResponseWriter, r *http.Request) {
+	var req CustomerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if msg := req.validate(); msg != "" {
+		respondError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	propertyInputs := make([]customers.PropertyInput, 0, len(req.Properties))
+	for _, property := range req.Properties {
+		propertyInputs = append(propertyInputs, property.toInput())
+	}
+
+	customer, err := h.repo.CreateCustomer(r.Context(), req.toInput(), propertyInputs)
+	if err != nil {
+		if errors.Is(err, customers.ErrAddressExists) {
+			respondError(w, http.StatusConflict, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, toCustomerResponse(customer))
+}
+
+// GetCustomer handles GET /api/customers/{id}
+func (h *CustomersHandler) GetCustomer(w http.ResponseWriter, r *http.Request) {
+	customerID := chi.URLParam(r, "id")
+	if customerID == "" {
+		respondError(w, http.StatusBadRequest, "Customer ID is required")
+		return
+	}
+
+	customer, err := h.repo.GetCustomerById(r.Context(), customerID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "Customer not found")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, toCustomerResponse(customer))
+}
+
+// UpdateCustomer handles PUT /api/customers/{id}
+// Linked forms pick up the new name and phones.
+func (h *CustomersHandler) UpdateCustomer(w http.ResponseWriter, r *http.Request) {
+	customerID := chi.URLParam(r, "id")
+	if customerID == "" {
+		respondError(w, http.StatusBadRequest, "Customer ID is required")
+		return
+	}
+
+	var req CustomerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if msg := req.validate(); msg != "" {
+		respondError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	customer, err := h.repo.UpdateCustomerById(r.Context(), customerID, getUserID(r), req.toInput())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "Customer not found")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, toCustomerResponse(customer))
+}
+
+// DeleteCustomer handles DELETE /api/customers/{id}
+func (h *CustomersHandler) DeleteCustomer(w http.ResponseWriter, r *http.Request) {
+	customerID := chi.URLParam(r, "id")
+	if customerID == "" {
+		respondError(w, http.StatusBadRequest, "Customer ID is required")
+		return
+	}
+
+	err := h.repo.DeleteCustomerById(r.Context(), customerID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "Customer not found")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondSuccess(w, "Customer deleted successfully")
+}
+
+// CreateProperty handles POST /api/customers/{id}/properties
+func (h *CustomersHandler) CreateProperty(w http.ResponseWriter, r *http.Request) {
+	customerID := chi.URLParam(r, "id")
+	if customerID == "" {
+		respondError(w, http.StatusBadRequest, "Customer ID is required")
+		return
+	}
+
+	var req PropertyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if msg := req.validate(); msg != "" {
+		respondError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	property, err := h.repo.CreateProperty(r.Context(), customerID, req.toInput())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "Customer not found")
+			return
+		}
+		if errors.Is(err, customers.ErrAddressExists) {
+			respondError(w, http.StatusConflict, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, toPropertyResponse(property))
+}
+
+// UpdateProperty handles PUT /api/customers/{id}/properties/{propertyId}
+// Linked forms pick up the new address.
+func (h *CustomersHandler) UpdateProperty(w http.ResponseWriter, r *http.Request) {
+	customerID := chi.URLParam(r, "id")
+	propertyID := chi.URLParam(r, "propertyId")
+	if customerID == "" || propertyID == "" {
+		respondError(w, http.StatusBadRequest, "Customer ID and property ID are required")
+		return
+	}
+
+	var req PropertyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if msg := req.validate(); msg != "" {
+		respondError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	property, err := h.repo.UpdatePropertyById(r.Context(), customerID, propertyID, getUserID(r), req.toInput())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "Property not found")
+			return
+		}
+		if errors.Is(err, customers.ErrAddressExists) {
+			respondError(w, http.StatusConflict, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, toPropertyResponse(property))
+}
+
+// DeleteProperty handles DELETE /api/customers/{id}/properties/{propertyId}
+func (h *CustomersHandler) DeleteProperty(w http.ResponseWriter, r *http.Request) {
+	customerID := chi.URLParam(r, "id")
+	propertyID := chi.URLParam(r, "propertyId")
+	if customerID == "" || propertyID == "" {
+		respondError(w, http.StatusBadRequest, "Customer ID and property ID are required")
+		return
+	}
+
+	err := h.repo.DeletePropertyById(r.Context(), customerID, propertyID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "Property not found")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondSuccess(w, "Property deleted successfully")
+}
+
+// TimelineEntryResponse represents a single event in an address timeline.
+// Exactly one of application, edit and note is set for the matching kinds.
+type TimelineEntryResponse struct {
+	Kind        string                       `json:"kind"`
+	OccurredAt  time.Time                    `json:"occurred_at"`
+	FormID      string                       `json:"form_id"`
+	FormType    string                       `json:"form_type"`
+	UserID      string                       `json:"user_id"`
+	UserName    string                       `json:"user_name"`
+	Application *TimelineApplicationResponse `json:"application,omitempty"`
+	Edit        *TimelineEditResponse        `json:"edit,omitempty"`
+	Note        *TimelineNoteResponse        `json:"note,omitempty"`
+}
+
+// TimelineApplicationResponse represents a pesticide application with its chemical resolved
+type TimelineApplicationResponse struct {
+	ID            int             `json:"id"`
+	ChemUsed      int             `json:"chem_used"`
+	BrandName     string          `json:"brand_name"`
+	ChemicalName  string          `json:"chemical_name"`
+	EpaRegNo      string          `json:"epa_reg_no"`
+	Unit          string          `json:"unit"`
+	Rate          string          `json:"rate"`
+	AmountApplied decimal.Decimal `json:"amount_applied"`
+	LocationCode  string          `json:"location_code"`
+	// LocationDescription decodes LocationCode, e.g. "Front Yard – Lawn"
+	LocationDescription string `json:"location_description"`
+}
+
+// TimelineEditResponse represents a recorded change to a form
+type TimelineEditResponse struct {
+	RevisionID int64              `json:"revision_id"`
+	Action     string             `json:"action"`
+	Diff       forms.RevisionDiff `json:"diff"`
+}
+
+// TimelineNoteResponse represents a note left on a form
+type TimelineNoteResponse struct {
+	ID      int    `json:"id"`
+	Message string `json:"message"`
+}
+
+// TimelineResponse represents one page of an address timeline
+type TimelineResponse struct {
+	Entries []TimelineEntryResponse `json:"entries"`
+	Count   int                     `json:"count"`
+	Total   int                     `json:"total"`
+}
+
+func toTimelineEntryResponse(entry customers.TimelineEntry) TimelineEntryResponse {
+	resp := TimelineEntryResponse{
+		Kind:       entry.Kind,
+		OccurredAt: entry.OccurredAt,
+		FormID:     entry.FormID,
+		FormType:   entry.FormType,
+		UserID:     entry.UserID,
+		UserName:   entry.UserName,
+	}
+
+	if app := entry.Application; app != nil {
+		resp.Application = &TimelineApplicationResponse{
+			ID:                  app.ID,
+			ChemUsed:            app.ChemicalID,
+			BrandName:           app.BrandName,
+			ChemicalName:        app.ChemicalName,
+			EpaRegNo:            app.EpaRegNo,
+			Unit:                app.Unit,
+			Rate:                app.Rate,
+			AmountApplied:       app.AmountApplied,
+			LocationCode:        app.LocationCode,
+			LocationDescription: sitecodes.Describe(app.LocationCode),
+		}
+	}
+	if edit := entry.Edit; edit != nil {
+		resp.Edit = &TimelineEditResponse{
+			RevisionID: edit.RevisionID,
+			Action:     edit.Action,
+			Diff:       edit.Diff,
+		}
+	}
+	if note := entry.Note; note != nil {
+		resp.Note = &TimelineNoteResponse{
+			ID:      note.ID,
+			Message: note.Message,
+		}
+	}
+
+	return resp
+}
+
+// GetTimeline handles GET /api/customers/timeline?street_number=12&street_name=Main%20St&zip_code=12345&limit=50&offset=0&order=DESC
+// (or ?property_id=... in place of the address) (admin only)
+func (h *CustomersHandler) GetTimeline(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	opts := customers.TimelineOptions{
+		PropertyID:   query.Get("property_id"),
+		StreetNumber: query.Get("street_number"),
+		StreetName:   query.Get("street_name"),
+		ZipCode:      query.Get("zip_code"),
+		Order:        query.Get("order"),
+	}
+	if limitStr := query.Get("limit"); limitStr != "" {
+		if limit, err := strconv.Atoi(limitStr); err == nil && limit > 0 {
+			opts.Limit = limit
+		}
+	}
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		if offset, err := strconv.Atoi(offsetStr); err == nil && offset >= 0 {
+			opts.Offset = offset
+		}
+	}
+
+	page, err := h.repo.GetTimeline(r.Context(), opts)
+	if err != nil {
+		if errors.Is(err, customers.ErrTimelineAddressRequired) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "Property not found")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	entries := make([]TimelineEntryResponse, 0, len(page.Entries))
+	for _, entry := range page.Entries {
+		entries = append(entries, toTimelineEntryResponse(entry))
+	}
+
+	respondJSON(w, http.StatusOK, TimelineResponse{
+		Entries: entries,
+		Count:   len(entries),
+		Total:   page.Total,
+	})
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/forms"
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/middleware"
+)
+
+// DuplicateCandidateResponse represents an existing form that looks like the same client
+type DuplicateCandidateResponse struct {
+	ID           string    `json:"id"`
+	FormType     string    `json:"form_type"`
+	CreatedBy    string    `json:"created_by"`
+	CreatedAt    time.Time `json:"created_at"`
+	FirstName    string    `json:"first_name"`
+	LastName     string    `json:"last_name"`
+	StreetNumber string    `json:"street_number"`
+	StreetName   string    `json:"street_name"`
+	Town         string    `json:"town"`
+	ZipCode      string    `json:"zip_code"`
+	HomePhone    string    `json:"home_phone"`
+	OtherPhone   string    `json:"other_phone"`
+	Reasons      []string  `json:"reasons,omitempty"`
+}
+
+// DuplicateFormsResponse is the 409 body returned when a new form looks like an existing one.
+// Resending the request with ?force=true creates the form anyway.
+type DuplicateFormsResponse struct {
+	Error        string   `json:"error"`
+	Message      string   `json:"message"`
+	CandidateIDs []string `json:"candidate_ids"`
+	// Reasons lists why each candidate matched, by candidate ID
+	Reasons map[string][]string `json:"reasons"`
+	// Candidates is only returned to admins, as the forms may belong to other users
+	Candidates []DuplicateCandidateResponse `json:"candidates,omitempty"`
+}
+
+// DuplicateClusterResponse represents a group of forms that look like the same client
+type DuplicateClusterResponse struct {
+	FormType string                       `json:"form_type"`
+	FormIDs  []string                     `json:"form_ids"`
+	Forms    []DuplicateCandidateResponse `json:"forms"`
+}
+
+// ListDuplicateClustersResponse represents the duplicate clusters report
+type ListDuplicateClustersResponse struct {
+	Year     int                        `json:"year"`
+	Clusters []DuplicateClusterResponse `json:"clusters"`
+	Count    int                        `json:"count"`
+}
+
+func duplicateCandidateToResponse(candidate forms.DuplicateCandidate) DuplicateCandidateResponse {
+	return DuplicateCandidateResponse{
+		ID:           candidate.ID,
+		FormType:     candidate.FormType,
+		CreatedBy:    candidate.CreatedBy,
+		CreatedAt:    candidate.CreatedAt,
+		FirstName:    candidate.FirstName,
+		LastName:     candidate.LastName,
+		StreetNumber: candidate.StreetNumber,
+		StreetName:   candidate.StreetName,
+		Town:         candidate.Town,
+		ZipCode:      candidate.ZipCode,
+		HomePhone:    candidate.HomePhone,
+		OtherPhone:   candidate.OtherPhone,
+		Reasons:      candidate.Reasons,
+	}
+}
+
+// respondDuplicateForms writes the 409 response listing the forms a new form may duplicate.
+// Only admins are sent the candidate forms themselves.
+func respondDuplicateForms(w http.ResponseWriter, r *http.Request, dupErr *forms.DuplicateFormsError) {
+	resp := DuplicateFormsResponse{
+		Error:        http.StatusText(http.StatusConflict),
+		Message:      "A form for this client already exists this season. Resend with ?force=true to create it anyway.",
+		CandidateIDs: make([]string, 0, len(dupErr.Candidates)),
+		Reasons:      make(map[string][]string, len(dupErr.Candidates)),
+	}
+	role, _ := middleware.GetUserRole(r.Context())
+	for _, candidate := range dupErr.Candidates {
+		resp.CandidateIDs = append(resp.CandidateIDs, candidate.ID)
+		resp.Reasons[candidate.ID] = candidate.Reasons
+		if role == "admin" {
+			resp.Candidates = append(resp.Candidates, duplicateCandidateToResponse(candidate))
+		}
+	}
+
+	respondJSON(w, http.StatusConflict, resp)
+}
+
+// parseForce reports whether the request asked to skip duplicate detection with ?force=true
+func parseForce(r *http.Request) bool {
+	force, _ := strconv.ParseBool(r.URL.Query().Get("force"))
+	return force
+}
+
+// ListDuplicateClusters handles GET /api/admin/forms/duplicates?year=2025&type=lawn
+// Year defaults to the current year and type to both form types.
+func (h *FormsHandler) ListDuplicateClusters(w http.ResponseWriter, r *http.Request) {
+	year := time.Now().Year()
+	if yearStr := r.URL.Query().Get("year"); yearStr != "" {
+		parsed, err := strconv.Atoi(yearStr)
+		if err != nil || parsed < 1 {
+			respondError(w, http.StatusBadRequest, "Invalid year")
+			return
+		}
+		year = parsed
+	}
+
+	formType := r.URL.Query().Get("type")
+	if formType != "" && formType != "shrub" && formType != "lawn" {
+		respondError(w, http.StatusBadRequest, "type must be 'lawn' or 'shrub'")
+		return
+	}
+
+	clusters, err := h.repo.ListDuplicateClusters(r.Context(), year, formType)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	clusterResponses := make([]DuplicateClusterResponse, 0, len(clusters))
+	for _, cluster := range clusters {
+		clusterResp := DuplicateClusterResponse{
+			FormType: cluster.FormType,
+			FormIDs:  make([]string, 0, len(cluster.Forms)),
+			Forms:    make([]DuplicateCandidateResponse, 0, len(cluster.Forms)),
+		}
+		for _, form := range cluster.Forms {
+			clusterResp.FormIDs = append(clusterResp.FormIDs, form.ID)
+			clusterResp.Forms = append(clusterResp.Forms, duplicateCandidateToResponse(form))
+		}
+		clusterResponses = append(clusterResponses, clusterResp)
+	}
+
+	respondJSON(w, http.StatusOK, ListDuplicateClustersResponse{
+		Year:     year,
+		Clusters: clusterResponses,
+		Count:    len(clusterResponses),
+	})
+}
@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/forms"
+	"github.com/shopspring/decimal"
+)
+
+// exportFlushRows is how many CSV rows are buffered before they are flushed to the client
+const exportFlushRows = 100
+
+// Columns shared by both export layouts, named like the import columns where they overlap
+var exportFormColumns = []string{
+	"form_id",
+	"form_type",
+	"created_by",
+	"created_at",
+	"first_name",
+	"last_name",
+	"street_number",
+	"street_name",
+	"town",
+	"zip_code",
+	"home_phone",
+	"other_phone",
+	"call_before",
+	"is_holiday",
+	"flea_only",
+	"lawn_area_sq_ft",
+	"fert_only",
+}
+
+var exportApplicationColumns = []string{
+	"application_id",
+	"app_timestamp",
+	"chem_used",
+	"brand_name",
+	"chemical_name",
+	"epa_reg_no",
+	"rate",
+	"amount_applied",
+	"unit",
+	"location_code",
+	"rate_amount",
+	"rate_unit",
+	"rate_basis",
+	"expected_amount",
+	"over_label_max",
+	"application_method",
+	"wind_speed_mph",
+	"wind_direction",
+	"temperature_f",
+	"sky_conditions",
+	"applicator_license_number",
+	"applied_by_name",
+}
+
+var exportFormSummaryColumns = []string{
+	"application_count",
+	"first_app_date",
+	"last_app_date",
+}
+
+// exportHeader returns the CSV header row of a layout
+func exportHeader(layout string) []string {
+	header := append([]string{}, exportFormColumns...)
+	if layout == forms.ExportLayoutForms {
+		return append(header, exportFormSummaryColumns...)
+	}
+	return append(header, exportApplicationColumns...)
+}
+
+// exportRecord returns the CSV record of an export row in a layout
+func exportRecord(layout string, row forms.ExportRow) []string {
+	optionalBool := func(b *bool) string {
+		if b == nil {
+			return ""
+		}
+		return strconv.FormatBool(*b)
+	}
+	lawnArea := ""
+	if row.LawnAreaSqFt != nil {
+		lawnArea = strconv.Itoa(*row.LawnAreaSqFt)
+	}
+
+	form := row.Form
+	record := []string{
+		form.ID,
+		form.FormType,
+		form.CreatedBy,
+		form.CreatedAt.Format(time.RFC3339),
+		form.FirstName,
+		form.LastName,
+		form.StreetNumber,
+		form.StreetName,
+		form.Town,
+		form.ZipCode,
+		form.HomePhone,
+		form.OtherPhone,
+		strconv.FormatBool(form.CallBefore),
+		strconv.FormatBool(form.IsHoliday),
+		optionalBool(row.FleaOnly),
+		lawnArea,
+		optionalBool(row.FertOnly),
+	}
+
+	if layout == forms.ExportLayoutForms {
+		firstAppDate, lastAppDate := "", ""
+		if row.ApplicationCount > 0 {
+			firstAppDate = form.FirstAppDate.Format(time.RFC3339)
+			lastAppDate = form.LastAppDate.Format(time.RFC3339)
+		}
+		return append(record, strconv.Itoa(row.ApplicationCount), firstAppDate, lastAppDate)
+	}
+
+	app := row.Application
+	if app == nil {
+		return append(record, make([]string, len(exportApplicationColumns))...)
+	}
+	rateAmount, rateUnit, rateBasis := "", "", ""
+	if rate := app.StructuredRate; rate != nil {
+		rateAmount, rateUnit, rateBasis = rate.Amount.String(), rate.Unit, rate.Basis
+	}
+	optionalDecimal := func(d *decimal.Decimal) string {
+		if d == nil {
+			return ""
+		}
+		return d.String()
+	}
+	return append(record,
+		strconv.Itoa(app.ID),
+		app.AppTimestamp.Format(time.RFC3339),
+		strconv.Itoa(app.ChemUsed),
+		app.BrandName,
+		app.ChemicalName,
+		app.EpaRegNo,
+		app.Rate,
+		app.AmountApplied.String(),
+		app.Unit,
+		app.LocationCode,
+		rateAmount,
+		rateUnit,
+		rateBasis,
+		optionalDecimal(app.ExpectedAmount),
+		strconv.FormatBool(app.OverLabelMax),
+		app.ApplicationMethod,
+		optionalDecimal(app.Weather.WindSpeedMph),
+		app.Weather.WindDirection,
+		optionalDecimal(app.Weather.TemperatureF),
+		app.Weather.SkyConditions,
+		app.ApplicatorLicenseNumber,
+		app.AppliedByName,
+	)
+}
+
+// exportForms streams the forms matching the request's list filters as CSV.
+// userID restricts the export to that user's forms, or is empty to export every form.
+func (h *FormsHandler) exportForms(w http.ResponseWriter, r *http.Request, userID string) {
+	opts := parseListFormsOptions(r)
+
+	layout := r.URL.Query().Get("layout")
+	if layout == "" {
+		layout = forms.ExportLayoutApplications
+	}
+	if layout != forms.ExportLayoutApplications && layout != forms.ExportLayoutForms {
+		respondError(w, http.StatusBadRequest, forms.ErrInvalidExportLayout.Error())
+		return
+	}
+
+	writer := csv.NewWriter(w)
+	started := false
+	start := func() error {
+		started = true
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(
+			`attachment; filename="forms-%s-%s.csv"`, layout, time.Now().Format("2006-01-02"),
+		))
+		w.WriteHeader(http.StatusOK)
+		return writer.Write(exportHeader(layout))
+	}
+
+	written := 0
+	err := h.repo.ExportForms(r.Context(), userID, opts, layout, func(row forms.ExportRow) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		if err := writer.Write(exportRecord(layout, row)); err != nil {
+			return err
+		}
+		written++
+		if written%exportFlushRows == 0 {
+			writer.Flush()
+			if flusher, ok := w.(http.Flusher); ok {
+				flusher.Flush()
+			}
+		}
+		return writer.Error()
+	})
+	if err == nil && !started {
+		err = start()
+	}
+	if err != nil {
+		if !started {
+			if errors.Is(err, forms.ErrInvalidExportLayout) {
+				respondError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		// The status line is already out, so the client only sees a truncated file
+		log.Printf("forms export aborted after %d rows: %v", written, err)
+		return
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		log.Printf("forms export failed to flush: %v", err)
+	}
+}
+
+// ExportForms handles GET /api/forms/export.csv?layout=applications - the user's forms as CSV.
+// Accepts the same filters and sorting as GET /api/forms; pagination is ignored.
+// layout is "applications" (one row per application, the default) or "forms" (one row per form).
+func (h *FormsHandler) ExportForms(w http.ResponseWriter, r *http.Request) {
+	h.exportForms(w, r, getUserID(r))
+}
+
+// ExportAllForms handles GET /api/admin/forms/export.csv - every user's forms as CSV (admin only).
+// Takes the same parameters as ExportForms.
+func (h *FormsHandler) ExportAllForms(w http.ResponseWriter, r *http.Request) {
+	h.exportForms(w, r, "")
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/BiryaniJedi/LandscapeForm-backend/internal/forms"
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/middleware"
 	"github.com/go-chi/chi/v5"
 	"github.com/shopspring/decimal"
 	"regexp"
@@ -36,7 +37,96 @@ func getUserID(r *http.Request) string {
 	return "00000000-0000-0000-0000-000000000001"
 }
 
+// pestAppFromRequest converts a pesticide application request into its domain model
+func pestAppFromRequest(appReq PesticideApplicationRequest) (forms.PestApp, error) {
+	appTime, err := time.Parse(time.RFC3339, appReq.AppTimestamp)
+	if err != nil {
+		return forms.PestApp{}, errors.New("Invalid application timestamp format: " + err.Error())
+	}
+
+	app := forms.PestApp{
+		ID:                appReq.ID,
+		ChemUsed:          appReq.ChemUsed,
+		AppTimestamp:      appTime,
+		Rate:              appReq.Rate,
+		AmountApplied:     decimal.NewFromFloat(appReq.AmountApplied),
+		LocationCode:      appReq.LocationCode,
+		ApplicationMethod: appReq.ApplicationMethod,
+		AppliedBy:         appReq.AppliedBy,
+		Weather: forms.Weather{
+			WindDirection: appReq.WindDirection,
+			SkyConditions: appReq.SkyConditions,
+		},
+	}
+	if appReq.WindSpeedMph != nil {
+		speed := decimal.NewFromFloat(*appReq.WindSpeedMph)
+		app.Weather.WindSpeedMph = &speed
+	}
+	if appReq.TemperatureF != nil {
+		temp := decimal.NewFromFloat(*appReq.TemperatureF)
+		app.Weather.TemperatureF = &temp
+	}
+	if appReq.RateAmount != nil {
+		app.StructuredRate = &forms.StructuredRate{
+			Amount: decimal.NewFromFloat(*appReq.RateAmount),
+			Unit:   appReq.RateUnit,
+			Basis:  appReq.RateBasis,
+		}
+	} else if appReq.RateUnit != "" || appReq.RateBasis != "" {
+		return forms.PestApp{}, errors.New("rate_amount is required with rate_unit and rate_basis")
+	}
+	return app, nil
+}
+
+// pestAppsFromRequest converts a list of pesticide application requests, preserving a nil list
+func pestAppsFromRequest(appReqs []PesticideApplicationRequest) ([]forms.PestApp, error) {
+	if appReqs == nil {
+		return nil, nil
+	}
+
+	applications := make([]forms.PestApp, 0, len(appReqs))
+	for _, appReq := range appReqs {
+		app, err := pestAppFromRequest(appReq)
+		if err != nil {
+			return nil, err
+		}
+		applications = append(applications, app)
+	}
+	return applications, nil
+}
+
+// errApplicatorNotAdmin is returned when a user who is not an admin records an application made by someone else
+var errApplicatorNotAdmin = errors.New("only admins can record applications made by another user")
+
+// restrictApplicator enforces who may set an application's applicator: admins may name anyone,
+// other users only themselves. An existing application edited by a user who is not an admin
+// keeps its stored applicator.
+func restrictApplicator(r *http.Request, app *forms.PestApp, isNew bool) error {
+	if role, _ := middleware.GetUserRole(r.Context()); role == "admin" {
+		return nil
+	}
+	if !isNew {
+		app.AppliedBy = ""
+		return nil
+	}
+	if app.AppliedBy != "" && app.AppliedBy != getUserID(r) {
+		return errApplicatorNotAdmin
+	}
+	return nil
+}
+
+// restrictApplicators applies restrictApplicator to every application, see pestAppsFromRequest
+func restrictApplicators(r *http.Request, apps []forms.PestApp) error {
+	for i := range apps {
+		if err := restrictApplicator(r, &apps[i], apps[i].ID == 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // CreateShrubForm creates a new shrub pesticide application form. Returns the created form ID upon success.
+// Responds 409 with the candidate forms if the client looks like they already have one this season, unless ?force=true.
 func (h *FormsHandler) CreateShrubForm(w http.ResponseWriter, r *http.Request) {
 	userID := getUserID(r)
 
@@ -46,25 +136,19 @@ func (h *FormsHandler) CreateShrubForm(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var applications []forms.PestApp
-	for _, appReq := range req.Applications {
-		appTime, err := time.Parse(time.RFC3339, appReq.AppTimestamp)
-		if err != nil {
-			respondError(w, http.StatusBadRequest, "Invalid application timestamp format: "+err.Error())
-			return
-		}
-
-		applications = append(applications, forms.PestApp{
-			ChemUsed:      appReq.ChemUsed,
-			AppTimestamp:  appTime,
-			Rate:          appReq.Rate,
-			AmountApplied: decimal.NewFromFloat(appReq.AmountApplied),
-			LocationCode:  appReq.LocationCode,
-		})
+	applications, err := pestAppsFromRequest(req.Applications)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := restrictApplicators(r, applications); err != nil {
+		respondError(w, http.StatusForbidden, err.Error())
+		return
 	}
 
 	shrubFormInput := forms.CreateShrubFormInput{
 		CreatedBy:    userID,
+		PropertyID:   req.PropertyID,
 		FirstName:    req.FirstName,
 		LastName:     req.LastName,
 		StreetNumber: req.StreetNumber,
@@ -77,10 +161,38 @@ func (h *FormsHandler) CreateShrubForm(w http.ResponseWriter, r *http.Request) {
 		IsHoliday:    req.IsHoliday,
 		FleaOnly:     req.FleaOnly,
 		Applications: applications,
+		Call:         callLogFromRequest(req.Call),
+		Force:        parseForce(r),
 	}
 
 	shrubFormId, err := h.repo.CreateShrubForm(r.Context(), shrubFormInput)
 	if err != nil {
+		var valErr *forms.ValidationError
+		if errors.As(err, &valErr) {
+			respondValidationError(w, valErr)
+			return
+		}
+		if errors.Is(err, forms.ErrPropertyNotFound) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, forms.ErrLicenseExpired) {
+			respondError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		if errors.Is(err, forms.ErrCallRequired) {
+			respondError(w, http.StatusConflict, err.Error())
+			return
+		}
+		if errors.Is(err, forms.ErrHolidayBlackout) {
+			respondError(w, http.StatusConflict, err.Error())
+			return
+		}
+		var dupErr *forms.DuplicateFormsError
+		if errors.As(err, &dupErr) {
+			respondDuplicateForms(w, r, dupErr)
+			return
+		}
 		respondError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -89,6 +201,7 @@ func (h *FormsHandler) CreateShrubForm(w http.ResponseWriter, r *http.Request) {
 }
 
 // CreateLawnForm creates a new lawn pesticide application form. Returns the created form ID upon success.
+// Responds 409 with the candidate forms if the client looks like they already have one this season, unless ?force=true.
 func (h *FormsHandler) CreateLawnForm(w http.ResponseWriter, r *http.Request) {
 	userID := getUserID(r)
 
@@ -98,25 +211,19 @@ func (h *FormsHandler) CreateLawnForm(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var applications []forms.PestApp
-	for _, appReq := range req.Applications {
-		appTime, err := time.Parse(time.RFC3339, appReq.AppTimestamp)
-		if err != nil {
-			respondError(w, http.StatusBadRequest, "Invalid application timestamp format: "+err.Error())
-			return
-		}
-
-		applications = append(applications, forms.PestApp{
-			ChemUsed:      appReq.ChemUsed,
-			AppTimestamp:  appTime,
-			Rate:          appReq.Rate,
-			AmountApplied: decimal.NewFromFloat(appReq.AmountApplied),
-			LocationCode:  appReq.LocationCode,
-		})
+	applications, err := pestAppsFromRequest(req.Applications)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := restrictApplicators(r, applications); err != nil {
+		respondError(w, http.StatusForbidden, err.Error())
+		return
 	}
 
 	lawnFormInput := forms.CreateLawnFormInput{
 		CreatedBy:    userID,
+		PropertyID:   req.PropertyID,
 		FirstName:    req.FirstName,
 		LastName:     req.LastName,
 		StreetNumber: req.StreetNumber,
@@ -130,10 +237,38 @@ func (h *FormsHandler) CreateLawnForm(w http.ResponseWriter, r *http.Request) {
 		LawnAreaSqFt: req.LawnAreaSqFt,
 		FertOnly:     req.FertOnly,
 		Applications: applications,
+		Call:         callLogFromRequest(req.Call),
+		Force:        parseForce(r),
 	}
 
 	lawnFormId, err := h.repo.CreateLawnForm(r.Context(), lawnFormInput)
 	if err != nil {
+		var valErr *forms.ValidationError
+		if errors.As(err, &valErr) {
+			respondValidationError(w, valErr)
+			return
+		}
+		if errors.Is(err, forms.ErrPropertyNotFound) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, forms.ErrLicenseExpired) {
+			respondError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		if errors.Is(err, forms.ErrCallRequired) {
+			respondError(w, http.StatusConflict, err.Error())
+			return
+		}
+		if errors.Is(err, forms.ErrHolidayBlackout) {
+			respondError(w, http.StatusConflict, err.Error())
+			return
+		}
+		var dupErr *forms.DuplicateFormsError
+		if errors.As(err, &dupErr) {
+			respondDuplicateForms(w, r, dupErr)
+			return
+		}
 		respondError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -141,48 +276,40 @@ func (h *FormsHandler) CreateLawnForm(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusCreated, CreateFormResponse{lawnFormId})
 }
 
-// ListForms handles GET /api/forms?sort_by=created_at&order=DESC&limit=10&offset=0&type=shrub&search=john
+// ListForms handles GET /api/forms?sort_by=created_at&order=DESC&limit=10&offset=0&type=shrub&search=john&q=maple (or &cursor=... in place of offset)
 func (h *FormsHandler) ListForms(w http.ResponseWriter, r *http.Request) {
 	userID := getUserID(r)
 
 	opts := parseListFormsOptions(r)
 
-	views, err := h.repo.ListFormsByUserId(r.Context(), userID, opts)
+	page, err := h.repo.ListFormsByUserId(r.Context(), userID, opts)
 	if err != nil {
+		if errors.Is(err, forms.ErrInvalidCursor) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		respondError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	formResponses := make([]FormViewResponse, 0, len(views))
-	for _, view := range views {
-		formResponses = append(formResponses, formViewToResponse(view))
-	}
-
-	respondJSON(w, http.StatusOK, ListFormsResponse{
-		Forms: formResponses,
-		Count: len(formResponses),
-	})
+	respondJSON(w, http.StatusOK, formsPageToResponse(page))
 }
 
 // ListAllForms handles GET /api/admin/forms - returns ALL forms from all users (admin only)
 func (h *FormsHandler) ListAllForms(w http.ResponseWriter, r *http.Request) {
 	opts := parseListFormsOptions(r)
 
-	views, err := h.repo.ListAllForms(r.Context(), opts)
+	page, err := h.repo.ListAllForms(r.Context(), opts)
 	if err != nil {
+		if errors.Is(err, forms.ErrInvalidCursor) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		respondError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	formResponses := make([]FormViewResponse, 0, len(views))
-	for _, view := range views {
-		formResponses = append(formResponses, formViewToResponse(view))
-	}
-
-	respondJSON(w, http.StatusOK, ListFormsResponse{
-		Forms: formResponses,
-		Count: len(formResponses),
-	})
+	respondJSON(w, http.StatusOK, formsPageToResponse(page))
 }
 
 // parseListFormsOptions parses query parameters for list forms endpoints
@@ -209,9 +336,13 @@ func parseListFormsOptions(r *http.Request) forms.ListFormsOptions {
 		}
 	}
 
+	// Keyset pagination - an opaque next_cursor/prev_cursor from a previous response, takes precedence over offset
+	opts.Cursor = r.URL.Query().Get("cursor")
+
 	// Filtering
 	opts.FormType = r.URL.Query().Get("type")     // "shrub" or "lawn"
 	opts.SearchName = r.URL.Query().Get("search") // search in first_name or last_name
+	opts.Query = r.URL.Query().Get("q")           // full-text search in name, address, phones and notes
 
 	// Parse chemical IDs - supports both ?chemicals=1,2,3 and ?chemicals[]=1&chemicals[]=2
 	if chemicalsStr := r.URL.Query().Get("chemicals"); chemicalsStr != "" {
@@ -249,6 +380,9 @@ func parseListFormsOptions(r *http.Request) forms.ListFormsOptions {
 		}
 	}
 
+	opts.CreatedBy = r.URL.Query().Get("created_by")
+	opts.AppliedBy = r.URL.Query().Get("applied_by")
+
 	if jewishHolidayString := r.URL.Query().Get("jewish_holiday"); jewishHolidayString != "" {
 		switch jewishHolidayString {
 		case "yes":
@@ -259,9 +393,9 @@ func parseListFormsOptions(r *http.Request) forms.ListFormsOptions {
 		}
 	}
 
-	// Sorting
+	// Sorting - searches default to relevance order
 	opts.SortBy = r.URL.Query().Get("sort_by")
-	if opts.SortBy == "" {
+	if opts.SortBy == "" && opts.Query == "" {
 		opts.SortBy = "created_at"
 	}
 
@@ -360,6 +494,16 @@ func (h *FormsHandler) UpdateShrubForm(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	applications, err := pestAppsFromRequest(req.Applications)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := restrictApplicators(r, applications); err != nil {
+		respondError(w, http.StatusForbidden, err.Error())
+		return
+	}
+
 	shrubFormInput := forms.UpdateShrubFormInput{
 		FirstName:    req.FirstName,
 		LastName:     req.LastName,
@@ -372,10 +516,28 @@ func (h *FormsHandler) UpdateShrubForm(w http.ResponseWriter, r *http.Request) {
 		CallBefore:   req.CallBefore,
 		IsHoliday:    req.IsHoliday,
 		FleaOnly:     req.FleaOnly,
+		Applications: applications,
 	}
 
 	shrubForm, err := h.repo.UpdateShrubFormById(r.Context(), formID, userID, shrubFormInput)
 	if err != nil {
+		var valErr *forms.ValidationError
+		if errors.As(err, &valErr) {
+			respondValidationError(w, valErr)
+			return
+		}
+		if errors.Is(err, forms.ErrLicenseExpired) {
+			respondError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		if errors.Is(err, forms.ErrCallRequired) {
+			respondError(w, http.StatusConflict, err.Error())
+			return
+		}
+		if errors.Is(err, forms.ErrHolidayBlackout) {
+			respondError(w, http.StatusConflict, err.Error())
+			return
+		}
 		if errors.Is(err, sql.ErrNoRows) {
 			respondError(w, http.StatusNotFound, err.Error())
 			return
@@ -404,6 +566,16 @@ func (h *FormsHandler) UpdateLawnForm(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	applications, err := pestAppsFromRequest(req.Applications)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := restrictApplicators(r, applications); err != nil {
+		respondError(w, http.StatusForbidden, err.Error())
+		return
+	}
+
 	lawnFormInput := forms.UpdateLawnFormInput{
 		FirstName:    req.FirstName,
 		LastName:     req.LastName,
@@ -417,10 +589,28 @@ func (h *FormsHandler) UpdateLawnForm(w http.ResponseWriter, r *http.Request) {
 		IsHoliday:    req.IsHoliday,
 		LawnAreaSqFt: req.LawnAreaSqFt,
 		FertOnly:     req.FertOnly,
+		Applications: applications,
 	}
 
 	lawnForm, err := h.repo.UpdateLawnFormById(r.Context(), formID, userID, lawnFormInput)
 	if err != nil {
+		var valErr *forms.ValidationError
+		if errors.As(err, &valErr) {
+			respondValidationError(w, valErr)
+			return
+		}
+		if errors.Is(err, forms.ErrLicenseExpired) {
+			respondError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		if errors.Is(err, forms.ErrCallRequired) {
+			respondError(w, http.StatusConflict, err.Error())
+			return
+		}
+		if errors.Is(err, forms.ErrHolidayBlackout) {
+			respondError(w, http.StatusConflict, err.Error())
+			return
+		}
 		if errors.Is(err, sql.ErrNoRows) {
 			respondError(w, http.StatusNotFound, err.Error())
 			return
@@ -432,7 +622,7 @@ func (h *FormsHandler) UpdateLawnForm(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, lawnFormToResponse(lawnForm))
 }
 
-// DeleteForm handles DELETE /api/forms/{id}
+// DeleteForm handles DELETE /api/forms/{id} - moves the form to the trash
 func (h *FormsHandler) DeleteForm(w http.ResponseWriter, r *http.Request) {
 	userID := getUserID(r)
 
@@ -452,5 +642,158 @@ func (h *FormsHandler) DeleteForm(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	respondSuccess(w, "Form deleted successfully")
+	respondSuccess(w, "Form moved to trash")
+}
+
+// parseAppID extracts the {appId} URL parameter as an integer
+func parseAppID(r *http.Request) (int, error) {
+	appID, err := strconv.Atoi(chi.URLParam(r, "appId"))
+	if err != nil || appID <= 0 {
+		return 0, errors.New("Invalid application ID")
+	}
+	return appID, nil
+}
+
+// CreatePestApp handles POST /api/forms/{id}/applications
+func (h *FormsHandler) CreatePestApp(w http.ResponseWriter, r *http.Request) {
+	userID := getUserID(r)
+
+	formID := chi.URLParam(r, "id")
+	if formID == "" {
+		respondError(w, http.StatusBadRequest, "Form ID is required")
+		return
+	}
+
+	var req PesticideApplicationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	app, err := pestAppFromRequest(req)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := restrictApplicator(r, &app, true); err != nil {
+		respondError(w, http.StatusForbidden, err.Error())
+		return
+	}
+
+	created, err := h.repo.CreatePestApp(r.Context(), formID, userID, app)
+	if err != nil {
+		var valErr *forms.ValidationError
+		if errors.As(err, &valErr) {
+			respondValidationError(w, valErr)
+			return
+		}
+		if errors.Is(err, forms.ErrLicenseExpired) {
+			respondError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		if errors.Is(err, forms.ErrCallRequired) {
+			respondError(w, http.StatusConflict, err.Error())
+			return
+		}
+		if errors.Is(err, forms.ErrHolidayBlackout) {
+			respondError(w, http.StatusConflict, err.Error())
+			return
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, pestAppToResponse(created))
+}
+
+// UpdatePestApp handles PUT /api/forms/{id}/applications/{appId}
+func (h *FormsHandler) UpdatePestApp(w http.ResponseWriter, r *http.Request) {
+	userID := getUserID(r)
+
+	formID := chi.URLParam(r, "id")
+	if formID == "" {
+		respondError(w, http.StatusBadRequest, "Form ID is required")
+		return
+	}
+
+	appID, err := parseAppID(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var req PesticideApplicationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	app, err := pestAppFromRequest(req)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	app.ID = appID
+	if err := restrictApplicator(r, &app, false); err != nil {
+		respondError(w, http.StatusForbidden, err.Error())
+		return
+	}
+
+	updated, err := h.repo.UpdatePestAppById(r.Context(), formID, userID, app)
+	if err != nil {
+		var valErr *forms.ValidationError
+		if errors.As(err, &valErr) {
+			respondValidationError(w, valErr)
+			return
+		}
+		if errors.Is(err, forms.ErrCallRequired) {
+			respondError(w, http.StatusConflict, err.Error())
+			return
+		}
+		if errors.Is(err, forms.ErrHolidayBlackout) {
+			respondError(w, http.StatusConflict, err.Error())
+			return
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, pestAppToResponse(updated))
+}
+
+// DeletePestApp handles DELETE /api/forms/{id}/applications/{appId}
+func (h *FormsHandler) DeletePestApp(w http.ResponseWriter, r *http.Request) {
+	userID := getUserID(r)
+
+	formID := chi.URLParam(r, "id")
+	if formID == "" {
+		respondError(w, http.StatusBadRequest, "Form ID is required")
+		return
+	}
+
+	appID, err := parseAppID(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = h.repo.DeletePestAppById(r.Context(), formID, userID, appID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondSuccess(w, "Application deleted successfully")
 }
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/holidays"
+)
+
+// HolidayResponse represents a holiday or Shabbat during which is_holiday customers are not serviced
+type HolidayResponse struct {
+	Name       string    `json:"name"`
+	Kind       string    `json:"kind"`
+	HebrewDate string    `json:"hebrew_date"`
+	FirstDay   string    `json:"first_day"`
+	LastDay    string    `json:"last_day"`
+	Start      time.Time `json:"start"`
+	End        time.Time `json:"end"`
+}
+
+// ListHolidaysResponse lists the holidays and Sabbaths of a year
+type ListHolidaysResponse struct {
+	Year     int               `json:"year"`
+	TimeZone string            `json:"time_zone"`
+	Holidays []HolidayResponse `json:"holidays"`
+	Count    int               `json:"count"`
+}
+
+// ListHolidays handles GET /api/holidays?year=2026 - the Jewish holidays and Sabbaths beginning
+// in year (default: the current year), with the times they begin and end at the company's location
+func ListHolidays(w http.ResponseWriter, r *http.Request) {
+	year := time.Now().Year()
+	if yearStr := r.URL.Query().Get("year"); yearStr != "" {
+		parsed, err := strconv.Atoi(yearStr)
+		if err != nil || parsed < 1 || parsed > 9999 {
+			respondError(w, http.StatusBadRequest, "Invalid year")
+			return
+		}
+		year = parsed
+	}
+
+	loc := holidays.LocationFromEnv()
+	windows := holidays.ForYear(year, loc)
+	resp := ListHolidaysResponse{
+		Year:     year,
+		TimeZone: loc.TimeZone.String(),
+		Holidays: make([]HolidayResponse, 0, len(windows)),
+		Count:    len(windows),
+	}
+	for _, window := range windows {
+		resp.Holidays = append(resp.Holidays, HolidayResponse{
+			Name:       window.Name,
+			Kind:       window.Kind,
+			HebrewDate: window.HebrewDate.String(),
+			FirstDay:   window.FirstDay.Format(licenseDateLayout),
+			LastDay:    window.LastDay.Format(licenseDateLayout),
+			Start:      window.Start,
+			End:        window.End,
+		})
+	}
+
+	respondJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,413 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/forms"
+	"github.com/shopspring/decimal"
+)
+
+// maxImportBytes caps the size of an import upload
+const maxImportBytes = 10 << 20
+
+// ImportFormRequest is one form in a JSON import
+type ImportFormRequest struct {
+	FormType     string                        `json:"form_type"`
+	PropertyID   string                        `json:"property_id,omitempty"`
+	FirstName    string                        `json:"first_name"`
+	LastName     string                        `json:"last_name"`
+	StreetNumber string                        `json:"street_number"`
+	StreetName   string                        `json:"street_name"`
+	Town         string                        `json:"town"`
+	ZipCode      string                        `json:"zip_code"`
+	HomePhone    string                        `json:"home_phone"`
+	OtherPhone   string                        `json:"other_phone"`
+	CallBefore   bool                          `json:"call_before"`
+	IsHoliday    bool                          `json:"is_holiday"`
+	FleaOnly     bool                          `json:"flea_only"`
+	LawnAreaSqFt int                           `json:"lawn_area_sq_ft"`
+	FertOnly     bool                          `json:"fert_only"`
+	Applications []PesticideApplicationRequest `json:"applications,omitempty"`
+}
+
+type ImportedFormResponse struct {
+	Row    int    `json:"row"`
+	FormID string `json:"form_id,omitempty"`
+}
+
+type ImportErrorResponse struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// ImportFormsResponse reports an import. Rows are numbered by CSV line (the header is line 1)
+// or by position in the JSON array, starting at 1.
+type ImportFormsResponse struct {
+	Mode          string                 `json:"mode"`
+	DryRun        bool                   `json:"dry_run"`
+	Committed     bool                   `json:"committed"`
+	Total         int                    `json:"total"`
+	ImportedCount int                    `json:"imported_count"`
+	ErrorCount    int                    `json:"error_count"`
+	Imported      []ImportedFormResponse `json:"imported"`
+	Errors        []ImportErrorResponse  `json:"errors"`
+}
+
+// importCSVColumns are the columns accepted in a CSV import. Rows sharing a form_ref are one
+// form: the first supplies the form fields and every row may add an application.
+var importCSVColumns = []string{
+	"form_ref",
+	"form_type",
+	"property_id",
+	"first_name",
+	"last_name",
+	"street_number",
+	"street_name",
+	"town",
+	"zip_code",
+	"home_phone",
+	"other_phone",
+	"call_before",
+	"is_holiday",
+	"flea_only",
+	"lawn_area_sq_ft",
+	"fert_only",
+	"chem_used",
+	"app_timestamp",
+	"rate",
+	"amount_applied",
+	"location_code",
+	"rate_amount",
+	"rate_unit",
+	"rate_basis",
+	"application_method",
+	"wind_speed_mph",
+	"wind_direction",
+	"temperature_f",
+	"sky_conditions",
+	"applied_by",
+}
+
+// importApplicationColumns are the CSV columns describing an application
+var importApplicationColumns = []string{
+	"chem_used", "app_timestamp", "rate", "amount_applied", "location_code", "rate_amount", "rate_unit", "rate_basis",
+	"application_method", "wind_speed_mph", "wind_direction", "temperature_f", "sky_conditions",
+}
+
+// parseImportTimestamp accepts an RFC 3339 timestamp or a plain date, as written on paper cards
+func parseImportTimestamp(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+// parseImportCSV reads a CSV import into rows. Problems with single values are recorded on
+// their row; an error is only returned when the file itself cannot be read.
+func parseImportCSV(body io.Reader) ([]forms.ImportRow, error) {
+	reader := csv.NewReader(body)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("error reading CSV header: %w", err)
+	}
+	known := map[string]bool{}
+	for _, column := range importCSVColumns {
+		known[column] = true
+	}
+	columns := map[string]int{}
+	for i, column := range header {
+		column = strings.ToLower(strings.TrimSpace(column))
+		if !known[column] {
+			return nil, fmt.Errorf("unknown CSV column %q", column)
+		}
+		columns[column] = i
+	}
+	if _, ok := columns["form_type"]; !ok {
+		return nil, errors.New("CSV is missing the form_type column")
+	}
+
+	var rows []*forms.ImportRow
+	byRef := map[string]*forms.ImportRow{}
+	line := 1
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, fmt.Errorf("error reading CSV line %d: %w", line, err)
+		}
+
+		get := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		row, grouped := byRef[get("form_ref")]
+		if !grouped {
+			row = &forms.ImportRow{
+				Row:          line,
+				FormType:     strings.ToLower(get("form_type")),
+				PropertyID:   get("property_id"),
+				FirstName:    get("first_name"),
+				LastName:     get("last_name"),
+				StreetNumber: get("street_number"),
+				StreetName:   get("street_name"),
+				Town:         get("town"),
+				ZipCode:      get("zip_code"),
+				HomePhone:    get("home_phone"),
+				OtherPhone:   get("other_phone"),
+			}
+			fail := func(field, message string) {
+				row.Errors = append(row.Errors, forms.ImportError{Row: line, Field: field, Message: message})
+			}
+			parseBool := func(column string) bool {
+				value := get(column)
+				if value == "" {
+					return false
+				}
+				b, err := strconv.ParseBool(value)
+				if err != nil {
+					fail(column, fmt.Sprintf("invalid boolean %q", value))
+				}
+				return b
+			}
+			row.CallBefore = parseBool("call_before")
+			row.IsHoliday = parseBool("is_holiday")
+			row.FleaOnly = parseBool("flea_only")
+			row.FertOnly = parseBool("fert_only")
+			if value := get("lawn_area_sq_ft"); value != "" {
+				area, err := strconv.Atoi(value)
+				if err != nil {
+					fail("lawn_area_sq_ft", fmt.Sprintf("invalid number %q", value))
+				}
+				row.LawnAreaSqFt = area
+			}
+
+			rows = append(rows, row)
+			if ref := get("form_ref"); ref != "" {
+				byRef[ref] = row
+			}
+		}
+
+		hasApplication := false
+		for _, column := range importApplicationColumns {
+			if get(column) != "" {
+				hasApplication = true
+			}
+		}
+		if !hasApplication {
+			continue
+		}
+
+		prefix := fmt.Sprintf("applications[%d].", len(row.Applications))
+		fail := func(field, message string) {
+			row.Errors = append(row.Errors, forms.ImportError{Row: line, Field: prefix + field, Message: message})
+		}
+		app := forms.PestApp{
+			Rate:              get("rate"),
+			LocationCode:      strings.ToUpper(get("location_code")),
+			ApplicationMethod: strings.ToLower(get("application_method")),
+			AppliedBy:         get("applied_by"),
+			Weather: forms.Weather{
+				WindDirection: strings.ToUpper(get("wind_direction")),
+				SkyConditions: strings.ToLower(get("sky_conditions")),
+			},
+		}
+		if value := get("chem_used"); value != "" {
+			chemID, err := strconv.Atoi(value)
+			if err != nil {
+				fail("chem_used", fmt.Sprintf("invalid chemical ID %q", value))
+			}
+			app.ChemUsed = chemID
+		}
+		if value := get("app_timestamp"); value != "" {
+			appTime, err := parseImportTimestamp(value)
+			if err != nil {
+				fail("app_timestamp", fmt.Sprintf("invalid date %q, expected YYYY-MM-DD or RFC 3339", value))
+			}
+			app.AppTimestamp = appTime
+		}
+		if value := get("amount_applied"); value != "" {
+			amount, err := decimal.NewFromString(value)
+			if err != nil {
+				fail("amount_applied", fmt.Sprintf("invalid amount %q", value))
+			}
+			app.AmountApplied = amount
+		}
+		if value := get("rate_amount"); value != "" {
+			amount, err := decimal.NewFromString(value)
+			if err != nil {
+				fail("rate_amount", fmt.Sprintf("invalid amount %q", value))
+			}
+			app.StructuredRate = &forms.StructuredRate{
+				Amount: amount,
+				Unit:   get("rate_unit"),
+				Basis:  get("rate_basis"),
+			}
+		} else if get("rate_unit") != "" || get("rate_basis") != "" {
+			fail("rate_amount", "rate_amount is required with rate_unit and rate_basis")
+		}
+		if value := get("wind_speed_mph"); value != "" {
+			speed, err := decimal.NewFromString(value)
+			if err != nil {
+				fail("wind_speed_mph", fmt.Sprintf("invalid wind speed %q", value))
+			}
+			app.Weather.WindSpeedMph = &speed
+		}
+		if value := get("temperature_f"); value != "" {
+			temp, err := decimal.NewFromString(value)
+			if err != nil {
+				fail("temperature_f", fmt.Sprintf("invalid temperature %q", value))
+			}
+			app.Weather.TemperatureF = &temp
+		}
+		row.Applications = append(row.Applications, app)
+	}
+
+	result := make([]forms.ImportRow, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, *row)
+	}
+	return result, nil
+}
+
+// parseImportJSON reads a JSON array of forms into rows
+func parseImportJSON(body io.Reader) ([]forms.ImportRow, error) {
+	var reqs []ImportFormRequest
+	if err := json.NewDecoder(body).Decode(&reqs); err != nil {
+		return nil, err
+	}
+
+	rows := make([]forms.ImportRow, 0, len(reqs))
+	for i, req := range reqs {
+		row := forms.ImportRow{
+			Row:          i + 1,
+			FormType:     req.FormType,
+			PropertyID:   req.PropertyID,
+			FirstName:    req.FirstName,
+			LastName:     req.LastName,
+			StreetNumber: req.StreetNumber,
+			StreetName:   req.StreetName,
+			Town:         req.Town,
+			ZipCode:      req.ZipCode,
+			HomePhone:    req.HomePhone,
+			OtherPhone:   req.OtherPhone,
+			CallBefore:   req.CallBefore,
+			IsHoliday:    req.IsHoliday,
+			FleaOnly:     req.FleaOnly,
+			LawnAreaSqFt: req.LawnAreaSqFt,
+			FertOnly:     req.FertOnly,
+		}
+		for j, appReq := range req.Applications {
+			app, err := pestAppFromRequest(appReq)
+			if err != nil {
+				row.Errors = append(row.Errors, forms.ImportError{
+					Row:     row.Row,
+					Field:   fmt.Sprintf("applications[%d].app_timestamp", j),
+					Message: err.Error(),
+				})
+			}
+			row.Applications = append(row.Applications, app)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// ImportForms handles POST /api/admin/forms/import?mode=all&dry_run=true
+// The body is a CSV file (Content-Type: text/csv) or a JSON array of forms. mode is "all"
+// (the default, nothing is saved unless every row is valid) or "valid_only"; dry_run reports
+// what would happen without saving and force skips duplicate detection.
+// Responds 201 when forms were saved, 200 for a dry run and 422 when an "all" import was rejected.
+func (h *FormsHandler) ImportForms(w http.ResponseWriter, r *http.Request) {
+	adminID := getUserID(r)
+	query := r.URL.Query()
+
+	mode := query.Get("mode")
+	if mode == "" {
+		mode = forms.ImportModeAll
+	}
+	dryRun, _ := strconv.ParseBool(query.Get("dry_run"))
+
+	body := http.MaxBytesReader(w, r.Body, maxImportBytes)
+	var (
+		rows []forms.ImportRow
+		err  error
+	)
+	contentType := strings.ToLower(r.Header.Get("Content-Type"))
+	if strings.HasPrefix(contentType, "text/csv") || strings.HasPrefix(contentType, "application/csv") {
+		rows, err = parseImportCSV(body)
+	} else {
+		rows, err = parseImportJSON(body)
+	}
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(rows) == 0 {
+		respondError(w, http.StatusBadRequest, "No forms to import")
+		return
+	}
+
+	result, err := h.repo.ImportForms(r.Context(), rows, forms.ImportFormsOptions{
+		CreatedBy: adminID,
+		Mode:      mode,
+		DryRun:    dryRun,
+		Force:     parseForce(r),
+	})
+	if err != nil {
+		if errors.Is(err, forms.ErrInvalidImportMode) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp := ImportFormsResponse{
+		Mode:          mode,
+		DryRun:        dryRun,
+		Committed:     result.Committed,
+		Total:         result.Total,
+		ImportedCount: len(result.Imported),
+		ErrorCount:    len(result.Errors),
+		Imported:      make([]ImportedFormResponse, 0, len(result.Imported)),
+		Errors:        make([]ImportErrorResponse, 0, len(result.Errors)),
+	}
+	for _, imported := range result.Imported {
+		resp.Imported = append(resp.Imported, ImportedFormResponse{
+			Row:    imported.Row,
+			FormID: imported.FormID,
+		})
+	}
+	for _, importErr := range result.Errors {
+		resp.Errors = append(resp.Errors, ImportErrorResponse{
+			Row:     importErr.Row,
+			Field:   importErr.Field,
+			Message: importErr.Message,
+		})
+	}
+
+	status := http.StatusOK
+	if result.Committed {
+		status = http.StatusCreated
+	} else if !dryRun {
+		status = http.StatusUnprocessableEntity
+	}
+	respondJSON(w, status, resp)
+}
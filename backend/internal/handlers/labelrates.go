@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/forms"
+	"github.com/go-chi/chi/v5"
+)
+
+// OverLabelAppResponse represents an application over its chemical's label maximum
+type OverLabelAppResponse struct {
+	PesticideApplicationResponse
+	FormID        string            `json:"form_id"`
+	FormType      string            `json:"form_type"`
+	FirstName     string            `json:"first_name"`
+	LastName      string            `json:"last_name"`
+	StreetNumber  string            `json:"street_number"`
+	StreetName    string            `json:"street_name"`
+	Town          string            `json:"town"`
+	CreatedBy     string            `json:"created_by"`
+	CreatedByName string            `json:"created_by_name"`
+	BrandName     string            `json:"brand_name"`
+	Unit          string            `json:"unit"`
+	LabelRate     LabelRateResponse `json:"label_rate"`
+}
+
+// ListOverLabelAppsResponse lists applications over their chemical's label maximum
+type ListOverLabelAppsResponse struct {
+	Applications []OverLabelAppResponse `json:"applications"`
+	Count        int                    `json:"count"`
+}
+
+// ListOverLabelApps handles GET /api/admin/forms/over-label?include_acknowledged=true - applications
+// over their chemical's label maximum, by default only those awaiting supervisor acknowledgement
+func (h *FormsHandler) ListOverLabelApps(w http.ResponseWriter, r *http.Request) {
+	includeAcknowledged, _ := strconv.ParseBool(r.URL.Query().Get("include_acknowledged"))
+
+	apps, err := h.repo.ListOverLabelApps(r.Context(), includeAcknowledged)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp := ListOverLabelAppsResponse{
+		Applications: make([]OverLabelAppResponse, 0, len(apps)),
+		Count:        len(apps),
+	}
+	for _, app := range apps {
+		resp.Applications = append(resp.Applications, OverLabelAppResponse{
+			PesticideApplicationResponse: pestAppToResponse(app.PestApp),
+			FormID:                       app.FormID,
+			FormType:                     app.FormType,
+			FirstName:                    app.FirstName,
+			LastName:                     app.LastName,
+			StreetNumber:                 app.StreetNumber,
+			StreetName:                   app.StreetName,
+			Town:                         app.Town,
+			CreatedBy:                    app.CreatedBy,
+			CreatedByName:                app.CreatedByName,
+			BrandName:                    app.BrandName,
+			Unit:                         app.Unit,
+			LabelRate: LabelRateResponse{
+				Min:   app.LabelRate.Min,
+				Max:   app.LabelRate.Max,
+				Unit:  app.LabelRate.Unit,
+				Basis: app.LabelRate.Basis,
+			},
+		})
+	}
+
+	respondJSON(w, http.StatusOK, resp)
+}
+
+// AcknowledgeOverLabelApp handles POST /api/admin/forms/{id}/applications/{appId}/acknowledge -
+// a supervisor signs off on an application over its chemical's label maximum
+func (h *FormsHandler) AcknowledgeOverLabelApp(w http.ResponseWriter, r *http.Request) {
+	supervisorID := getUserID(r)
+
+	formID := chi.URLParam(r, "id")
+	if formID == "" {
+		respondError(w, http.StatusBadRequest, "Form ID is required")
+		return
+	}
+
+	appID, err := parseAppID(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	app, err := h.repo.AcknowledgeOverLabelMax(r.Context(), formID, appID, supervisorID)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			respondError(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, forms.ErrNotOverLabelMax):
+			respondError(w, http.StatusConflict, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, pestAppToResponse(app))
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/forms"
+)
+
+// MergeForms handles POST /api/admin/forms/merge - folds duplicate forms into a surviving form
+func (h *FormsHandler) MergeForms(w http.ResponseWriter, r *http.Request) {
+	adminID := getUserID(r)
+
+	var req MergeFormsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.SurvivorID == "" || len(req.DuplicateIDs) == 0 {
+		respondError(w, http.StatusBadRequest, "survivor_id and duplicate_ids are required")
+		return
+	}
+
+	merge, err := h.repo.MergeForms(r.Context(), forms.MergeFormsInput{
+		SurvivorID:   req.SurvivorID,
+		DuplicateIDs: req.DuplicateIDs,
+		FieldSources: req.FieldSources,
+		MergedBy:     adminID,
+	})
+	if err != nil {
+		if errors.Is(err, forms.ErrInvalidMerge) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "One or more forms not found")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, FormMergeResponse{
+		ID:             merge.ID,
+		SurvivorID:     merge.SurvivorID,
+		MergedIDs:      merge.MergedIDs,
+		MergedBy:       merge.MergedBy,
+		MergedAt:       merge.MergedAt,
+		FieldSources:   merge.FieldSources,
+		ApplicationIDs: merge.ApplicationIDs,
+		NoteIDs:        merge.NoteIDs,
+	})
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// parseNoteID extracts the {noteId} URL parameter as an integer
+func parseNoteID(r *http.Request) (int, error) {
+	noteID, err := strconv.Atoi(chi.URLParam(r, "noteId"))
+	if err != nil || noteID <= 0 {
+		return 0, errors.New("Invalid note ID")
+	}
+	return noteID, nil
+}
+
+// decodeNoteRequest parses and validates a note request body
+func decodeNoteRequest(r *http.Request) (NoteRequest, error) {
+	var req NoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return NoteRequest{}, err
+	}
+
+	req.Message = strings.TrimSpace(req.Message)
+	if req.Message == "" {
+		return NoteRequest{}, errors.New("message is required")
+	}
+	return req, nil
+}
+
+// ListNotes handles GET /api/forms/{id}/notes
+func (h *FormsHandler) ListNotes(w http.ResponseWriter, r *http.Request) {
+	userID := getUserID(r)
+
+	formID := chi.URLParam(r, "id")
+	if formID == "" {
+		respondError(w, http.StatusBadRequest, "Form ID is required")
+		return
+	}
+
+	notes, err := h.repo.ListNotesByFormId(r.Context(), formID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	noteResponses := notesToResponse(notes)
+	respondJSON(w, http.StatusOK, ListNotesResponse{
+		Notes: noteResponses,
+		Count: len(noteResponses),
+	})
+}
+
+// CreateNote handles POST /api/forms/{id}/notes
+func (h *FormsHandler) CreateNote(w http.ResponseWriter, r *http.Request) {
+	userID := getUserID(r)
+
+	formID := chi.URLParam(r, "id")
+	if formID == "" {
+		respondError(w, http.StatusBadRequest, "Form ID is required")
+		return
+	}
+
+	req, err := decodeNoteRequest(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	note, err := h.repo.CreateNote(r.Context(), formID, userID, req.Message)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, noteToResponse(note))
+}
+
+// UpdateNote handles PUT /api/forms/{id}/notes/{noteId}
+func (h *FormsHandler) UpdateNote(w http.ResponseWriter, r *http.Request) {
+	userID := getUserID(r)
+
+	formID := chi.URLParam(r, "id")
+	if formID == "" {
+		respondError(w, http.StatusBadRequest, "Form ID is required")
+		return
+	}
+
+	noteID, err := parseNoteID(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	req, err := decodeNoteRequest(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	note, err := h.repo.UpdateNoteById(r.Context(), formID, userID, noteID, req.Message)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, noteToResponse(note))
+}
+
+// DeleteNote handles DELETE /api/forms/{id}/notes/{noteId}
+func (h *FormsHandler) DeleteNote(w http.ResponseWriter, r *http.Request) {
+	userID := getUserID(r)
+
+	formID := chi.URLParam(r, "id")
+	if formID == "" {
+		respondError(w, http.StatusBadRequest, "Form ID is required")
+		return
+	}
+
+	noteID, err := parseNoteID(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = h.repo.DeleteNoteById(r.Context(), formID, userID, noteID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondSuccess(w, "Note deleted successfully")
+}
@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/forms"
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/pdf"
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	// pdfPacketRetention is how long a background packet can be downloaded
+	pdfPacketRetention = 24 * time.Hour
+	// pdfPacketTimeout bounds how long a background packet may take to render
+	pdfPacketTimeout = 10 * time.Minute
+)
+
+// pdfPacketSyncLimit returns the largest packet rendered during the request; larger packets
+// are rendered in the background
+func pdfPacketSyncLimit() int {
+	limit, err := strconv.Atoi(os.Getenv("PDF_PACKET_SYNC_LIMIT"))
+	if err != nil || limit < 0 {
+		limit = 25 // Fallback when unset or invalid
+	}
+	return limit
+}
+
+// CreatePdfPacketRequest selects the forms of a PDF packet: either form_ids,
+// or any of the date, creator and zip code filters of the form lists
+type CreatePdfPacketRequest struct {
+	FormIDs   []string  `json:"form_ids"`
+	DateLow   time.Time `json:"date_low"`
+	DateHigh  time.Time `json:"date_high"`
+	CreatedBy string    `json:"created_by"`
+	ZipCode   string    `json:"zip_code"`
+}
+
+// PdfPacketResponse represents a PDF packet rendered in the background
+type PdfPacketResponse struct {
+	ID          string     `json:"id"`
+	Status      string     `json:"status"`
+	FormCount   int        `json:"form_count"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	// DownloadURL is set once the packet is ready
+	DownloadURL string `json:"download_url,omitempty"`
+}
+
+func pdfPacketToResponse(packet forms.PdfPacket) PdfPacketResponse {
+	response := PdfPacketResponse{
+		ID:          packet.ID,
+		Status:      packet.Status,
+		FormCount:   len(packet.FormIDs),
+		CreatedAt:   packet.CreatedAt,
+		CompletedAt: packet.CompletedAt,
+		Error:       packet.Error,
+	}
+	if packet.Status == forms.PacketStatusReady {
+		response.DownloadURL = fmt.Sprintf("/api/admin/forms/pdf-packet/%s/pdf", packet.ID)
+	}
+	return response
+}
+
+// packetCriteria describes how a packet's forms were chosen, for its cover page
+func packetCriteria(sel forms.PacketSelection, printouts []*forms.Printout) []string {
+	if len(sel.FormIDs) > 0 {
+		return []string{"Selected forms"}
+	}
+
+	criteria := []string{}
+	filters := sel.Filters
+	if !filters.DateLow.IsZero() {
+		criteria = append(criteria, "First application on or after "+filters.DateLow.Local().Format("01/02/2006"))
+	}
+	if !filters.DateHigh.IsZero() {
+		criteria = append(criteria, "Last application on or before "+filters.DateHigh.Local().Format("01/02/2006"))
+	}
+	if filters.CreatedBy != "" {
+		createdBy := filters.CreatedBy
+		if len(printouts) > 0 {
+			createdBy = printouts[0].CreatedByName
+		}
+		criteria = append(criteria, "Created by "+createdBy)
+	}
+	if filters.ZipCode != "" {
+		criteria = append(criteria, "Zip code "+filters.ZipCode)
+	}
+	return criteria
+}
+
+// renderPdfPacket renders the packet of the given forms
+func (h *FormsHandler) renderPdfPacket(ctx context.Context, sel forms.PacketSelection, formIDs []string) ([]byte, error) {
+	printouts := make([]*forms.Printout, 0, len(formIDs))
+	for _, formID := range formIDs {
+		printout, err := h.repo.GetFormPrintoutById(ctx, formID, "")
+		if err != nil {
+			return nil, fmt.Errorf("error loading form %s: %w", formID, err)
+		}
+		printouts = append(printouts, printout)
+	}
+
+	var doc bytes.Buffer
+	if err := pdf.Packet(&doc, packetCriteria(sel, printouts), printouts, companyFromEnv()); err != nil {
+		return nil, err
+	}
+	return doc.Bytes(), nil
+}
+
+// buildPdfPacket renders a background packet and stores the result
+func (h *FormsHandler) buildPdfPacket(packetID string, sel forms.PacketSelection, formIDs []string) {
+	ctx, cancel := context.WithTimeout(context.Background(), pdfPacketTimeout)
+	defer cancel()
+
+	doc, err := h.renderPdfPacket(ctx, sel, formIDs)
+	if err == nil {
+		err = h.repo.CompletePdfPacket(ctx, packetID, doc)
+	}
+	if err != nil {
+		log.Printf("pdf packet %s failed: %v", packetID, err)
+		if err := h.repo.FailPdfPacket(context.Background(), packetID, err.Error()); err != nil {
+			log.Printf("pdf packet %s could not be marked failed: %v", packetID, err)
+		}
+	}
+}
+
+// CreatePdfPacket handles POST /api/admin/forms/pdf-packet - one PDF with a cover page and every selected form.
+// Packets of up to PDF_PACKET_SYNC_LIMIT forms are returned directly; larger ones are rendered in the
+// background and answered with 202 and the packet, to be polled and downloaded once ready.
+func (h *FormsHandler) CreatePdfPacket(w http.ResponseWriter, r *http.Request) {
+	userID := getUserID(r)
+
+	var req CreatePdfPacketRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	hasFilters := !req.DateLow.IsZero() || !req.DateHigh.IsZero() || req.CreatedBy != "" || req.ZipCode != ""
+	if len(req.FormIDs) > 0 && hasFilters {
+		respondError(w, http.StatusBadRequest, "Use either form_ids or filters, not both")
+		return
+	}
+
+	sel := forms.PacketSelection{
+		FormIDs: req.FormIDs,
+		Filters: forms.ListFormsOptions{
+			DateLow:   req.DateLow,
+			DateHigh:  req.DateHigh,
+			CreatedBy: req.CreatedBy,
+			ZipCode:   req.ZipCode,
+		},
+	}
+	formIDs, err := h.repo.SelectPacketForms(r.Context(), sel)
+	if err != nil {
+		switch {
+		case errors.Is(err, forms.ErrEmptyPacketSelection):
+			respondError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, forms.ErrPacketFormNotFound):
+			respondError(w, http.StatusNotFound, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	if len(formIDs) == 0 {
+		respondError(w, http.StatusNotFound, "No forms match the filters")
+		return
+	}
+
+	if len(formIDs) <= pdfPacketSyncLimit() {
+		doc, err := h.renderPdfPacket(r.Context(), sel, formIDs)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		respondPDF(w, "attachment", fmt.Sprintf("forms-packet-%s.pdf", time.Now().Format("2006-01-02")), doc)
+		return
+	}
+
+	packet, err := h.repo.CreatePdfPacket(r.Context(), userID, formIDs, pdfPacketRetention)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	go h.buildPdfPacket(packet.ID, sel, formIDs)
+
+	w.Header().Set("Location", "/api/admin/forms/pdf-packet/"+packet.ID)
+	respondJSON(w, http.StatusAccepted, pdfPacketToResponse(packet))
+}
+
+// GetPdfPacket handles GET /api/admin/forms/pdf-packet/{packetId} - the status of a background packet
+func (h *FormsHandler) GetPdfPacket(w http.ResponseWriter, r *http.Request) {
+	packet, err := h.repo.GetPdfPacketById(r.Context(), chi.URLParam(r, "packetId"))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, pdfPacketToResponse(packet))
+}
+
+// DownloadPdfPacket handles GET /api/admin/forms/pdf-packet/{packetId}/pdf - a ready background packet.
+// Returns 409 while the packet is pending or if it failed.
+func (h *FormsHandler) DownloadPdfPacket(w http.ResponseWriter, r *http.Request) {
+	packetID := chi.URLParam(r, "packetId")
+
+	packet, err := h.repo.GetPdfPacketById(r.Context(), packetID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	switch packet.Status {
+	case forms.PacketStatusPending:
+		respondError(w, http.StatusConflict, "Packet is not ready yet")
+		return
+	case forms.PacketStatusFailed:
+		respondError(w, http.StatusConflict, "Packet failed: "+packet.Error)
+		return
+	}
+
+	doc, err := h.repo.GetPdfPacketDocument(r.Context(), packetID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondPDF(w, "attachment", fmt.Sprintf("forms-packet-%s.pdf", packet.CreatedAt.Format("2006-01-02")), doc)
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/pdf"
+	"github.com/go-chi/chi/v5"
+)
+
+// companyFromEnv returns the company header printed on form PDFs.
+// COMPANY_ADDRESS separates lines with semicolons.
+func companyFromEnv() pdf.Company {
+	company := pdf.Company{
+		Name:                         os.Getenv("COMPANY_NAME"),
+		Phone:                        os.Getenv("COMPANY_PHONE"),
+		LicenseNo:                    os.Getenv("COMPANY_LICENSE_NO"),
+		ResponsibleApplicator:        os.Getenv("RESPONSIBLE_APPLICATOR"),
+		ResponsibleApplicatorLicense: os.Getenv("RESPONSIBLE_APPLICATOR_LICENSE"),
+	}
+	for _, line := range strings.Split(os.Getenv("COMPANY_ADDRESS"), ";") {
+		if line = strings.TrimSpace(line); line != "" {
+			company.Address = append(company.Address, line)
+		}
+	}
+	return company
+}
+
+// respondPDF writes a rendered PDF document
+func respondPDF(w http.ResponseWriter, disposition string, filename string, doc []byte) {
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`%s; filename="%s"`, disposition, filename))
+	w.WriteHeader(http.StatusOK)
+	w.Write(doc)
+}
+
+// GetFormPDF handles GET /api/forms/{id}/pdf - the form's card as a PDF, laid out like the print page
+func (h *FormsHandler) GetFormPDF(w http.ResponseWriter, r *http.Request) {
+	userID := getUserID(r)
+
+	formID := chi.URLParam(r, "id")
+	if formID == "" {
+		respondError(w, http.StatusBadRequest, "Form ID is required")
+		return
+	}
+
+	printout, err := h.repo.GetFormPrintoutById(r.Context(), formID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	var doc bytes.Buffer
+	if err := pdf.Form(&doc, printout, companyFromEnv()); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondPDF(w, "inline", fmt.Sprintf("form-%s.pdf", formID), doc.Bytes())
+}
@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/pdf"
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/reports"
+)
+
+// ReportsHandler handles the admin report endpoints
+type ReportsHandler struct {
+	repo *reports.ReportsRepository
+}
+
+// NewReportsHandler creates a new reports handler with the given repository
+func NewReportsHandler(repo *reports.ReportsRepository) *ReportsHandler {
+	return &ReportsHandler{repo: repo}
+}
+
+// PesticideUsageRowResponse represents one row of the pesticide usage report
+type PesticideUsageRowResponse struct {
+	Month               int      `json:"month"`
+	EpaRegNo            string   `json:"epa_reg_no"`
+	Unit                string   `json:"unit"`
+	Town                string   `json:"town"`
+	BrandNames          []string `json:"brand_names"`
+	TotalAmount         string   `json:"total_amount"`
+	Applications        int      `json:"applications"`
+	Properties          int      `json:"properties"`
+	FlaggedApplications int      `json:"flagged_applications"`
+}
+
+// FlaggedApplicationResponse represents an application whose chemical changed after it was made
+type FlaggedApplicationResponse struct {
+	ApplicationID     int        `json:"application_id"`
+	FormID            string     `json:"form_id"`
+	AppTimestamp      time.Time  `json:"app_timestamp"`
+	ChemUsed          int        `json:"chem_used"`
+	BrandName         string     `json:"brand_name"`
+	EpaRegNo          string     `json:"epa_reg_no"`
+	Unit              string     `json:"unit"`
+	AmountApplied     string     `json:"amount_applied"`
+	Town              string     `json:"town"`
+	Reasons           []string   `json:"reasons"`
+	ChemicalEditedAt  *time.Time `json:"chemical_edited_at,omitempty"`
+	ChemicalDeletedAt *time.Time `json:"chemical_deleted_at,omitempty"`
+}
+
+// PesticideUsageResponse represents the annual pesticide usage report
+type PesticideUsageResponse struct {
+	Year    int                          `json:"year"`
+	Rows    []PesticideUsageRowResponse  `json:"rows"`
+	Flagged []FlaggedApplicationResponse `json:"flagged"`
+}
+
+var pesticideUsageColumns = []string{
+	"year",
+	"month",
+	"epa_reg_no",
+	"brand_names",
+	"town",
+	"unit",
+	"total_amount",
+	"applications",
+	"properties",
+	"flagged_applications",
+}
+
+func pesticideUsageToResponse(report reports.PesticideUsageReport) PesticideUsageResponse {
+	response := PesticideUsageResponse{
+		Year:    report.Year,
+		Rows:    make([]PesticideUsageRowResponse, 0, len(report.Rows)),
+		Flagged: make([]FlaggedApplicationResponse, 0, len(report.Flagged)),
+	}
+	for _, row := range report.Rows {
+		response.Rows = append(response.Rows, PesticideUsageRowResponse{
+			Month:               int(row.Month),
+			EpaRegNo:            row.EpaRegNo,
+			Unit:                row.Unit,
+			Town:                row.Town,
+			BrandNames:          row.BrandNames,
+			TotalAmount:         row.TotalAmount.String(),
+			Applications:        row.Applications,
+			Properties:          row.Properties,
+			FlaggedApplications: row.FlaggedApplications,
+		})
+	}
+	for _, app := range report.Flagged {
+		response.Flagged = append(response.Flagged, FlaggedApplicationResponse{
+			ApplicationID:     app.ApplicationID,
+			FormID:            app.FormID,
+			AppTimestamp:      app.AppTimestamp,
+			ChemUsed:          app.ChemUsed,
+			BrandName:         app.BrandName,
+			EpaRegNo:          app.EpaRegNo,
+			Unit:              app.Unit,
+			AmountApplied:     app.AmountApplied.String(),
+			Town:              app.Town,
+			Reasons:           app.Reasons,
+			ChemicalEditedAt:  app.ChemicalEditedAt,
+			ChemicalDeletedAt: app.ChemicalDeletedAt,
+		})
+	}
+	return response
+}
+
+// writePesticideUsageCSV writes one CSV record per report row
+func writePesticideUsageCSV(w http.ResponseWriter, report reports.PesticideUsageReport) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	writer.Write(pesticideUsageColumns)
+	for _, row := range report.Rows {
+		writer.Write([]string{
+			strconv.Itoa(report.Year),
+			strconv.Itoa(int(row.Month)),
+			row.EpaRegNo,
+			strings.Join(row.BrandNames, "; "),
+			row.Town,
+			row.Unit,
+			row.TotalAmount.String(),
+			strconv.Itoa(row.Applications),
+			strconv.Itoa(row.Properties),
+			strconv.Itoa(row.FlaggedApplications),
+		})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="pesticide-usage-%d.csv"`, report.Year))
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+// writePesticideUsagePDF renders the report as a PDF document
+func writePesticideUsagePDF(w http.ResponseWriter, report reports.PesticideUsageReport) {
+	var doc bytes.Buffer
+	if err := pdf.PesticideUsage(&doc, report); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondPDF(w, "attachment", fmt.Sprintf("pesticide-usage-%d.pdf", report.Year), doc.Bytes())
+}
+
+// GetPesticideUsage handles GET /api/admin/reports/pesticide-usage?year=2025&format=json
+// Year defaults to the current year; format is json (the default), csv or pdf.
+// The CSV has one record per report row; flagged applications appear in the JSON and PDF.
+func (h *ReportsHandler) GetPesticideUsage(w http.ResponseWriter, r *http.Request) {
+	year := time.Now().Year()
+	if yearStr := r.URL.Query().Get("year"); yearStr != "" {
+		parsed, err := strconv.Atoi(yearStr)
+		if err != nil || parsed < 1 {
+			respondError(w, http.StatusBadRequest, "Invalid year")
+			return
+		}
+		year = parsed
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "csv" && format != "pdf" {
+		respondError(w, http.StatusBadRequest, "format must be 'json', 'csv' or 'pdf'")
+		return
+	}
+
+	report, err := h.repo.GetPesticideUsage(r.Context(), year)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	switch format {
+	case "csv":
+		writePesticideUsageCSV(w, report)
+	case "pdf":
+		writePesticideUsagePDF(w, report)
+	default:
+		respondJSON(w, http.StatusOK, pesticideUsageToResponse(report))
+	}
+}
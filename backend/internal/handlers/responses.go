@@ -2,10 +2,16 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/BiryaniJedi/LandscapeForm-backend/internal/forms"
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/sitecodes"
 	"github.com/BiryaniJedi/LandscapeForm-backend/internal/users"
+	"github.com/shopspring/decimal"
 )
 
 // respondJSON writes a JSON response with the given status code
@@ -26,6 +32,20 @@ func respondError(w http.ResponseWriter, status int, message string) {
 	})
 }
 
+// respondValidationError writes the 400 response listing the fields that failed validation
+func respondValidationError(w http.ResponseWriter, valErr *forms.ValidationError) {
+	resp := ValidationErrorResponse{
+		Error:   http.StatusText(http.StatusBadRequest),
+		Message: "Some fields are invalid",
+		Fields:  make([]FieldErrorResponse, 0, len(valErr.Fields)),
+	}
+	for _, field := range valErr.Fields {
+		resp.Fields = append(resp.Fields, FieldErrorResponse{Field: field.Field, Message: field.Message})
+	}
+
+	respondJSON(w, http.StatusBadRequest, resp)
+}
+
 // respondSuccess writes a JSON success message
 func respondSuccess(w http.ResponseWriter, message string) {
 	respondJSON(w, http.StatusOK, SuccessResponse{
@@ -33,15 +53,58 @@ func respondSuccess(w http.ResponseWriter, message string) {
 	})
 }
 
+// labelWarning is the warning shown on an application over its label maximum until a supervisor acknowledges it
+const labelWarning = "Exceeds the chemical's label maximum rate; needs supervisor acknowledgement"
+
+// windRulesFromEnv returns the wind speed limits applications are warned against.
+// WIND_WARNING_RULES lists method=mph pairs, e.g. "spray=10,default=15".
+func windRulesFromEnv() forms.WindRules {
+	rules, err := forms.ParseWindRules(os.Getenv("WIND_WARNING_RULES"))
+	if err != nil || len(rules) == 0 {
+		rules = forms.WindRules{forms.ApplicationMethodSpray: decimal.NewFromInt(10)} // Fallback when unset or invalid
+	}
+	return rules
+}
+
 func pestAppToResponse(pestApp forms.PestApp) PesticideApplicationResponse {
-	return PesticideApplicationResponse{
-		ID:            pestApp.ID,
-		ChemUsed:      pestApp.ChemUsed,
-		AppTimestamp:  pestApp.AppTimestamp,
-		Rate:          pestApp.Rate,
-		AmountApplied: pestApp.AmountApplied,
-		LocationCode:  pestApp.LocationCode,
+	resp := PesticideApplicationResponse{
+		ID:                      pestApp.ID,
+		ChemUsed:                pestApp.ChemUsed,
+		AppTimestamp:            pestApp.AppTimestamp,
+		Rate:                    pestApp.Rate,
+		AmountApplied:           pestApp.AmountApplied,
+		LocationCode:            pestApp.LocationCode,
+		LocationDescription:     sitecodes.Describe(pestApp.LocationCode),
+		ExpectedAmount:          pestApp.ExpectedAmount,
+		OverLabelMax:            pestApp.OverLabelMax,
+		OverLabelAcknowledgedBy: pestApp.OverLabelAcknowledgedBy,
+		OverLabelAcknowledgedAt: pestApp.OverLabelAcknowledgedAt,
+		ApplicationMethod:       pestApp.ApplicationMethod,
+		WindSpeedMph:            pestApp.Weather.WindSpeedMph,
+		WindDirection:           pestApp.Weather.WindDirection,
+		TemperatureF:            pestApp.Weather.TemperatureF,
+		SkyConditions:           pestApp.Weather.SkyConditions,
+		WeatherWarning:          windRulesFromEnv().Warning(pestApp),
+		AppliedBy:               pestApp.AppliedBy,
+		AppliedByName:           pestApp.AppliedByName,
 	}
+	if license := pestApp.ApplicatorLicense; license != nil {
+		resp.ApplicatorLicense = licenseToResponse(&users.License{
+			Number:    license.Number,
+			Category:  license.Category,
+			State:     license.State,
+			ExpiresOn: license.ExpiresOn,
+		}, pestApp.AppTimestamp)
+	}
+	if rate := pestApp.StructuredRate; rate != nil {
+		resp.RateAmount = &rate.Amount
+		resp.RateUnit = rate.Unit
+		resp.RateBasis = rate.Basis
+	}
+	if pestApp.OverLabelMax && pestApp.OverLabelAcknowledgedAt == nil {
+		resp.LabelWarning = labelWarning
+	}
+	return resp
 }
 
 func pestAppsToResponse(pestApps []forms.PestApp) []PesticideApplicationResponse {
@@ -52,6 +115,36 @@ func pestAppsToResponse(pestApps []forms.PestApp) []PesticideApplicationResponse
 	return responses
 }
 
+func noteToResponse(note forms.Note) NoteResponse {
+	return NoteResponse{
+		ID:        note.ID,
+		CreatedBy: note.CreatedBy,
+		CreatedAt: note.CreatedAt,
+		UpdatedAt: note.UpdatedAt,
+		Message:   note.Message,
+	}
+}
+
+func notesToResponse(notes []forms.Note) []NoteResponse {
+	responses := make([]NoteResponse, 0, len(notes))
+	for _, note := range notes {
+		responses = append(responses, noteToResponse(note))
+	}
+	return responses
+}
+
+func revisionToResponse(revision forms.FormRevision) FormRevisionResponse {
+	return FormRevisionResponse{
+		ID:            revision.ID,
+		FormID:        revision.FormID,
+		ChangedBy:     revision.ChangedBy,
+		ChangedByName: revision.ChangedByName,
+		ChangedAt:     revision.ChangedAt,
+		Action:        revision.Action,
+		Diff:          revision.Diff,
+	}
+}
+
 func shrubFormToResponse(shrubForm forms.ShrubForm) ShrubFormResponse {
 	return ShrubFormResponse{
 		ID:           shrubForm.ID,
@@ -59,6 +152,8 @@ func shrubFormToResponse(shrubForm forms.ShrubForm) ShrubFormResponse {
 		CreatedAt:    shrubForm.CreatedAt,
 		UpdatedAt:    shrubForm.UpdatedAt,
 		FormType:     shrubForm.FormType,
+		PropertyID:   shrubForm.PropertyID,
+		ClonedFrom:   shrubForm.ClonedFrom,
 		FirstName:    shrubForm.FirstName,
 		LastName:     shrubForm.LastName,
 		StreetNumber: shrubForm.StreetNumber,
@@ -73,6 +168,7 @@ func shrubFormToResponse(shrubForm forms.ShrubForm) ShrubFormResponse {
 		LastAppDate:  shrubForm.LastAppDate,
 		FleaOnly:     shrubForm.FleaOnly,
 		PestApps:     pestAppsToResponse(shrubForm.AppTimes),
+		Notes:        notesToResponse(shrubForm.Notes),
 	}
 }
 
@@ -83,6 +179,8 @@ func lawnFormToResponse(lawnForm forms.LawnForm) LawnFormResponse {
 		CreatedAt:    lawnForm.CreatedAt,
 		UpdatedAt:    lawnForm.UpdatedAt,
 		FormType:     lawnForm.FormType,
+		PropertyID:   lawnForm.PropertyID,
+		ClonedFrom:   lawnForm.ClonedFrom,
 		FirstName:    lawnForm.FirstName,
 		LastName:     lawnForm.LastName,
 		StreetNumber: lawnForm.StreetNumber,
@@ -98,10 +196,25 @@ func lawnFormToResponse(lawnForm forms.LawnForm) LawnFormResponse {
 		LawnAreaSqFt: lawnForm.LawnAreaSqFt,
 		FertOnly:     lawnForm.FertOnly,
 		PestApps:     pestAppsToResponse(lawnForm.AppTimes),
+		Notes:        notesToResponse(lawnForm.Notes),
 	}
 }
 
 // formViewToResponse converts a FormView from the repository to a FormResponse for the API
+func formsPageToResponse(page forms.FormsPage) ListFormsResponse {
+	formResponses := make([]FormViewResponse, 0, len(page.Forms))
+	for _, view := range page.Forms {
+		formResponses = append(formResponses, formViewToResponse(view))
+	}
+	return ListFormsResponse{
+		Forms:      formResponses,
+		Count:      len(formResponses),
+		Total:      page.Total,
+		NextCursor: page.NextCursor,
+		PrevCursor: page.PrevCursor,
+	}
+}
+
 func formViewToResponse(view *forms.FormView) FormViewResponse {
 	resp := FormViewResponse{
 		FormType: view.FormType,
@@ -112,6 +225,8 @@ func formViewToResponse(view *forms.FormView) FormViewResponse {
 		resp.CreatedBy = view.Shrub.Form.CreatedBy
 		resp.CreatedAt = view.Shrub.Form.CreatedAt
 		resp.UpdatedAt = view.Shrub.Form.UpdatedAt
+		resp.PropertyID = view.Shrub.Form.PropertyID
+		resp.ClonedFrom = view.Shrub.Form.ClonedFrom
 		resp.FirstName = view.Shrub.Form.FirstName
 		resp.LastName = view.Shrub.Form.LastName
 		resp.StreetNumber = view.Shrub.Form.StreetNumber
@@ -124,7 +239,11 @@ func formViewToResponse(view *forms.FormView) FormViewResponse {
 		resp.IsHoliday = view.Shrub.Form.IsHoliday
 		resp.FirstAppDate = view.Shrub.Form.FirstAppDate
 		resp.LastAppDate = view.Shrub.Form.LastAppDate
+		resp.DeletedAt = view.Shrub.Form.DeletedAt
+		resp.DeletedBy = view.Shrub.Form.DeletedBy
+		resp.SearchSnippet = view.Shrub.Form.SearchSnippet
 		resp.PestApps = pestAppsToResponse(view.Shrub.Form.AppTimes)
+		resp.Notes = notesToResponse(view.Shrub.Form.Notes)
 		resp.FleaOnly = &view.Shrub.FleaOnly
 	}
 
@@ -133,6 +252,8 @@ func formViewToResponse(view *forms.FormView) FormViewResponse {
 		resp.CreatedBy = view.Lawn.Form.CreatedBy
 		resp.CreatedAt = view.Lawn.Form.CreatedAt
 		resp.UpdatedAt = view.Lawn.Form.UpdatedAt
+		resp.PropertyID = view.Lawn.Form.PropertyID
+		resp.ClonedFrom = view.Lawn.Form.ClonedFrom
 		resp.FirstName = view.Lawn.Form.FirstName
 		resp.LastName = view.Lawn.Form.LastName
 		resp.StreetNumber = view.Lawn.Form.StreetNumber
@@ -145,8 +266,12 @@ func formViewToResponse(view *forms.FormView) FormViewResponse {
 		resp.IsHoliday = view.Lawn.Form.IsHoliday
 		resp.FirstAppDate = view.Lawn.Form.FirstAppDate
 		resp.LastAppDate = view.Lawn.Form.LastAppDate
+		resp.DeletedAt = view.Lawn.Form.DeletedAt
+		resp.DeletedBy = view.Lawn.Form.DeletedBy
+		resp.SearchSnippet = view.Lawn.Form.SearchSnippet
 		resp.LawnAreaSqFt = &view.Lawn.LawnAreaSqFt
 		resp.PestApps = pestAppsToResponse(view.Lawn.Form.AppTimes)
+		resp.Notes = notesToResponse(view.Lawn.Form.Notes)
 		resp.FertOnly = &view.Lawn.FertOnly
 	}
 
@@ -164,5 +289,37 @@ func UserRepoToFullResponse(user users.GetUserResponse) FullUserResponse {
 		LastName:  user.LastName,
 		DoB:       user.DateOfBirth,
 		Username:  user.Username,
+		License:   licenseToResponse(user.License, time.Now()),
+	}
+}
+
+// licenseDateLayout formats license expiry dates
+const licenseDateLayout = "2006-01-02"
+
+// licenseToResponse converts a license, reporting whether it had expired by asOf
+func licenseToResponse(license *users.License, asOf time.Time) *LicenseResponse {
+	if license == nil {
+		return nil
+	}
+	return &LicenseResponse{
+		Number:    license.Number,
+		Category:  license.Category,
+		State:     license.State,
+		ExpiresOn: license.ExpiresOn.Format(licenseDateLayout),
+		Expired:   license.ExpiredOn(asOf),
+	}
+}
+
+// licenseFromRequest converts a license request, upper-casing the state
+func licenseFromRequest(req LicenseRequest) (users.License, error) {
+	expiresOn, err := time.Parse(licenseDateLayout, req.ExpiresOn)
+	if err != nil {
+		return users.License{}, errors.New("Invalid license expires_on, expected YYYY-MM-DD")
 	}
+	return users.License{
+		Number:    strings.TrimSpace(req.Number),
+		Category:  strings.TrimSpace(req.Category),
+		State:     strings.ToUpper(strings.TrimSpace(req.State)),
+		ExpiresOn: expiresOn,
+	}, nil
 }
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/forms"
+	"github.com/go-chi/chi/v5"
+)
+
+func revisionsToResponse(revisions []forms.FormRevision) ListFormRevisionsResponse {
+	responses := make([]FormRevisionResponse, 0, len(revisions))
+	for _, revision := range revisions {
+		responses = append(responses, revisionToResponse(revision))
+	}
+	return ListFormRevisionsResponse{
+		Revisions: responses,
+		Count:     len(responses),
+	}
+}
+
+// GetFormHistory handles GET /api/forms/{id}/history
+func (h *FormsHandler) GetFormHistory(w http.ResponseWriter, r *http.Request) {
+	userID := getUserID(r)
+
+	formID := chi.URLParam(r, "id")
+	if formID == "" {
+		respondError(w, http.StatusBadRequest, "Form ID is required")
+		return
+	}
+
+	revisions, err := h.repo.ListFormRevisions(r.Context(), formID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, revisionsToResponse(revisions))
+}
+
+// GetAnyFormHistory handles GET /api/admin/forms/{id}/history - history of any form, including deleted ones (admin only)
+func (h *FormsHandler) GetAnyFormHistory(w http.ResponseWriter, r *http.Request) {
+	formID := chi.URLParam(r, "id")
+	if formID == "" {
+		respondError(w, http.StatusBadRequest, "Form ID is required")
+		return
+	}
+
+	revisions, err := h.repo.ListAllFormRevisions(r.Context(), formID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, revisionsToResponse(revisions))
+}
+
+// RollbackForm handles POST /api/admin/forms/{id}/rollback (admin only)
+func (h *FormsHandler) RollbackForm(w http.ResponseWriter, r *http.Request) {
+	adminID := getUserID(r)
+
+	formID := chi.URLParam(r, "id")
+	if formID == "" {
+		respondError(w, http.StatusBadRequest, "Form ID is required")
+		return
+	}
+
+	var req RollbackFormRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.RevisionID <= 0 {
+		respondError(w, http.StatusBadRequest, "revision_id is required")
+		return
+	}
+
+	revision, err := h.repo.RollbackFormToRevision(r.Context(), formID, req.RevisionID, adminID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, revisionToResponse(revision))
+}
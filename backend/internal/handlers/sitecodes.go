@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/sitecodes"
+)
+
+// SiteCodePartResponse represents one area or feature of a site code
+type SiteCodePartResponse struct {
+	Code        string `json:"code"`
+	Description string `json:"description"`
+}
+
+// SiteCodeResponse represents a complete site code
+type SiteCodeResponse struct {
+	Code        string `json:"code"`
+	Area        string `json:"area"`
+	Feature     string `json:"feature"`
+	Description string `json:"description"`
+}
+
+// ListSiteCodesResponse lists every site code along with the areas and features they combine
+type ListSiteCodesResponse struct {
+	Areas     []SiteCodePartResponse `json:"areas"`
+	Features  []SiteCodePartResponse `json:"features"`
+	SiteCodes []SiteCodeResponse     `json:"site_codes"`
+	Count     int                    `json:"count"`
+}
+
+func siteCodePartsToResponse(parts []sitecodes.Part) []SiteCodePartResponse {
+	responses := make([]SiteCodePartResponse, 0, len(parts))
+	for _, part := range parts {
+		responses = append(responses, SiteCodePartResponse{Code: part.Code, Description: part.Description})
+	}
+	return responses
+}
+
+// ListSiteCodes handles GET /api/site-codes - the site location codes accepted on applications
+func ListSiteCodes(w http.ResponseWriter, r *http.Request) {
+	codes := sitecodes.All()
+	resp := ListSiteCodesResponse{
+		Areas:     siteCodePartsToResponse(sitecodes.Areas),
+		Features:  siteCodePartsToResponse(sitecodes.Features),
+		SiteCodes: make([]SiteCodeResponse, 0, len(codes)),
+		Count:     len(codes),
+	}
+	for _, code := range codes {
+		resp.SiteCodes = append(resp.SiteCodes, SiteCodeResponse{
+			Code:        code.Code,
+			Area:        code.Area,
+			Feature:     code.Feature,
+			Description: code.Description,
+		})
+	}
+
+	respondJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/forms"
+	"github.com/go-chi/chi/v5"
+)
+
+// trashRetention returns how long deleted forms stay in the trash before they can be purged
+func trashRetention() time.Duration {
+	days, err := strconv.Atoi(os.Getenv("TRASH_RETENTION_DAYS"))
+	if err != nil || days < 0 {
+		days = 30 // Fallback when unset or invalid
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// ListTrash handles GET /api/forms/trash - the user's deleted forms, supports the same options as ListForms
+func (h *FormsHandler) ListTrash(w http.ResponseWriter, r *http.Request) {
+	userID := getUserID(r)
+
+	opts := parseListFormsOptions(r)
+	opts.Deleted = true
+
+	page, err := h.repo.ListFormsByUserId(r.Context(), userID, opts)
+	if err != nil {
+		if errors.Is(err, forms.ErrInvalidCursor) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, formsPageToResponse(page))
+}
+
+// ListAllTrash handles GET /api/admin/forms/trash - deleted forms from all users (admin only)
+func (h *FormsHandler) ListAllTrash(w http.ResponseWriter, r *http.Request) {
+	opts := parseListFormsOptions(r)
+	opts.Deleted = true
+
+	page, err := h.repo.ListAllForms(r.Context(), opts)
+	if err != nil {
+		if errors.Is(err, forms.ErrInvalidCursor) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, formsPageToResponse(page))
+}
+
+// RestoreForm handles POST /api/forms/{id}/restore
+func (h *FormsHandler) RestoreForm(w http.ResponseWriter, r *http.Request) {
+	userID := getUserID(r)
+
+	formID := chi.URLParam(r, "id")
+	if formID == "" {
+		respondError(w, http.StatusBadRequest, "Form ID is required")
+		return
+	}
+
+	err := h.repo.RestoreFormById(r.Context(), formID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondSuccess(w, "Form restored successfully")
+}
+
+// PurgeTrash handles POST /api/admin/forms/trash/purge - permanently removes forms
+// that have been in the trash longer than TRASH_RETENTION_DAYS (admin only)
+func (h *FormsHandler) PurgeTrash(w http.ResponseWriter, r *http.Request) {
+	adminID := getUserID(r)
+
+	deletedBefore := time.Now().Add(-trashRetention())
+
+	purgedIDs, err := h.repo.PurgeDeletedForms(r.Context(), deletedBefore, adminID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if purgedIDs == nil {
+		purgedIDs = []string{}
+	}
+
+	respondJSON(w, http.StatusOK, PurgeTrashResponse{
+		PurgedIDs:     purgedIDs,
+		Count:         len(purgedIDs),
+		DeletedBefore: deletedBefore,
+	})
+}
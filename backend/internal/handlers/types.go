@@ -1,23 +1,41 @@
 package handlers
 
 import (
-	"github.com/shopspring/decimal"
 	"time"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/forms"
+	"github.com/shopspring/decimal"
 )
 
 // Pesticide Applications
 
 type PesticideApplicationRequest struct {
+	ID            int     `json:"id,omitempty"`
 	ChemUsed      int     `json:"chem_used"`
 	AppTimestamp  string  `json:"app_timestamp"`
 	Rate          string  `json:"rate"`
 	AmountApplied float64 `json:"amount_applied"`
 	LocationCode  string  `json:"location_code"`
+	// Optional structured rate, e.g. 2 oz per_1000_sq_ft; rate_amount is required with the others
+	RateAmount *float64 `json:"rate_amount,omitempty"`
+	RateUnit   string   `json:"rate_unit,omitempty"`
+	RateBasis  string   `json:"rate_basis,omitempty"`
+	// Optional method and weather at the time of application
+	ApplicationMethod string   `json:"application_method,omitempty"`
+	WindSpeedMph      *float64 `json:"wind_speed_mph,omitempty"`
+	WindDirection     string   `json:"wind_direction,omitempty"`
+	TemperatureF      *float64 `json:"temperature_f,omitempty"`
+	SkyConditions     string   `json:"sky_conditions,omitempty"`
+	// AppliedBy is the ID of the user who made the application; only admins may name someone else.
+	// New applications default to the caller, existing ones keep their applicator.
+	AppliedBy string `json:"applied_by,omitempty"`
 }
 
 // Forms
 
 type CreateShrubFormRequest struct {
+	// PropertyID links the form to a customer property on file; its client fields are copied from the property
+	PropertyID   string                        `json:"property_id,omitempty"`
 	FirstName    string                        `json:"first_name"`
 	LastName     string                        `json:"last_name"`
 	StreetNumber string                        `json:"street_number"`
@@ -30,9 +48,12 @@ type CreateShrubFormRequest struct {
 	IsHoliday    bool                          `json:"is_holiday"`
 	FleaOnly     bool                          `json:"flea_only"`
 	Applications []PesticideApplicationRequest `json:"applications,omitempty"`
+	// Call logs the call that cleared the applications of a call_before customer
+	Call *FormCallRequest `json:"call,omitempty"`
 }
 
 type CreateLawnFormRequest struct {
+	PropertyID   string                        `json:"property_id,omitempty"`
 	FirstName    string                        `json:"first_name"`
 	LastName     string                        `json:"last_name"`
 	StreetNumber string                        `json:"street_number"`
@@ -46,53 +67,61 @@ type CreateLawnFormRequest struct {
 	LawnAreaSqFt int                           `json:"lawn_area_sq_ft"`
 	FertOnly     bool                          `json:"fert_only"`
 	Applications []PesticideApplicationRequest `json:"applications,omitempty"`
+	Call         *FormCallRequest              `json:"call,omitempty"`
 }
 
+// Applications on update requests replace the form's applications when present.
+// Entries carrying an id are edited, entries without one are added and
+// existing applications left out of the list are removed.
 type UpdateShrubFormRequest struct {
-	FirstName    string `json:"first_name"`
-	LastName     string `json:"last_name"`
-	StreetNumber string `json:"street_number"`
-	StreetName   string `json:"street_name"`
-	Town         string `json:"town"`
-	ZipCode      string `json:"zip_code"`
-	HomePhone    string `json:"home_phone"`
-	OtherPhone   string `json:"other_phone"`
-	CallBefore   bool   `json:"call_before"`
-	IsHoliday    bool   `json:"is_holiday"`
-	FleaOnly     bool   `json:"flea_only"`
+	FirstName    string                        `json:"first_name"`
+	LastName     string                        `json:"last_name"`
+	StreetNumber string                        `json:"street_number"`
+	StreetName   string                        `json:"street_name"`
+	Town         string                        `json:"town"`
+	ZipCode      string                        `json:"zip_code"`
+	HomePhone    string                        `json:"home_phone"`
+	OtherPhone   string                        `json:"other_phone"`
+	CallBefore   bool                          `json:"call_before"`
+	IsHoliday    bool                          `json:"is_holiday"`
+	FleaOnly     bool                          `json:"flea_only"`
+	Applications []PesticideApplicationRequest `json:"applications,omitempty"`
 }
 
 type UpdateLawnFormRequest struct {
-	FirstName    string `json:"first_name"`
-	LastName     string `json:"last_name"`
-	StreetNumber string `json:"street_number"`
-	StreetName   string `json:"street_name"`
-	Town         string `json:"town"`
-	ZipCode      string `json:"zip_code"`
-	HomePhone    string `json:"home_phone"`
-	OtherPhone   string `json:"other_phone"`
-	CallBefore   bool   `json:"call_before"`
-	IsHoliday    bool   `json:"is_holiday"`
-	LawnAreaSqFt int    `json:"lawn_area_sq_ft"`
-	FertOnly     bool   `json:"fert_only"`
+	FirstName    string                        `json:"first_name"`
+	LastName     string                        `json:"last_name"`
+	StreetNumber string                        `json:"street_number"`
+	StreetName   string                        `json:"street_name"`
+	Town         string                        `json:"town"`
+	ZipCode      string                        `json:"zip_code"`
+	HomePhone    string                        `json:"home_phone"`
+	OtherPhone   string                        `json:"other_phone"`
+	CallBefore   bool                          `json:"call_before"`
+	IsHoliday    bool                          `json:"is_holiday"`
+	LawnAreaSqFt int                           `json:"lawn_area_sq_ft"`
+	FertOnly     bool                          `json:"fert_only"`
+	Applications []PesticideApplicationRequest `json:"applications,omitempty"`
 }
 
 // Forms response types
 
 type FormViewResponse struct {
-	ID           string     `json:"id"`
-	CreatedBy    string     `json:"created_by"`
-	CreatedAt    time.Time  `json:"created_at"`
-	UpdatedAt    time.Time  `json:"updated_at"`
-	FormType     string     `json:"form_type"`
-	FirstName    string     `json:"first_name"`
-	LastName     string     `json:"last_name"`
-	StreetNumber string     `json:"street_number"`
-	StreetName   string     `json:"street_name"`
-	Town         string     `json:"town"`
-	ZipCode      string     `json:"zip_code"`
-	HomePhone    string     `json:"home_phone"`
-	OtherPhone   string     `json:"other_phone"`
+	ID           string    `json:"id"`
+	CreatedBy    string    `json:"created_by"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	FormType     string    `json:"form_type"`
+	PropertyID   string    `json:"property_id,omitempty"`
+	ClonedFrom   string    `json:"cloned_from,omitempty"`
+	FirstName    string    `json:"first_name"`
+	LastName     string    `json:"last_name"`
+	StreetNumber string    `json:"street_number"`
+	StreetName   string    `json:"street_name"`
+	Town         string    `json:"town"`
+	ZipCode      string    `json:"zip_code"`
+	HomePhone    string    `json:"home_phone"`
+	OtherPhone   string    `json:"other_phone"`
 	CallBefore   bool      `json:"call_before"`
 	IsHoliday    bool      `json:"is_holiday"`
 	FirstAppDate time.Time `json:"first_app_date"`
@@ -103,6 +132,12 @@ type FormViewResponse struct {
 	LawnAreaSqFt *int                           `json:"lawn_area_sq_ft,omitempty"`
 	FertOnly     *bool                          `json:"fert_only,omitempty"`
 	PestApps     []PesticideApplicationResponse `json:"pest_apps"`
+	Notes        []NoteResponse                 `json:"notes"`
+	// Set while the form is in the trash
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	DeletedBy string     `json:"deleted_by,omitempty"`
+	// Matched text with query terms wrapped in <mark> tags, set on q= searches
+	SearchSnippet string `json:"search_snippet,omitempty"`
 }
 
 type ShrubFormResponse struct {
@@ -111,6 +146,8 @@ type ShrubFormResponse struct {
 	CreatedAt    time.Time                      `json:"created_at"`
 	UpdatedAt    time.Time                      `json:"updated_at"`
 	FormType     string                         `json:"form_type"`
+	PropertyID   string                         `json:"property_id,omitempty"`
+	ClonedFrom   string                         `json:"cloned_from,omitempty"`
 	FirstName    string                         `json:"first_name"`
 	LastName     string                         `json:"last_name"`
 	StreetNumber string                         `json:"street_number"`
@@ -125,6 +162,7 @@ type ShrubFormResponse struct {
 	LastAppDate  time.Time                      `json:"last_app_date"`
 	FleaOnly     bool                           `json:"flea_only"`
 	PestApps     []PesticideApplicationResponse `json:"pest_apps"`
+	Notes        []NoteResponse                 `json:"notes"`
 }
 
 type LawnFormResponse struct {
@@ -133,6 +171,8 @@ type LawnFormResponse struct {
 	CreatedAt    time.Time                      `json:"created_at"`
 	UpdatedAt    time.Time                      `json:"updated_at"`
 	FormType     string                         `json:"form_type"`
+	PropertyID   string                         `json:"property_id,omitempty"`
+	ClonedFrom   string                         `json:"cloned_from,omitempty"`
 	FirstName    string                         `json:"first_name"`
 	LastName     string                         `json:"last_name"`
 	StreetNumber string                         `json:"street_number"`
@@ -148,6 +188,7 @@ type LawnFormResponse struct {
 	LawnAreaSqFt int                            `json:"lawn_area_sq_ft"`
 	FertOnly     bool                           `json:"fert_only"`
 	PestApps     []PesticideApplicationResponse `json:"pest_apps"`
+	Notes        []NoteResponse                 `json:"notes"`
 }
 
 type PesticideApplicationResponse struct {
@@ -157,10 +198,128 @@ type PesticideApplicationResponse struct {
 	Rate          string          `json:"rate"`
 	AmountApplied decimal.Decimal `json:"amount_applied"`
 	LocationCode  string          `json:"location_code"`
+	// LocationDescription decodes LocationCode, e.g. "Front Yard – Lawn"
+	LocationDescription string `json:"location_description"`
+
+	RateAmount     *decimal.Decimal `json:"rate_amount"`
+	RateUnit       string           `json:"rate_unit,omitempty"`
+	RateBasis      string           `json:"rate_basis,omitempty"`
+	ExpectedAmount *decimal.Decimal `json:"expected_amount"`
+	OverLabelMax   bool             `json:"over_label_max"`
+	// LabelWarning is set while an application over the label maximum awaits supervisor acknowledgement
+	LabelWarning            string     `json:"label_warning,omitempty"`
+	OverLabelAcknowledgedBy string     `json:"over_label_acknowledged_by,omitempty"`
+	OverLabelAcknowledgedAt *time.Time `json:"over_label_acknowledged_at,omitempty"`
+
+	ApplicationMethod string           `json:"application_method,omitempty"`
+	WindSpeedMph      *decimal.Decimal `json:"wind_speed_mph"`
+	WindDirection     string           `json:"wind_direction,omitempty"`
+	TemperatureF      *decimal.Decimal `json:"temperature_f"`
+	SkyConditions     string           `json:"sky_conditions,omitempty"`
+	// WeatherWarning is set when the wind exceeds the limit for the application method, see WIND_WARNING_RULES
+	WeatherWarning string `json:"weather_warning,omitempty"`
+	// ApplicatorLicense is the license the application was recorded under, nil if there was none on file
+	ApplicatorLicense *LicenseResponse `json:"applicator_license"`
+	AppliedBy         string           `json:"applied_by"`
+	AppliedByName     string           `json:"applied_by_name"`
+}
+
+// Notes
+
+type NoteRequest struct {
+	Message string `json:"message"`
+}
+
+type NoteResponse struct {
+	ID        int       `json:"id"`
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Message   string    `json:"message"`
+}
+
+type ListNotesResponse struct {
+	Notes []NoteResponse `json:"notes"`
+	Count int            `json:"count"`
+}
+
+// Form revisions
+
+type RollbackFormRequest struct {
+	RevisionID int64 `json:"revision_id"`
+}
+
+type FormRevisionResponse struct {
+	ID            int64              `json:"id"`
+	FormID        string             `json:"form_id"`
+	ChangedBy     string             `json:"changed_by"`
+	ChangedByName string             `json:"changed_by_name"`
+	ChangedAt     time.Time          `json:"changed_at"`
+	Action        string             `json:"action"`
+	Diff          forms.RevisionDiff `json:"diff"`
 }
+
+type ListFormRevisionsResponse struct {
+	Revisions []FormRevisionResponse `json:"revisions"`
+	Count     int                    `json:"count"`
+}
+
 type ListFormsResponse struct {
 	Forms []FormViewResponse `json:"forms"`
-	Count int                `json:"count"`
+	// Count is the number of forms on this page, Total the number matching the filters
+	Count      int    `json:"count"`
+	Total      int    `json:"total"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+}
+
+type PurgeTrashResponse struct {
+	PurgedIDs     []string  `json:"purged_ids"`
+	Count         int       `json:"count"`
+	DeletedBefore time.Time `json:"deleted_before"`
+}
+
+// Form merges
+
+// FieldSources maps a field name such as "home_phone" to the form whose value the survivor keeps
+type MergeFormsRequest struct {
+	SurvivorID   string            `json:"survivor_id"`
+	DuplicateIDs []string          `json:"duplicate_ids"`
+	FieldSources map[string]string `json:"field_sources,omitempty"`
+}
+
+type FormMergeResponse struct {
+	ID             int64             `json:"id"`
+	SurvivorID     string            `json:"survivor_id"`
+	MergedIDs      []string          `json:"merged_ids"`
+	MergedBy       string            `json:"merged_by"`
+	MergedAt       time.Time         `json:"merged_at"`
+	FieldSources   map[string]string `json:"field_sources"`
+	ApplicationIDs []int             `json:"application_ids"`
+	NoteIDs        []int             `json:"note_ids"`
+}
+
+// Form clones
+
+type ClonedFormResponse struct {
+	SourceID string `json:"source_id"`
+	CloneID  string `json:"clone_id"`
+}
+
+// SkippedCloneResponse is a form left alone because its client already has a form in the new season
+type SkippedCloneResponse struct {
+	SourceID     string                       `json:"source_id"`
+	CandidateIDs []string                     `json:"candidate_ids"`
+	Candidates   []DuplicateCandidateResponse `json:"candidates"`
+}
+
+type SeasonCloneResponse struct {
+	FromYear     int                    `json:"from_year"`
+	ToYear       int                    `json:"to_year"`
+	Cloned       []ClonedFormResponse   `json:"cloned"`
+	ClonedCount  int                    `json:"cloned_count"`
+	Skipped      []SkippedCloneResponse `json:"skipped"`
+	SkippedCount int                    `json:"skipped_count"`
 }
 
 type CreateFormResponse struct {
@@ -174,6 +333,26 @@ type CreateOrUpdateUserRequest struct {
 	DoB       time.Time `json:"date_of_birth"`
 	Username  string    `json:"username"`
 	Password  string    `json:"password"`
+	// License replaces the applicator license when present; omit it to leave the license unchanged
+	License *LicenseRequest `json:"license,omitempty"`
+}
+
+// LicenseRequest is a certified applicator's license; expires_on is a YYYY-MM-DD date
+type LicenseRequest struct {
+	Number    string `json:"number"`
+	Category  string `json:"category"`
+	State     string `json:"state"`
+	ExpiresOn string `json:"expires_on"`
+}
+
+// LicenseResponse represents an applicator license
+type LicenseResponse struct {
+	Number    string `json:"number"`
+	Category  string `json:"category"`
+	State     string `json:"state"`
+	ExpiresOn string `json:"expires_on"`
+	// Expired reports whether the license had expired by today, or on an application by its date
+	Expired bool `json:"expired"`
 }
 
 type ShortUserResponse struct {
@@ -192,6 +371,8 @@ type FullUserResponse struct {
 	LastName  string    `json:"last_name"`
 	DoB       time.Time `json:"date_of_birth"`
 	Username  string    `json:"username"`
+	// License is nil for users without an applicator license on file
+	License *LicenseResponse `json:"license"`
 }
 
 type ListUsersResponse struct {
@@ -205,6 +386,19 @@ type ErrorResponse struct {
 	Message string `json:"message,omitempty"`
 }
 
+// FieldErrorResponse is a problem with one request field
+type FieldErrorResponse struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationErrorResponse lists the request fields that failed validation
+type ValidationErrorResponse struct {
+	Error   string               `json:"error"`
+	Message string               `json:"message"`
+	Fields  []FieldErrorResponse `json:"fields"`
+}
+
 type SuccessResponse struct {
 	Message string `json:"message"`
 }
@@ -132,6 +132,14 @@ func (h *UsersHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 		Username:  req.Username,
 		Password:  req.Password, // Empty string if not provided
 	}
+	if req.License != nil {
+		license, err := licenseFromRequest(*req.License)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		userInput.License = &license
+	}
 
 	updatedUser, err := h.repo.UpdateUserById(r.Context(), userID, userInput)
 	if err != nil {
@@ -139,6 +147,10 @@ func (h *UsersHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 			respondError(w, http.StatusNotFound, "User not found")
 			return
 		}
+		if errors.Is(err, users.ErrInvalidLicense) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		respondError(w, http.StatusInternalServerError, "Failed to update user")
 		return
 	}
@@ -190,3 +202,62 @@ func (h *UsersHandler) ApproveUser(w http.ResponseWriter, r *http.Request) {
 
 	respondJSON(w, http.StatusOK, approvedUser)
 }
+
+// SetUserLicense handles PUT /api/users/{id}/license - records a user's applicator license (admin only).
+// Returns the updated user upon success.
+func (h *UsersHandler) SetUserLicense(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "id")
+	if userID == "" {
+		respondError(w, http.StatusBadRequest, "User ID is required")
+		return
+	}
+
+	var req LicenseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	license, err := licenseFromRequest(req)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user, err := h.repo.SetUserLicense(r.Context(), userID, &license)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "User not found")
+			return
+		}
+		if errors.Is(err, users.ErrInvalidLicense) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Failed to update license")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, UserRepoToFullResponse(user))
+}
+
+// DeleteUserLicense handles DELETE /api/users/{id}/license - removes a user's applicator license (admin only).
+// Returns the updated user upon success.
+func (h *UsersHandler) DeleteUserLicense(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "id")
+	if userID == "" {
+		respondError(w, http.StatusBadRequest, "User ID is required")
+		return
+	}
+
+	user, err := h.repo.SetUserLicense(r.Context(), userID, nil)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "User not found")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Failed to remove license")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, UserRepoToFullResponse(user))
+}
@@ -0,0 +1,193 @@
+package holidays
+
+import (
+	"fmt"
+	"time"
+)
+
+// Hebrew months, numbered from Nisan as in the Torah. The year begins in Tishrei; Adar II only
+// exists in leap years, when Adar is called Adar I.
+const (
+	Nisan    = 1
+	Iyyar    = 2
+	Sivan    = 3
+	Tamuz    = 4
+	Av       = 5
+	Elul     = 6
+	Tishrei  = 7
+	Cheshvan = 8
+	Kislev   = 9
+	Tevet    = 10
+	Shvat    = 11
+	Adar     = 12
+	AdarII   = 13
+)
+
+var monthNames = map[int]string{
+	Nisan:    "Nisan",
+	Iyyar:    "Iyyar",
+	Sivan:    "Sivan",
+	Tamuz:    "Tamuz",
+	Av:       "Av",
+	Elul:     "Elul",
+	Tishrei:  "Tishrei",
+	Cheshvan: "Cheshvan",
+	Kislev:   "Kislev",
+	Tevet:    "Tevet",
+	Shvat:    "Sh'vat",
+	Adar:     "Adar",
+	AdarII:   "Adar II",
+}
+
+// Days are counted as fixed day numbers, where day 1 is January 1 of year 1 in the proleptic
+// Gregorian calendar. hebrewEpoch is the fixed day of 1 Tishrei, year 1.
+const (
+	hebrewEpoch = -1373427
+	// unixEpochFixed is the fixed day of January 1, 1970
+	unixEpochFixed = 719163
+)
+
+// Date is a day in the Hebrew calendar
+type Date struct {
+	Year  int
+	Month int
+	Day   int
+}
+
+// String reads like "15 Nisan 5786"
+func (d Date) String() string {
+	name := monthNames[d.Month]
+	if d.Month == Adar && isLeapYear(d.Year) {
+		name = "Adar I"
+	}
+	return fmt.Sprintf("%d %s %d", d.Day, name, d.Year)
+}
+
+// Civil returns the civil day on which d falls, at midnight in loc. A Hebrew day begins at the
+// previous nightfall; Civil is the day it shares its daylight hours with.
+func (d Date) Civil(loc *time.Location) time.Time {
+	return civilFromFixed(fixedFromHebrew(d.Year, d.Month, d.Day), loc)
+}
+
+// FromCivil returns the Hebrew date whose daylight hours fall on t's civil day
+func FromCivil(t time.Time) Date {
+	return hebrewFromFixed(fixedFromCivil(t))
+}
+
+func fixedFromCivil(t time.Time) int {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return int(midnight.Unix()/86400) + unixEpochFixed
+}
+
+func civilFromFixed(fixed int, loc *time.Location) time.Time {
+	return time.Date(1970, time.January, 1+fixed-unixEpochFixed, 0, 0, 0, 0, loc)
+}
+
+func isLeapYear(year int) bool {
+	return mod(7*year+1, 19) < 7
+}
+
+func lastMonthOfYear(year int) int {
+	if isLeapYear(year) {
+		return AdarII
+	}
+	return Adar
+}
+
+// elapsedDays is the number of days from the epoch to the molad of Tishrei of year, moved to the
+// next day when the molad falls on a Sunday, Wednesday or Friday
+func elapsedDays(year int) int {
+	monthsElapsed := floorDiv(235*year-234, 19)
+	partsElapsed := 12084 + 13753*monthsElapsed
+	day := 29*monthsElapsed + floorDiv(partsElapsed, 25920)
+	if mod(3*(day+1), 7) < 3 {
+		return day + 1
+	}
+	return day
+}
+
+// yearLengthCorrection delays Rosh Hashanah so no year is 356 or 382 days long
+func yearLengthCorrection(year int) int {
+	previous, current, next := elapsedDays(year-1), elapsedDays(year), elapsedDays(year+1)
+	switch {
+	case next-current == 356:
+		return 2
+	case current-previous == 382:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// newYear returns the fixed day of 1 Tishrei of year
+func newYear(year int) int {
+	return hebrewEpoch + elapsedDays(year) + yearLengthCorrection(year)
+}
+
+func daysInYear(year int) int {
+	return newYear(year+1) - newYear(year)
+}
+
+func daysInMonth(month, year int) int {
+	switch {
+	case month == Iyyar || month == Tamuz || month == Elul || month == Tevet || month == AdarII:
+		return 29
+	case month == Adar && !isLeapYear(year):
+		return 29
+	case month == Cheshvan && mod(daysInYear(year), 10) != 5:
+		// Cheshvan only has 30 days in a complete year
+		return 29
+	case month == Kislev && mod(daysInYear(year), 10) == 3:
+		// Kislev only has 29 days in a deficient year
+		return 29
+	default:
+		return 30
+	}
+}
+
+func fixedFromHebrew(year, month, day int) int {
+	fixed := newYear(year) + day - 1
+	if month < Tishrei {
+		for m := Tishrei; m <= lastMonthOfYear(year); m++ {
+			fixed += daysInMonth(m, year)
+		}
+		for m := Nisan; m < month; m++ {
+			fixed += daysInMonth(m, year)
+		}
+	} else {
+		for m := Tishrei; m < month; m++ {
+			fixed += daysInMonth(m, year)
+		}
+	}
+	return fixed
+}
+
+func hebrewFromFixed(fixed int) Date {
+	// The mean year is 35975351/98496 days long
+	year := floorDiv((fixed-hebrewEpoch)*98496, 35975351)
+	for newYear(year+1) <= fixed {
+		year++
+	}
+
+	month := Tishrei
+	if fixed >= fixedFromHebrew(year, Nisan, 1) {
+		month = Nisan
+	}
+	for fixed > fixedFromHebrew(year, month, daysInMonth(month, year)) {
+		month++
+	}
+
+	return Date{Year: year, Month: month, Day: fixed - fixedFromHebrew(year, month, 1) + 1}
+}
+
+func floorDiv(a, b int) int {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
+
+func mod(a, b int) int {
+	return a - b*floorDiv(a, b)
+}
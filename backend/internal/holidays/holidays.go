@@ -0,0 +1,181 @@
+// Package holidays computes the Jewish holidays and Sabbaths on which customers who observe them
+// must not be serviced. Dates come from the arithmetic Hebrew calendar and times from the sun's
+// position at the company's location, so no network access is needed for any year.
+package holidays
+
+import (
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	// Time zones must load without the host's zoneinfo
+	_ "time/tzdata"
+)
+
+// Kinds of blackout window
+const (
+	KindHoliday = "holiday"
+	KindShabbat = "shabbat"
+)
+
+// CandleLighting is how long before sunset a holiday or Shabbat begins
+const CandleLighting = 18 * time.Minute
+
+// Location is where sunset and nightfall are computed
+type Location struct {
+	Latitude  float64
+	Longitude float64
+	TimeZone  *time.Location
+}
+
+// Newark, NJ
+const (
+	defaultLatitude  = 40.7357
+	defaultLongitude = -74.1724
+	defaultTimeZone  = "America/New_York"
+)
+
+// LocationFromEnv returns the location set with HOLIDAY_LATITUDE, HOLIDAY_LONGITUDE and
+// HOLIDAY_TIMEZONE, defaulting to Newark, NJ
+func LocationFromEnv() Location {
+	loc := Location{Latitude: defaultLatitude, Longitude: defaultLongitude}
+
+	if latitude, err := strconv.ParseFloat(os.Getenv("HOLIDAY_LATITUDE"), 64); err == nil && latitude >= -90 && latitude <= 90 {
+		loc.Latitude = latitude
+	}
+	if longitude, err := strconv.ParseFloat(os.Getenv("HOLIDAY_LONGITUDE"), 64); err == nil && longitude >= -180 && longitude <= 180 {
+		loc.Longitude = longitude
+	}
+
+	name := os.Getenv("HOLIDAY_TIMEZONE")
+	if name == "" {
+		name = defaultTimeZone
+	}
+	zone, err := time.LoadLocation(name)
+	if err != nil {
+		log.Printf("Invalid HOLIDAY_TIMEZONE %q, using %s: %v", name, defaultTimeZone, err)
+		zone, _ = time.LoadLocation(defaultTimeZone) // Fallback when invalid
+	}
+	loc.TimeZone = zone
+
+	return loc
+}
+
+// holiday is a festival on which work is forbidden, kept for two days outside Israel
+type holiday struct {
+	name  string
+	month int
+	day   int
+	days  int
+}
+
+// festivals are in civil year order: the spring festivals close one Hebrew year and the autumn
+// ones open the next
+var festivals = []holiday{
+	{name: "Pesach", month: Nisan, day: 15, days: 2},
+	{name: "Pesach (last days)", month: Nisan, day: 21, days: 2},
+	{name: "Shavuot", month: Sivan, day: 6, days: 2},
+	{name: "Rosh Hashanah", month: Tishrei, day: 1, days: 2},
+	{name: "Yom Kippur", month: Tishrei, day: 10, days: 1},
+	{name: "Sukkot", month: Tishrei, day: 15, days: 2},
+	{name: "Shemini Atzeret & Simchat Torah", month: Tishrei, day: 22, days: 2},
+}
+
+// Window is a holiday or Shabbat, from candle lighting the evening before its first day until
+// nightfall on its last day
+type Window struct {
+	Name string
+	// Kind is KindHoliday or KindShabbat
+	Kind string
+	// HebrewDate is the date of the first day
+	HebrewDate Date
+	// FirstDay and LastDay are the civil days observed, at midnight in the location's time zone
+	FirstDay time.Time
+	LastDay  time.Time
+	Start    time.Time
+	End      time.Time
+}
+
+// Contains reports whether t falls within the window
+func (w Window) Contains(t time.Time) bool {
+	return !t.Before(w.Start) && t.Before(w.End)
+}
+
+func newWindow(name, kind string, firstDay, lastDay time.Time, loc Location) Window {
+	window := Window{
+		Name:       name,
+		Kind:       kind,
+		HebrewDate: FromCivil(firstDay),
+		FirstDay:   firstDay,
+		LastDay:    lastDay,
+	}
+
+	eve := firstDay.AddDate(0, 0, -1)
+	if sunset, ok := evening(eve, loc.Latitude, loc.Longitude, sunsetZenith); ok {
+		window.Start = sunset.Add(-CandleLighting).In(loc.TimeZone)
+	} else {
+		// Without a sunset the whole evening is kept
+		window.Start = eve.Add(12 * time.Hour)
+	}
+	if nightfall, ok := evening(lastDay, loc.Latitude, loc.Longitude, nightfallZenith); ok {
+		window.End = nightfall.In(loc.TimeZone)
+	} else {
+		window.End = lastDay.AddDate(0, 0, 1)
+	}
+
+	return window
+}
+
+// ForYear returns the holidays and Sabbaths whose first day falls in the civil year, ordered by
+// when they begin
+func ForYear(year int, loc Location) []Window {
+	windows := make([]Window, 0, len(festivals)+53)
+
+	for _, festival := range festivals {
+		hebrewYear := year + 3760
+		if festival.month >= Tishrei {
+			hebrewYear++
+		}
+		firstDay := Date{Year: hebrewYear, Month: festival.month, Day: festival.day}.Civil(loc.TimeZone)
+		lastDay := firstDay.AddDate(0, 0, festival.days-1)
+		windows = append(windows, newWindow(festival.name, KindHoliday, firstDay, lastDay, loc))
+	}
+
+	saturday := time.Date(year, time.January, 1, 0, 0, 0, 0, loc.TimeZone)
+	saturday = saturday.AddDate(0, 0, int(time.Saturday-saturday.Weekday()))
+	for ; saturday.Year() == year; saturday = saturday.AddDate(0, 0, 7) {
+		windows = append(windows, newWindow("Shabbat", KindShabbat, saturday, saturday, loc))
+	}
+
+	sort.SliceStable(windows, func(i, j int) bool {
+		return windows[i].Start.Before(windows[j].Start)
+	})
+	return windows
+}
+
+// Observed returns the holiday or Shabbat in progress at t, preferring a holiday when both are
+func Observed(t time.Time, loc Location) (Window, bool) {
+	year := t.In(loc.TimeZone).Year()
+
+	var (
+		found Window
+		ok    bool
+	)
+	// A window that begins on New Year's Eve belongs to the next year
+	for _, candidates := range [][]Window{ForYear(year, loc), ForYear(year+1, loc)} {
+		for _, window := range candidates {
+			if !window.Contains(t) {
+				continue
+			}
+			if window.Kind == KindHoliday {
+				return window, true
+			}
+			if !ok {
+				found, ok = window, true
+			}
+		}
+	}
+	return found, ok
+}
@@ -0,0 +1,109 @@
+package holidays
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newark(t *testing.T) Location {
+	zone, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	return Location{Latitude: defaultLatitude, Longitude: defaultLongitude, TimeZone: zone}
+}
+
+func TestHebrewDates(t *testing.T) {
+	tests := []struct {
+		civil  string
+		hebrew Date
+		text   string
+	}{
+		{"2024-10-03", Date{5785, Tishrei, 1}, "1 Tishrei 5785"},
+		{"2025-04-13", Date{5785, Nisan, 15}, "15 Nisan 5785"},
+		{"2025-12-20", Date{5786, Kislev, 30}, "30 Kislev 5786"},
+		{"2024-03-24", Date{5784, AdarII, 14}, "14 Adar II 5784"},
+		{"2024-02-23", Date{5784, Adar, 14}, "14 Adar I 5784"},
+		{"2026-09-12", Date{5787, Tishrei, 1}, "1 Tishrei 5787"},
+		{"2000-01-01", Date{5760, Tevet, 23}, "23 Tevet 5760"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.civil, func(t *testing.T) {
+			civil, err := time.Parse("2006-01-02", tt.civil)
+			require.NoError(t, err)
+			require.Equal(t, tt.hebrew, FromCivil(civil))
+			require.Equal(t, tt.text, tt.hebrew.String())
+			require.Equal(t, civil, tt.hebrew.Civil(time.UTC))
+		})
+	}
+}
+
+func TestForYear(t *testing.T) {
+	loc := newark(t)
+	windows := ForYear(2025, loc)
+
+	firstDays := map[string]string{}
+	shabbatot := 0
+	for i, window := range windows {
+		if i > 0 {
+			require.False(t, window.Start.Before(windows[i-1].Start))
+		}
+		require.True(t, window.Start.Before(window.End))
+		if window.Kind == KindShabbat {
+			shabbatot++
+			require.Equal(t, time.Saturday, window.FirstDay.Weekday())
+			continue
+		}
+		firstDays[window.Name] = window.FirstDay.Format("2006-01-02")
+	}
+	require.Equal(t, 52, shabbatot)
+	require.Equal(t, map[string]string{
+		"Pesach":                          "2025-04-13",
+		"Pesach (last days)":              "2025-04-19",
+		"Shavuot":                         "2025-06-02",
+		"Rosh Hashanah":                   "2025-09-23",
+		"Yom Kippur":                      "2025-10-02",
+		"Sukkot":                          "2025-10-07",
+		"Shemini Atzeret & Simchat Torah": "2025-10-14",
+	}, firstDays)
+}
+
+func TestObserved(t *testing.T) {
+	loc := newark(t)
+	at := func(value string) time.Time {
+		parsed, err := time.ParseInLocation("2006-01-02 15:04", value, loc.TimeZone)
+		require.NoError(t, err)
+		return parsed
+	}
+
+	// Rosh Hashanah 5786 runs from candle lighting on Monday 22 September until nightfall Wednesday
+	window, ok := Observed(at("2025-09-22 19:00"), loc)
+	require.True(t, ok)
+	require.Equal(t, "Rosh Hashanah", window.Name)
+	require.Equal(t, KindHoliday, window.Kind)
+	require.InDelta(t, at("2025-09-22 18:36").Unix(), window.Start.Unix(), 180)
+	require.InDelta(t, at("2025-09-24 19:31").Unix(), window.End.Unix(), 180)
+
+	_, ok = Observed(at("2025-09-22 12:00"), loc)
+	require.False(t, ok)
+	_, ok = Observed(at("2025-09-24 21:00"), loc)
+	require.False(t, ok)
+
+	// Shabbat at the summer solstice
+	window, ok = Observed(at("2025-06-21 10:00"), loc)
+	require.True(t, ok)
+	require.Equal(t, "Shabbat", window.Name)
+	require.InDelta(t, at("2025-06-20 20:13").Unix(), window.Start.Unix(), 180)
+	_, ok = Observed(at("2025-06-20 19:30"), loc)
+	require.False(t, ok)
+
+	// A holiday on Shabbat is reported as the holiday
+	window, ok = Observed(at("2024-10-12 10:00"), loc)
+	require.True(t, ok)
+	require.Equal(t, "Yom Kippur", window.Name)
+
+	// Friday night on New Year's Eve belongs to the first Shabbat of the next year
+	window, ok = Observed(at("2027-12-31 20:00"), loc)
+	require.True(t, ok)
+	require.Equal(t, "2028-01-01", window.FirstDay.Format("2006-01-02"))
+}
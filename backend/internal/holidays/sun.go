@@ -0,0 +1,73 @@
+package holidays
+
+import (
+	"math"
+	"time"
+)
+
+// Zeniths of the sun at the times that bound a holiday
+const (
+	// sunsetZenith accounts for refraction and the sun's radius
+	sunsetZenith = 90.833
+	// nightfallZenith is when three stars are visible, the sun 8.5° below the horizon
+	nightfallZenith = 98.5
+)
+
+// evening returns when the sun sinks to zenith degrees on day at the given coordinates, using the
+// sunrise/sunset algorithm of the Almanac for Computers. ok is false when the sun never gets that
+// low, as in a polar summer.
+func evening(day time.Time, latitude, longitude, zenith float64) (time.Time, bool) {
+	year, month, date := day.Date()
+	midnight := time.Date(year, month, date, 0, 0, 0, 0, time.UTC)
+
+	lngHour := longitude / 15
+	t := float64(midnight.YearDay()) + (18-lngHour)/24
+
+	meanAnomaly := 0.9856*t - 3.289
+	trueLongitude := normalizeDegrees(meanAnomaly + 1.916*sinDeg(meanAnomaly) + 0.020*sinDeg(2*meanAnomaly) + 282.634)
+
+	rightAscension := normalizeDegrees(atanDeg(0.91764 * tanDeg(trueLongitude)))
+	// Right ascension is in the same quadrant as the true longitude
+	rightAscension += math.Floor(trueLongitude/90)*90 - math.Floor(rightAscension/90)*90
+	rightAscension /= 15
+
+	sinDeclination := 0.39782 * sinDeg(trueLongitude)
+	cosDeclination := math.Cos(math.Asin(sinDeclination))
+
+	cosHourAngle := (cosDeg(zenith) - sinDeclination*sinDeg(latitude)) / (cosDeclination * cosDeg(latitude))
+	if cosHourAngle < -1 || cosHourAngle > 1 {
+		return time.Time{}, false
+	}
+	hourAngle := acosDeg(cosHourAngle) / 15
+
+	localMeanTime := hourAngle + rightAscension - 0.06571*t - 6.622
+	universalTime := math.Mod(localMeanTime-lngHour, 24)
+	if universalTime < 0 {
+		universalTime += 24
+	}
+
+	event := midnight.Add(time.Duration(universalTime * float64(time.Hour)))
+	// West of Greenwich the evening can fall after midnight UTC
+	if solarNoon := midnight.Add(time.Duration((12 - lngHour) * float64(time.Hour))); event.Before(solarNoon) {
+		event = event.Add(24 * time.Hour)
+	}
+	return event.Truncate(time.Minute), true
+}
+
+func normalizeDegrees(degrees float64) float64 {
+	degrees = math.Mod(degrees, 360)
+	if degrees < 0 {
+		degrees += 360
+	}
+	return degrees
+}
+
+func sinDeg(degrees float64) float64 { return math.Sin(degrees * math.Pi / 180) }
+
+func cosDeg(degrees float64) float64 { return math.Cos(degrees * math.Pi / 180) }
+
+func tanDeg(degrees float64) float64 { return math.Tan(degrees * math.Pi / 180) }
+
+func atanDeg(x float64) float64 { return math.Atan(x) * 180 / math.Pi }
+
+func acosDeg(x float64) float64 { return math.Acos(x) * 180 / math.Pi }
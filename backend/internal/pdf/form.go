@@ -0,0 +1,207 @@
+package pdf
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/forms"
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/sitecodes"
+)
+
+// Company is the applicator business printed in the header of every form
+type Company struct {
+	Name string
+	// Address is printed one line per entry
+	Address []string
+	Phone   string
+	// LicenseNo is the business's NJDEP pesticide license number
+	LicenseNo string
+	// ResponsibleApplicator is the certified applicator responsible for the business's work
+	ResponsibleApplicator        string
+	ResponsibleApplicatorLicense string
+}
+
+// describeLocation decodes a site code such as "1A" into "1A (Front Yard – Lawn)".
+// Unknown codes, which older forms may still have, are printed as they are.
+func describeLocation(code string) string {
+	desc := sitecodes.Describe(code)
+	if desc == "" {
+		return code
+	}
+	return code + " (" + desc + ")"
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "Yes"
+	}
+	return "No"
+}
+
+// Form renders a form's card in the layout of the frontend print page
+func Form(w io.Writer, printout *forms.Printout, company Company) error {
+	form := printout.Form()
+	doc := newDocument("P", fmt.Sprintf("%s %s - %s form", form.FirstName, form.LastName, form.FormType))
+	doc.formPage(printout, company)
+	return doc.Output(w)
+}
+
+// formPage adds a page with a form's card
+func (d *document) formPage(printout *forms.Printout, company Company) {
+	form := printout.Form()
+	d.AddPage()
+
+	left, top, right, _ := d.GetMargins()
+	pageWidth, _ := d.GetPageSize()
+	contentWidth := pageWidth - left - right
+	const gap = 4.0
+	titleWidth := contentWidth * 0.6
+	customerWidth := contentWidth - titleWidth - gap
+
+	// Company header, centred in the title box
+	season := form.CreatedAt.Year()
+	if len(printout.Applications) > 0 {
+		season = form.LastAppDate.Year()
+	}
+	type headerLine struct {
+		style string
+		size  float64
+		text  string
+	}
+	header := []headerLine{
+		{"B", 12, fmt.Sprintf("%s Treatment Program April - November %d", strings.ToUpper(form.FormType), season)},
+	}
+	if company.Name != "" {
+		header = append(header, headerLine{"B", 9, company.Name})
+	}
+	for _, line := range company.Address {
+		header = append(header, headerLine{"", 8, line})
+	}
+	if company.Phone != "" {
+		header = append(header, headerLine{"", 8, company.Phone})
+	}
+	if company.LicenseNo != "" {
+		header = append(header, headerLine{"", 8, "NJDEP#: " + company.LicenseNo})
+	}
+	if company.ResponsibleApplicator != "" {
+		line := "Resp Applic: " + company.ResponsibleApplicator
+		if company.ResponsibleApplicatorLicense != "" {
+			line += " - Lic #: " + company.ResponsibleApplicatorLicense
+		}
+		header = append(header, headerLine{"", 8, line})
+	}
+	header = append(header, headerLine{"", 8, "Operator: " + printout.CreatedByName})
+
+	const padding = 3.0
+	d.SetXY(left, top+padding)
+	for _, line := range header {
+		d.SetFont(fontFamily, line.style, line.size)
+		d.SetX(left + padding)
+		d.CellFormat(titleWidth-2*padding, lineHeight, d.fit(line.text, titleWidth-2*padding), "", 1, "C", false, 0, "")
+	}
+	titleBottom := d.GetY() + padding
+
+	// Customer information
+	boxX := left + titleWidth + gap
+	innerX := boxX + padding
+	innerWidth := customerWidth - 2*padding
+	d.SetXY(innerX, top+padding)
+	d.SetFont(fontFamily, "B", 10)
+	d.CellFormat(innerWidth, lineHeight, "Customer Information", "B", 1, "L", false, 0, "")
+	d.SetY(d.GetY() + 1)
+
+	type field struct{ label, value string }
+	fieldRow := func(fields []field) {
+		if len(fields) == 0 {
+			return
+		}
+		y := d.GetY()
+		width := (innerWidth - float64(len(fields)-1)*2) / float64(len(fields))
+		for i, f := range fields {
+			x := innerX + float64(i)*(width+2)
+			d.SetXY(x, y)
+			d.SetFont(fontFamily, "", 8)
+			d.CellFormat(width, lineHeight, d.fit(f.value, width), "1", 0, "L", false, 0, "")
+			d.SetXY(x, y+lineHeight)
+			d.SetFont(fontFamily, "", 6)
+			d.CellFormat(width, 3, d.tr(f.label), "", 0, "L", false, 0, "")
+		}
+		d.SetXY(innerX, y+lineHeight+4)
+	}
+
+	fieldRow([]field{{"Name", form.FirstName + " " + form.LastName}})
+	fieldRow([]field{{"Address", fmt.Sprintf("%s %s, %s %s", form.StreetNumber, form.StreetName, form.Town, form.ZipCode)}})
+	phones := []field{{"Home Phone", form.HomePhone}}
+	if form.OtherPhone != "" {
+		phones = append(phones, field{"Other Phone", form.OtherPhone})
+	}
+	fieldRow(phones)
+	flags := []field{
+		{"Call Before", yesNo(form.CallBefore)},
+		{"Jewish Holiday", yesNo(form.IsHoliday)},
+	}
+	switch {
+	case printout.View.Lawn != nil:
+		lawn := printout.View.Lawn.LawnDetails
+		flags = append(flags,
+			field{"Lawn Area", strconv.Itoa(lawn.LawnAreaSqFt) + " sq ft"},
+			field{"Fertilizer Only", yesNo(lawn.FertOnly)},
+		)
+	case printout.View.Shrub != nil:
+		flags = append(flags, field{"Flea Only", yesNo(printout.View.Shrub.ShrubDetails.FleaOnly)})
+	}
+	fieldRow(flags)
+	customerBottom := d.GetY() + padding - 1
+
+	bottom := max(titleBottom, customerBottom)
+	d.SetLineWidth(0.6)
+	d.Rect(left, top, titleWidth, bottom-top, "D")
+	d.SetLineWidth(0.2)
+	d.Rect(boxX, top, customerWidth, bottom-top, "D")
+	d.SetXY(left, bottom+gap)
+
+	// Applications
+	d.text("B", 10, "Pesticide Applications")
+	if len(printout.Applications) == 0 {
+		d.text("I", 8, "No applications recorded")
+	} else {
+		rows := make([][]string, 0, len(printout.Applications))
+		for _, app := range printout.Applications {
+			rows = append(rows, []string{
+				formatDate(app.AppTimestamp),
+				app.BrandName,
+				app.EpaRegNo,
+				app.Rate,
+				strings.TrimSpace(app.AmountApplied.String() + " " + app.Unit),
+				describeLocation(app.LocationCode),
+				app.Weather.String(),
+			})
+		}
+		d.table([]column{
+			{header: "Date", width: contentWidth * 0.10, align: "L"},
+			{header: "Brand Name", width: contentWidth * 0.18, align: "L"},
+			{header: "EPA Reg. No", width: contentWidth * 0.11, align: "L"},
+			{header: "Rate", width: contentWidth * 0.13, align: "L"},
+			{header: "Amount", width: contentWidth * 0.09, align: "R"},
+			{header: "Location", width: contentWidth * 0.19, align: "L"},
+			{header: "Weather", width: contentWidth * 0.20, align: "L"},
+		}, rows)
+	}
+
+	// Agency contacts
+	d.Ln(gap)
+	d.SetFont(fontFamily, "B", 9)
+	contacts := []string{
+		"New Jersey D.E.P. Pesticide Control Program: (609) 984-6507",
+		"National Pesticide Information Center: (800) 858-7378 (General Questions)",
+		"National Pesticide Information & Education System: (800) 222-1222 (Emergencies)",
+	}
+	y := d.GetY()
+	d.SetY(y + 1)
+	for _, line := range contacts {
+		d.CellFormat(0, lineHeight, d.tr(line), "", 1, "C", false, 0, "")
+	}
+	d.Rect(left, y, contentWidth, d.GetY()-y+1, "D")
+}
@@ -0,0 +1,77 @@
+package pdf
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/forms"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescribeLocation(t *testing.T) {
+	require.Equal(t, "1A (Front Yard – Lawn)", describeLocation("1A"))
+	require.Equal(t, "4C (Entire Property – Ornamental Tree(s))", describeLocation("4C"))
+	require.Equal(t, "4c", describeLocation("4c"))
+	require.Equal(t, "ZZ", describeLocation("ZZ"))
+}
+
+func TestForm(t *testing.T) {
+	appTime := time.Date(2025, time.May, 2, 10, 0, 0, 0, time.UTC)
+	form := forms.Form{
+		ID:           "5b0c7c53-3c4e-4a8e-9a43-0d4f2f5e8c11",
+		FormType:     "lawn",
+		CreatedAt:    appTime,
+		FirstName:    "José",
+		LastName:     "Print",
+		StreetNumber: "12",
+		StreetName:   "Card Ln",
+		Town:         "Town",
+		ZipCode:      "10001",
+		HomePhone:    "555-1200",
+		OtherPhone:   "555-1201",
+		LastAppDate:  appTime,
+	}
+	windSpeed := decimal.NewFromInt(8)
+	printout := &forms.Printout{
+		View:          forms.NewLawnFormView(forms.LawnForm{Form: form, LawnDetails: forms.LawnDetails{LawnAreaSqFt: 1200}}),
+		CreatedByName: "Test User",
+		Applications: []forms.PrintoutApplication{
+			{
+				PestApp: forms.PestApp{
+					ID:            1,
+					AppTimestamp:  appTime,
+					Rate:          "2 oz/1000 sq ft",
+					AmountApplied: decimal.NewFromFloat(2.4),
+					LocationCode:  "1A",
+					Weather: forms.Weather{
+						WindSpeedMph:  &windSpeed,
+						WindDirection: "NW",
+						SkyConditions: "partly_cloudy",
+					},
+				},
+				BrandName: "Test Brand",
+				EpaRegNo:  "12345-67",
+				Unit:      "oz",
+			},
+		},
+	}
+	company := Company{
+		Name:                  "Green Lawns",
+		Address:               []string{"1 Main St", "Town, NJ 10001"},
+		LicenseNo:             "97000",
+		ResponsibleApplicator: "Pat Smith",
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, Form(&buf, printout, company))
+	require.True(t, bytes.HasPrefix(buf.Bytes(), []byte("%PDF-")))
+
+	// Shrub forms and forms without applications render too
+	form.FormType = "shrub"
+	printout.View = forms.NewShrubFormView(forms.ShrubForm{Form: form, ShrubDetails: forms.ShrubDetails{FleaOnly: true}})
+	printout.Applications = nil
+	buf.Reset()
+	require.NoError(t, Form(&buf, printout, Company{}))
+}
@@ -0,0 +1,74 @@
+package pdf
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/forms"
+)
+
+// pageAlias is the placeholder for the starting page of the i-th form of a packet
+func pageAlias(i int) string {
+	return fmt.Sprintf("{p%d}", i)
+}
+
+// Packet renders several forms into one document: a cover page summarizing the packet,
+// followed by each form's card on its own page. criteria describes how the forms were chosen.
+func Packet(w io.Writer, criteria []string, printouts []*forms.Printout, company Company) error {
+	doc := newDocument("P", "Form Packet")
+	doc.AddPage()
+
+	title := "Form Packet"
+	if company.Name != "" {
+		title = company.Name + " - " + title
+	}
+	doc.text("B", 14, title)
+	doc.text("", 8, "Generated "+time.Now().Format("01/02/2006 3:04 PM"))
+	for _, line := range criteria {
+		doc.text("", 9, line)
+	}
+
+	applications := 0
+	for _, printout := range printouts {
+		applications += len(printout.Applications)
+	}
+	doc.text("B", 9, fmt.Sprintf("%d forms, %d applications", len(printouts), applications))
+	doc.Ln(2)
+
+	rows := make([][]string, 0, len(printouts))
+	for i, printout := range printouts {
+		form := printout.Form()
+		lastApplication := ""
+		if len(printout.Applications) > 0 {
+			lastApplication = formatDate(form.LastAppDate)
+		}
+		rows = append(rows, []string{
+			pageAlias(i),
+			form.FirstName + " " + form.LastName,
+			fmt.Sprintf("%s %s, %s %s", form.StreetNumber, form.StreetName, form.Town, form.ZipCode),
+			form.FormType,
+			strconv.Itoa(len(printout.Applications)),
+			lastApplication,
+			printout.CreatedByName,
+		})
+	}
+	doc.table([]column{
+		{header: "Page", width: 12, align: "L"},
+		{header: "Client", width: 38, align: "L"},
+		{header: "Address", width: 62, align: "L"},
+		{header: "Type", width: 12, align: "L"},
+		{header: "Apps", width: 12, align: "R"},
+		{header: "Last Application", width: 24, align: "L"},
+		{header: "Created By", width: 31.9, align: "L"},
+	}, rows)
+
+	// The cover lists the page each form starts on, known once the form is rendered
+	for i, printout := range printouts {
+		doc.RegisterAlias(pageAlias(i), strconv.Itoa(doc.PageNo()+1))
+		doc.formPage(printout, company)
+	}
+
+	return doc.Output(w)
+}
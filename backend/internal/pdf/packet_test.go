@@ -0,0 +1,67 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/forms"
+	"github.com/stretchr/testify/require"
+)
+
+// pageContent returns the uncompressed content streams of a rendered document
+func pageContent(t *testing.T, doc []byte) string {
+	t.Helper()
+
+	var content bytes.Buffer
+	for {
+		start := bytes.Index(doc, []byte("stream\n"))
+		if start < 0 {
+			break
+		}
+		doc = doc[start+len("stream\n"):]
+		end := bytes.Index(doc, []byte("\nendstream"))
+		require.GreaterOrEqual(t, end, 0)
+		if reader, err := zlib.NewReader(bytes.NewReader(doc[:end])); err == nil {
+			_, err = io.Copy(&content, reader)
+			require.NoError(t, err)
+		}
+		doc = doc[end+len("\nendstream"):]
+	}
+	return content.String()
+}
+
+func TestPacket(t *testing.T) {
+	printouts := make([]*forms.Printout, 0, 3)
+	for i, name := range []string{"Ada", "Ben", "Cy"} {
+		form := forms.Form{
+			ID:           name,
+			FormType:     "shrub",
+			CreatedAt:    time.Now(),
+			FirstName:    name,
+			LastName:     "Packet",
+			StreetNumber: "1",
+			StreetName:   "Route Rd",
+			Town:         "Town",
+			ZipCode:      "10001",
+			HomePhone:    "555-0000",
+		}
+		printouts = append(printouts, &forms.Printout{
+			View:          forms.NewShrubFormView(forms.ShrubForm{Form: form, ShrubDetails: forms.ShrubDetails{FleaOnly: i%2 == 0}}),
+			CreatedByName: "Test User",
+		})
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, Packet(&buf, []string{"Zip code 10001"}, printouts, Company{Name: "Green Lawns"}))
+	require.True(t, bytes.HasPrefix(buf.Bytes(), []byte("%PDF-")))
+	// A cover page plus one page per form
+	require.Equal(t, 4, bytes.Count(buf.Bytes(), []byte("/Type /Page\n")))
+	// Page placeholders on the cover are filled in
+	content := pageContent(t, buf.Bytes())
+	require.Contains(t, content, "Ada Packet")
+	require.NotContains(t, content, "{p0}")
+	require.Contains(t, content, "(4)")
+}
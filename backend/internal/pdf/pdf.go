@@ -0,0 +1,107 @@
+// Package pdf renders forms and reports as PDF documents on the server.
+// It uses gofpdf's built-in Helvetica, so text is limited to the Windows-1252 character set;
+// other characters are dropped.
+package pdf
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+const (
+	fontFamily = "Helvetica"
+	lineHeight = 5.0
+	rowHeight  = 6.0
+)
+
+// document wraps a gofpdf document with the text translation and table helpers shared by
+// every rendering
+type document struct {
+	*gofpdf.Fpdf
+	tr func(string) string
+}
+
+// newDocument starts a document with a page number footer.
+// orientation is "P" for portrait or "L" for landscape.
+func newDocument(orientation string, title string) *document {
+	pdf := gofpdf.New(orientation, "mm", "Letter", "")
+	pdf.SetMargins(12, 12, 12)
+	pdf.SetAutoPageBreak(true, 15)
+	pdf.SetTitle(title, true)
+	pdf.SetCreator("LandscapeForm", true)
+	pdf.AliasNbPages("")
+
+	doc := &document{Fpdf: pdf, tr: pdf.UnicodeTranslatorFromDescriptor("")}
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-12)
+		pdf.SetFont(fontFamily, "", 7)
+		pdf.CellFormat(0, 4, fmt.Sprintf("Page %d of {nb}", pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+	return doc
+}
+
+// text writes a full-width line in the given style and size
+func (d *document) text(style string, size float64, s string) {
+	d.SetFont(fontFamily, style, size)
+	d.CellFormat(0, lineHeight+size/4, d.tr(s), "", 1, "L", false, 0, "")
+}
+
+// fit shortens s with an ellipsis until it fits in width at the current font
+func (d *document) fit(s string, width float64) string {
+	s = d.tr(s)
+	const padding = 2
+	if d.GetStringWidth(s)+padding <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && d.GetStringWidth(string(runes)+"...")+padding > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
+
+// column is a table column; align is "L", "C" or "R"
+type column struct {
+	header string
+	width  float64
+	align  string
+}
+
+// table draws rows under a shaded header row, repeating the header on every page it spans.
+// Cell text that does not fit its column is shortened.
+func (d *document) table(columns []column, rows [][]string) {
+	header := func() {
+		d.SetFont(fontFamily, "B", 7)
+		d.SetFillColor(224, 224, 224)
+		for _, col := range columns {
+			d.CellFormat(col.width, rowHeight, d.fit(col.header, col.width), "1", 0, "C", true, 0, "")
+		}
+		d.Ln(-1)
+	}
+
+	header()
+	_, pageHeight := d.GetPageSize()
+	_, bottomMargin := d.GetAutoPageBreak()
+	for _, row := range rows {
+		if d.GetY()+rowHeight > pageHeight-bottomMargin {
+			d.AddPage()
+			header()
+		}
+		d.SetFont(fontFamily, "", 7)
+		for i, col := range columns {
+			value := ""
+			if i < len(row) {
+				value = row[i]
+			}
+			d.CellFormat(col.width, rowHeight, d.fit(value, col.width), "1", 0, col.align, false, 0, "")
+		}
+		d.Ln(-1)
+	}
+}
+
+// formatDate formats a timestamp as a calendar date in the server's time zone
+func formatDate(t time.Time) string {
+	return t.Local().Format("01/02/2006")
+}
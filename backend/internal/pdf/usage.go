@@ -0,0 +1,98 @@
+package pdf
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/reports"
+)
+
+// PesticideUsage renders the annual pesticide usage report: the totals table followed by the
+// applications flagged because their chemical changed afterwards.
+func PesticideUsage(w io.Writer, report reports.PesticideUsageReport) error {
+	doc := newDocument("L", fmt.Sprintf("Pesticide Usage Report %d", report.Year))
+	doc.AddPage()
+
+	doc.text("B", 14, fmt.Sprintf("Pesticide Usage Report %d", report.Year))
+	doc.text("", 8, "Generated "+time.Now().Format("01/02/2006 3:04 PM"))
+	doc.Ln(2)
+
+	if len(report.Rows) == 0 {
+		doc.text("I", 9, "No applications were recorded this year.")
+	} else {
+		rows := make([][]string, 0, len(report.Rows))
+		for _, row := range report.Rows {
+			flagged := ""
+			if row.FlaggedApplications > 0 {
+				flagged = strconv.Itoa(row.FlaggedApplications)
+			}
+			rows = append(rows, []string{
+				row.Month.String(),
+				row.EpaRegNo,
+				strings.Join(row.BrandNames, ", "),
+				row.Town,
+				row.TotalAmount.String(),
+				row.Unit,
+				strconv.Itoa(row.Applications),
+				strconv.Itoa(row.Properties),
+				flagged,
+			})
+		}
+		doc.table([]column{
+			{header: "Month", width: 22, align: "L"},
+			{header: "EPA Reg No", width: 30, align: "L"},
+			{header: "Product", width: 62, align: "L"},
+			{header: "Town", width: 40, align: "L"},
+			{header: "Amount", width: 24, align: "R"},
+			{header: "Unit", width: 18, align: "L"},
+			{header: "Applications", width: 22, align: "R"},
+			{header: "Properties", width: 20, align: "R"},
+			{header: "Flagged", width: 17, align: "R"},
+		}, rows)
+	}
+
+	if len(report.Flagged) > 0 {
+		doc.Ln(6)
+		doc.text("B", 11, "Flagged applications")
+		doc.text("", 8, "The chemical of these applications was edited or deleted afterwards; check the totals above against the form.")
+		doc.Ln(1)
+
+		rows := make([][]string, 0, len(report.Flagged))
+		for _, app := range report.Flagged {
+			reasons := make([]string, 0, len(app.Reasons))
+			for _, reason := range app.Reasons {
+				switch reason {
+				case reports.FlagChemicalEdited:
+					reasons = append(reasons, "edited "+formatDate(*app.ChemicalEditedAt))
+				case reports.FlagChemicalDeleted:
+					reasons = append(reasons, "deleted "+formatDate(*app.ChemicalDeletedAt))
+				}
+			}
+			rows = append(rows, []string{
+				formatDate(app.AppTimestamp),
+				app.EpaRegNo,
+				app.BrandName,
+				app.Town,
+				app.AmountApplied.String(),
+				app.Unit,
+				strings.Join(reasons, ", "),
+				app.FormID,
+			})
+		}
+		doc.table([]column{
+			{header: "Date", width: 20, align: "L"},
+			{header: "EPA Reg No", width: 28, align: "L"},
+			{header: "Product", width: 45, align: "L"},
+			{header: "Town", width: 32, align: "L"},
+			{header: "Amount", width: 20, align: "R"},
+			{header: "Unit", width: 15, align: "L"},
+			{header: "Chemical", width: 41, align: "L"},
+			{header: "Form", width: 54, align: "L"},
+		}, rows)
+	}
+
+	return doc.Output(w)
+}
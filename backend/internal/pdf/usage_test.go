@@ -0,0 +1,61 @@
+package pdf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/reports"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPesticideUsage(t *testing.T) {
+	editedAt := time.Date(2025, time.August, 1, 9, 0, 0, 0, time.UTC)
+	report := reports.PesticideUsageReport{
+		Year: 2025,
+		Rows: []reports.PesticideUsageRow{
+			{
+				Month:               time.June,
+				EpaRegNo:            "12345-67",
+				Unit:                "oz",
+				Town:                "Town",
+				BrandNames:          []string{"Test Brand", strings.Repeat("Very Long Brand Name ", 10)},
+				TotalAmount:         decimal.NewFromFloat(4.5),
+				Applications:        2,
+				Properties:          1,
+				FlaggedApplications: 1,
+			},
+		},
+		Flagged: []reports.FlaggedApplication{
+			{
+				ApplicationID:    1,
+				FormID:           "5b0c7c53-3c4e-4a8e-9a43-0d4f2f5e8c11",
+				AppTimestamp:     time.Date(2025, time.June, 3, 9, 0, 0, 0, time.UTC),
+				BrandName:        "Test Brand",
+				EpaRegNo:         "12345-67",
+				Unit:             "oz",
+				AmountApplied:    decimal.NewFromFloat(3),
+				Town:             "Town",
+				Reasons:          []string{reports.FlagChemicalEdited},
+				ChemicalEditedAt: &editedAt,
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, PesticideUsage(&buf, report))
+	require.True(t, bytes.HasPrefix(buf.Bytes(), []byte("%PDF-")))
+
+	// A report spanning several pages still renders
+	for i := 0; i < 200; i++ {
+		report.Rows = append(report.Rows, report.Rows[0])
+	}
+	buf.Reset()
+	require.NoError(t, PesticideUsage(&buf, report))
+
+	// So does an empty year
+	buf.Reset()
+	require.NoError(t, PesticideUsage(&buf, reports.PesticideUsageReport{Year: 2024}))
+}
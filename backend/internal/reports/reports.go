@@ -0,0 +1,203 @@
+// Package reports provides the aggregate reports filed with regulators.
+package reports
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/shopspring/decimal"
+)
+
+// ReportsRepository provides read-only access to report data.
+type ReportsRepository struct {
+	db *sql.DB
+}
+
+// NewReportsRepository returns a repository backed by the given database connection.
+func NewReportsRepository(database *sql.DB) *ReportsRepository {
+	return &ReportsRepository{db: database}
+}
+
+// Reasons an application is flagged in the pesticide usage report
+const (
+	// FlagChemicalEdited means the chemical's details changed after the application was made
+	FlagChemicalEdited = "chemical_edited"
+	// FlagChemicalDeleted means the chemical has since been deleted
+	FlagChemicalDeleted = "chemical_deleted"
+)
+
+// PesticideUsageRow totals the product applied under one EPA registration number and unit
+// in one town and month
+type PesticideUsageRow struct {
+	Month    time.Month
+	EpaRegNo string
+	Unit     string
+	Town     string
+	// BrandNames lists the chemicals sharing the registration number that were applied
+	BrandNames  []string
+	TotalAmount decimal.Decimal
+	// Applications counts the applications, Properties the distinct addresses treated
+	Applications int
+	Properties   int
+	// FlaggedApplications counts the applications listed in PesticideUsageReport.Flagged
+	FlaggedApplications int
+}
+
+// FlaggedApplication is an application whose chemical was edited or deleted after it was made,
+// so its row in the report may not reflect what was actually applied
+type FlaggedApplication struct {
+	ApplicationID int
+	FormID        string
+	AppTimestamp  time.Time
+	ChemUsed      int
+	BrandName     string
+	EpaRegNo      string
+	Unit          string
+	AmountApplied decimal.Decimal
+	Town          string
+	// Reasons lists FlagChemicalEdited and/or FlagChemicalDeleted
+	Reasons           []string
+	ChemicalEditedAt  *time.Time
+	ChemicalDeletedAt *time.Time
+}
+
+// PesticideUsageReport is the annual pesticide use report
+type PesticideUsageReport struct {
+	Year    int
+	Rows    []PesticideUsageRow
+	Flagged []FlaggedApplication
+}
+
+// propertyKey identifies the property an application was made at: the linked property,
+// or the normalized address for forms without one
+const propertyKey = `COALESCE(
+	f.property_id::text,
+	LOWER(TRIM(f.street_number)) || '|' || normalize_street(f.street_name) || '|' || TRIM(f.zip_code)
+)`
+
+// flaggedCondition matches applications whose chemical changed after they were made
+const flaggedCondition = `(c.edited_at > pa.app_timestamp OR c.deleted_at IS NOT NULL)`
+
+// GetPesticideUsage totals the product applied in the given year, grouped by the chemical's
+// EPA registration number and unit, the town of the form and the month of the application.
+// Applications on forms in the trash are left out. Rows are ordered by month, EPA registration
+// number, unit and town; flagged applications oldest first.
+func (r *ReportsRepository) GetPesticideUsage(ctx context.Context, year int) (PesticideUsageReport, error) {
+	report := PesticideUsageReport{
+		Year:    year,
+		Rows:    []PesticideUsageRow{},
+		Flagged: []FlaggedApplication{},
+	}
+
+	yearApplications := `
+		FROM pesticide_applications pa
+		JOIN forms f ON f.id = pa.form_id
+		JOIN chemicals c ON c.id = pa.chem_used
+		WHERE f.deleted_at IS NULL
+		  AND pa.app_timestamp >= make_timestamptz($1, 1, 1, 0, 0, 0)
+		  AND pa.app_timestamp < make_timestamptz($1 + 1, 1, 1, 0, 0, 0)
+	`
+
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT
+			EXTRACT(MONTH FROM pa.app_timestamp)::int AS month,
+			c.epa_reg_no,
+			c.unit,
+			f.town,
+			array_agg(DISTINCT c.brand_name ORDER BY c.brand_name),
+			SUM(pa.amount_applied),
+			COUNT(*),
+			COUNT(DISTINCT %s),
+			COUNT(*) FILTER (WHERE %s)
+		%s
+		GROUP BY month, c.epa_reg_no, c.unit, f.town
+		ORDER BY month, c.epa_reg_no, c.unit, f.town
+	`, propertyKey, flaggedCondition, yearApplications), year)
+	if err != nil {
+		return PesticideUsageReport{}, fmt.Errorf("error querying pesticide usage: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			row   PesticideUsageRow
+			month int
+		)
+		err := rows.Scan(
+			&month,
+			&row.EpaRegNo,
+			&row.Unit,
+			&row.Town,
+			pq.Array(&row.BrandNames),
+			&row.TotalAmount,
+			&row.Applications,
+			&row.Properties,
+			&row.FlaggedApplications,
+		)
+		if err != nil {
+			return PesticideUsageReport{}, fmt.Errorf("error scanning pesticide usage row: %w", err)
+		}
+		row.Month = time.Month(month)
+		report.Rows = append(report.Rows, row)
+	}
+	if err := rows.Err(); err != nil {
+		return PesticideUsageReport{}, fmt.Errorf("error after pesticide usage query: %w", err)
+	}
+
+	flaggedRows, err := r.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT
+			pa.id,
+			pa.form_id,
+			pa.app_timestamp,
+			c.id,
+			c.brand_name,
+			c.epa_reg_no,
+			c.unit,
+			pa.amount_applied,
+			f.town,
+			c.edited_at,
+			c.deleted_at
+		%s
+		  AND %s
+		ORDER BY pa.app_timestamp, pa.id
+	`, yearApplications, flaggedCondition), year)
+	if err != nil {
+		return PesticideUsageReport{}, fmt.Errorf("error querying flagged applications: %w", err)
+	}
+	defer flaggedRows.Close()
+
+	for flaggedRows.Next() {
+		var app FlaggedApplication
+		err := flaggedRows.Scan(
+			&app.ApplicationID,
+			&app.FormID,
+			&app.AppTimestamp,
+			&app.ChemUsed,
+			&app.BrandName,
+			&app.EpaRegNo,
+			&app.Unit,
+			&app.AmountApplied,
+			&app.Town,
+			&app.ChemicalEditedAt,
+			&app.ChemicalDeletedAt,
+		)
+		if err != nil {
+			return PesticideUsageReport{}, fmt.Errorf("error scanning flagged application: %w", err)
+		}
+		if app.ChemicalEditedAt != nil && app.ChemicalEditedAt.After(app.AppTimestamp) {
+			app.Reasons = append(app.Reasons, FlagChemicalEdited)
+		}
+		if app.ChemicalDeletedAt != nil {
+			app.Reasons = append(app.Reasons, FlagChemicalDeleted)
+		}
+		report.Flagged = append(report.Flagged, app)
+	}
+	if err := flaggedRows.Err(); err != nil {
+		return PesticideUsageReport{}, fmt.Errorf("error after flagged applications query: %w", err)
+	}
+
+	return report, nil
+}
@@ -0,0 +1,191 @@
+package reports
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/chemicals"
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/db"
+	"github.com/BiryaniJedi/LandscapeForm-backend/internal/forms"
+	"github.com/joho/godotenv"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	// Load test-specific environment variables
+	_ = godotenv.Load("../../.env.testing")
+
+	os.Exit(m.Run())
+}
+
+func createTestUser(t testing.TB, db *sql.DB) string {
+	t.Helper()
+
+	var id string
+	err := db.QueryRow(`
+		INSERT INTO users (first_name, last_name, username, password_hash)
+		VALUES ('Test', 'User', 'TestUser_' || gen_random_uuid()::text, 'TestPass')
+		RETURNING id
+	`).Scan(&id)
+
+	require.NoError(t, err)
+	return id
+}
+
+func createTestChemical(t testing.TB, db *sql.DB, brandName string, epaRegNo string) int {
+	t.Helper()
+
+	var id int
+	err := db.QueryRow(`
+		INSERT INTO chemicals (category, brand_name, chemical_name, epa_reg_no, recipe, unit)
+		VALUES ('lawn', $1, 'Test Chemical', $2, 'Test Recipe', 'oz')
+		RETURNING id
+	`, brandName, epaRegNo).Scan(&id)
+
+	require.NoError(t, err)
+	return id
+}
+
+func TestGetPesticideUsage(t *testing.T) {
+	ctx := context.Background()
+	database := db.TestDB(t)
+	repo := NewReportsRepository(database)
+	formsRepo := forms.NewFormsRepository(database)
+	chemicalsRepo := chemicals.NewChemicalsRepository(database)
+
+	userID := createTestUser(t, database)
+	keptID := createTestChemical(t, database, "Kept Brand", "111-1")
+	editedID := createTestChemical(t, database, "Edited Brand", "222-2")
+	deletedID := createTestChemical(t, database, "Deleted Brand", "333-3")
+
+	june := time.Date(2025, time.June, 10, 9, 0, 0, 0, time.UTC)
+	july := time.Date(2025, time.July, 10, 9, 0, 0, 0, time.UTC)
+	app := func(chemID int, at time.Time, amount float64) forms.PestApp {
+		return forms.PestApp{
+			ChemUsed:      chemID,
+			AppTimestamp:  at,
+			Rate:          "1 oz/1000 sq ft",
+			AmountApplied: decimal.NewFromFloat(amount),
+			LocationCode:  "1A",
+		}
+	}
+
+	_, err := formsRepo.CreateLawnForm(ctx, forms.CreateLawnFormInput{
+		CreatedBy:    userID,
+		FirstName:    "Rita",
+		LastName:     "Report",
+		StreetNumber: "1",
+		StreetName:   "Usage Rd",
+		Town:         "Northfield",
+		ZipCode:      "10001",
+		HomePhone:    "555-0001",
+		LawnAreaSqFt: 1000,
+		Applications: []forms.PestApp{
+			app(keptID, june, 2),
+			app(keptID, june.Add(24*time.Hour), 1.5),
+			app(editedID, june, 4),
+			app(deletedID, july, 1),
+		},
+	})
+	require.NoError(t, err)
+
+	_, err = formsRepo.CreateLawnForm(ctx, forms.CreateLawnFormInput{
+		CreatedBy:    userID,
+		FirstName:    "Sam",
+		LastName:     "Summary",
+		StreetNumber: "22",
+		StreetName:   "Total Ave",
+		Town:         "Northfield",
+		ZipCode:      "10001",
+		HomePhone:    "555-0022",
+		LawnAreaSqFt: 2000,
+		Applications: []forms.PestApp{
+			app(keptID, june, 3),
+			// Another year is left out
+			app(keptID, june.AddDate(-1, 0, 0), 10),
+		},
+	})
+	require.NoError(t, err)
+
+	trashedID, err := formsRepo.CreateLawnForm(ctx, forms.CreateLawnFormInput{
+		CreatedBy:    userID,
+		FirstName:    "Tess",
+		LastName:     "Trashed",
+		StreetNumber: "3",
+		StreetName:   "Bin Ln",
+		Town:         "Northfield",
+		ZipCode:      "10001",
+		HomePhone:    "555-0003",
+		LawnAreaSqFt: 500,
+		Applications: []forms.PestApp{app(keptID, june, 100)},
+	})
+	require.NoError(t, err)
+	require.NoError(t, formsRepo.DeleteFormById(ctx, trashedID, userID))
+
+	// Edit one chemical and delete another after they were applied
+	_, err = chemicalsRepo.UpdateChemicalById(ctx, editedID, chemicals.ChemicalInput{
+		Category:     "lawn",
+		BrandName:    "Edited Brand",
+		ChemicalName: "Test Chemical",
+		EpaRegNo:     "222-9",
+		Recipe:       "Test Recipe",
+		Unit:         "oz",
+	})
+	require.NoError(t, err)
+	require.NoError(t, chemicalsRepo.DeleteChemicalById(ctx, deletedID))
+
+	report, err := repo.GetPesticideUsage(ctx, 2025)
+	require.NoError(t, err)
+	require.Equal(t, 2025, report.Year)
+	require.Len(t, report.Rows, 3)
+
+	kept := report.Rows[0]
+	require.Equal(t, time.June, kept.Month)
+	require.Equal(t, "111-1", kept.EpaRegNo)
+	require.Equal(t, "Northfield", kept.Town)
+	require.Equal(t, []string{"Kept Brand"}, kept.BrandNames)
+	require.Equal(t, "6.5", kept.TotalAmount.String())
+	require.Equal(t, 3, kept.Applications)
+	require.Equal(t, 2, kept.Properties)
+	require.Zero(t, kept.FlaggedApplications)
+
+	edited := report.Rows[1]
+	require.Equal(t, time.June, edited.Month)
+	require.Equal(t, "222-9", edited.EpaRegNo)
+	require.Equal(t, 1, edited.FlaggedApplications)
+
+	deleted := report.Rows[2]
+	require.Equal(t, time.July, deleted.Month)
+	require.Equal(t, "333-3", deleted.EpaRegNo)
+	require.Equal(t, 1, deleted.FlaggedApplications)
+
+	require.Len(t, report.Flagged, 2)
+	require.Equal(t, editedID, report.Flagged[0].ChemUsed)
+	require.Equal(t, []string{FlagChemicalEdited}, report.Flagged[0].Reasons)
+	require.Equal(t, deletedID, report.Flagged[1].ChemUsed)
+	require.Equal(t, []string{FlagChemicalDeleted}, report.Flagged[1].Reasons)
+	require.NotNil(t, report.Flagged[1].ChemicalDeletedAt)
+
+	// Applications made after the edit are not flagged
+	_, err = formsRepo.CreateLawnForm(ctx, forms.CreateLawnFormInput{
+		CreatedBy:    userID,
+		FirstName:    "Nora",
+		LastName:     "Now",
+		StreetNumber: "4",
+		StreetName:   "Present Pl",
+		Town:         "Southfield",
+		ZipCode:      "10002",
+		HomePhone:    "555-0004",
+		LawnAreaSqFt: 800,
+		Applications: []forms.PestApp{app(editedID, time.Now().Add(-time.Minute), 1)},
+	})
+	require.NoError(t, err)
+
+	report, err = repo.GetPesticideUsage(ctx, time.Now().Year())
+	require.NoError(t, err)
+	require.Empty(t, report.Flagged)
+}
@@ -0,0 +1,89 @@
+// Package sitecodes defines the site location codes recorded on pesticide applications.
+// A code is an area number followed by a feature letter, e.g. "1A" for the lawn in the front yard.
+package sitecodes
+
+// Part is one half of a site code
+type Part struct {
+	Code        string
+	Description string
+}
+
+// Areas are the first half of a site code: where on the property the application was made
+var Areas = []Part{
+	{Code: "1", Description: "Front Yard"},
+	{Code: "2", Description: "Side Yard(s)"},
+	{Code: "3", Description: "Rear Yard"},
+	{Code: "4", Description: "Entire Property"},
+	{Code: "5", Description: "Other"},
+}
+
+// Features are the second half of a site code: what was treated
+var Features = []Part{
+	{Code: "A", Description: "Lawn"},
+	{Code: "B", Description: "Shrub(s)"},
+	{Code: "C", Description: "Ornamental Tree(s)"},
+	{Code: "D", Description: "Other"},
+}
+
+// SiteCode is a complete site code with its decoded parts
+type SiteCode struct {
+	Code    string
+	Area    string
+	Feature string
+	// Description reads like "Front Yard – Lawn"
+	Description string
+}
+
+func findPart(parts []Part, code string) (Part, bool) {
+	for _, part := range parts {
+		if part.Code == code {
+			return part, true
+		}
+	}
+	return Part{}, false
+}
+
+// Lookup decodes a site code. Codes are matched exactly, so lowercase letters are unknown.
+func Lookup(code string) (SiteCode, bool) {
+	if len(code) != 2 {
+		return SiteCode{}, false
+	}
+	area, ok := findPart(Areas, code[:1])
+	if !ok {
+		return SiteCode{}, false
+	}
+	feature, ok := findPart(Features, code[1:])
+	if !ok {
+		return SiteCode{}, false
+	}
+	return SiteCode{
+		Code:        code,
+		Area:        area.Description,
+		Feature:     feature.Description,
+		Description: area.Description + " – " + feature.Description,
+	}, true
+}
+
+// Valid reports whether code is a known site code
+func Valid(code string) bool {
+	_, ok := Lookup(code)
+	return ok
+}
+
+// Describe returns the description of a site code, or an empty string if the code is unknown
+func Describe(code string) string {
+	siteCode, _ := Lookup(code)
+	return siteCode.Description
+}
+
+// All returns every site code, ordered by area and then feature
+func All() []SiteCode {
+	codes := make([]SiteCode, 0, len(Areas)*len(Features))
+	for _, area := range Areas {
+		for _, feature := range Features {
+			siteCode, _ := Lookup(area.Code + feature.Code)
+			codes = append(codes, siteCode)
+		}
+	}
+	return codes
+}
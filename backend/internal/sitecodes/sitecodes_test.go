@@ -0,0 +1,36 @@
+package sitecodes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookup(t *testing.T) {
+	code, ok := Lookup("1A")
+	require.True(t, ok)
+	require.Equal(t, SiteCode{
+		Code:        "1A",
+		Area:        "Front Yard",
+		Feature:     "Lawn",
+		Description: "Front Yard – Lawn",
+	}, code)
+
+	require.Equal(t, "Entire Property – Ornamental Tree(s)", Describe("4C"))
+	require.True(t, Valid("5D"))
+
+	for _, bad := range []string{"", "1", "A1", "1a", "6A", "1E", "1AB", "FL"} {
+		require.False(t, Valid(bad), bad)
+		require.Empty(t, Describe(bad), bad)
+	}
+}
+
+func TestAll(t *testing.T) {
+	codes := All()
+	require.Len(t, codes, len(Areas)*len(Features))
+	require.Equal(t, "1A", codes[0].Code)
+	require.Equal(t, "5D", codes[len(codes)-1].Code)
+	for _, code := range codes {
+		require.True(t, Valid(code.Code), code.Code)
+	}
+}
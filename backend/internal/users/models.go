@@ -1,9 +1,50 @@
 package users
 
 import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
 	"time"
 )
 
+// ErrInvalidLicense is wrapped by the errors License.Validate returns
+var ErrInvalidLicense = errors.New("invalid applicator license")
+
+// licenseStatePattern matches a two-letter state code such as "NJ"
+var licenseStatePattern = regexp.MustCompile(`^[A-Z]{2}$`)
+
+// License is a certified pesticide applicator's license
+type License struct {
+	Number    string    `json:"number"`
+	Category  string    `json:"category"`
+	State     string    `json:"state"`
+	ExpiresOn time.Time `json:"expires_on"`
+}
+
+// Validate returns an error wrapping ErrInvalidLicense if a field is missing or malformed
+func (l License) Validate() error {
+	switch {
+	case strings.TrimSpace(l.Number) == "":
+		return fmt.Errorf("%w: number is required", ErrInvalidLicense)
+	case strings.TrimSpace(l.Category) == "":
+		return fmt.Errorf("%w: category is required", ErrInvalidLicense)
+	case !licenseStatePattern.MatchString(l.State):
+		return fmt.Errorf("%w: state must be a two-letter code such as NJ", ErrInvalidLicense)
+	case l.ExpiresOn.IsZero():
+		return fmt.Errorf("%w: expiry date is required", ErrInvalidLicense)
+	}
+	return nil
+}
+
+// ExpiredOn reports whether the license had expired by the given day.
+// A license is good through its expiry date.
+func (l License) ExpiredOn(day time.Time) bool {
+	y, m, d := day.Date()
+	return l.ExpiresOn.Before(time.Date(y, m, d, 0, 0, 0, 0, time.UTC))
+}
+
 type User struct {
 	ID           string
 	CreatedAt    time.Time
@@ -33,4 +74,32 @@ type GetUserResponse struct {
 	LastName    string    `json:"last_name"`
 	DateOfBirth time.Time `json:"date_of_birth"`
 	Username    string    `json:"username"`
+	// License is nil for users without an applicator license on file
+	License *License `json:"license"`
+}
+
+// licenseRow holds a user's nullable license columns
+type licenseRow struct {
+	Number    sql.NullString
+	Category  sql.NullString
+	State     sql.NullString
+	ExpiresOn sql.NullTime
+}
+
+// dest returns the scan destinations of the license columns, in the order
+// license_number, license_category, license_state, license_expires_on
+func (row *licenseRow) dest() []any {
+	return []any{&row.Number, &row.Category, &row.State, &row.ExpiresOn}
+}
+
+func (row licenseRow) toDomain() *License {
+	if !row.Number.Valid {
+		return nil
+	}
+	return &License{
+		Number:    row.Number.String,
+		Category:  row.Category.String,
+		State:     row.State.String,
+		ExpiresOn: row.ExpiresOn.Time,
+	}
 }
@@ -41,6 +41,8 @@ type UpdateUserInput struct {
 	DoB       time.Time
 	Username  string
 	Password  string
+	// License replaces the user's applicator license when non-nil; nil leaves it unchanged
+	License *License
 }
 
 // CreateUser creates a new user in the Users table, it, role is 'employee'
@@ -110,13 +112,20 @@ func (r *UsersRepository) GetUserById(
 			u.first_name,
 			u.last_name,
 			u.date_of_birth,
-			u.username
+			u.username,
+			u.license_number,
+			u.license_category,
+			u.license_state,
+			u.license_expires_on
 		FROM users u
 		WHERE u.id = $1
 	`
 
-	var res GetUserResponse
-	err := r.db.QueryRowContext(ctx, query, userID).Scan(
+	var (
+		res     GetUserResponse
+		license licenseRow
+	)
+	dest := append([]any{
 		&res.ID,
 		&res.CreatedAt,
 		&res.UpdatedAt,
@@ -126,11 +135,13 @@ func (r *UsersRepository) GetUserById(
 		&res.LastName,
 		&res.DateOfBirth,
 		&res.Username,
-	)
+	}, license.dest()...)
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(dest...)
 	if err != nil {
 		// Important: let sql.ErrNoRows propagate
 		return GetUserResponse{}, err
 	}
+	res.License = license.toDomain()
 
 	return res, nil
 }
@@ -252,7 +263,11 @@ func (r *UsersRepository) ListUsers(
 			first_name,
 			last_name,
 			date_of_birth,
-			username
+			username,
+			license_number,
+			license_category,
+			license_state,
+			license_expires_on
 		FROM users
 		ORDER BY %s %s
 	`, sortColumn, order)
@@ -263,11 +278,13 @@ func (r *UsersRepository) ListUsers(
 	}
 	defer rows.Close()
 
-	var getUserResponse GetUserResponse
 	var users []GetUserResponse
 	for rows.Next() {
-
-		err := rows.Scan(
+		var (
+			getUserResponse GetUserResponse
+			license         licenseRow
+		)
+		dest := append([]any{
 			&getUserResponse.ID,
 			&getUserResponse.CreatedAt,
 			&getUserResponse.UpdatedAt,
@@ -277,11 +294,14 @@ func (r *UsersRepository) ListUsers(
 			&getUserResponse.LastName,
 			&getUserResponse.DateOfBirth,
 			&getUserResponse.Username,
-		)
+		}, license.dest()...)
+
+		err := rows.Scan(dest...)
 
 		if err != nil {
 			return nil, err
 		}
+		getUserResponse.License = license.toDomain()
 
 		users = append(users, getUserResponse)
 	}
@@ -293,12 +313,19 @@ func (r *UsersRepository) ListUsers(
 }
 
 // UpdateUserById updates a user and its associated subtype fields.
-// It returns sql.ErrNoRows if the user does not exist.
+// It returns sql.ErrNoRows if the user does not exist,
+// and an error wrapping ErrInvalidLicense if the new license is invalid.
 func (r *UsersRepository) UpdateUserById(
 	ctx context.Context,
 	userID string,
 	userInput UpdateUserInput,
 ) (UserRepResponse, error) {
+	if userInput.License != nil {
+		if err := userInput.License.Validate(); err != nil {
+			return UserRepResponse{}, err
+		}
+	}
+
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return UserRepResponse{}, err
@@ -349,6 +376,12 @@ func (r *UsersRepository) UpdateUserById(
 		return UserRepResponse{}, err
 	}
 
+	if userInput.License != nil {
+		if err := setLicense(ctx, tx, userID, userInput.License); err != nil {
+			return UserRepResponse{}, err
+		}
+	}
+
 	if err := tx.Commit(); err != nil {
 		return UserRepResponse{}, err
 	}
@@ -356,6 +389,56 @@ func (r *UsersRepository) UpdateUserById(
 	return res, nil
 }
 
+// setLicense replaces a user's applicator license inside tx, clearing it when license is nil.
+// It returns sql.ErrNoRows if the user does not exist.
+func setLicense(ctx context.Context, tx *sql.Tx, userID string, license *License) error {
+	var number, category, state, expiresOn any
+	if license != nil {
+		number, category, state, expiresOn = license.Number, license.Category, license.State, license.ExpiresOn
+	}
+	return tx.QueryRowContext(ctx, `
+		UPDATE users
+		SET license_number = $1,
+			license_category = $2,
+			license_state = $3,
+			license_expires_on = $4
+		WHERE id = $5
+		RETURNING id
+	`, number, category, state, expiresOn, userID).Scan(&userID)
+}
+
+// SetUserLicense replaces a user's applicator license, or clears it when license is nil.
+// Returns the updated user upon success.
+// It returns sql.ErrNoRows if the user does not exist,
+// and an error wrapping ErrInvalidLicense if the license is invalid.
+func (r *UsersRepository) SetUserLicense(
+	ctx context.Context,
+	userID string,
+	license *License,
+) (GetUserResponse, error) {
+	if license != nil {
+		if err := license.Validate(); err != nil {
+			return GetUserResponse{}, err
+		}
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return GetUserResponse{}, err
+	}
+	defer tx.Rollback()
+
+	if err := setLicense(ctx, tx, userID, license); err != nil {
+		return GetUserResponse{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return GetUserResponse{}, err
+	}
+
+	return r.GetUserById(ctx, userID)
+}
+
 // DeleteUserById deletes a user.
 // It returns sql.ErrNoRows if the user does not exist.
 func (r *UsersRepository) DeleteUserById(
@@ -627,3 +627,87 @@ func TestApproveUserRegistrationAlreadyApproved(t *testing.T) {
 	require.NoError(t, err, "GetUserById failed after second approval")
 	require.False(t, user.Pending, "User should still not be pending after second approval")
 }
+
+// TestUserLicense tests recording, updating and clearing an applicator license
+func TestUserLicense(t *testing.T) {
+	ctx := context.Background()
+	database := db.TestDB(t)
+	repo := NewUsersRepository(database)
+
+	createRes, err := repo.CreateUser(ctx, CreateUserInput{
+		FirstName: "Licensed",
+		LastName:  "Applicator",
+		DoB:       time.Date(1985, 4, 2, 0, 0, 0, 0, time.UTC),
+		Username:  "licensed",
+		Password:  "password123",
+	})
+	require.NoError(t, err, "CreateUser failed")
+
+	user, err := repo.GetUserById(ctx, createRes.ID)
+	require.NoError(t, err)
+	require.Nil(t, user.License, "New users have no license on file")
+
+	license := License{
+		Number:    "57123A",
+		Category:  "3B",
+		State:     "NJ",
+		ExpiresOn: time.Date(2027, 10, 31, 0, 0, 0, 0, time.UTC),
+	}
+	user, err = repo.SetUserLicense(ctx, createRes.ID, &license)
+	require.NoError(t, err, "SetUserLicense failed")
+	require.NotNil(t, user.License)
+	require.Equal(t, license.Number, user.License.Number)
+	require.Equal(t, license.Category, user.License.Category)
+	require.Equal(t, license.State, user.License.State)
+	require.True(t, license.ExpiresOn.Equal(user.License.ExpiresOn))
+
+	// Updating a user without a license leaves it unchanged
+	_, err = repo.UpdateUserById(ctx, createRes.ID, UpdateUserInput{
+		FirstName: "Licensed",
+		LastName:  "Applicator",
+		DoB:       time.Date(1985, 4, 2, 0, 0, 0, 0, time.UTC),
+		Username:  "licensed",
+	})
+	require.NoError(t, err, "UpdateUserById failed")
+	users, err := repo.ListUsers(ctx, "last_name", "ASC")
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	require.NotNil(t, users[0].License)
+	require.Equal(t, license.Number, users[0].License.Number)
+
+	// ...and replaces it when given one
+	renewed := license
+	renewed.ExpiresOn = time.Date(2030, 10, 31, 0, 0, 0, 0, time.UTC)
+	_, err = repo.UpdateUserById(ctx, createRes.ID, UpdateUserInput{
+		FirstName: "Licensed",
+		LastName:  "Applicator",
+		DoB:       time.Date(1985, 4, 2, 0, 0, 0, 0, time.UTC),
+		Username:  "licensed",
+		License:   &renewed,
+	})
+	require.NoError(t, err, "UpdateUserById failed")
+	user, err = repo.GetUserById(ctx, createRes.ID)
+	require.NoError(t, err)
+	require.True(t, renewed.ExpiresOn.Equal(user.License.ExpiresOn))
+
+	invalid := license
+	invalid.State = "New Jersey"
+	_, err = repo.SetUserLicense(ctx, createRes.ID, &invalid)
+	require.ErrorIs(t, err, ErrInvalidLicense)
+
+	_, err = repo.SetUserLicense(ctx, "00000000-0000-0000-0000-000000000000", &license)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	user, err = repo.SetUserLicense(ctx, createRes.ID, nil)
+	require.NoError(t, err, "Clearing the license failed")
+	require.Nil(t, user.License)
+}
+
+// TestLicenseExpiredOn tests that a license is good through its expiry date
+func TestLicenseExpiredOn(t *testing.T) {
+	license := License{ExpiresOn: time.Date(2026, 10, 31, 0, 0, 0, 0, time.UTC)}
+
+	require.False(t, license.ExpiredOn(time.Date(2026, 10, 30, 12, 0, 0, 0, time.UTC)))
+	require.False(t, license.ExpiredOn(time.Date(2026, 10, 31, 23, 59, 0, 0, time.UTC)))
+	require.True(t, license.ExpiredOn(time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)))
+}
@@ -6,6 +6,7 @@ import dynamic from 'next/dynamic';
 import { formsClient } from '@/lib/api/forms';
 import { chemicalsClient } from '@/lib/api/chemicals';
 import { FormViewResponse, ListChemicalsResponse } from '@/lib/api/types';
+import { useSiteCodes } from '@/lib/common/siteCodes';
 
 // Dynamically import PDF components (they don't work with SSR)
 const PDFViewer = dynamic(
@@ -29,6 +30,7 @@ export default function PrintFormPage() {
 
     const [form, setForm] = useState<FormViewResponse | null>(null);
     const [chemList, setChemList] = useState<ListChemicalsResponse | null>(null);
+    const { siteCodesFirst, siteCodesSecond, error: siteCodesError } = useSiteCodes();
     const [isLoading, setIsLoading] = useState(true);
     const [error, setError] = useState<string | null>(null);
 
@@ -84,13 +86,13 @@ export default function PrintFormPage() {
         );
     }
 
-    if (error || !form) {
+    if (error || siteCodesError || !form) {
         return (
             <div className="min-h-screen bg-zinc-50 dark:bg-zinc-950 flex items-center justify-center">
                 <div className="bg-white dark:bg-zinc-900 rounded-lg shadow p-8 max-w-md">
                     <h1 className="text-2xl font-bold text-red-600 dark:text-red-400 mb-4">Error</h1>
                     <p className="text-zinc-900 dark:text-zinc-50 mb-4">
-                        {error || 'Form not found'}
+                        {error || siteCodesError || 'Form not found'}
                     </p>
                     <button
                         onClick={() => router.push('/forms')}
@@ -131,7 +133,11 @@ export default function PrintFormPage() {
                         </h2>
                         <div style={{ height: '800px', width: '100%' }}>
                             <PDFViewer style={{ width: '100%', height: '100%' }}>
-                                <FormPDFDocument form={processedData} chemicalList={chemList!} />
+                                <FormPDFDocument
+                                    form={processedData}
+                                    chemicalList={chemList!}
+                                    siteCodes={{ siteCodesFirst, siteCodesSecond }}
+                                />
                             </PDFViewer>
                         </div>
                     </div>
@@ -5,7 +5,7 @@ import { useRouter } from 'next/navigation';
 import { formsClient } from '@/lib/api/forms';
 import { chemicalsClient } from '@/lib/api/chemicals';
 import { Chemical, PesticideApplication } from '@/lib/api/types';
-import { siteCodesFirst, siteCodesSecond } from '@/lib/common/siteCodes';
+import { useSiteCodes } from '@/lib/common/siteCodes';
 
 /**
  * Create Lawn Form Page
@@ -16,6 +16,7 @@ import { siteCodesFirst, siteCodesSecond } from '@/lib/common/siteCodes';
 export default function CreateLawnFormPage() {
     const router = useRouter();
     const [chemicals, setChemicals] = useState<Chemical[]>([]);
+    const { siteCodesFirst, siteCodesSecond, error: siteCodesError } = useSiteCodes();
     const [firstName, setFirstName] = useState('');
     const [lastName, setLastName] = useState('');
     const [streetNumber, setStreetNumber] = useState('');
@@ -266,9 +267,9 @@ export default function CreateLawnFormPage() {
                 </div>
                 <div className="bg-white dark:bg-zinc-900 rounded-lg shadow p-6">
                     <form onSubmit={handleSubmit} className="space-y-4">
-                        {error && (
+                        {(error || siteCodesError) && (
                             <div className="bg-red-50 dark:bg-red-900/20 border border-red-200 dark:border-red-800 rounded-lg p-4">
-                                <p className="text-red-800 dark:text-red-200">{error}</p>
+                                <p className="text-red-800 dark:text-red-200">{error || siteCodesError}</p>
                             </div>
                         )}
 
@@ -0,0 +1,35 @@
+import { ListSiteCodesResponse } from './types'
+import ApiClient from './common'
+
+/**
+ * Client for interacting with the Site Codes API.
+ *
+ * This client wraps the `/api/site-codes` endpoint, the site location codes
+ * the backend accepts on pesticide applications.
+ *
+ * @extends ApiClient
+ */
+export class SiteCodesClient extends ApiClient {
+    /**
+     * List every site code along with the areas and features they combine.
+     *
+     * Sends a `GET` request to `/api/site-codes`.
+     *
+     * @returns A promise that resolves to the site code areas, features and codes
+     *
+     * @throws {AuthError} If the user is not authenticated
+     */
+    async listSiteCodes(): Promise<ListSiteCodesResponse> {
+        return await this.request<ListSiteCodesResponse>('/site-codes', {
+            method: 'GET',
+            credentials: 'include',
+        })
+    }
+}
+
+/**
+ * Singleton instance of {@link SiteCodesClient}.
+ *
+ * Use this instance for all site code API interactions.
+ */
+export const siteCodesClient = new SiteCodesClient();
@@ -314,6 +314,29 @@ export interface ListChemicalsResponse {
     count: number;
 }
 
+// ============================================================================
+// Site Codes API Types
+// ============================================================================
+
+export interface SiteCodePart {
+    code: string;
+    description: string;
+}
+
+export interface SiteCode {
+    code: string;
+    area: string;
+    feature: string;
+    description: string;
+}
+
+export interface ListSiteCodesResponse {
+    areas: SiteCodePart[];
+    features: SiteCodePart[];
+    site_codes: SiteCode[];
+    count: number;
+}
+
 // ============================================================================
 // Forms API Error Classes
 // ============================================================================
@@ -6,18 +6,51 @@
  * - Alphabetic suffix (A-D): Feature type identifier
  *
  * Example: "1A" = Front Yard, Lawn
+ *
+ * The codes are defined by the backend and loaded from `GET /api/site-codes`,
+ * so the forms accept exactly the codes the backend validates.
  */
 
-export const siteCodesFirst = {
-    '1': 'Front Yard',
-    '2': 'Side Yard(s)',
-    '3': 'Rear Yard',
-    '4': 'Entire Property',
-    '5': 'Other',
+import { useEffect, useState } from 'react';
+import { siteCodesClient } from '../api/siteCodes';
+import { SiteCodePart } from '../api/types';
+
+/** Descriptions keyed by code, e.g. { '1': 'Front Yard' } */
+export type SiteCodeTable = Record<string, string>;
+
+export interface SiteCodes {
+    /** Areas, the first character of a code */
+    siteCodesFirst: SiteCodeTable;
+    /** Features, the second character of a code */
+    siteCodesSecond: SiteCodeTable;
 }
-export const siteCodesSecond = {
-    'A': 'Lawn',
-    'B': 'Shrub(s)',
-    'C': 'Ornamental Tree(s)',
-    'D': 'Other',
+
+const toTable = (parts: SiteCodePart[]): SiteCodeTable =>
+    Object.fromEntries(parts.map((part) => [part.code, part.description]));
+
+/**
+ * Fetch the site codes from the backend.
+ */
+export async function fetchSiteCodes(): Promise<SiteCodes> {
+    const data = await siteCodesClient.listSiteCodes();
+    return {
+        siteCodesFirst: toTable(data.areas),
+        siteCodesSecond: toTable(data.features),
+    };
+}
+
+/**
+ * Load the site codes once on mount. The tables are empty until they load.
+ */
+export function useSiteCodes(): SiteCodes & { error: string | null } {
+    const [siteCodes, setSiteCodes] = useState<SiteCodes>({ siteCodesFirst: {}, siteCodesSecond: {} });
+    const [error, setError] = useState<string | null>(null);
+
+    useEffect(() => {
+        fetchSiteCodes()
+            .then(setSiteCodes)
+            .catch((err) => setError(err instanceof Error ? err.message : 'Failed to load site codes'));
+    }, []);
+
+    return { ...siteCodes, error };
 }
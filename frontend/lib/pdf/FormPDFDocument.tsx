@@ -1,7 +1,7 @@
 import React from 'react';
 import { Document, Page, Text, View, StyleSheet } from '@react-pdf/renderer';
 import { FormViewResponse, ListChemicalsResponse } from '@/lib/api/types';
-import { siteCodesFirst, siteCodesSecond } from '../common/siteCodes';
+import { SiteCodes } from '../common/siteCodes';
 
 // Compact styles for single-page layout
 const styles = StyleSheet.create({
@@ -120,9 +120,11 @@ interface FormPDFDocumentProps {
         fullAddress?: string;
     };
     chemicalList: ListChemicalsResponse;
+    siteCodes: SiteCodes;
 }
 
-const FormPDFDocument: React.FC<FormPDFDocumentProps> = ({ form, chemicalList }) => {
+const FormPDFDocument: React.FC<FormPDFDocumentProps> = ({ form, chemicalList, siteCodes }) => {
+    const { siteCodesFirst, siteCodesSecond } = siteCodes;
     // Get unique chemicals used in this form
     return (
         <Document>